@@ -1,21 +1,36 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
 	"fmt"
 	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
 	"super-characters/audio"
+	"super-characters/config"
 	"super-characters/elevenlabs"
+	"super-characters/forwarder"
 	"super-characters/gemini"
+	"super-characters/history"
 	"super-characters/hotkey"
+	"super-characters/llm"
+	"super-characters/metrics"
 	"super-characters/permissions"
+	"super-characters/personas"
+	"super-characters/playercontrol"
 	"super-characters/settings"
+	"super-characters/spotify"
 	"super-characters/transcription"
+	"super-characters/tts"
 	"super-characters/vad"
+	"super-characters/voicemsg"
 
 	"github.com/wailsapp/wails/v3/pkg/application"
 )
@@ -30,6 +45,13 @@ const (
 	ConversationStateSpeaking   ConversationState = "speaking"
 )
 
+// bargeInEnergyThreshold is the elevated RMS energy threshold VAD uses while
+// Settings.BargeInMode keeps listening active through
+// ConversationStateSpeaking - high enough to ignore the agent's own TTS
+// bleeding into the mic, low enough that a user talking over it still
+// triggers onVADSpeechStart.
+const bargeInEnergyThreshold float32 = 0.05
+
 // App struct holds application state and dependencies
 type App struct {
 	app           *application.App
@@ -42,10 +64,46 @@ type App struct {
 	hotkeyService        *hotkey.HotkeyService
 	permissionsService   *permissions.PermissionsService
 
+	// vocabLoader loads the user-editable JSONC vocab file (custom words,
+	// filler overrides, stutter threshold) - see ReloadVocab and
+	// transcription.VocabLoader.
+	vocabLoader *transcription.VocabLoader
+
+	// bindingManager loads the user-editable keybindings file mapping
+	// hotkey actions (hotkey.ActionPushToTalk, hotkey.ActionRecordToggle)
+	// to hotkey strings - see RegisterHotkeys and hotkey.BindingManager.
+	bindingManager *hotkey.BindingManager
+
+	// Voice messages (record-and-keep, as opposed to transcribe-and-discard)
+	voiceMessageService *voicemsg.Service
+
+	// Media player control (PlayPauseMusic, NextTrack, PreviousTrack,
+	// SetVolume, GetNowPlaying) - see player_control.go and the
+	// playercontrol package for the per-platform backends.
+	playerController playercontrol.PlayerController
+
+	// Spotify Web API integration (see spotify_control.go and the spotify
+	// package), a richer alternative to playerController that
+	// resolveMusicBackend picks between per Settings.MusicControlBackend.
+	spotifyService *spotify.Service
+
 	// Conversation mode services
-	geminiService     *gemini.GeminiService
-	elevenlabsService *elevenlabs.ElevenLabsService
-	settingsService   *settings.SettingsService
+	geminiService          *gemini.GeminiService
+	elevenlabsService      *elevenlabs.ElevenLabsService
+	piperService           *tts.PiperService
+	openaiTTSService       *tts.OpenAIService
+	ttsRegistry            *tts.Registry
+	llmRegistry            *llm.Registry
+	openaiLLMProvider      *llm.OpenAIProvider
+	anthropicProvider      *llm.AnthropicProvider
+	ollamaProvider         *llm.OllamaProvider
+	settingsService        *settings.SettingsService
+	configLoader           *config.CharacterConfigLoader
+	metricsRegistry        *metrics.Registry
+	metricsServer          *http.Server
+	forwarderServer        *forwarder.Server
+	transcriptionRPC       *transcription.RPCServer
+	transcriptionRPCCancel context.CancelFunc
 
 	// Recording state
 	isTranscribing bool
@@ -59,6 +117,21 @@ type App struct {
 	isConversationMode  bool
 	conversationHistory []gemini.ChatMessage
 
+	// activePersona is the persona selected by StartConversationWithPersona
+	// for the current conversation, or nil if none is active (e.g.
+	// StartConversation was used instead). It overrides the system prompt
+	// and TTS voice for the conversation without touching the corresponding
+	// global Settings.
+	activePersona *personas.Persona
+
+	// Persistent conversation history (see recordTurn, StartConversation,
+	// ListConversations/GetConversation/SearchConversations/
+	// ResumeConversation/ExportConversation). activeConversationID is the
+	// history.Store row recordTurn appends to; 0 means nothing is being
+	// persisted (e.g. historyStore failed to open).
+	historyStore         *history.Store
+	activeConversationID int64
+
 	// Continuous conversation mode (VAD-based)
 	vadService             *vad.VADService
 	continuousMode         bool
@@ -66,6 +139,40 @@ type App struct {
 	continuousState        ConversationState
 	pendingSpeechProcessed bool // Prevents duplicate processing
 
+	// Streaming transcription (see startStreamingRecognition). When
+	// Settings.STTStreamingMode is enabled and streamingRecognizer is
+	// configured, startContinuousListening opens a streamSession instead of
+	// buffering audio until VAD detects silence.
+	streamingRecognizer transcription.StreamingRecognizer
+	streamMutex         sync.Mutex
+	streamSession       transcription.StreamSession
+	lastInterimText     string
+
+	// Barge-in (see handleBargeIn, InterruptConversation,
+	// processConversationWithCallback). turnCancel aborts the in-flight
+	// Gemini/TTS goroutine for the current conversation turn;
+	// pendingAssistantIndex is that turn's assistant message in
+	// conversationHistory, and pendingTurnSentences the sentences of it
+	// already streamed to the frontend (see ProcessVoiceInput) - both are
+	// used to truncate the message to whatever was actually heard if the
+	// turn is interrupted. NotifyInterrupted does the same truncation from
+	// frontend-reported text, for the older processConversationWithCallback
+	// flow that doesn't track per-sentence seq numbers.
+	turnMutex             sync.Mutex
+	turnCancel            context.CancelFunc
+	pendingAssistantIndex int
+	pendingTurnSentences  []string
+
+	// Streaming TTS playback tracking (see streamTTSAudio /
+	// NotifyPlaybackComplete). Each conversation:audio-chunk event carries a
+	// monotonically increasing id; once the frontend has played the chunk
+	// marked "final" it calls NotifyPlaybackComplete(id), which resumes
+	// listening instead of waiting out an estimated duration.
+	playbackMutex     sync.Mutex
+	playbackSeq       int
+	pendingPlaybackID int
+	pendingOnComplete func()
+
 	// Context for transcription
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -85,16 +192,110 @@ func NewApp() *App {
 	vadCfg := vad.DefaultConfig()
 	vadCfg.SilenceDuration = silenceDuration
 
+	metricsRegistry := metrics.NewRegistry()
+	geminiSvc := gemini.NewGeminiService()
+	geminiSvc.SetMetrics(metricsRegistry)
+
+	elevenlabsSvc := elevenlabs.NewElevenLabsService()
+	elevenlabsSvc.SetMetrics(metricsRegistry)
+	piperSvc := tts.NewPiperService("")
+	openaiTTSSvc := tts.NewOpenAIService()
+
+	ttsRegistry := tts.NewRegistry()
+	ttsRegistry.Register(elevenlabs.ProviderName, elevenlabsSvc)
+	ttsRegistry.Register(tts.PiperProviderName, piperSvc)
+	ttsRegistry.Register(tts.OpenAIProviderName, openaiTTSSvc)
+	tts.RegisterOSProviders(ttsRegistry)
+
+	var openaiAPIKey, anthropicAPIKey, ollamaBaseURL string
+	if settingsSvc != nil {
+		openaiAPIKey = settingsSvc.GetOpenAIAPIKey()
+		anthropicAPIKey = settingsSvc.GetAnthropicAPIKey()
+		ollamaBaseURL = settingsSvc.GetOllamaBaseURL()
+	}
+	openaiTTSSvc.SetAPIKey(openaiAPIKey)
+	openaiLLMProvider := llm.NewOpenAIProvider(openaiAPIKey, "")
+	anthropicProvider := llm.NewAnthropicProvider(anthropicAPIKey)
+	ollamaProvider := llm.NewOllamaProvider(ollamaBaseURL)
+
+	llmRegistry := llm.NewRegistry()
+	llmRegistry.Register(llm.GeminiProviderName, llm.NewGeminiProvider(geminiSvc))
+	llmRegistry.Register(llm.OpenAIProviderName, openaiLLMProvider)
+	llmRegistry.Register(llm.AnthropicProviderName, anthropicProvider)
+	llmRegistry.Register(llm.OllamaProviderName, ollamaProvider)
+
+	var configLoader *config.CharacterConfigLoader
+	if homeDir, err := os.UserHomeDir(); err == nil {
+		configLoader = config.NewCharacterConfigLoader(filepath.Join(homeDir, ".super-characters"))
+	}
+
+	var googleCredentialsPath string
+	if settingsSvc != nil {
+		googleCredentialsPath = settingsSvc.GetGoogleSpeechCredentialsPath()
+	}
+
+	voiceMsgSvc, err := voicemsg.NewService()
+	if err != nil {
+		slog.Warn("[VoiceMessage] Service unavailable", "error", err)
+	}
+
+	var historyStore *history.Store
+	if homeDir, err := os.UserHomeDir(); err == nil {
+		store, err := history.Open(filepath.Join(homeDir, ".super-characters", history.DBFileName))
+		if err != nil {
+			slog.Warn("[History] Conversation history database unavailable", "error", err)
+		} else {
+			historyStore = store
+		}
+	}
+
+	configDir := ""
+	if homeDir, err := os.UserHomeDir(); err == nil {
+		configDir = filepath.Join(homeDir, ".super-characters")
+	}
+	spotifySvc := spotify.NewService(configDir)
+	if settingsSvc != nil {
+		spotifySvc.Configure(spotify.Config{ClientID: settingsSvc.GetSpotifyClientID()})
+	}
+
+	transcriptionSvc := transcription.NewTranscriptionService()
+
+	var vocabLoader *transcription.VocabLoader
+	var bindingManager *hotkey.BindingManager
+	if homeDir, err := os.UserHomeDir(); err == nil {
+		vocabLoader = transcription.NewVocabLoader(filepath.Join(homeDir, ".super-characters", transcription.VocabFileName))
+		bindingManager = hotkey.NewBindingManager(filepath.Join(homeDir, ".super-characters", hotkey.BindingsFileName))
+	}
+
 	return &App{
-		transcriptionService: transcription.NewTranscriptionService(),
-		audioService:         audio.NewAudioService(),
-		hotkeyService:        hotkey.NewHotkeyService(),
-		permissionsService:   permSvc,
-		geminiService:        gemini.NewGeminiService(),
-		elevenlabsService:    elevenlabs.NewElevenLabsService(),
-		settingsService:      settingsSvc,
-		vadService:           vad.NewVADService(vadCfg),
-		continuousState:      ConversationStateIdle,
+		transcriptionService:  transcriptionSvc,
+		audioService:          audio.NewAudioService(),
+		hotkeyService:         hotkey.NewHotkeyService(),
+		permissionsService:    permSvc,
+		vocabLoader:           vocabLoader,
+		bindingManager:        bindingManager,
+		voiceMessageService:   voiceMsgSvc,
+		playerController:      playercontrol.New(),
+		spotifyService:        spotifySvc,
+		geminiService:         geminiSvc,
+		elevenlabsService:     elevenlabsSvc,
+		piperService:          piperSvc,
+		openaiTTSService:      openaiTTSSvc,
+		ttsRegistry:           ttsRegistry,
+		llmRegistry:           llmRegistry,
+		openaiLLMProvider:     openaiLLMProvider,
+		anthropicProvider:     anthropicProvider,
+		ollamaProvider:        ollamaProvider,
+		settingsService:       settingsSvc,
+		configLoader:          configLoader,
+		metricsRegistry:       metricsRegistry,
+		forwarderServer:       forwarder.NewServer(),
+		transcriptionRPC:      transcription.NewRPCServer(transcriptionSvc),
+		vadService:            vad.NewVADService(vadCfg),
+		continuousState:       ConversationStateIdle,
+		streamingRecognizer:   transcription.NewGoogleStreamingRecognizer(googleCredentialsPath),
+		historyStore:          historyStore,
+		pendingAssistantIndex: -1,
 	}
 }
 
@@ -291,6 +492,65 @@ func (a *App) ServiceStartup(ctx context.Context, options application.ServiceOpt
 				a.elevenlabsService.SetVoiceID(currentSettings.ElevenLabsVoiceID)
 			}
 		}
+		if a.piperService != nil && currentSettings.PiperModelPath != "" {
+			a.piperService.SetModelPath(currentSettings.PiperModelPath)
+		}
+		if a.ttsRegistry != nil {
+			a.ttsRegistry.SetActive(currentSettings.TTSProvider)
+		}
+		if a.llmRegistry != nil {
+			a.llmRegistry.SetActive(currentSettings.LLMProvider)
+		}
+		if currentSettings.WhisperImplementation != "" {
+			if err := a.transcriptionService.SetWhisperImplementation(currentSettings.WhisperImplementation); err != nil {
+				slog.Warn("failed to set whisper implementation from settings", "error", err)
+			}
+		}
+		if currentSettings.PreferredDevice != "" {
+			if err := a.transcriptionService.SetPreferredDevice(currentSettings.PreferredDevice); err != nil {
+				slog.Warn("failed to set preferred device from settings", "error", err)
+			}
+		}
+	}
+
+	// Load character packs and start watching for changes
+	if a.configLoader != nil {
+		if err := a.configLoader.Reload(); err != nil {
+			slog.Warn("failed to load character packs", "error", err)
+		}
+		a.configLoader.Watch()
+	}
+
+	// Load the vocab file (custom words, filler overrides, stutter
+	// threshold) and start watching for changes - see ReloadVocab for the
+	// user-triggered equivalent.
+	if a.vocabLoader != nil {
+		if err := a.vocabLoader.Reload(); err != nil {
+			slog.Warn("failed to load vocab file", "error", err)
+		}
+		a.vocabLoader.Watch()
+	}
+
+	// Load the keybindings file (overriding the built-in hotkey defaults)
+	// and start watching for changes - a later change re-registers the
+	// affected hotkey automatically. SetOnChange is deliberately set after
+	// this first Reload, so loading the file's initial contents doesn't
+	// itself trigger a re-register before RegisterHotkeys runs below.
+	if a.bindingManager != nil {
+		if err := a.bindingManager.Reload(); err != nil {
+			slog.Warn("failed to load keybindings file", "error", err)
+		}
+		a.bindingManager.SetOnChange(func(action, hotkeyStr string) {
+			slog.Info("keybinding changed, re-registering hotkeys", "action", action, "hotkey", hotkeyStr)
+			a.hotkeyService.Stop()
+			a.RegisterHotkeys()
+		})
+		a.bindingManager.Watch()
+	}
+
+	// Start the /metrics endpoint if Settings.MetricsEnabled is set
+	if a.settingsService != nil && a.settingsService.GetMetricsEnabled() {
+		a.startMetricsServer()
 	}
 
 	// Initialize transcription service
@@ -320,6 +580,14 @@ func (a *App) ServiceStartup(ctx context.Context, options application.ServiceOpt
 		}
 	})
 
+	// Notify the frontend when the OS default microphone changes, so the
+	// settings pane can refresh its device picker.
+	a.audioService.OnDeviceChange(func(device audio.DeviceInfo) {
+		if a.app != nil {
+			a.app.Event.Emit("audio:device-changed", device)
+		}
+	})
+
 	// Register hotkeys after a short delay to ensure event loop is running
 	go func() {
 		time.Sleep(500 * time.Millisecond)
@@ -336,6 +604,29 @@ func (a *App) ServiceShutdown() error {
 	// Stop continuous listening if active
 	a.stopContinuousListening()
 
+	// Stop watching character packs
+	if a.configLoader != nil {
+		a.configLoader.StopWatch()
+	}
+
+	// Stop watching the vocab file
+	if a.vocabLoader != nil {
+		a.vocabLoader.StopWatch()
+	}
+
+	// Stop watching the keybindings file
+	if a.bindingManager != nil {
+		a.bindingManager.StopWatch()
+	}
+
+	// Stop the /metrics endpoint
+	a.stopMetricsServer()
+
+	// Stop the forwarder server
+	if a.forwarderServer != nil {
+		a.forwarderServer.Stop()
+	}
+
 	// Stop hotkey service
 	a.hotkeyService.Stop()
 
@@ -350,6 +641,11 @@ func (a *App) ServiceShutdown() error {
 	// Close transcription service
 	a.transcriptionService.Close()
 
+	// Close the conversation history database
+	if a.historyStore != nil {
+		a.historyStore.Close()
+	}
+
 	return nil
 }
 
@@ -366,6 +662,17 @@ func (a *App) RegisterHotkeys() {
 		pressAndTalkHotkey = a.settingsService.GetPressAndTalkHotkey()
 	}
 
+	// The keybindings file (hotkey.BindingManager) takes priority over both
+	// defaults above when it has an explicit binding for the action.
+	if a.bindingManager != nil {
+		if hk := a.bindingManager.Lookup(hotkey.ActionPushToTalk); hk != "" {
+			holdToTalkHotkey = hk
+		}
+		if hk := a.bindingManager.Lookup(hotkey.ActionRecordToggle); hk != "" {
+			pressAndTalkHotkey = hk
+		}
+	}
+
 	// Set up the press-and-talk toggle callback
 	// This is called when the hands-free (press-and-talk) hotkey is toggled
 	a.hotkeyService.SetHandsFreeCallback(a.onPressAndTalkToggle)
@@ -482,7 +789,7 @@ func (a *App) StopTranscription() string {
 	slog.Info("processing audio", "samples", len(samples))
 
 	// Process transcription (this emits transcription-segment events)
-	text, lang, err := a.transcriptionService.Process(samples, a.ctx)
+	text, lang, err := a.transcriptionService.Process("", samples, a.ctx)
 	if err != nil {
 		slog.Error("transcription failed", "error", err)
 		return "Transcription failed: " + err.Error()
@@ -503,6 +810,78 @@ func (a *App) IsTranscribing() bool {
 	return a.isTranscribing
 }
 
+// #region Voice Messages (record-and-keep)
+
+// StartVoiceMessage begins recording an offline voice message from the
+// microphone - a "record and keep" alternative to the live
+// transcribe-and-discard flow. Recording continues until StopVoiceMessage,
+// with voicemsg:level events emitted periodically for a live level meter.
+func (a *App) StartVoiceMessage() {
+	if a.voiceMessageService == nil {
+		slog.Error("[VoiceMessage] Service not available")
+		return
+	}
+
+	if err := a.voiceMessageService.Start(int(a.audioService.GetSampleRate())); err != nil {
+		slog.Error("[VoiceMessage] Failed to start recording", "error", err)
+		return
+	}
+
+	a.audioService.SetStreamCallback(func(samples []float32) {
+		a.voiceMessageService.AddSamples(samples, func(level float32) {
+			if a.app != nil {
+				a.app.Event.Emit("voicemsg:level", map[string]interface{}{
+					"level": level,
+				})
+			}
+		})
+	})
+
+	if err := a.audioService.Start(); err != nil {
+		slog.Error("[VoiceMessage] Failed to start audio capture", "error", err)
+		a.audioService.ClearStreamCallback()
+		a.voiceMessageService.Stop()
+		return
+	}
+
+	slog.Info("[VoiceMessage] Recording started")
+}
+
+// StopVoiceMessage ends the in-progress voice message recording, encodes it
+// to a standalone Ogg Opus file, and returns its path, waveform preview
+// (see voicemsg.WaveformBars), and duration. It also emits voicemsg:saved
+// with the same metadata.
+func (a *App) StopVoiceMessage() (path string, waveform []float32, durationMs int) {
+	a.audioService.ClearStreamCallback()
+	if err := a.audioService.Stop(); err != nil {
+		slog.Warn("[VoiceMessage] Failed to stop audio capture", "error", err)
+	}
+
+	if a.voiceMessageService == nil {
+		slog.Error("[VoiceMessage] Service not available")
+		return "", nil, 0
+	}
+
+	msg, err := a.voiceMessageService.Stop()
+	if err != nil {
+		slog.Error("[VoiceMessage] Failed to save recording", "error", err)
+		return "", nil, 0
+	}
+
+	if a.app != nil {
+		a.app.Event.Emit("voicemsg:saved", map[string]interface{}{
+			"path":       msg.Path,
+			"waveform":   msg.Waveform,
+			"durationMs": msg.DurationMs,
+		})
+	}
+
+	slog.Info("[VoiceMessage] Recording saved", "path", msg.Path, "durationMs", msg.DurationMs)
+	return msg.Path, msg.Waveform, msg.DurationMs
+}
+
+// #endregion Voice Messages
+
 // #region Continuous Conversation Mode (VAD-based)
 
 // startContinuousListening begins continuous voice activity detection
@@ -520,7 +899,7 @@ func (a *App) startContinuousListening() {
 
 	// Start conversation mode if not already active
 	if !a.isConversationMode {
-		a.StartConversation()
+		a.StartConversation("")
 	}
 
 	// Set up VAD callbacks
@@ -529,9 +908,14 @@ func (a *App) startContinuousListening() {
 		a.onVADSpeechEnd,
 	)
 
-	// Set up audio streaming to VAD
+	// Set up audio streaming to VAD, and to the streaming recognizer when
+	// Settings.STTStreamingMode selects it
+	streaming := a.startStreamingRecognition()
 	a.audioService.SetStreamCallback(func(samples []float32) {
 		a.vadService.ProcessSamples(samples)
+		if streaming {
+			a.sendStreamingAudio(samples)
+		}
 	})
 
 	// Start audio capture
@@ -575,6 +959,14 @@ func (a *App) stopContinuousListening() {
 	// Stop audio capture
 	a.audioService.Stop()
 
+	// Tear down any open streaming-recognition session
+	a.streamMutex.Lock()
+	if a.streamSession != nil {
+		a.streamSession.Close()
+		a.streamSession = nil
+	}
+	a.streamMutex.Unlock()
+
 	// Emit event to frontend
 	if a.app != nil {
 		a.app.Event.Emit("conversation:listening-stopped", nil)
@@ -586,11 +978,19 @@ func (a *App) stopContinuousListening() {
 // onVADSpeechStart is called when VAD detects speech starting
 func (a *App) onVADSpeechStart() {
 	a.continuousStateMutex.Lock()
-	if a.continuousState != ConversationStateListening {
-		a.continuousStateMutex.Unlock()
+	state := a.continuousState
+	a.continuousStateMutex.Unlock()
+
+	if state == ConversationStateSpeaking {
+		if a.settingsService != nil && a.settingsService.GetBargeInMode() {
+			a.handleBargeIn()
+		}
+		return
+	}
+
+	if state != ConversationStateListening {
 		return
 	}
-	a.continuousStateMutex.Unlock()
 
 	slog.Debug("[ContinuousMode] Speech detected")
 
@@ -600,6 +1000,91 @@ func (a *App) onVADSpeechStart() {
 	}
 }
 
+// handleBargeIn is called when VAD detects the user speaking while the agent
+// is still talking (Settings.BargeInMode only - see onVADSpeechStart). It
+// transitions back to listening so the interrupting speech is picked up as
+// a new utterance, then defers the rest of the interruption to
+// InterruptConversation.
+func (a *App) handleBargeIn() {
+	a.continuousStateMutex.Lock()
+	if a.continuousState != ConversationStateSpeaking {
+		a.continuousStateMutex.Unlock()
+		return
+	}
+	a.continuousState = ConversationStateListening
+	a.continuousStateMutex.Unlock()
+
+	slog.Info("[ContinuousMode] Barge-in detected, interrupting agent speech")
+	a.InterruptConversation()
+}
+
+// InterruptConversation cancels whatever conversation turn is in flight,
+// truncates its assistant message in conversationHistory to only the
+// sentences already streamed to the frontend (see ProcessVoiceInput's
+// pendingTurnSentences), and emits conversation:interrupted so the frontend
+// stops TTS playback. It's bound to the frontend directly - e.g. for a click
+// on the 3D avatar - in addition to being used internally by handleBargeIn
+// for VAD-triggered barge-in. When no sentences have been streamed (the
+// processConversationWithCallback flow), the message is left as it is and
+// pendingAssistantIndex is kept for NotifyInterrupted to truncate.
+func (a *App) InterruptConversation() {
+	a.turnMutex.Lock()
+	cancel := a.turnCancel
+	a.turnCancel = nil
+	idx := a.pendingAssistantIndex
+	sentences := a.pendingTurnSentences
+	if len(sentences) > 0 {
+		a.pendingAssistantIndex = -1
+	}
+	a.pendingTurnSentences = nil
+	a.turnMutex.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+
+	a.continuousStateMutex.Lock()
+	if a.continuousState == ConversationStateSpeaking {
+		a.continuousState = ConversationStateListening
+	}
+	a.continuousStateMutex.Unlock()
+
+	if a.vadService != nil {
+		a.vadService.SetEnergyThreshold(vad.DefaultConfig().EnergyThreshold)
+	}
+
+	if len(sentences) > 0 && idx >= 0 && idx < len(a.conversationHistory) {
+		spoken := strings.Join(sentences, " ")
+		a.conversationHistory[idx].Content = spoken
+		slog.Info("[Conversation] Truncated interrupted response to what was spoken", "spoken", spoken)
+	}
+
+	if a.app != nil {
+		a.app.Event.Emit("conversation:interrupted", nil)
+	}
+}
+
+// NotifyInterrupted is called by the frontend once it has stopped TTS
+// playback in response to a conversation:interrupted event, passing the
+// prefix of the response that had actually played. It's the
+// processConversationWithCallback flow's counterpart to
+// InterruptConversation's seq-based truncation: that flow doesn't stream
+// sentence-by-sentence, so it has no pendingTurnSentences to truncate from
+// and relies on the frontend reporting what it actually played instead.
+func (a *App) NotifyInterrupted(spokenText string) {
+	a.turnMutex.Lock()
+	idx := a.pendingAssistantIndex
+	a.pendingAssistantIndex = -1
+	a.turnMutex.Unlock()
+
+	if idx < 0 || idx >= len(a.conversationHistory) {
+		return
+	}
+
+	a.conversationHistory[idx].Content = spokenText
+	slog.Info("[Conversation] Truncated interrupted response to what was spoken", "spoken", spokenText)
+}
+
 // onVADSpeechEnd is called when VAD detects speech ending (silence threshold reached)
 func (a *App) onVADSpeechEnd(samples []float32) {
 	a.continuousStateMutex.Lock()
@@ -618,10 +1103,27 @@ func (a *App) onVADSpeechEnd(samples []float32) {
 	if a.app != nil {
 		a.app.Event.Emit("conversation:processing", nil)
 	}
+	if a.forwarderServer != nil {
+		a.forwarderServer.BroadcastState(string(ConversationStateProcessing))
+	}
 
 	// Pause VAD during processing to avoid picking up TTS audio
 	a.vadService.Pause()
 
+	a.streamMutex.Lock()
+	streaming := a.streamSession != nil
+	a.streamMutex.Unlock()
+
+	if streaming {
+		// Audio was already sent incrementally via sendStreamingAudio; use
+		// whatever the recognizer has settled on so far rather than
+		// re-transcribing the batch. The server may still deliver its own
+		// final result shortly after, in which case finishStreamingTranscript
+		// simply runs again with (usually) the same text.
+		go a.finalizeStreamingUtterance()
+		return
+	}
+
 	// Process the speech samples
 	go a.processContinuousSpeech(samples)
 }
@@ -634,7 +1136,7 @@ func (a *App) processContinuousSpeech(samples []float32) {
 	}
 
 	// Transcribe the audio
-	text, lang, err := a.transcriptionService.Process(samples, a.ctx)
+	text, lang, err := a.transcriptionService.Process("", samples, a.ctx)
 	if err != nil {
 		slog.Error("[ContinuousMode] Transcription failed", "error", err)
 		a.resumeListening()
@@ -649,6 +1151,10 @@ func (a *App) processContinuousSpeech(samples []float32) {
 
 	slog.Info("[ContinuousMode] Transcribed", "text", text, "language", lang)
 
+	if a.forwarderServer != nil {
+		a.forwarderServer.BroadcastText(text)
+	}
+
 	// Note: transcription-complete event is already emitted by TranscriptionService.Process()
 	// The frontend AI SDK agent listens for that event and handles LLM processing
 
@@ -659,13 +1165,198 @@ func (a *App) processContinuousSpeech(samples []float32) {
 	a.continuousStateMutex.Unlock()
 }
 
-// processConversationWithCallback processes a conversation turn and calls the callback when done
+// startStreamingRecognition opens a transcription.StreamSession against
+// a.streamingRecognizer when Settings.STTStreamingMode is enabled and the
+// recognizer is configured, storing it in a.streamSession and spawning the
+// goroutine that drains its results. It returns whether streaming mode is
+// active for this continuous-listening session.
+func (a *App) startStreamingRecognition() bool {
+	if a.settingsService == nil || !a.settingsService.GetSTTStreamingMode() {
+		return false
+	}
+	if a.streamingRecognizer == nil || !a.streamingRecognizer.IsConfigured() {
+		return false
+	}
+
+	lang := a.settingsService.GetSTTLanguageOverride(a.streamingRecognizer.Name())
+	session, err := a.streamingRecognizer.StartStream(a.ctx, 16000, lang)
+	if err != nil {
+		slog.Error("[ContinuousMode] Failed to start streaming recognition", "error", err)
+		return false
+	}
+
+	a.streamMutex.Lock()
+	a.streamSession = session
+	a.lastInterimText = ""
+	a.streamMutex.Unlock()
+
+	go a.drainStreamingResults(session)
+	return true
+}
+
+// sendStreamingAudio forwards samples to the open streaming-recognition
+// session, converting them to the little-endian mono PCM16 format
+// transcription.StreamSession.Send expects. It's a no-op if no session is
+// open.
+func (a *App) sendStreamingAudio(samples []float32) {
+	a.streamMutex.Lock()
+	session := a.streamSession
+	a.streamMutex.Unlock()
+	if session == nil {
+		return
+	}
+
+	if err := session.Send(floatSamplesToPCM16(samples)); err != nil {
+		slog.Warn("[ContinuousMode] Failed to send audio to streaming recognizer", "error", err)
+	}
+}
+
+// drainStreamingResults forwards interim and final results from session as
+// transcription:interim events, and hands final results to
+// finishStreamingTranscript. It returns once session's Results channel
+// closes.
+func (a *App) drainStreamingResults(session transcription.StreamSession) {
+	for result := range session.Results() {
+		if a.app != nil {
+			a.app.Event.Emit("transcription:interim", map[string]interface{}{
+				"text":    result.Text,
+				"isFinal": result.IsFinal,
+			})
+		}
+
+		a.streamMutex.Lock()
+		a.lastInterimText = result.Text
+		a.streamMutex.Unlock()
+
+		if result.IsFinal {
+			a.finishStreamingTranscript(result.Text)
+		}
+	}
+}
+
+// finalizeStreamingUtterance is called on VAD speech-end while a streaming
+// session is open. The recognizer may still be mid-flight on its own final
+// result, so this finalizes using whatever text it has settled on so far
+// rather than waiting indefinitely.
+func (a *App) finalizeStreamingUtterance() {
+	a.streamMutex.Lock()
+	text := a.lastInterimText
+	a.lastInterimText = ""
+	a.streamMutex.Unlock()
+
+	a.finishStreamingTranscript(text)
+}
+
+// finishStreamingTranscript is the streaming-mode equivalent of
+// processContinuousSpeech's tail: it emits transcription-complete for text
+// settled on by the streaming recognizer and hands off to the frontend, or
+// resumes listening if nothing was transcribed.
+func (a *App) finishStreamingTranscript(text string) {
+	if text == "" {
+		slog.Debug("[ContinuousMode] Empty streaming transcription, resuming listening")
+		a.resumeListening()
+		return
+	}
+
+	slog.Info("[ContinuousMode] Streaming transcription finalized", "text", text)
+
+	if a.forwarderServer != nil {
+		a.forwarderServer.BroadcastText(text)
+	}
+
+	if a.app != nil {
+		a.app.Event.Emit("transcription-complete", map[string]interface{}{
+			"text":      text,
+			"segments":  1,
+			"timestamp": time.Now().Unix(),
+		})
+	}
+
+	// Keep VAD paused - frontend will call ResumeListening after TTS completes
+	a.continuousStateMutex.Lock()
+	a.continuousState = ConversationStateProcessing
+	a.continuousStateMutex.Unlock()
+}
+
+// floatSamplesToPCM16 converts mono float32 samples in [-1, 1] to
+// little-endian PCM16 bytes, the format transcription.StreamSession.Send
+// expects.
+func floatSamplesToPCM16(samples []float32) []byte {
+	out := make([]byte, len(samples)*2)
+	for i, s := range samples {
+		v := int16(s * 32767)
+		out[i*2] = byte(v)
+		out[i*2+1] = byte(v >> 8)
+	}
+	return out
+}
+
+// activeTTSProvider returns the tts.Provider currently selected by
+// Settings.TTSProvider, or nil if the registry isn't set up or the selection
+// is invalid.
+func (a *App) activeTTSProvider() tts.Provider {
+	if a.ttsRegistry == nil {
+		return nil
+	}
+	provider, err := a.ttsRegistry.Active()
+	if err != nil {
+		return nil
+	}
+	return provider
+}
+
+// activePersonaVoiceID returns the TTS voice ID override for the active
+// persona (see StartConversationWithPersona), or "" if no persona is active
+// or it doesn't set one - meaning callers should use the provider's
+// configured default voice.
+func (a *App) activePersonaVoiceID() string {
+	if a.activePersona == nil {
+		return ""
+	}
+	return a.activePersona.VoiceID
+}
+
+// activeLLMProvider returns the llm.Provider currently selected by
+// Settings.LLMProvider, or nil if the registry isn't set up or the
+// selection is invalid.
+func (a *App) activeLLMProvider() llm.Provider {
+	if a.llmRegistry == nil {
+		return nil
+	}
+	provider, err := a.llmRegistry.Active()
+	if err != nil {
+		return nil
+	}
+	return provider
+}
+
+// toLLMMessages converts conversationHistory's gemini.ChatMessage entries
+// (the app's one shared conversation format) to llm.Message, which has an
+// identical shape, for calling through the active llm.Provider.
+func toLLMMessages(history []gemini.ChatMessage) []llm.Message {
+	messages := make([]llm.Message, len(history))
+	for i, m := range history {
+		messages[i] = llm.Message{Role: m.Role, Content: m.Content}
+	}
+	return messages
+}
+
+// processConversationWithCallback processes a conversation turn and calls
+// the callback when done. The turn can be aborted mid-flight by a barge-in
+// (see handleBargeIn): a.turnCancel is armed for the duration of the call,
+// and TTS synthesis is run against the resulting context so it unblocks
+// promptly instead of finishing a response nobody is listening to anymore.
 func (a *App) processConversationWithCallback(text string, onComplete func()) {
 	if text == "" {
 		onComplete()
 		return
 	}
 
+	turnCtx, cancel := context.WithCancel(a.ctx)
+	a.turnMutex.Lock()
+	a.turnCancel = cancel
+	a.turnMutex.Unlock()
+
 	slog.Info("[Conversation] Processing user input", "text", text)
 
 	// Emit user message to frontend
@@ -680,6 +1371,7 @@ func (a *App) processConversationWithCallback(text string, onComplete func()) {
 		Role:    "user",
 		Content: text,
 	})
+	a.recordTurn("user", text, "")
 
 	// Trim history to max turns (keep system prompt + last N turn pairs)
 	maxMessages := 1 + gemini.MaxConversationTurns*2 // system + N*(user+assistant)
@@ -695,35 +1387,69 @@ func (a *App) processConversationWithCallback(text string, onComplete func()) {
 		a.app.Event.Emit("conversation:thinking", nil)
 	}
 
-	// Call Gemini
-	if a.geminiService == nil || !a.geminiService.IsConfigured() {
-		a.emitConversationError("Gemini API key not configured")
+	// Call the active LLM provider
+	llmProvider := a.activeLLMProvider()
+	if llmProvider == nil || !llmProvider.IsConfigured() {
+		a.emitConversationError("LLM provider not configured")
 		onComplete()
 		return
 	}
 
-	response, err := a.geminiService.Chat(a.conversationHistory)
+	response, err := llmProvider.Chat(turnCtx, toLLMMessages(a.conversationHistory))
 	if err != nil {
-		a.emitConversationError(fmt.Sprintf("Gemini error: %v", err))
+		a.emitConversationError(fmt.Sprintf("LLM error: %v", err))
 		onComplete()
 		return
 	}
 
+	if turnCtx.Err() != nil {
+		// Interrupted while waiting on the LLM - InterruptConversation has
+		// already reset the turn state, so just drop the response.
+		return
+	}
+
 	// Append assistant response to history
 	a.conversationHistory = append(a.conversationHistory, gemini.ChatMessage{
 		Role:    "assistant",
 		Content: response,
 	})
+	a.recordTurn("assistant", response, llmProvider.Name())
+	a.turnMutex.Lock()
+	a.pendingAssistantIndex = len(a.conversationHistory) - 1
+	a.turnMutex.Unlock()
+
+	a.continuousStateMutex.Lock()
+	a.continuousState = ConversationStateSpeaking
+	a.continuousStateMutex.Unlock()
+
+	// Synthesize TTS via the active provider. A provider that can stream
+	// (ElevenLabsService today) gets first crack at it: chunks go out over
+	// conversation:audio-chunk as they're produced, and onComplete fires
+	// once the frontend acknowledges the final one via
+	// NotifyPlaybackComplete - no duration estimate involved. Non-streaming
+	// providers fall back to the previous one-shot Synthesize path.
+	provider := a.activeTTSProvider()
+	if streamProvider, ok := provider.(tts.StreamingProvider); ok && streamProvider.IsConfigured() && !streamProvider.SpeaksAloud() {
+		if a.streamTTSAudio(turnCtx, streamProvider, response, onComplete) {
+			slog.Info("[Conversation] Response sent", "text", response, "streaming", true)
+			return
+		}
+	}
 
-	// Synthesize TTS via ElevenLabs
 	var audioBase64 string
 	var audioDuration time.Duration
-	if a.elevenlabsService != nil && a.elevenlabsService.IsConfigured() {
-		mp3Bytes, err := a.elevenlabsService.Synthesize(response)
+	var spokeAloud bool
+	if provider != nil && provider.IsConfigured() {
+		audioBytes, _, err := provider.Synthesize(turnCtx, response, a.activePersonaVoiceID())
 		if err != nil {
-			slog.Warn("[Conversation] ElevenLabs TTS error (falling back to text-only)", "error", err)
+			slog.Warn("[Conversation] TTS error (falling back to text-only)", "provider", provider.Name(), "error", err)
+		} else if provider.SpeaksAloud() {
+			// The provider already played the audio itself (e.g. an OS-native
+			// backend) by the time Synthesize returned - no playback to wait
+			// on and nothing to send the frontend to play.
+			spokeAloud = true
 		} else {
-			audioBase64 = base64.StdEncoding.EncodeToString(mp3Bytes)
+			audioBase64 = base64.StdEncoding.EncodeToString(audioBytes)
 			// Estimate audio duration (rough estimate: ~150 words per minute, ~5 chars per word)
 			wordCount := float64(len(response)) / 5.0
 			audioDuration = time.Duration(wordCount/150.0*60.0) * time.Second
@@ -732,7 +1458,7 @@ func (a *App) processConversationWithCallback(text string, onComplete func()) {
 			}
 		}
 	} else {
-		slog.Info("[Conversation] ElevenLabs not configured, sending text-only response")
+		slog.Info("[Conversation] TTS not configured, sending text-only response")
 	}
 
 	// Emit response to frontend
@@ -743,19 +1469,30 @@ func (a *App) processConversationWithCallback(text string, onComplete func()) {
 		if audioBase64 != "" {
 			payload["audio"] = audioBase64
 		}
+		if a.activePersona != nil {
+			payload["personaId"] = a.activePersona.ID
+		}
 		a.app.Event.Emit("conversation:response", payload)
 	}
 
 	slog.Info("[Conversation] Response sent", "text", response, "hasAudio", audioBase64 != "")
 
-	// Wait for audio playback to complete before resuming listening
-	// Add a small buffer to ensure audio finishes
-	if audioDuration > 0 {
+	// Wait for audio playback to complete before resuming listening. A
+	// SpeaksAloud provider has already finished playing by the time
+	// Synthesize returns, so there's nothing left to wait on beyond a short
+	// buffer; otherwise fall back to estimating from audioDuration.
+	switch {
+	case spokeAloud:
+		go func() {
+			time.Sleep(500 * time.Millisecond)
+			onComplete()
+		}()
+	case audioDuration > 0:
 		go func() {
 			time.Sleep(audioDuration + 500*time.Millisecond)
 			onComplete()
 		}()
-	} else {
+	default:
 		// No audio, resume immediately after a short delay
 		go func() {
 			time.Sleep(500 * time.Millisecond)
@@ -764,23 +1501,108 @@ func (a *App) processConversationWithCallback(text string, onComplete func()) {
 	}
 }
 
-// resumeListening resumes listening after processing/speaking is complete
-func (a *App) resumeListening() {
-	a.continuousStateMutex.Lock()
-	if !a.continuousMode {
-		a.continuousStateMutex.Unlock()
-		return
+// streamTTSAudio synthesizes response through provider's streaming API and
+// emits each chunk as a conversation:audio-chunk event carrying a
+// monotonically increasing id, rather than buffering the whole utterance
+// before sending anything. The last chunk is marked "final"; onComplete is
+// deferred until the frontend acknowledges having played it via
+// NotifyPlaybackComplete, instead of an estimated duration. Returns false
+// (having emitted nothing) if synthesis produced no chunks at all, so the
+// caller can fall back to the one-shot Synthesize path.
+func (a *App) streamTTSAudio(ctx context.Context, provider tts.StreamingProvider, response string, onComplete func()) bool {
+	chunks, errc := provider.SynthesizeStream(ctx, response, a.activePersonaVoiceID())
+
+	sent := false
+	for chunk := range chunks {
+		a.playbackMutex.Lock()
+		a.playbackSeq++
+		id := a.playbackSeq
+		a.playbackMutex.Unlock()
+
+		if a.app != nil {
+			a.app.Event.Emit("conversation:audio-chunk", map[string]interface{}{
+				"id":    id,
+				"audio": base64.StdEncoding.EncodeToString(chunk),
+				"final": false,
+			})
+		}
+		sent = true
 	}
-	a.continuousState = ConversationStateListening
-	a.continuousStateMutex.Unlock()
 
-	// Resume VAD
-	a.vadService.Resume()
+	if err := <-errc; err != nil {
+		slog.Warn("[Conversation] streaming TTS error", "provider", provider.Name(), "error", err)
+		if !sent {
+			return false
+		}
+	}
+	if !sent {
+		return false
+	}
+
+	a.playbackMutex.Lock()
+	a.playbackSeq++
+	finalID := a.playbackSeq
+	a.pendingPlaybackID = finalID
+	a.pendingOnComplete = onComplete
+	a.playbackMutex.Unlock()
 
-	// Emit event to frontend
+	if a.app != nil {
+		a.app.Event.Emit("conversation:audio-chunk", map[string]interface{}{
+			"id":    finalID,
+			"audio": "",
+			"final": true,
+		})
+		responsePayload := map[string]interface{}{
+			"text": response,
+		}
+		if a.activePersona != nil {
+			responsePayload["personaId"] = a.activePersona.ID
+		}
+		a.app.Event.Emit("conversation:response", responsePayload)
+	}
+
+	return true
+}
+
+// NotifyPlaybackComplete is called by the frontend once it has finished
+// playing the conversation:audio-chunk marked "final" with the given id.
+// If id matches the playback streamTTSAudio is currently waiting on, this
+// resumes listening; a stale or unexpected id (e.g. a duplicate call) is
+// ignored.
+func (a *App) NotifyPlaybackComplete(id int) {
+	a.playbackMutex.Lock()
+	onComplete := a.pendingOnComplete
+	expected := a.pendingPlaybackID
+	if onComplete == nil || id != expected {
+		a.playbackMutex.Unlock()
+		return
+	}
+	a.pendingOnComplete = nil
+	a.playbackMutex.Unlock()
+
+	onComplete()
+}
+
+// resumeListening resumes listening after processing/speaking is complete
+func (a *App) resumeListening() {
+	a.continuousStateMutex.Lock()
+	if !a.continuousMode {
+		a.continuousStateMutex.Unlock()
+		return
+	}
+	a.continuousState = ConversationStateListening
+	a.continuousStateMutex.Unlock()
+
+	// Resume VAD
+	a.vadService.Resume()
+
+	// Emit event to frontend
 	if a.app != nil {
 		a.app.Event.Emit("conversation:listening-resumed", nil)
 	}
+	if a.forwarderServer != nil {
+		a.forwarderServer.BroadcastState(string(ConversationStateListening))
+	}
 
 	slog.Info("[ContinuousMode] Resumed listening")
 }
@@ -799,23 +1621,43 @@ func (a *App) IsContinuousMode() bool {
 	return a.continuousMode
 }
 
-// PauseListening pauses the VAD to prevent picking up TTS audio
+// PauseListening pauses the VAD to prevent picking up TTS audio, unless
+// Settings.BargeInMode is enabled, in which case VAD keeps running at an
+// elevated energy threshold so the user can interrupt the agent.
 // Called by frontend when agent starts speaking
 func (a *App) PauseListening() {
-	if a.vadService != nil {
-		a.vadService.Pause()
-		slog.Info("[ContinuousMode] Listening paused by frontend")
+	a.continuousStateMutex.Lock()
+	a.continuousState = ConversationStateSpeaking
+	a.continuousStateMutex.Unlock()
+
+	if a.vadService == nil {
+		return
 	}
+
+	if a.settingsService != nil && a.settingsService.GetBargeInMode() {
+		a.vadService.SetEnergyThreshold(bargeInEnergyThreshold)
+		slog.Info("[ContinuousMode] Listening kept active for barge-in by frontend")
+		return
+	}
+
+	a.vadService.Pause()
+	slog.Info("[ContinuousMode] Listening paused by frontend")
 }
 
 // ResumeListening resumes the VAD after TTS playback is complete
 // Called by frontend when agent finishes speaking
 func (a *App) ResumeListening() {
 	if a.vadService != nil {
+		a.vadService.SetEnergyThreshold(vad.DefaultConfig().EnergyThreshold)
 		a.vadService.Resume()
 		slog.Info("[ContinuousMode] Listening resumed by frontend")
 	}
 
+	a.turnMutex.Lock()
+	a.turnCancel = nil
+	a.pendingAssistantIndex = -1
+	a.turnMutex.Unlock()
+
 	// Update state and emit event for overlay
 	a.continuousStateMutex.Lock()
 	a.continuousState = ConversationStateListening
@@ -855,6 +1697,50 @@ func (a *App) listModels() []transcription.ModelInfo {
 	return a.transcriptionService.ListModels()
 }
 
+// ListModels returns all whisper models - built-in and user-registered
+// custom ones - with IsDownloaded/IsActive computed identically for both.
+func (a *App) ListModels() []transcription.ModelInfo {
+	return a.transcriptionService.ListModels()
+}
+
+// RegisterCustomModel adds a user-defined whisper model so it can be
+// downloaded and switched to like a built-in one. model.Url must point at
+// a ggml-*.bin file; model.SHA256 is optional but recommended.
+func (a *App) RegisterCustomModel(model transcription.ModelInfo) error {
+	return a.transcriptionService.RegisterCustomModel(model)
+}
+
+// RemoveCustomModel unregisters a previously added custom model.
+func (a *App) RemoveCustomModel(name string) error {
+	return a.transcriptionService.RemoveCustomModel(name)
+}
+
+// GetRecommendedModel inspects this Mac's RAM, CPU, chip, and free disk
+// space and returns the largest whisper model expected to run comfortably,
+// so the settings pane can show "Recommended for this Mac: <name>".
+func (a *App) GetRecommendedModel(prefs transcription.SelectionPrefs) transcription.ModelInfo {
+	return transcription.SelectRecommendedModel(prefs)
+}
+
+// ListInputDevices returns the available microphone devices for a settings
+// picker, with their default sample rate, channel count, and whether each
+// is the OS default.
+func (a *App) ListInputDevices() ([]audio.DeviceInfo, error) {
+	return a.audioService.ListInputDevices()
+}
+
+// SetInputDevice selects which microphone future audio capture should use.
+// Pass an empty id to fall back to the OS default input device.
+func (a *App) SetInputDevice(id string) error {
+	return a.audioService.SetInputDevice(id)
+}
+
+// SwitchInputDevice changes the active microphone while audio capture is
+// already running, without interrupting an in-progress recording.
+func (a *App) SwitchInputDevice(id string) error {
+	return a.audioService.SwitchInputDevice(id)
+}
+
 // Greet returns a greeting message - example method exposed to frontend
 func (a *App) Greet(name string) string {
 	if name == "" {
@@ -887,11 +1773,16 @@ func (a *App) CheckMicrophone() string {
 	return string(a.permissionsService.CheckMicrophone())
 }
 
-// RequestMicrophonePermission triggers the system microphone permission dialog
-func (a *App) RequestMicrophonePermission() {
-	if a.permissionsService != nil {
-		a.permissionsService.RequestMicrophonePermission()
+// RequestMicrophonePermission triggers the system microphone permission
+// dialog and blocks until the user responds (or a.ctx is cancelled),
+// returning the resulting status - the frontend awaits this instead of
+// polling CheckMicrophone.
+func (a *App) RequestMicrophonePermission() string {
+	if a.permissionsService == nil {
+		return string(permissions.StatusUnknown)
 	}
+	status := <-a.permissionsService.RequestMicrophonePermission(a.ctx)
+	return string(status)
 }
 
 // OpenMicrophoneSettings opens System Settings to the Microphone pane
@@ -906,20 +1797,179 @@ func (a *App) OpenMicrophoneSettings() {
 // StartConversation enters conversation mode. In this mode, hotkey-triggered
 // transcriptions are routed to the LLM for a conversational reply, which is
 // then synthesized to speech via TTS and sent to the frontend for playback.
-func (a *App) StartConversation() string {
+//
+// characterName selects a character pack loaded by a.configLoader to
+// override the system prompt and TTS provider/voice for this session; pass
+// "" to use the defaults (gemini.ConversationSystemPrompt and whatever TTS
+// provider Settings already selects).
+func (a *App) StartConversation(characterName string) string {
 	a.isConversationMode = true
+	a.activePersona = nil
+
+	systemPrompt := gemini.ConversationSystemPrompt
+	if characterName != "" && a.configLoader != nil {
+		if character, ok := a.configLoader.GetCharacter(characterName); ok {
+			if character.SystemPrompt != "" {
+				systemPrompt = character.SystemPrompt
+			}
+			if character.TTSProvider != "" && a.ttsRegistry != nil {
+				if err := a.ttsRegistry.SetActive(character.TTSProvider); err != nil {
+					slog.Warn("[Conversation] Character's TTS provider is not available", "character", characterName, "provider", character.TTSProvider, "error", err)
+				}
+			}
+		} else {
+			slog.Warn("[Conversation] Unknown character, using defaults", "character", characterName)
+		}
+	}
+
 	a.conversationHistory = []gemini.ChatMessage{
-		{Role: "system", Content: gemini.ConversationSystemPrompt},
+		{Role: "system", Content: systemPrompt},
+	}
+
+	a.activeConversationID = 0
+	if a.historyStore != nil {
+		model := ""
+		if llmProvider := a.activeLLMProvider(); llmProvider != nil {
+			model = llmProvider.Name()
+		}
+		id, err := a.historyStore.StartConversation(characterName, model)
+		if err != nil {
+			slog.Warn("[History] Failed to start persisted conversation", "error", err)
+		} else {
+			a.activeConversationID = id
+		}
 	}
 
-	slog.Info("[Conversation] Mode started")
+	slog.Info("[Conversation] Mode started", "character", characterName)
 	return "Conversation started"
 }
 
+// StartConversationWithPersona enters conversation mode using a saved
+// persona (see ListPersonas/CreatePersona) for its system prompt and TTS
+// voice, overriding both for this conversation only - the persona's voice
+// is passed per-call to Synthesize/SynthesizeStream rather than touching
+// Settings.ElevenLabsVoiceID. Pass "" for id to start with the same
+// defaults as StartConversation.
+func (a *App) StartConversationWithPersona(id string) string {
+	a.isConversationMode = true
+	a.activePersona = nil
+
+	systemPrompt := gemini.ConversationSystemPrompt
+	if id != "" && a.settingsService != nil {
+		if persona, ok := a.settingsService.GetPersona(id); ok {
+			a.activePersona = &persona
+			if persona.SystemPrompt != "" {
+				systemPrompt = persona.SystemPrompt
+			}
+		} else {
+			slog.Warn("[Conversation] Unknown persona, using defaults", "persona", id)
+		}
+	}
+
+	a.conversationHistory = []gemini.ChatMessage{
+		{Role: "system", Content: systemPrompt},
+	}
+
+	a.activeConversationID = 0
+	if a.historyStore != nil {
+		model := ""
+		if llmProvider := a.activeLLMProvider(); llmProvider != nil {
+			model = llmProvider.Name()
+		}
+		convID, err := a.historyStore.StartConversation(id, model)
+		if err != nil {
+			slog.Warn("[History] Failed to start persisted conversation", "error", err)
+		} else {
+			a.activeConversationID = convID
+		}
+	}
+
+	slog.Info("[Conversation] Mode started", "persona", id)
+	return "Conversation started"
+}
+
+// ListPersonas returns every saved persona.
+func (a *App) ListPersonas() []personas.Persona {
+	if a.settingsService == nil {
+		return nil
+	}
+	return a.settingsService.GetPersonas()
+}
+
+// CreatePersona saves a new persona, assigning it an ID if one wasn't
+// provided, and returns the saved persona.
+func (a *App) CreatePersona(p personas.Persona) (personas.Persona, error) {
+	if a.settingsService == nil {
+		return personas.Persona{}, fmt.Errorf("settings are not available")
+	}
+	return a.settingsService.AddPersona(p)
+}
+
+// UpdatePersona replaces the persona with the same ID as p.
+func (a *App) UpdatePersona(p personas.Persona) error {
+	if a.settingsService == nil {
+		return fmt.Errorf("settings are not available")
+	}
+	return a.settingsService.UpdatePersona(p)
+}
+
+// DeletePersona removes the persona with the given ID.
+func (a *App) DeletePersona(id string) error {
+	if a.settingsService == nil {
+		return fmt.Errorf("settings are not available")
+	}
+	return a.settingsService.DeletePersona(id)
+}
+
+// recordTurn persists one conversation turn to a.historyStore, if a
+// conversation is currently being recorded (see StartConversation,
+// ResumeConversation), and emits history:updated so a live conversation
+// list stays current. It's a no-op if history persistence isn't
+// available or no conversation is active.
+func (a *App) recordTurn(role, content, model string) {
+	if a.historyStore == nil || a.activeConversationID == 0 {
+		return
+	}
+	turn := history.Turn{
+		Role:      role,
+		Content:   content,
+		CreatedAt: time.Now(),
+		Model:     model,
+	}
+	if err := a.historyStore.AppendTurn(a.activeConversationID, turn); err != nil {
+		slog.Warn("[History] Failed to persist turn", "error", err)
+		return
+	}
+	if a.app != nil {
+		a.app.Event.Emit("history:updated", nil)
+	}
+}
+
+// ListCharacters returns the names of every loaded character pack.
+func (a *App) ListCharacters() []string {
+	if a.configLoader == nil {
+		return nil
+	}
+	return a.configLoader.ListCharacters()
+}
+
+// ReloadCharacters re-scans the character packs directory immediately,
+// instead of waiting for the next watch poll.
+func (a *App) ReloadCharacters() string {
+	if a.configLoader == nil {
+		return "Character packs not available"
+	}
+	if err := a.configLoader.Reload(); err != nil {
+		return fmt.Sprintf("Failed to reload character packs: %v", err)
+	}
+	return "Character packs reloaded"
+}
+
 // StopConversation exits conversation mode and clears history.
 func (a *App) StopConversation() string {
 	a.isConversationMode = false
 	a.conversationHistory = nil
+	a.activeConversationID = 0
 	slog.Info("[Conversation] Mode stopped")
 	return "Conversation stopped"
 }
@@ -929,13 +1979,116 @@ func (a *App) IsConversationMode() bool {
 	return a.isConversationMode
 }
 
-// ProcessVoiceInput takes a transcribed user message, sends it to the LLM,
-// synthesizes the response with TTS, and emits events for the frontend.
+// ListConversations returns every persisted conversation, most recent
+// first, or nil if conversation history isn't available.
+func (a *App) ListConversations() []history.ConversationSummary {
+	if a.historyStore == nil {
+		return nil
+	}
+	conversations, err := a.historyStore.ListConversations()
+	if err != nil {
+		slog.Warn("[History] Failed to list conversations", "error", err)
+		return nil
+	}
+	return conversations
+}
+
+// GetConversation returns every turn of a persisted conversation, in the
+// order they were recorded.
+func (a *App) GetConversation(id int64) []gemini.ChatMessage {
+	if a.historyStore == nil {
+		return nil
+	}
+	messages, err := a.historyStore.GetConversation(id)
+	if err != nil {
+		slog.Warn("[History] Failed to load conversation", "id", id, "error", err)
+		return nil
+	}
+	return messages
+}
+
+// SearchConversations runs a full-text search over every persisted turn.
+func (a *App) SearchConversations(query string) []history.Hit {
+	if a.historyStore == nil {
+		return nil
+	}
+	hits, err := a.historyStore.Search(query)
+	if err != nil {
+		slog.Warn("[History] Search failed", "query", query, "error", err)
+		return nil
+	}
+	return hits
+}
+
+// ResumeConversation rehydrates conversationHistory from a persisted
+// conversation and makes it the active conversation, so ProcessVoiceInput
+// continues it instead of starting fresh.
+func (a *App) ResumeConversation(id int64) error {
+	if a.historyStore == nil {
+		return fmt.Errorf("conversation history is not available")
+	}
+	messages, err := a.historyStore.GetConversation(id)
+	if err != nil {
+		return err
+	}
+	if len(messages) == 0 {
+		return fmt.Errorf("conversation %d not found", id)
+	}
+
+	a.conversationHistory = append([]gemini.ChatMessage{
+		{Role: "system", Content: gemini.ConversationSystemPrompt},
+	}, messages...)
+	a.activeConversationID = id
+	a.isConversationMode = true
+
+	slog.Info("[Conversation] Resumed from history", "id", id)
+	return nil
+}
+
+// ExportConversation renders a persisted conversation as Markdown (the
+// default) or JSON; see history.Store.Export for supported formats.
+func (a *App) ExportConversation(id int64, format string) (string, error) {
+	if a.historyStore == nil {
+		return "", fmt.Errorf("conversation history is not available")
+	}
+	return a.historyStore.Export(id, format)
+}
+
+// sentenceTerminators are the characters ProcessVoiceInput treats as the
+// end of a speakable sentence when chunking a streamed LLM reply for
+// sentence-level TTS synthesis.
+const sentenceTerminators = ".?!"
+
+// sentenceFlushTimeout is the soft timeout ProcessVoiceInput waits for a
+// sentence terminator before flushing whatever text has accumulated anyway,
+// so a reply with unusual punctuation (or a slow token) still starts
+// speaking instead of stalling indefinitely.
+const sentenceFlushTimeout = 2 * time.Second
+
+// ProcessVoiceInput takes a transcribed user message, streams the LLM's
+// reply via the active llm.Provider's ChatStream, and synthesizes it sentence by
+// sentence as each one completes, emitting a conversation:response-delta
+// event per sentence so the frontend can start playing sentence N while
+// sentence N+1 is still being generated and synthesized. The turn is
+// cancellable for barge-in: a.handleBargeIn cancels the context stored in
+// a.turnCancel, which aborts both the LLM stream and any in-flight
+// synthesis.
 func (a *App) ProcessVoiceInput(text string) {
 	if text == "" {
 		return
 	}
 
+	turnCtx, cancel := context.WithCancel(a.ctx)
+	a.turnMutex.Lock()
+	if a.turnCancel != nil {
+		// A previous turn is still in flight (e.g. the user spoke again
+		// before it finished) - cancel it before starting this one so its
+		// goroutine doesn't keep streaming/synthesizing into the void.
+		a.turnCancel()
+	}
+	a.turnCancel = cancel
+	a.turnMutex.Unlock()
+
 	slog.Info("[Conversation] Processing user input", "text", text)
 
 	// Emit user message to frontend
@@ -950,6 +2103,7 @@ func (a *App) ProcessVoiceInput(text string) {
 		Role:    "user",
 		Content: text,
 	})
+	a.recordTurn("user", text, "")
 
 	// Trim history to max turns (keep system prompt + last N turn pairs)
 	maxMessages := 1 + gemini.MaxConversationTurns*2 // system + N*(user+assistant)
@@ -966,49 +2120,149 @@ func (a *App) ProcessVoiceInput(text string) {
 		a.app.Event.Emit("conversation:thinking", nil)
 	}
 
-	// Call Gemini
-	if a.geminiService == nil || !a.geminiService.IsConfigured() {
-		a.emitConversationError("Gemini API key not configured")
+	// Call the active LLM provider
+	llmProvider := a.activeLLMProvider()
+	if llmProvider == nil || !llmProvider.IsConfigured() {
+		a.emitConversationError("LLM provider not configured")
 		return
 	}
 
-	response, err := a.geminiService.Chat(a.conversationHistory)
-	if err != nil {
-		a.emitConversationError(fmt.Sprintf("Gemini error: %v", err))
-		return
-	}
+	ttsProvider := a.activeTTSProvider()
+	streamProvider, _ := ttsProvider.(tts.StreamingProvider)
+	if ttsProvider == nil || !ttsProvider.IsConfigured() {
+		slog.Info("[Conversation] TTS not configured, sending text-only response")
+		ttsProvider = nil
+	}
+
+	// Reserve this turn's assistant message up front and track it (and the
+	// sentences streamed into it so far) via pendingAssistantIndex/
+	// pendingTurnSentences, so InterruptConversation can truncate it to
+	// whatever was actually sent to the frontend if this turn gets
+	// barged in on.
+	a.conversationHistory = append(a.conversationHistory, gemini.ChatMessage{Role: "assistant"})
+	assistantIdx := len(a.conversationHistory) - 1
+	a.turnMutex.Lock()
+	a.pendingAssistantIndex = assistantIdx
+	a.pendingTurnSentences = nil
+	a.turnMutex.Unlock()
+
+	deltas, errc := llmProvider.ChatStream(turnCtx, toLLMMessages(a.conversationHistory[:assistantIdx]))
+
+	var sentence strings.Builder
+	seq := 0
+	flush := func(final bool) {
+		s := strings.TrimSpace(sentence.String())
+		sentence.Reset()
+		if s == "" && !final {
+			return
+		}
+		seq++
 
-	// Append assistant response to history
-	a.conversationHistory = append(a.conversationHistory, gemini.ChatMessage{
-		Role:    "assistant",
-		Content: response,
-	})
+		var audioB64 string
+		if s != "" && ttsProvider != nil {
+			audioB64 = a.synthesizeSentence(turnCtx, streamProvider, ttsProvider, s)
+		}
 
-	// Synthesize TTS via ElevenLabs
-	var audioBase64 string
-	if a.elevenlabsService != nil && a.elevenlabsService.IsConfigured() {
-		mp3Bytes, err := a.elevenlabsService.Synthesize(response)
-		if err != nil {
-			slog.Warn("[Conversation] ElevenLabs TTS error (falling back to text-only)", "error", err)
-		} else {
-			audioBase64 = base64.StdEncoding.EncodeToString(mp3Bytes)
+		if s != "" {
+			a.turnMutex.Lock()
+			a.pendingTurnSentences = append(a.pendingTurnSentences, s)
+			spoken := strings.Join(a.pendingTurnSentences, " ")
+			a.turnMutex.Unlock()
+			a.conversationHistory[assistantIdx].Content = spoken
+		}
+
+		if a.app != nil {
+			deltaPayload := map[string]interface{}{
+				"text":       s,
+				"audioChunk": audioB64,
+				"seq":        seq,
+				"final":      final,
+			}
+			if a.activePersona != nil {
+				deltaPayload["personaId"] = a.activePersona.ID
+			}
+			a.app.Event.Emit("conversation:response-delta", deltaPayload)
 		}
-	} else {
-		slog.Info("[Conversation] ElevenLabs not configured, sending text-only response")
 	}
 
-	// Emit response to frontend
-	if a.app != nil {
-		payload := map[string]interface{}{
-			"text": response,
+	timer := time.NewTimer(sentenceFlushTimeout)
+	defer timer.Stop()
+loop:
+	for {
+		select {
+		case delta, ok := <-deltas:
+			if !ok {
+				break loop
+			}
+			sentence.WriteString(delta.Text)
+			if strings.ContainsAny(delta.Text, sentenceTerminators) {
+				flush(false)
+			}
+			if !timer.Stop() {
+				<-timer.C
+			}
+			timer.Reset(sentenceFlushTimeout)
+		case <-timer.C:
+			flush(false)
+			timer.Reset(sentenceFlushTimeout)
+		case <-turnCtx.Done():
+			// Interrupted - InterruptConversation has already truncated
+			// conversationHistory[assistantIdx] and cleared
+			// pendingAssistantIndex/pendingTurnSentences, so there's
+			// nothing left to do here.
+			return
 		}
-		if audioBase64 != "" {
-			payload["audio"] = audioBase64
+	}
+
+	if err := <-errc; err != nil {
+		a.emitConversationError(fmt.Sprintf("LLM error: %v", err))
+		a.turnMutex.Lock()
+		a.pendingAssistantIndex = -1
+		a.pendingTurnSentences = nil
+		a.turnMutex.Unlock()
+		a.conversationHistory = a.conversationHistory[:assistantIdx]
+		return
+	}
+
+	flush(true)
+
+	response := a.conversationHistory[assistantIdx].Content
+	a.recordTurn("assistant", response, llmProvider.Name())
+	a.turnMutex.Lock()
+	a.turnCancel = nil
+	a.pendingAssistantIndex = -1
+	a.pendingTurnSentences = nil
+	a.turnMutex.Unlock()
+
+	slog.Info("[Conversation] Response sent", "text", response, "sentences", seq)
+}
+
+// synthesizeSentence synthesizes one sentence of a streamed reply, base64
+// encoding the result for a conversation:response-delta payload. It prefers
+// streamProvider's streaming API when available (buffering its chunks,
+// since a single sentence is short enough that splitting it further
+// wouldn't help playback latency), falling back to provider's one-shot
+// Synthesize otherwise.
+func (a *App) synthesizeSentence(ctx context.Context, streamProvider tts.StreamingProvider, provider tts.Provider, sentence string) string {
+	if streamProvider != nil {
+		chunks, errc := streamProvider.SynthesizeStream(ctx, sentence, a.activePersonaVoiceID())
+		var buf bytes.Buffer
+		for chunk := range chunks {
+			buf.Write(chunk)
 		}
-		a.app.Event.Emit("conversation:response", payload)
+		if err := <-errc; err != nil {
+			slog.Warn("[Conversation] streaming TTS error", "provider", provider.Name(), "error", err)
+			return ""
+		}
+		return base64.StdEncoding.EncodeToString(buf.Bytes())
 	}
 
-	slog.Info("[Conversation] Response sent", "text", response, "hasAudio", audioBase64 != "")
+	audioBytes, _, err := provider.Synthesize(ctx, sentence, a.activePersonaVoiceID())
+	if err != nil {
+		slog.Warn("[Conversation] TTS error (falling back to text-only)", "provider", provider.Name(), "error", err)
+		return ""
+	}
+	return base64.StdEncoding.EncodeToString(audioBytes)
 }
 
 // emitConversationError sends an error event to the frontend.
@@ -1100,8 +2354,393 @@ func (a *App) GetSilenceDurationMs() int {
 	return settings.DefaultSilenceDurationMs
 }
 
+// SetTTSProvider updates which TTS provider is active in settings and the
+// running app.
+func (a *App) SetTTSProvider(provider string) string {
+	if a.ttsRegistry != nil {
+		a.ttsRegistry.SetActive(provider)
+	}
+	if a.settingsService != nil {
+		if err := a.settingsService.SetTTSProvider(provider); err != nil {
+			return fmt.Sprintf("Failed to save: %v", err)
+		}
+	}
+	return ""
+}
+
+// ListTTSProviders returns the names of every registered TTS provider, for
+// populating a settings picker.
+func (a *App) ListTTSProviders() []string {
+	if a.ttsRegistry == nil {
+		return nil
+	}
+	return a.ttsRegistry.List()
+}
+
+// SetLLMProvider updates which LLM provider is active in settings and the
+// running app.
+func (a *App) SetLLMProvider(provider string) string {
+	if a.llmRegistry != nil {
+		a.llmRegistry.SetActive(provider)
+	}
+	if a.settingsService != nil {
+		if err := a.settingsService.SetLLMProvider(provider); err != nil {
+			return fmt.Sprintf("Failed to save: %v", err)
+		}
+	}
+	return ""
+}
+
+// ListLLMProviders returns the names of every registered LLM provider, for
+// populating a settings picker.
+func (a *App) ListLLMProviders() []string {
+	if a.llmRegistry == nil {
+		return nil
+	}
+	return a.llmRegistry.List()
+}
+
+// SetWhisperImplementation updates which transcription.WhisperBackend is
+// active in settings and the running app.
+func (a *App) SetWhisperImplementation(name string) string {
+	if err := a.transcriptionService.SetWhisperImplementation(name); err != nil {
+		return fmt.Sprintf("Failed to set whisper implementation: %v", err)
+	}
+	if a.settingsService != nil {
+		if err := a.settingsService.SetWhisperImplementation(name); err != nil {
+			return fmt.Sprintf("Failed to save: %v", err)
+		}
+	}
+	return ""
+}
+
+// ListWhisperImplementations returns the names of every registered whisper
+// backend, for populating a settings picker.
+func (a *App) ListWhisperImplementations() []string {
+	return a.transcriptionService.ListWhisperImplementations()
+}
+
+// SetPreferredDevice updates which accelerator the active whisper backend
+// should try first, in settings and the running app. Only takes effect for a
+// backend that supports device selection (transcription.DeviceSelector) -
+// whisper-cpp today.
+func (a *App) SetPreferredDevice(device string) string {
+	if err := a.transcriptionService.SetPreferredDevice(device); err != nil {
+		return fmt.Sprintf("Failed to set preferred device: %v", err)
+	}
+	if a.settingsService != nil {
+		if err := a.settingsService.SetPreferredDevice(device); err != nil {
+			return fmt.Sprintf("Failed to save: %v", err)
+		}
+	}
+	return ""
+}
+
+// ListDevices returns the devices the active whisper backend's Load would
+// consider, for populating a settings picker.
+func (a *App) ListDevices() []string {
+	return a.transcriptionService.ListDevices()
+}
+
+// SetOpenAIAPIKey updates the OpenAI API key in settings and services. The
+// key is shared by the OpenAI llm.Provider and the OpenAI tts.Provider.
+func (a *App) SetOpenAIAPIKey(key string) string {
+	if a.openaiLLMProvider != nil {
+		a.openaiLLMProvider.SetAPIKey(key)
+	}
+	if a.openaiTTSService != nil {
+		a.openaiTTSService.SetAPIKey(key)
+	}
+	if a.settingsService != nil {
+		if err := a.settingsService.SetOpenAIAPIKey(key); err != nil {
+			return fmt.Sprintf("Failed to save: %v", err)
+		}
+	}
+	return ""
+}
+
+// SetAnthropicAPIKey updates the Anthropic API key in settings and service.
+func (a *App) SetAnthropicAPIKey(key string) string {
+	if a.anthropicProvider != nil {
+		a.anthropicProvider.SetAPIKey(key)
+	}
+	if a.settingsService != nil {
+		if err := a.settingsService.SetAnthropicAPIKey(key); err != nil {
+			return fmt.Sprintf("Failed to save: %v", err)
+		}
+	}
+	return ""
+}
+
+// SetOllamaBaseURL updates the Ollama server address in settings and service.
+func (a *App) SetOllamaBaseURL(baseURL string) string {
+	if a.ollamaProvider != nil {
+		a.ollamaProvider.SetBaseURL(baseURL)
+	}
+	if a.settingsService != nil {
+		if err := a.settingsService.SetOllamaBaseURL(baseURL); err != nil {
+			return fmt.Sprintf("Failed to save: %v", err)
+		}
+	}
+	return ""
+}
+
+// SetPiperModelPath updates the Piper voice model path in settings and
+// service.
+func (a *App) SetPiperModelPath(path string) string {
+	if a.piperService != nil {
+		a.piperService.SetModelPath(path)
+	}
+	if a.settingsService != nil {
+		if err := a.settingsService.SetPiperModelPath(path); err != nil {
+			return fmt.Sprintf("Failed to save: %v", err)
+		}
+	}
+	return ""
+}
+
+// SetMetricsEnabled toggles the /metrics endpoint in settings and the
+// running app.
+func (a *App) SetMetricsEnabled(enabled bool) string {
+	if enabled {
+		a.startMetricsServer()
+	} else {
+		a.stopMetricsServer()
+	}
+	if a.settingsService != nil {
+		if err := a.settingsService.SetMetricsEnabled(enabled); err != nil {
+			return fmt.Sprintf("Failed to save: %v", err)
+		}
+	}
+	return ""
+}
+
+// SetMetricsAuthToken updates the /metrics basic-auth token in settings.
+func (a *App) SetMetricsAuthToken(token string) string {
+	if a.settingsService != nil {
+		if err := a.settingsService.SetMetricsAuthToken(token); err != nil {
+			return fmt.Sprintf("Failed to save: %v", err)
+		}
+	}
+	return ""
+}
+
 // #endregion Settings API
 
+// #region Metrics
+
+// metricsAddr is the local-only address the /metrics endpoint listens on.
+const metricsAddr = "127.0.0.1:9977"
+
+// startMetricsServer begins serving /metrics in the background. Safe to
+// call when already running - it's a no-op in that case.
+func (a *App) startMetricsServer() {
+	if a.metricsServer != nil || a.metricsRegistry == nil {
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", a.handleMetrics)
+	server := &http.Server{Addr: metricsAddr, Handler: mux}
+	a.metricsServer = server
+
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			slog.Warn("[Metrics] Server stopped", "error", err)
+		}
+	}()
+	slog.Info("[Metrics] Serving /metrics", "addr", metricsAddr)
+}
+
+// stopMetricsServer shuts down the /metrics endpoint if it's running.
+func (a *App) stopMetricsServer() {
+	if a.metricsServer == nil {
+		return
+	}
+	if err := a.metricsServer.Close(); err != nil {
+		slog.Warn("[Metrics] Failed to stop server", "error", err)
+	}
+	a.metricsServer = nil
+}
+
+// handleMetrics serves the current metrics in Prometheus text exposition
+// format, gated by Settings.MetricsAuthToken when one is configured.
+func (a *App) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	token := ""
+	if a.settingsService != nil {
+		token = a.settingsService.GetMetricsAuthToken()
+	}
+	if token != "" {
+		_, password, ok := r.BasicAuth()
+		if !ok || password != token {
+			w.Header().Set("WWW-Authenticate", `Basic realm="metrics"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	fmt.Fprint(w, a.metricsRegistry.Render())
+}
+
+// #endregion Metrics
+
+// #region Forwarder
+
+// StartForwarder begins streaming listening state and transcribed text to
+// TCP/WebSocket clients (see forwarder.Server for the wire format and
+// forwarder.DefaultPort for the default). port <= 0 selects
+// forwarder.DefaultPort; WebSocket clients connect on port+1. The auth
+// token and LAN-binding settings are applied from settings before Start -
+// BindLAN only takes effect here (it can't rebind a live listener), but the
+// auth token can also be changed later, while running, via
+// SetForwarderAuthToken.
+func (a *App) StartForwarder(port int) error {
+	if a.forwarderServer == nil {
+		return fmt.Errorf("forwarder not available")
+	}
+	if a.settingsService != nil {
+		a.forwarderServer.SetAuthToken(a.settingsService.GetForwarderAuthToken())
+		a.forwarderServer.SetBindLAN(a.settingsService.GetForwarderAllowLAN())
+	}
+	return a.forwarderServer.Start(port)
+}
+
+// StopForwarder stops the forwarder server. Safe to call when not running.
+func (a *App) StopForwarder() {
+	if a.forwarderServer != nil {
+		a.forwarderServer.Stop()
+	}
+}
+
+// IsForwarding reports whether the forwarder server is currently running.
+func (a *App) IsForwarding() bool {
+	return a.forwarderServer != nil && a.forwarderServer.IsRunning()
+}
+
+// SetForwarderAuthToken updates the token required from forwarder clients,
+// including on the currently running server (if any) - tightening it takes
+// effect immediately rather than waiting for a restart.
+func (a *App) SetForwarderAuthToken(token string) string {
+	if a.settingsService != nil {
+		if err := a.settingsService.SetForwarderAuthToken(token); err != nil {
+			return fmt.Sprintf("Failed to save: %v", err)
+		}
+	}
+	if a.forwarderServer != nil {
+		a.forwarderServer.SetAuthToken(token)
+	}
+	return ""
+}
+
+// SetForwarderAllowLAN toggles whether the forwarder binds all interfaces
+// instead of loopback-only. Only takes effect on the next StartForwarder -
+// it can't rebind a listener that's already running.
+func (a *App) SetForwarderAllowLAN(allow bool) string {
+	if a.settingsService != nil {
+		if err := a.settingsService.SetForwarderAllowLAN(allow); err != nil {
+			return fmt.Sprintf("Failed to save: %v", err)
+		}
+	}
+	return ""
+}
+
+// #endregion Forwarder
+
+// #region Transcription RPC
+
+// transcriptionRPCSocketPath returns the default Unix socket path for the
+// transcription RPC server, alongside the app's other per-user state (see
+// configLoader/historyStore's use of ~/.super-characters).
+func transcriptionRPCSocketPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	dir := filepath.Join(homeDir, ".super-characters")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create state directory: %w", err)
+	}
+	return filepath.Join(dir, transcription.DefaultSocketName), nil
+}
+
+// StartTranscriptionRPC begins serving transcription.RPCServer on a Unix
+// domain socket at ~/.super-characters/transcription.sock, so an external
+// CLI or web client can transcribe through the same running process (and
+// loaded model) the embedded UI uses. Safe to call when already running.
+func (a *App) StartTranscriptionRPC() error {
+	if a.transcriptionRPC == nil {
+		return fmt.Errorf("transcription RPC server not available")
+	}
+	if a.transcriptionRPCCancel != nil {
+		return nil
+	}
+
+	socketPath, err := transcriptionRPCSocketPath()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	a.transcriptionRPCCancel = cancel
+	go func() {
+		if err := a.transcriptionRPC.Serve(ctx, socketPath); err != nil {
+			slog.Warn("[TranscriptionRPC] server stopped", "error", err)
+		}
+	}()
+	return nil
+}
+
+// StartTranscriptionRPCTCP begins serving transcription.RPCServer over TCP
+// on addr (e.g. ":9090"), gated by the auth token configured via
+// SetTranscriptionRPCAuthToken - a TCP listener has no filesystem
+// permission boundary to rely on the way the Unix socket does.
+func (a *App) StartTranscriptionRPCTCP(addr string) error {
+	if a.transcriptionRPC == nil {
+		return fmt.Errorf("transcription RPC server not available")
+	}
+	if a.transcriptionRPCCancel != nil {
+		return fmt.Errorf("transcription RPC server already running")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	a.transcriptionRPCCancel = cancel
+	go func() {
+		if err := a.transcriptionRPC.ServeTCP(ctx, addr); err != nil {
+			slog.Warn("[TranscriptionRPC] TCP server stopped", "error", err)
+		}
+	}()
+	return nil
+}
+
+// SetTranscriptionRPCAuthToken configures the token StartTranscriptionRPCTCP
+// requires. Has no effect on the Unix socket transport.
+func (a *App) SetTranscriptionRPCAuthToken(token string) {
+	if a.transcriptionRPC != nil {
+		a.transcriptionRPC.SetAuthToken(token)
+	}
+}
+
+// StopTranscriptionRPC stops the transcription RPC server. Safe to call
+// when not running.
+func (a *App) StopTranscriptionRPC() {
+	if a.transcriptionRPCCancel != nil {
+		a.transcriptionRPCCancel()
+		a.transcriptionRPCCancel = nil
+	}
+	if a.transcriptionRPC != nil {
+		a.transcriptionRPC.Close()
+	}
+}
+
+// IsTranscriptionRPCRunning reports whether the transcription RPC server is
+// currently serving requests.
+func (a *App) IsTranscriptionRPCRunning() bool {
+	return a.transcriptionRPCCancel != nil
+}
+
+// #endregion Transcription RPC
+
 // #region TTS API
 
 // SynthesizeSpeech takes text and returns base64-encoded audio.
@@ -1112,25 +2751,27 @@ func (a *App) SynthesizeSpeech(text string) (string, error) {
 		return "", fmt.Errorf("empty text provided")
 	}
 
-	if a.elevenlabsService == nil || !a.elevenlabsService.IsConfigured() {
-		return "", fmt.Errorf("ElevenLabs not configured")
+	provider := a.activeTTSProvider()
+	if provider == nil || !provider.IsConfigured() {
+		return "", fmt.Errorf("TTS provider not configured")
 	}
 
-	mp3Bytes, err := a.elevenlabsService.Synthesize(text)
+	audioBytes, _, err := provider.Synthesize(a.ctx, text, "")
 	if err != nil {
-		slog.Error("[TTS] Synthesis failed", "error", err)
+		slog.Error("[TTS] Synthesis failed", "provider", provider.Name(), "error", err)
 		return "", fmt.Errorf("synthesis failed: %w", err)
 	}
 
-	audioBase64 := base64.StdEncoding.EncodeToString(mp3Bytes)
-	slog.Info("[TTS] Synthesized speech", "textLength", len(text), "audioBytes", len(mp3Bytes))
+	audioBase64 := base64.StdEncoding.EncodeToString(audioBytes)
+	slog.Info("[TTS] Synthesized speech", "provider", provider.Name(), "textLength", len(text), "audioBytes", len(audioBytes))
 
 	return audioBase64, nil
 }
 
-// IsTTSConfigured returns whether the TTS service (ElevenLabs) is configured.
+// IsTTSConfigured returns whether the active TTS provider is configured.
 func (a *App) IsTTSConfigured() bool {
-	return a.elevenlabsService != nil && a.elevenlabsService.IsConfigured()
+	provider := a.activeTTSProvider()
+	return provider != nil && provider.IsConfigured()
 }
 
 // #endregion TTS API
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"log/slog"
 	"sync"
+	"sync/atomic"
+	"time"
 	"unsafe"
 
 	"github.com/gen2brain/malgo"
@@ -18,14 +20,20 @@ type StreamCallback func(samples []float32)
 
 // AudioService handles audio capture from microphone
 type AudioService struct {
-	ctx          context.Context
-	cancel       context.CancelFunc
-	device       *malgo.Device
-	malgoCtx     *malgo.AllocatedContext // Keep context alive
-	sampleRate   uint32
-	buffer       []float32
-	mutex        sync.RWMutex
-	isRunning    bool
+	ctx        context.Context
+	cancel     context.CancelFunc
+	device     *malgo.Device
+	malgoCtx   *malgo.AllocatedContext // Keep context alive
+	sampleRate uint32
+	mutex      sync.RWMutex
+
+	// samples backs GetSamples. It's a lock-free SPSC ring (see
+	// ring_buffer.go): audioDataCallback is its only producer and GetSamples
+	// its only consumer, so neither takes a.mutex or allocates on the hot
+	// path. isRunning is likewise atomic so the callback never blocks on a
+	// lock just to check whether capture is still active.
+	samples   *sampleRing
+	isRunning atomic.Bool
 	// Audio processing components
 
 	// Audio level monitoring for visualization
@@ -33,20 +41,52 @@ type AudioService struct {
 	levelSampleCounter  int
 	levelUpdateInterval int // Update level every N samples
 
-	// Recording components
+	// Recording components. Recording is just an EncoderSink subscribed via the
+	// fan-out mechanism below; these track it so StartRecording/
+	// StopRecording can be idempotent and close the file.
 	recordingMutex sync.Mutex
 	isRecording    bool
-	wavWriter      *WAVWriter
-	recordingChan  chan []float32
-	recordingDone  chan struct{}
-	
+	recordingSink  *EncoderSink
+	recordingSubID SubscriptionID
+
 	// Level processing
 	levelChan      chan float32
 	levelDone      chan struct{}
-	
-	// Streaming mode for real-time processing (e.g., VAD)
-	streamCallback StreamCallback
-	streamMutex    sync.RWMutex
+
+	// Fan-out subscriber system (see sinks.go): every captured chunk is
+	// published to each subscription's own bounded channel, drained by a
+	// per-sink goroutine, so one slow sink only drops its own chunks
+	// instead of blocking the audio thread or other sinks.
+	streamMutex   sync.RWMutex
+	subscriptions []*subscription
+	nextSubID     SubscriptionID
+
+	// SetStreamCallback/ClearStreamCallback are a thin compatibility layer
+	// over Subscribe/Unsubscribe for callers (e.g. VAD) that just want a
+	// single function called with each chunk.
+	streamCallbackMu sync.Mutex
+	streamSubID      SubscriptionID
+	hasStreamSub     bool
+
+	// Device selection (see devices.go). selectedDeviceID is nil when the
+	// backend's default capture device should be used.
+	selectedDeviceID *malgo.DeviceID
+	deviceCache      map[string]malgo.DeviceID
+	deviceMutex      sync.RWMutex
+
+	// Default-device-change notification (see devices.go)
+	onDeviceChange      func(DeviceInfo)
+	deviceWatchOnce     sync.Once
+	lastDefaultDeviceID string
+
+	// Capture-time processing pipeline (see processors.go), run in order on
+	// every chunk before it reaches the buffer, stream callback, and recorder.
+	processors []AudioProcessor
+
+	// Active VAD segmenter, if StartVAD has been called (see vad.go). Kept
+	// only so StopVAD has something to clear; the actual wiring goes
+	// through streamCallback like any other stream consumer.
+	vadSegmenter *VADSegmenter
 }
 
 // NewAudioService creates a new audio service for capturing microphone input
@@ -54,9 +94,8 @@ func NewAudioService() *AudioService {
 	sampleRate := uint32(16000) // Whisper requires 16kHz
 	
 	return &AudioService{
-		sampleRate:          sampleRate,
-		buffer:              make([]float32, 0, 16000*5), // 5 second buffer capacity
-		isRunning:           false,
+		sampleRate: sampleRate,
+		samples:    newSampleRing(16000 * 5), // 5 second buffer capacity
 		// Initialize audio processing pipeline
 
 		// Audio level updates ~30fps (every 533 samples at 16kHz)
@@ -66,8 +105,16 @@ func NewAudioService() *AudioService {
 	}
 }
 
-// StartRecording starts recording audio to a file
+// StartRecording starts recording audio to a 16-bit PCM WAV file - a thin
+// wrapper over StartRecordingAs for the common case.
 func (a *AudioService) StartRecording(filename string) error {
+	return a.StartRecordingAs(filename, EncoderConfig{Codec: "wav"})
+}
+
+// StartRecordingAs starts recording audio to filename using the codec
+// selected by cfg.Codec ("wav", "wav-float", "flac", "opus", "ogg-opus", or
+// "mp3"), or inferred from filename's extension if cfg.Codec is empty.
+func (a *AudioService) StartRecordingAs(filename string, cfg EncoderConfig) error {
 	a.recordingMutex.Lock()
 	defer a.recordingMutex.Unlock()
 
@@ -75,20 +122,21 @@ func (a *AudioService) StartRecording(filename string) error {
 		return fmt.Errorf("already recording")
 	}
 
-	writer, err := NewWAVWriter(filename, int(a.sampleRate))
+	if cfg.SampleRate == 0 {
+		cfg.SampleRate = int(a.sampleRate)
+	}
+
+	encoder, err := NewEncoder(filename, cfg)
 	if err != nil {
-		return fmt.Errorf("failed to create WAV writer: %w", err)
+		return fmt.Errorf("failed to create encoder: %w", err)
 	}
 
-	a.wavWriter = writer
-	a.recordingChan = make(chan []float32, 100) // Buffer for ~10s of audio if chunks are 100ms
-	a.recordingDone = make(chan struct{})
+	sink := &EncoderSink{Encoder: encoder}
+	a.recordingSink = sink
+	a.recordingSubID = a.Subscribe(sink)
 	a.isRecording = true
 
-	// Start background writer
-	go a.recordingLoop()
-
-	slog.Info("Started recording", "filename", filename)
+	slog.Info("Started recording", "filename", filename, "codec", cfg.Codec)
 	return nil
 }
 
@@ -99,37 +147,21 @@ func (a *AudioService) StopRecording() error {
 		a.recordingMutex.Unlock()
 		return nil
 	}
-	
-	// Signal writer to stop
-	close(a.recordingChan)
+
+	subID := a.recordingSubID
+	sink := a.recordingSink
 	a.isRecording = false
+	a.recordingSink = nil
 	a.recordingMutex.Unlock()
 
-	// Wait for writer to finish closing the file
-	<-a.recordingDone
-	
-	slog.Info("Recording stopped")
-	return nil
-}
-
-// recordingLoop handles writing audio samples to disk in the background
-func (a *AudioService) recordingLoop() {
-	defer close(a.recordingDone)
-	defer func() {
-		if a.wavWriter != nil {
-			a.wavWriter.Close()
-			a.wavWriter = nil
-		}
-	}()
+	a.Unsubscribe(subID)
 
-	for samples := range a.recordingChan {
-		if a.wavWriter != nil {
-			if err := a.wavWriter.WriteSamples(samples); err != nil {
-				slog.Error("Error writing audio samples", "error", err)
-				return // Stop writing on error
-			}
-		}
+	if err := sink.Close(); err != nil {
+		return fmt.Errorf("failed to close recording: %w", err)
 	}
+
+	slog.Info("Recording stopped")
+	return nil
 }
 
 // SetAudioLevelCallback sets the callback for audio level updates (for waveform visualization)
@@ -139,19 +171,30 @@ func (a *AudioService) SetAudioLevelCallback(callback AudioLevelCallback) {
 	a.onAudioLevel = callback
 }
 
-// SetStreamCallback sets the callback for real-time audio streaming (e.g., for VAD processing)
-// The callback receives audio samples as they arrive from the microphone
+// SetStreamCallback sets the callback for real-time audio streaming (e.g.,
+// for VAD processing). The callback receives audio samples as they arrive
+// from the microphone. Internally this just subscribes a CallbackSink -
+// for anything beyond a single callback, use Subscribe directly.
 func (a *AudioService) SetStreamCallback(callback StreamCallback) {
-	a.streamMutex.Lock()
-	defer a.streamMutex.Unlock()
-	a.streamCallback = callback
+	a.streamCallbackMu.Lock()
+	defer a.streamCallbackMu.Unlock()
+
+	if a.hasStreamSub {
+		a.Unsubscribe(a.streamSubID)
+	}
+	a.streamSubID = a.Subscribe(CallbackSink{Callback: callback})
+	a.hasStreamSub = true
 }
 
-// ClearStreamCallback removes the stream callback
+// ClearStreamCallback removes the stream callback set by SetStreamCallback.
 func (a *AudioService) ClearStreamCallback() {
-	a.streamMutex.Lock()
-	defer a.streamMutex.Unlock()
-	a.streamCallback = nil
+	a.streamCallbackMu.Lock()
+	defer a.streamCallbackMu.Unlock()
+
+	if a.hasStreamSub {
+		a.Unsubscribe(a.streamSubID)
+		a.hasStreamSub = false
+	}
 }
 
 // Start begins audio capture from the default microphone
@@ -159,13 +202,21 @@ func (a *AudioService) Start() error {
 	a.mutex.Lock()
 	defer a.mutex.Unlock()
 
-	if a.isRunning {
+	if a.isRunning.Load() {
 		return fmt.Errorf("audio service is already running")
 	}
 
 	// Initialize context for this session
 	a.ctx, a.cancel = context.WithCancel(context.Background())
 
+	a.samples.Reset()
+	for _, p := range a.processors {
+		p.Reset()
+	}
+	if a.vadSegmenter != nil {
+		a.vadSegmenter.Reset()
+	}
+
 	slog.Info("Initializing malgo audio context")
 
 	// Initialize malgo context
@@ -184,8 +235,11 @@ func (a *AudioService) Start() error {
 	deviceConfig.Capture.Channels = 1
 	deviceConfig.SampleRate = a.sampleRate
 	deviceConfig.Alsa.NoMMap = 1
+	if a.selectedDeviceID != nil {
+		deviceConfig.Capture.DeviceID = a.selectedDeviceID.Pointer()
+	}
 
-	slog.Info("Audio config", "format", "F32", "channels", 1, "samplerate", a.sampleRate)
+	slog.Info("Audio config", "format", "F32", "channels", 1, "samplerate", a.sampleRate, "deviceSelected", a.selectedDeviceID != nil)
 
 	// Create capture device
 	slog.Info("Creating capture device")
@@ -208,8 +262,8 @@ func (a *AudioService) Start() error {
 
 	a.device = device
 	a.malgoCtx = ctx
-	a.isRunning = true
-	
+	a.isRunning.Store(true)
+
 	// Start level processing loop
 	a.levelDone = make(chan struct{})
 	go a.levelLoop()
@@ -250,17 +304,14 @@ func (a *AudioService) Stop() error {
 		slog.Error("Error stopping recording", "error", err)
 	}
 
-	a.mutex.Lock()
-
-	if !a.isRunning {
-		a.mutex.Unlock()
+	if !a.isRunning.Load() {
 		return nil
 	}
 
+	a.mutex.Lock()
 	a.cancel()
-	a.cancel()
-	a.isRunning = false
-	
+	a.isRunning.Store(false)
+
 	// Wait for level loop to finish
 	if a.levelDone != nil {
 		// Close level chan? Or wait for context done?
@@ -285,60 +336,61 @@ func (a *AudioService) Stop() error {
 		a.malgoCtx = nil
 	}
 
-	// Clear buffer and reset audio processors
-	a.mutex.Lock()
-	a.buffer = a.buffer[:0]
-	// Reset filter states for clean start next time
-
-	a.mutex.Unlock()
+	a.samples.Reset()
 
 	slog.Info("Audio capture stopped")
 	return nil
 }
 
-// GetSamples returns a copy of the current audio buffer and clears it
+// GetSamples drains whatever is currently available from the capture ring
+// and returns it. It's the ring's sole consumer - no lock, no reallocation
+// of a growing buffer, just a copy sized to what's actually there.
 func (a *AudioService) GetSamples() []float32 {
-	a.mutex.Lock()
-	defer a.mutex.Unlock()
-
-	bufferLen := len(a.buffer)
-	if bufferLen == 0 {
+	n := a.samples.Len()
+	if n == 0 {
+		a.samples.underruns.Add(1)
 		return nil
 	}
 
-	// Count non-zero samples
-	nonZeroCount := 0
-	for _, sample := range a.buffer {
-		if sample != 0.0 {
-			nonZeroCount++
-		}
-	}
-
-	// fmt.Printf("GetSamples: returning %d samples (%d non-zero)\n", bufferLen, nonZeroCount)
-
-	// Copy buffer contents
-	samples := make([]float32, bufferLen)
-	copy(samples, a.buffer)
-
-	// Clear buffer for next batch
-	a.buffer = a.buffer[:0]
-
-	return samples
+	samples := make([]float32, n)
+	got := a.samples.Read(samples)
+	return samples[:got]
 }
 
 // IsRunning returns whether the audio service is currently capturing
 func (a *AudioService) IsRunning() bool {
-	a.mutex.RLock()
-	defer a.mutex.RUnlock()
-	return a.isRunning
+	return a.isRunning.Load()
 }
 
-// audioDataCallback is called by malgo when new audio data is available
-func (a *AudioService) audioDataCallback(outputSample, inputSamples []byte, framecount uint32) {
-	a.mutex.Lock()
-	defer a.mutex.Unlock()
+// AudioStats reports the capture ring's health, for tuning its capacity.
+type AudioStats struct {
+	// Buffered is how many samples are currently waiting to be read via
+	// GetSamples.
+	Buffered int
+	// Overruns counts samples dropped because the ring was full when
+	// audioDataCallback wrote to it - GetSamples isn't being drained often
+	// enough for the configured capacity.
+	Overruns uint64
+	// Underruns counts GetSamples calls that found nothing to read.
+	Underruns uint64
+}
 
-	if !a.isRunning {
+// Stats returns the current capture ring statistics.
+func (a *AudioService) Stats() AudioStats {
+	return AudioStats{
+		Buffered:  a.samples.Len(),
+		Overruns:  a.samples.overruns.Load(),
+		Underruns: a.samples.underruns.Load(),
+	}
+}
+
+// audioDataCallback is called by malgo when new audio data is available.
+// This runs on the audio thread: it must never block. The only shared state
+// it touches - the capture ring and isRunning - is lock-free; processors and
+// onAudioLevel are read under a brief RLock since AddProcessor/
+// SetAudioLevelCallback only ever change them, never per-sample.
+func (a *AudioService) audioDataCallback(outputSample, inputSamples []byte, framecount uint32) {
+	if !a.isRunning.Load() {
 		return
 	}
 
@@ -358,13 +410,11 @@ func (a *AudioService) audioDataCallback(outputSample, inputSamples []byte, fram
 	sampleCount := 0
 	maxAmplitude := float32(0.0)
 
-	// AUDIO PROCESSING PIPELINE:
-	// 1. Convert bytes to float32
-	// 2. Apply high-pass filter (removes low-frequency rumble < 80Hz)
-	// 3. Apply noise gate (reduces background noise)
-	// 4. Apply loudness normalization (consistent audio levels)
-	// 5. Apply soft clipping (prevents harsh distortion)
-	
+	// Convert bytes to float32, then run the configured processing pipeline
+	// (high-pass filter, noise gate, loudness normalization, soft clipping,
+	// or whatever AddProcessor has registered) over the chunk in place. The
+	// buffer, stream callback, and recorder below all see the same
+	// processed samples, so Whisper/VAD/recording benefit uniformly.
 	for i := uint32(0); i < framecount; i++ {
 		offset := i * 4
 		if offset+4 <= uint32(len(inputSamples)) {
@@ -374,38 +424,44 @@ func (a *AudioService) audioDataCallback(outputSample, inputSamples []byte, fram
 					uint32(inputSamples[offset+2])<<16 |
 					uint32(inputSamples[offset+3])<<24
 			sample := *(*float32)(unsafe.Pointer(&bits))
-
-			// Step 1: No high-pass filter - raw audio capture
-			// sample = a.highPassFilter.Process(sample)
-			
-			// Step 2: No soft clipping - raw audio capture
-			// sample = softClip(sample)
-
 			samples[i] = sample
 
-			// Track statistics
 			if sample != 0.0 {
 				sampleCount++
 			}
-			absSample := sample
-			if absSample < 0 {
-				absSample = -absSample
-			}
-			if absSample > maxAmplitude {
-				maxAmplitude = absSample
-			}
+		}
+	}
+
+	a.mutex.RLock()
+	processors := a.processors
+	onAudioLevel := a.onAudioLevel
+	a.mutex.RUnlock()
+
+	for _, p := range processors {
+		p.Process(samples)
+	}
+
+	// Track statistics on the post-pipeline samples, since that's what
+	// actually reaches the buffer, stream callback, and recorder.
+	for _, sample := range samples {
+		absSample := sample
+		if absSample < 0 {
+			absSample = -absSample
+		}
+		if absSample > maxAmplitude {
+			maxAmplitude = absSample
 		}
 	}
 
 	// Debug: Log audio data statistics (only occasionally to avoid spam)
-	// if len(a.buffer) == 0 { // Only log the first time we get data
+	// if a.samples.Len() == 0 { // Only log the first time we get data
 	// 	fmt.Printf("Audio callback: framecount=%d, inputSamples=%d bytes, non-zero samples=%d/%d, max amplitude=%.6f\n",
 	// 		framecount, len(inputSamples), sampleCount, len(samples), maxAmplitude)
 	// }
 
 	// Emit audio level for waveform visualization
 	a.levelSampleCounter += int(framecount)
-	if a.onAudioLevel != nil && a.levelSampleCounter >= a.levelUpdateInterval {
+	if onAudioLevel != nil && a.levelSampleCounter >= a.levelUpdateInterval {
 		// Normalize maxAmplitude to 0-1 range with some headroom
 		normalizedLevel := maxAmplitude * 2.0 // Boost for visibility
 		if normalizedLevel > 1.0 {
@@ -420,46 +476,15 @@ func (a *AudioService) audioDataCallback(outputSample, inputSamples []byte, fram
 		a.levelSampleCounter = 0
 	}
 
-	// Append to capture buffer
-	a.buffer = append(a.buffer, samples...)
-	
-	// Call stream callback for real-time processing (e.g., VAD)
-	// Use RLock to check without blocking other readers
-	a.streamMutex.RLock()
-	streamCb := a.streamCallback
-	a.streamMutex.RUnlock()
-	if streamCb != nil {
-		// Copy samples for callback to avoid race conditions
-		samplesCopy := make([]float32, len(samples))
-		copy(samplesCopy, samples)
-		// Call synchronously - callback must be fast to avoid audio issues
-		streamCb(samplesCopy)
-	}
+	// Write into the capture ring (backs GetSamples). Never blocks: if
+	// GetSamples hasn't drained fast enough, the oldest unread samples are
+	// dropped and counted in Stats().Overruns instead.
+	a.samples.Write(samples)
 
-	// Send to recorder if active
-	// We need to release the main mutex briefly to acquire recording mutex?
-	// OR we just use a channel which is thread safe.
-	// We check atomic flag or just push if channel is not nil? 
-	// To be safe, we guard with recordingMutex
-	
-	// IMPORTANT: Don't block audio callback!
-	// Send to recorder if active
-	// Use TryLock to avoid blocking the audio callback and avoid spawning goroutines
-	if a.recordingMutex.TryLock() {
-		if a.isRecording && a.recordingChan != nil {
-			// Try to send to channel without blocking
-			select {
-			case a.recordingChan <- samples:
-				// sent
-			default:
-				// buffer full, drop to avoid blocking
-				// fmt.Println("Warning: recording buffer full, dropping audio chunk")
-			}
-		}
-		a.recordingMutex.Unlock()
-	} else {
-		// Failed to acquire lock immediately, drop chunk to maintain audio performance
-	}
+	// Fan out to every subscribed sink (recording, VAD, waveform, websocket
+	// streaming, ...). Each sink has its own bounded channel and goroutine,
+	// so a slow one only drops its own chunks rather than blocking here.
+	a.publishToSinks(samples, time.Now())
 }
 
 // GetSampleRate returns the sample rate being used
@@ -0,0 +1,252 @@
+package audio
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/gen2brain/malgo"
+)
+
+// DeviceInfo describes a capture device as reported by the audio backend,
+// the same shape cpal/portaudio-based recorders expose to a device picker.
+type DeviceInfo struct {
+	ID                string
+	Name              string
+	DefaultSampleRate uint32
+	Channels          uint32
+	IsDefault         bool
+}
+
+// deviceWatchInterval is how often OnDeviceChange polls for a change in the
+// OS default input device. Like cpal/portaudio, malgo has no push
+// notification for this, so polling is the only option.
+const deviceWatchInterval = 2 * time.Second
+
+// ListInputDevices enumerates available capture devices. It opens its own
+// short-lived malgo context rather than reusing the one Start creates, so
+// it works whether or not audio capture is currently running.
+func (a *AudioService) ListInputDevices() ([]DeviceInfo, error) {
+	ctx, err := malgo.InitContext(nil, malgo.ContextConfig{}, func(message string) {
+		slog.Debug("malgo message", "message", message)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize audio context for device enumeration: %w", err)
+	}
+	defer ctx.Uninit()
+	defer ctx.Free()
+
+	return a.enumerateCaptureDevices(ctx)
+}
+
+// enumerateCaptureDevices queries malgo for capture devices and caches their
+// raw malgo.DeviceID values, keyed by the string ID returned to callers, so
+// SetInputDevice/SwitchInputDevice can look them back up later - including
+// after this ctx has been uninitialized, since a DeviceID just carries the
+// backend's raw device identifier rather than a handle into ctx itself.
+func (a *AudioService) enumerateCaptureDevices(ctx *malgo.AllocatedContext) ([]DeviceInfo, error) {
+	raw, err := ctx.Devices(malgo.Capture)
+	if err != nil {
+		return nil, fmt.Errorf("failed to enumerate capture devices: %w", err)
+	}
+
+	cache := make(map[string]malgo.DeviceID, len(raw))
+	devices := make([]DeviceInfo, 0, len(raw))
+
+	for _, info := range raw {
+		id := fmt.Sprintf("%v", info.ID)
+		cache[id] = info.ID
+
+		device := DeviceInfo{
+			ID:        id,
+			Name:      info.Name(),
+			IsDefault: info.IsDefault > 0,
+		}
+
+		if full, err := ctx.DeviceInfo(malgo.Capture, info.ID, malgo.Shared); err == nil {
+			device.DefaultSampleRate = full.MaxSampleRate
+			device.Channels = full.MaxChannels
+		} else {
+			slog.Warn("failed to query detailed capture device info", "device", device.Name, "error", err)
+		}
+
+		devices = append(devices, device)
+	}
+
+	a.deviceMutex.Lock()
+	a.deviceCache = cache
+	a.deviceMutex.Unlock()
+
+	return devices, nil
+}
+
+// SetInputDevice selects which capture device Start (and a later
+// SwitchInputDevice) should use. Pass an empty id to fall back to the
+// backend's default capture device. The id must come from a prior
+// ListInputDevices call.
+func (a *AudioService) SetInputDevice(id string) error {
+	if id == "" {
+		a.mutex.Lock()
+		a.selectedDeviceID = nil
+		a.mutex.Unlock()
+		return nil
+	}
+
+	a.deviceMutex.RLock()
+	deviceID, ok := a.deviceCache[id]
+	a.deviceMutex.RUnlock()
+	if !ok {
+		return fmt.Errorf("unknown input device id %q - call ListInputDevices first", id)
+	}
+
+	a.mutex.Lock()
+	a.selectedDeviceID = &deviceID
+	a.mutex.Unlock()
+	return nil
+}
+
+// SetSampleRate configures the capture sample rate Start should use. It
+// can't be changed while capture is already running - Stop then Start
+// instead (SwitchInputDevice keeps the current rate).
+func (a *AudioService) SetSampleRate(rate uint32) error {
+	if rate == 0 {
+		return fmt.Errorf("sample rate must be positive")
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if a.isRunning.Load() {
+		return fmt.Errorf("cannot change sample rate while audio capture is running")
+	}
+	a.sampleRate = rate
+	return nil
+}
+
+// SwitchInputDevice tears down the current malgo.Device and re-initializes
+// it against the device identified by id (or the backend default, if id is
+// empty), preserving the recording/streaming state and callbacks - only the
+// underlying malgo.Device is replaced. If capture isn't currently running,
+// this just behaves like SetInputDevice.
+func (a *AudioService) SwitchInputDevice(id string) error {
+	a.mutex.Lock()
+	if !a.isRunning.Load() {
+		a.mutex.Unlock()
+		return a.SetInputDevice(id)
+	}
+
+	var deviceID *malgo.DeviceID
+	if id != "" {
+		a.deviceMutex.RLock()
+		d, ok := a.deviceCache[id]
+		a.deviceMutex.RUnlock()
+		if !ok {
+			a.mutex.Unlock()
+			return fmt.Errorf("unknown input device id %q - call ListInputDevices first", id)
+		}
+		deviceID = &d
+	}
+
+	oldDevice := a.device
+	ctx := a.malgoCtx
+	sampleRate := a.sampleRate
+	a.mutex.Unlock()
+
+	if ctx == nil {
+		return fmt.Errorf("audio service is not running")
+	}
+
+	deviceConfig := malgo.DefaultDeviceConfig(malgo.Capture)
+	deviceConfig.Capture.Format = malgo.FormatF32
+	deviceConfig.Capture.Channels = 1
+	deviceConfig.SampleRate = sampleRate
+	deviceConfig.Alsa.NoMMap = 1
+	if deviceID != nil {
+		deviceConfig.Capture.DeviceID = deviceID.Pointer()
+	}
+
+	newDevice, err := malgo.InitDevice(ctx.Context, deviceConfig, malgo.DeviceCallbacks{
+		Data: a.audioDataCallback,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to initialize new capture device: %w", err)
+	}
+	if err := newDevice.Start(); err != nil {
+		newDevice.Uninit()
+		return fmt.Errorf("failed to start new capture device: %w", err)
+	}
+
+	a.mutex.Lock()
+	a.device = newDevice
+	a.selectedDeviceID = deviceID
+	a.mutex.Unlock()
+
+	if oldDevice != nil {
+		oldDevice.Stop()
+		oldDevice.Uninit()
+	}
+
+	slog.Info("switched input device", "id", id)
+	return nil
+}
+
+// OnDeviceChange registers a callback fired when the OS default input
+// device changes. Like cpal/portaudio, malgo has no push notification for
+// this, so the first call to OnDeviceChange starts a background goroutine
+// that polls the default device every deviceWatchInterval for the lifetime
+// of the process.
+func (a *AudioService) OnDeviceChange(callback func(DeviceInfo)) {
+	a.deviceMutex.Lock()
+	a.onDeviceChange = callback
+	a.deviceMutex.Unlock()
+
+	a.deviceWatchOnce.Do(func() {
+		go a.watchDefaultDevice()
+	})
+}
+
+// watchDefaultDevice polls ListInputDevices and fires onDeviceChange when
+// the default device's ID differs from the last observed one.
+func (a *AudioService) watchDefaultDevice() {
+	if devices, err := a.ListInputDevices(); err == nil {
+		for _, d := range devices {
+			if d.IsDefault {
+				a.deviceMutex.Lock()
+				a.lastDefaultDeviceID = d.ID
+				a.deviceMutex.Unlock()
+				break
+			}
+		}
+	}
+
+	ticker := time.NewTicker(deviceWatchInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		devices, err := a.ListInputDevices()
+		if err != nil {
+			slog.Warn("failed to poll default input device", "error", err)
+			continue
+		}
+
+		var def *DeviceInfo
+		for i := range devices {
+			if devices[i].IsDefault {
+				def = &devices[i]
+				break
+			}
+		}
+		if def == nil {
+			continue
+		}
+
+		a.deviceMutex.Lock()
+		changed := def.ID != a.lastDefaultDeviceID
+		a.lastDefaultDeviceID = def.ID
+		callback := a.onDeviceChange
+		a.deviceMutex.Unlock()
+
+		if changed && callback != nil {
+			callback(*def)
+		}
+	}
+}
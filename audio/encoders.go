@@ -0,0 +1,84 @@
+package audio
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Encoder receives float32 samples in the capture pipeline's own format and
+// writes them out in some codec, so no intermediate conversion is needed
+// upstream. WAVWriter already satisfies this interface.
+type Encoder interface {
+	WriteSamples(samples []float32) error
+	Close() error
+}
+
+// EncoderConfig selects and tunes an Encoder.
+type EncoderConfig struct {
+	// Codec selects the encoder explicitly: "wav", "wav-float", "flac",
+	// "opus", "ogg-opus", or "mp3". Empty means "infer from filename's
+	// extension".
+	Codec string
+	// Bitrate is used by the lossy codecs (opus, mp3), in bits per second.
+	// Zero uses the codec's own default.
+	Bitrate int
+	// SampleRate defaults to 16000 (Whisper's required rate) when zero.
+	SampleRate int
+}
+
+// withDefaults fills in zero fields with the package's usual defaults.
+func (cfg EncoderConfig) withDefaults() EncoderConfig {
+	if cfg.SampleRate == 0 {
+		cfg.SampleRate = 16000
+	}
+	return cfg
+}
+
+// EncoderFactory builds an Encoder that writes to filename.
+type EncoderFactory func(filename string, cfg EncoderConfig) (Encoder, error)
+
+// encoderFactories maps a codec name to the factory that builds it.
+var encoderFactories = map[string]EncoderFactory{
+	"wav": func(filename string, cfg EncoderConfig) (Encoder, error) {
+		return NewWAVWriter(filename, cfg.SampleRate)
+	},
+	"wav-float": func(filename string, cfg EncoderConfig) (Encoder, error) {
+		return NewFloatWAVWriter(filename, cfg.SampleRate)
+	},
+	"flac":     newFLACEncoder,
+	"opus":     newOpusEncoder,
+	"ogg-opus": newOggOpusEncoder,
+	"mp3":      newMP3Encoder,
+}
+
+// extensionCodecs maps a filename extension to its default codec, used
+// when EncoderConfig.Codec is left empty.
+var extensionCodecs = map[string]string{
+	".wav":  "wav",
+	".flac": "flac",
+	".opus": "ogg-opus",
+	".ogg":  "ogg-opus",
+	".mp3":  "mp3",
+}
+
+// NewEncoder resolves cfg.Codec (or, if empty, filename's extension) to a
+// registered EncoderFactory and builds an Encoder with it.
+func NewEncoder(filename string, cfg EncoderConfig) (Encoder, error) {
+	cfg = cfg.withDefaults()
+
+	codec := cfg.Codec
+	if codec == "" {
+		ext := strings.ToLower(filepath.Ext(filename))
+		codec = extensionCodecs[ext]
+		if codec == "" {
+			return nil, fmt.Errorf("cannot infer codec from extension %q - pass EncoderConfig.Codec explicitly", ext)
+		}
+	}
+
+	factory, ok := encoderFactories[codec]
+	if !ok {
+		return nil, fmt.Errorf("unknown encoder codec %q", codec)
+	}
+	return factory(filename, cfg)
+}
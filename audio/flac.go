@@ -0,0 +1,111 @@
+package audio
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/mewkiz/flac"
+	"github.com/mewkiz/flac/frame"
+	"github.com/mewkiz/flac/meta"
+)
+
+// flacBlockSize is the number of samples encoded per FLAC frame. FLAC
+// encodes fixed-size blocks rather than accepting a continuous stream, so
+// flacEncoder buffers until it has a full block.
+const flacBlockSize = 4096
+
+// flacEncoder writes float32 samples to a FLAC file via
+// github.com/mewkiz/flac, quantizing to 16-bit PCM and encoding each block
+// as a single verbatim subframe.
+type flacEncoder struct {
+	enc        *flac.Encoder
+	file       *os.File
+	sampleRate int
+	buf        []float32
+}
+
+// newFLACEncoder implements EncoderFactory for the "flac" codec.
+func newFLACEncoder(filename string, cfg EncoderConfig) (Encoder, error) {
+	file, err := os.Create(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create FLAC file: %w", err)
+	}
+
+	info := &meta.StreamInfo{
+		BlockSizeMin:  flacBlockSize,
+		BlockSizeMax:  flacBlockSize,
+		SampleRate:    uint32(cfg.SampleRate),
+		NChannels:     1,
+		BitsPerSample: 16,
+	}
+
+	enc, err := flac.NewEncoder(file, info)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to create FLAC encoder: %w", err)
+	}
+
+	return &flacEncoder{enc: enc, file: file, sampleRate: cfg.SampleRate}, nil
+}
+
+// WriteSamples buffers samples and flushes full flacBlockSize blocks as a
+// FLAC frame each.
+func (f *flacEncoder) WriteSamples(samples []float32) error {
+	f.buf = append(f.buf, samples...)
+	for len(f.buf) >= flacBlockSize {
+		if err := f.writeFrame(f.buf[:flacBlockSize]); err != nil {
+			return err
+		}
+		f.buf = f.buf[flacBlockSize:]
+	}
+	return nil
+}
+
+// writeFrame quantizes a block to 16-bit PCM and encodes it as a single
+// mono, verbatim-predictor FLAC subframe.
+func (f *flacEncoder) writeFrame(block []float32) error {
+	pcm := make([]int32, len(block))
+	for i, s := range block {
+		if s > 1.0 {
+			s = 1.0
+		} else if s < -1.0 {
+			s = -1.0
+		}
+		pcm[i] = int32(s * 32767)
+	}
+
+	fr := &frame.Frame{
+		Header: frame.Header{
+			HasFixedBlockSize: true,
+			BlockSize:         uint16(len(block)),
+			SampleRate:        uint32(f.sampleRate),
+			Channels:          frame.ChannelsMono,
+			BitsPerSample:     16,
+		},
+		Subframes: []*frame.Subframe{
+			{
+				SubHeader: frame.SubHeader{Pred: frame.PredVerbatim},
+				Samples:   pcm,
+			},
+		},
+	}
+
+	return f.enc.WriteFrame(fr)
+}
+
+// Close flushes any remaining partial block and finalizes the FLAC stream.
+func (f *flacEncoder) Close() error {
+	if len(f.buf) > 0 {
+		if err := f.writeFrame(f.buf); err != nil {
+			f.enc.Close()
+			f.file.Close()
+			return err
+		}
+		f.buf = nil
+	}
+	if err := f.enc.Close(); err != nil {
+		f.file.Close()
+		return err
+	}
+	return f.file.Close()
+}
@@ -0,0 +1,63 @@
+package audio
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/viert/lame"
+)
+
+// mp3Encoder writes float32 samples to an MP3 file via github.com/viert/lame,
+// a cgo binding to libmp3lame.
+type mp3Encoder struct {
+	file *os.File
+	enc  *lame.LameWriter
+}
+
+// newMP3Encoder implements EncoderFactory for the "mp3" codec.
+func newMP3Encoder(filename string, cfg EncoderConfig) (Encoder, error) {
+	file, err := os.Create(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create MP3 file: %w", err)
+	}
+
+	enc := lame.NewWriter(file)
+	enc.Encoder.SetInSamplerate(cfg.SampleRate)
+	enc.Encoder.SetNumChannels(1)
+	if cfg.Bitrate > 0 {
+		enc.Encoder.SetBrate(cfg.Bitrate / 1000)
+	}
+	if err := enc.Encoder.InitParams(); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to init MP3 encoder: %w", err)
+	}
+
+	return &mp3Encoder{file: file, enc: enc}, nil
+}
+
+// WriteSamples quantizes samples to 16-bit PCM and feeds them to the lame
+// encoder.
+func (m *mp3Encoder) WriteSamples(samples []float32) error {
+	pcm := make([]byte, len(samples)*2)
+	for i, s := range samples {
+		if s > 1.0 {
+			s = 1.0
+		} else if s < -1.0 {
+			s = -1.0
+		}
+		v := int16(s * 32767)
+		pcm[i*2] = byte(v)
+		pcm[i*2+1] = byte(v >> 8)
+	}
+	_, err := m.enc.Write(pcm)
+	return err
+}
+
+// Close flushes and closes the MP3 encoder and its output file.
+func (m *mp3Encoder) Close() error {
+	if err := m.enc.Close(); err != nil {
+		m.file.Close()
+		return err
+	}
+	return m.file.Close()
+}
@@ -0,0 +1,261 @@
+package audio
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+
+	"gopkg.in/hraban/opus.v2"
+)
+
+// oggOpusSerial is incremented for each recording so concurrent or
+// back-to-back files don't share a bitstream serial number.
+var oggOpusSerial uint32 = 0x53430000 // "SC" prefix, low word increments
+
+// oggMaxPageSegments is the Ogg page format's own limit: page_segments is a
+// single byte.
+const oggMaxPageSegments = 255
+
+// oggOpusEncoder writes float32 samples as a real, spec-conformant Ogg
+// container around Opus packets (RFC 7845) via gopkg.in/hraban/opus.v2 -
+// unlike opusEncoder's raw length-prefixed packets, this produces a
+// standalone .opus/.ogg file any conformant player can open.
+type oggOpusEncoder struct {
+	file      *os.File
+	enc       *opus.Encoder
+	frameSize int
+	inputRate int
+
+	buf []float32
+
+	serial  uint32
+	pageSeq uint32
+	granule int64
+
+	pendingPackets  [][]byte
+	pendingSegments int
+}
+
+// newOggOpusEncoder implements EncoderFactory for the "ogg-opus" codec.
+func newOggOpusEncoder(filename string, cfg EncoderConfig) (Encoder, error) {
+	file, err := os.Create(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Ogg Opus file: %w", err)
+	}
+
+	enc, err := opus.NewEncoder(cfg.SampleRate, 1, opus.AppVoIP)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to create Opus encoder: %w", err)
+	}
+	if cfg.Bitrate > 0 {
+		if err := enc.SetBitrate(cfg.Bitrate); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to set Opus bitrate: %w", err)
+		}
+	}
+
+	oggOpusSerial++
+	o := &oggOpusEncoder{
+		file:      file,
+		enc:       enc,
+		frameSize: cfg.SampleRate * opusFrameMs / 1000,
+		inputRate: cfg.SampleRate,
+		serial:    oggOpusSerial,
+	}
+
+	if err := o.writeHeaderPages(); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return o, nil
+}
+
+// writeHeaderPages writes the mandatory OpusHead and OpusTags pages that
+// must precede any audio data in an Ogg Opus stream (RFC 7845 section 5).
+func (o *oggOpusEncoder) writeHeaderPages() error {
+	head := make([]byte, 19)
+	copy(head[0:8], "OpusHead")
+	head[8] = 1 // version
+	head[9] = 1 // channel count (mono)
+	binary.LittleEndian.PutUint16(head[10:12], 0) // pre-skip
+	binary.LittleEndian.PutUint32(head[12:16], uint32(o.inputRate))
+	binary.LittleEndian.PutUint16(head[16:18], 0) // output gain
+	head[18] = 0                                  // channel mapping family
+
+	if err := o.writePage([][]byte{head}, 0, true, false); err != nil {
+		return fmt.Errorf("failed to write OpusHead page: %w", err)
+	}
+
+	const vendor = "super-characters"
+	tags := make([]byte, 0, 8+4+len(vendor)+4)
+	tags = append(tags, []byte("OpusTags")...)
+	lenBuf := make([]byte, 4)
+	binary.LittleEndian.PutUint32(lenBuf, uint32(len(vendor)))
+	tags = append(tags, lenBuf...)
+	tags = append(tags, []byte(vendor)...)
+	tags = append(tags, 0, 0, 0, 0) // zero user comments
+
+	if err := o.writePage([][]byte{tags}, 0, false, false); err != nil {
+		return fmt.Errorf("failed to write OpusTags page: %w", err)
+	}
+	return nil
+}
+
+// WriteSamples buffers samples and encodes+muxes complete Opus frames as
+// they accumulate.
+func (o *oggOpusEncoder) WriteSamples(samples []float32) error {
+	o.buf = append(o.buf, samples...)
+	for len(o.buf) >= o.frameSize {
+		if err := o.encodeFrame(o.buf[:o.frameSize]); err != nil {
+			return err
+		}
+		o.buf = o.buf[o.frameSize:]
+	}
+	return nil
+}
+
+// encodeFrame encodes one frame to an Opus packet and queues it for the
+// current page, flushing that page first if the packet wouldn't fit in its
+// remaining segment-table space.
+func (o *oggOpusEncoder) encodeFrame(frame []float32) error {
+	data := make([]byte, opusMaxPacketBytes)
+	n, err := o.enc.EncodeFloat32(frame, data)
+	if err != nil {
+		return fmt.Errorf("failed to encode Opus frame: %w", err)
+	}
+	packet := data[:n]
+
+	if o.pendingSegments+lacingSegments(len(packet)) > oggMaxPageSegments {
+		if err := o.flushPendingPage(false); err != nil {
+			return err
+		}
+	}
+
+	o.granule += int64(len(frame)) * 48000 / int64(o.inputRate)
+	o.pendingPackets = append(o.pendingPackets, packet)
+	o.pendingSegments += lacingSegments(len(packet))
+	return nil
+}
+
+// flushPendingPage writes out whatever packets have accumulated since the
+// last page as one Ogg page.
+func (o *oggOpusEncoder) flushPendingPage(eos bool) error {
+	if len(o.pendingPackets) == 0 && !eos {
+		return nil
+	}
+
+	packets := o.pendingPackets
+	o.pendingPackets = nil
+	o.pendingSegments = 0
+	return o.writePage(packets, o.granule, false, eos)
+}
+
+// Close flushes any buffered partial frame and the final page (marked eos),
+// then closes the file.
+func (o *oggOpusEncoder) Close() error {
+	if len(o.buf) > 0 {
+		padded := make([]float32, o.frameSize)
+		copy(padded, o.buf)
+		if err := o.encodeFrame(padded); err != nil {
+			o.file.Close()
+			return err
+		}
+		o.buf = nil
+	}
+
+	if err := o.flushPendingPage(true); err != nil {
+		o.file.Close()
+		return err
+	}
+	return o.file.Close()
+}
+
+// lacingSegments returns how many Ogg lacing-table entries a packet of n
+// bytes needs: one 255 entry per full 255 bytes, plus a final entry
+// (possibly 0) marking where the packet ends.
+func lacingSegments(n int) int {
+	return n/255 + 1
+}
+
+// writePage writes one Ogg page containing packets, with the given granule
+// position and bos/eos flags, computing its CRC per the Ogg bitstream spec.
+func (o *oggOpusEncoder) writePage(packets [][]byte, granule int64, bos bool, eos bool) error {
+	var segmentTable []byte
+	for _, p := range packets {
+		remaining := len(p)
+		for remaining >= 255 {
+			segmentTable = append(segmentTable, 255)
+			remaining -= 255
+		}
+		segmentTable = append(segmentTable, byte(remaining))
+	}
+	if len(segmentTable) > oggMaxPageSegments {
+		return fmt.Errorf("ogg page segment table overflow (%d segments)", len(segmentTable))
+	}
+
+	header := make([]byte, 27+len(segmentTable))
+	copy(header[0:4], "OggS")
+	header[4] = 0 // stream structure version
+
+	var flags byte
+	if bos {
+		flags |= 0x02
+	}
+	if eos {
+		flags |= 0x04
+	}
+	header[5] = flags
+
+	binary.LittleEndian.PutUint64(header[6:14], uint64(granule))
+	binary.LittleEndian.PutUint32(header[14:18], o.serial)
+	binary.LittleEndian.PutUint32(header[18:22], o.pageSeq)
+	// header[22:26] (CRC) is left zero here and filled in below, once the
+	// full page - header and body - is assembled.
+	header[26] = byte(len(segmentTable))
+	copy(header[27:], segmentTable)
+
+	o.pageSeq++
+
+	var body []byte
+	for _, p := range packets {
+		body = append(body, p...)
+	}
+
+	page := append(header, body...)
+	binary.LittleEndian.PutUint32(page[22:26], oggCRC32(page))
+
+	_, err := o.file.Write(page)
+	return err
+}
+
+// oggCRC32Table is the lookup table for the Ogg bitstream's CRC-32 variant:
+// polynomial 0x04c11db7, processed MSB-first with no input/output
+// reflection and no final XOR - notably different from the CRC-32 used by
+// zip/gzip (IEEE 802.3), which is why this isn't just hash/crc32.
+var oggCRC32Table [256]uint32
+
+func init() {
+	for i := 0; i < 256; i++ {
+		crc := uint32(i) << 24
+		for j := 0; j < 8; j++ {
+			if crc&0x80000000 != 0 {
+				crc = (crc << 1) ^ 0x04c11db7
+			} else {
+				crc <<= 1
+			}
+		}
+		oggCRC32Table[i] = crc
+	}
+}
+
+// oggCRC32 computes the Ogg page checksum over data, which must have its
+// own CRC field zeroed.
+func oggCRC32(data []byte) uint32 {
+	var crc uint32
+	for _, b := range data {
+		crc = (crc << 8) ^ oggCRC32Table[byte(crc>>24)^b]
+	}
+	return crc
+}
@@ -0,0 +1,99 @@
+package audio
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+
+	"gopkg.in/hraban/opus.v2"
+)
+
+// opusFrameMs is the Opus frame duration used for each encode call - 20ms
+// is the codec's own recommended default for voice.
+const opusFrameMs = 20
+
+// opusMaxPacketBytes is large enough for any Opus packet at the bitrates
+// this package uses.
+const opusMaxPacketBytes = 4000
+
+// opusEncoder writes float32 samples as 16kHz mono Opus packets via
+// gopkg.in/hraban/opus.v2, suitable for streaming to a Whisper endpoint
+// that accepts Opus. Packets are each length-prefixed rather than wrapped
+// in a full Ogg container, so this format is meant for this package's own
+// streaming/decoding round-trip, not as a drop-in .ogg file for other
+// players - for that, see the "ogg-opus" codec in ogg_opus.go.
+type opusEncoder struct {
+	file      *os.File
+	enc       *opus.Encoder
+	frameSize int
+	buf       []float32
+}
+
+// newOpusEncoder implements EncoderFactory for the "opus" codec.
+func newOpusEncoder(filename string, cfg EncoderConfig) (Encoder, error) {
+	file, err := os.Create(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Opus file: %w", err)
+	}
+
+	enc, err := opus.NewEncoder(cfg.SampleRate, 1, opus.AppVoIP)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to create Opus encoder: %w", err)
+	}
+	if cfg.Bitrate > 0 {
+		if err := enc.SetBitrate(cfg.Bitrate); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to set Opus bitrate: %w", err)
+		}
+	}
+
+	return &opusEncoder{
+		file:      file,
+		enc:       enc,
+		frameSize: cfg.SampleRate * opusFrameMs / 1000,
+	}, nil
+}
+
+// WriteSamples buffers samples and encodes complete Opus frames as they
+// accumulate.
+func (o *opusEncoder) WriteSamples(samples []float32) error {
+	o.buf = append(o.buf, samples...)
+	for len(o.buf) >= o.frameSize {
+		if err := o.encodeAndWrite(o.buf[:o.frameSize]); err != nil {
+			return err
+		}
+		o.buf = o.buf[o.frameSize:]
+	}
+	return nil
+}
+
+// encodeAndWrite encodes one frame and appends it to the output file as a
+// length-prefixed packet.
+func (o *opusEncoder) encodeAndWrite(frame []float32) error {
+	data := make([]byte, opusMaxPacketBytes)
+	n, err := o.enc.EncodeFloat32(frame, data)
+	if err != nil {
+		return fmt.Errorf("failed to encode Opus frame: %w", err)
+	}
+
+	if err := binary.Write(o.file, binary.LittleEndian, uint32(n)); err != nil {
+		return err
+	}
+	_, err = o.file.Write(data[:n])
+	return err
+}
+
+// Close pads and flushes any partial trailing frame, then closes the file.
+func (o *opusEncoder) Close() error {
+	if len(o.buf) > 0 {
+		padded := make([]float32, o.frameSize)
+		copy(padded, o.buf)
+		if err := o.encodeAndWrite(padded); err != nil {
+			o.file.Close()
+			return err
+		}
+		o.buf = nil
+	}
+	return o.file.Close()
+}
@@ -0,0 +1,28 @@
+package audio
+
+// AudioProcessor is a single stage in AudioService's capture-time processing
+// pipeline. Stages run in registration order, in place, on the raw float32
+// samples handed to audioDataCallback - before they're appended to the
+// capture buffer, handed to the stream callback, and sent to the recorder,
+// so every consumer benefits uniformly from the same cleanup.
+type AudioProcessor interface {
+	// Process filters samples in place.
+	Process(samples []float32)
+	// Reset clears any internal state (filter memory, envelope followers,
+	// gain smoothing), used when starting a fresh capture session.
+	Reset()
+}
+
+// AddProcessor appends a processing stage to the end of the pipeline.
+func (a *AudioService) AddProcessor(p AudioProcessor) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.processors = append(a.processors, p)
+}
+
+// ClearProcessors removes every configured processing stage.
+func (a *AudioService) ClearProcessors() {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.processors = nil
+}
@@ -0,0 +1,217 @@
+package audio
+
+import "math"
+
+// DefaultHighPassCutoffHz is the cutoff used when callers don't have a more
+// specific preference - low enough to leave speech untouched while removing
+// mic rumble, HVAC hum, and handling noise below it.
+const DefaultHighPassCutoffHz = 80.0
+
+// HighPassFilter is a biquad high-pass filter using the RBJ Audio EQ Cookbook
+// transfer function. It carries its own coefficients and per-instance
+// x1/x2/y1/y2 state, so each capture session (or each AudioService) should
+// use its own instance rather than sharing one across streams.
+type HighPassFilter struct {
+	sampleRate float64
+	q          float64
+
+	b0, b1, b2, a1, a2 float64
+	x1, x2, y1, y2     float64
+}
+
+// NewHighPassFilter builds a high-pass filter for the given cutoff and
+// sample rate, using the standard Butterworth Q (0.707).
+func NewHighPassFilter(cutoffHz float64, sampleRate uint32) *HighPassFilter {
+	f := &HighPassFilter{sampleRate: float64(sampleRate), q: 0.707}
+	f.setCutoff(cutoffHz)
+	return f
+}
+
+// setCutoff recomputes the filter's coefficients for a new cutoff frequency.
+func (f *HighPassFilter) setCutoff(cutoffHz float64) {
+	w0 := 2 * math.Pi * cutoffHz / f.sampleRate
+	cosw0 := math.Cos(w0)
+	alpha := math.Sin(w0) / (2 * f.q)
+
+	a0 := 1 + alpha
+	f.b0 = ((1 + cosw0) / 2) / a0
+	f.b1 = (-(1 + cosw0)) / a0
+	f.b2 = ((1 + cosw0) / 2) / a0
+	f.a1 = (-2 * cosw0) / a0
+	f.a2 = (1 - alpha) / a0
+}
+
+// Process applies y[n] = b0*x[n] + b1*x[n-1] + b2*x[n-2] - a1*y[n-1] - a2*y[n-2]
+// to samples in place.
+func (f *HighPassFilter) Process(samples []float32) {
+	for i, s := range samples {
+		x0 := float64(s)
+		y0 := f.b0*x0 + f.b1*f.x1 + f.b2*f.x2 - f.a1*f.y1 - f.a2*f.y2
+		f.x2, f.x1 = f.x1, x0
+		f.y2, f.y1 = f.y1, y0
+		samples[i] = float32(y0)
+	}
+}
+
+// Reset clears the filter's delay line, used when starting a fresh capture
+// session so the previous session's tail doesn't bleed into the next one.
+func (f *HighPassFilter) Reset() {
+	f.x1, f.x2, f.y1, f.y2 = 0, 0, 0, 0
+}
+
+// gateFloor keeps 20*log10 from blowing up on exact silence.
+const gateFloor = 1e-9
+
+// NoiseGate is a hysteresis noise gate: it opens once the signal exceeds
+// openThresholdDB and stays open until it drops below the lower
+// closeThresholdDB, smoothing the open/close transition over
+// attackMs/releaseMs so it doesn't click or chatter on sounds that hover
+// near the threshold.
+type NoiseGate struct {
+	openThresholdDB  float64
+	closeThresholdDB float64
+	attackCoeff      float64
+	releaseCoeff     float64
+
+	open bool
+	gain float64
+}
+
+// NewNoiseGate builds a noise gate. openThresholdDB/closeThresholdDB are in
+// dBFS (closeThresholdDB should be lower than openThresholdDB to provide
+// hysteresis); attackMs/releaseMs control how fast the gain ramps open and
+// closed.
+func NewNoiseGate(openThresholdDB, closeThresholdDB, attackMs, releaseMs float64, sampleRate uint32) *NoiseGate {
+	return &NoiseGate{
+		openThresholdDB:  openThresholdDB,
+		closeThresholdDB: closeThresholdDB,
+		attackCoeff:      onePoleCoeff(attackMs, sampleRate),
+		releaseCoeff:     onePoleCoeff(releaseMs, sampleRate),
+	}
+}
+
+// Process applies the gate to samples in place.
+func (g *NoiseGate) Process(samples []float32) {
+	for i, s := range samples {
+		level := float64(s)
+		if level < 0 {
+			level = -level
+		}
+		db := 20 * math.Log10(level+gateFloor)
+
+		if g.open && db < g.closeThresholdDB {
+			g.open = false
+		} else if !g.open && db > g.openThresholdDB {
+			g.open = true
+		}
+
+		target, coeff := 0.0, g.releaseCoeff
+		if g.open {
+			target, coeff = 1.0, g.attackCoeff
+		}
+		g.gain = coeff*g.gain + (1-coeff)*target
+		samples[i] = float32(float64(s) * g.gain)
+	}
+}
+
+// Reset clears the gate's open/closed state and gain, used when starting a
+// fresh capture session.
+func (g *NoiseGate) Reset() {
+	g.open = false
+	g.gain = 0
+}
+
+// LoudnessNormalizer tracks a running RMS level and applies a smoothed gain
+// to bring it toward targetRMS, so quiet speakers and loud speakers end up
+// at roughly the same level without the gain itself pumping audibly.
+type LoudnessNormalizer struct {
+	targetRMS float64
+	maxGain   float64
+	rmsCoeff  float64
+	gainCoeff float64
+
+	meanSquare float64
+	gain       float64
+}
+
+// NewLoudnessNormalizer builds a normalizer targeting targetRMS (linear,
+// 0.0-1.0; e.g. 0.1 for a target around -20 dBFS).
+func NewLoudnessNormalizer(targetRMS float64, sampleRate uint32) *LoudnessNormalizer {
+	return &LoudnessNormalizer{
+		targetRMS: targetRMS,
+		maxGain:   4.0,
+		rmsCoeff:  onePoleCoeff(50, sampleRate),  // 50ms RMS window
+		gainCoeff: onePoleCoeff(200, sampleRate), // 200ms gain smoothing to avoid pumping
+		gain:      1.0,
+	}
+}
+
+// Process applies the normalizer to samples in place.
+func (n *LoudnessNormalizer) Process(samples []float32) {
+	for i, s := range samples {
+		x := float64(s)
+		n.meanSquare = n.rmsCoeff*n.meanSquare + (1-n.rmsCoeff)*x*x
+		rms := math.Sqrt(n.meanSquare)
+
+		target := n.gain
+		if rms > 1e-6 {
+			target = n.targetRMS / rms
+			if target > n.maxGain {
+				target = n.maxGain
+			}
+		}
+		n.gain = n.gainCoeff*n.gain + (1-n.gainCoeff)*target
+
+		out := x * n.gain
+		if out > 1 {
+			out = 1
+		} else if out < -1 {
+			out = -1
+		}
+		samples[i] = float32(out)
+	}
+}
+
+// Reset clears the normalizer's RMS estimate and gain, used when starting a
+// fresh capture session.
+func (n *LoudnessNormalizer) Reset() {
+	n.meanSquare = 0
+	n.gain = 1.0
+}
+
+// SoftClipper is a tanh-based soft clipper: it drives samples through
+// tanh(drive*x)/tanh(drive), which saturates smoothly toward +-1 instead of
+// the harsh distortion a hard clip produces, while leaving unity gain at
+// drive's own output ceiling.
+type SoftClipper struct {
+	drive float64
+}
+
+// NewSoftClipper builds a soft clipper with the given drive (higher drive
+// clips earlier and harder; 1.0 is a mild curve).
+func NewSoftClipper(drive float64) *SoftClipper {
+	if drive <= 0 {
+		drive = 1.0
+	}
+	return &SoftClipper{drive: drive}
+}
+
+// Process applies the soft clip to samples in place.
+func (c *SoftClipper) Process(samples []float32) {
+	norm := math.Tanh(c.drive)
+	for i, s := range samples {
+		samples[i] = float32(math.Tanh(c.drive*float64(s)) / norm)
+	}
+}
+
+// Reset is a no-op; SoftClipper is stateless.
+func (c *SoftClipper) Reset() {}
+
+// onePoleCoeff converts a time constant in milliseconds into the feedback
+// coefficient for a single-pole (one-zero) smoothing filter at sampleRate.
+func onePoleCoeff(timeMs float64, sampleRate uint32) float64 {
+	if timeMs <= 0 {
+		return 0
+	}
+	return math.Exp(-1.0 / (float64(sampleRate) * timeMs / 1000))
+}
@@ -0,0 +1,139 @@
+package audio
+
+import "sync/atomic"
+
+// sampleRing is a lock-free single-producer/single-consumer ring buffer of
+// float32 samples. audioDataCallback is the sole producer and GetSamples is
+// the sole consumer; each writes only the atomic index it owns, so neither
+// ever blocks on the other and the hot audio-thread path does no locking or
+// per-chunk allocation. Capacity is rounded up to a power of two so index
+// wrapping is a bitmask instead of a modulo.
+//
+// On overrun, the producer can't update head itself - Read runs
+// concurrently with Write in real usage, so two writers racing on the same
+// atomic could silently drop one side's update. Instead the producer bumps
+// minValid, a high-water mark for how much of the ring it has overwritten;
+// Read reconciles its own head against minValid before computing how much
+// is available, so head is only ever written by the consumer.
+type sampleRing struct {
+	data []float32
+	mask uint64
+
+	head atomic.Uint64 // next slot the consumer will read; consumer-owned
+	tail atomic.Uint64 // next slot the producer will write; producer-owned
+
+	// minValid is the oldest slot index the producer hasn't overwritten
+	// yet; producer-owned. Read advances head to at least minValid before
+	// computing availability, catching up past whatever the producer has
+	// evicted.
+	minValid atomic.Uint64
+
+	overruns  atomic.Uint64 // samples dropped because the ring was full
+	underruns atomic.Uint64 // reads that found the ring empty
+}
+
+// newSampleRing creates a ring sized to at least capacity samples.
+func newSampleRing(capacity int) *sampleRing {
+	size := nextPowerOfTwo(capacity)
+	return &sampleRing{
+		data: make([]float32, size),
+		mask: uint64(size - 1),
+	}
+}
+
+// nextPowerOfTwo rounds n up to the nearest power of two (minimum 1).
+func nextPowerOfTwo(n int) int {
+	if n <= 1 {
+		return 1
+	}
+	p := 1
+	for p < n {
+		p <<= 1
+	}
+	return p
+}
+
+// Write appends samples. Producer-side only; never blocks. If samples would
+// overrun the capacity, the oldest unread data is dropped (minValid
+// advances) and the drop count is added to Overruns.
+func (r *sampleRing) Write(samples []float32) {
+	if len(samples) == 0 {
+		return
+	}
+
+	capacity := uint64(len(r.data))
+	if uint64(len(samples)) > capacity {
+		// Can't even fit the whole write; keep only its tail.
+		dropped := uint64(len(samples)) - capacity
+		r.overruns.Add(dropped)
+		samples = samples[dropped:]
+	}
+
+	tail := r.tail.Load()
+	oldest := r.minValid.Load()
+	if head := r.head.Load(); head > oldest {
+		// Samples the consumer has already read don't count as occupied.
+		oldest = head
+	}
+	free := capacity - (tail - oldest)
+	if uint64(len(samples)) > free {
+		overrun := uint64(len(samples)) - free
+		r.overruns.Add(overrun)
+		r.minValid.Store(oldest + overrun)
+	}
+
+	for _, s := range samples {
+		r.data[tail&r.mask] = s
+		tail++
+	}
+
+	r.tail.Store(tail)
+}
+
+// Read copies up to len(dst) available samples into dst, consumer-side
+// only, and returns how many were copied. If the ring is empty, Read
+// returns 0 and counts an underrun.
+func (r *sampleRing) Read(dst []float32) int {
+	head := r.head.Load()
+	tail := r.tail.Load()
+	if minValid := r.minValid.Load(); head < minValid {
+		// The producer overwrote data before we got to it; catch up.
+		head = minValid
+	}
+	available := tail - head
+
+	if available == 0 {
+		if len(dst) > 0 {
+			r.underruns.Add(1)
+		}
+		return 0
+	}
+
+	n := uint64(len(dst))
+	if n > available {
+		n = available
+	}
+	for i := uint64(0); i < n; i++ {
+		dst[i] = r.data[(head+i)&r.mask]
+	}
+	r.head.Store(head + n)
+	return int(n)
+}
+
+// Len returns the number of samples currently available to read.
+func (r *sampleRing) Len() int {
+	head := r.head.Load()
+	if minValid := r.minValid.Load(); head < minValid {
+		head = minValid
+	}
+	return int(r.tail.Load() - head)
+}
+
+// Reset drops all buffered samples, used when starting a fresh capture
+// session. Overrun/underrun counters are left intact so Stats reflects the
+// service's lifetime, not just the current session.
+func (r *sampleRing) Reset() {
+	r.head.Store(0)
+	r.tail.Store(0)
+	r.minValid.Store(0)
+}
@@ -0,0 +1,233 @@
+package audio
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// sinkChannelCapacity bounds how many chunks a slow sink may lag behind by
+// before audioDataCallback starts dropping chunks for it, rather than ever
+// blocking the audio thread on a slow consumer.
+const sinkChannelCapacity = 64
+
+// AudioSink receives captured audio chunks. Implementations run on their own
+// goroutine (see Subscribe) and should return promptly; a sink that can't
+// keep up only causes its own chunks to drop, never the audio callback.
+type AudioSink interface {
+	Write(samples []float32, ts time.Time) error
+}
+
+// SubscriptionID identifies a sink registered via Subscribe.
+type SubscriptionID uint64
+
+// SinkStats reports how far a subscribed sink has fallen behind.
+type SinkStats struct {
+	ID      SubscriptionID
+	Dropped uint64
+}
+
+// sinkChunk is one unit of work handed to a sink's goroutine.
+type sinkChunk struct {
+	samples []float32
+	ts      time.Time
+}
+
+// subscription pairs a sink with its bounded delivery channel and drop
+// counter.
+type subscription struct {
+	id      SubscriptionID
+	sink    AudioSink
+	ch      chan sinkChunk
+	done    chan struct{}
+	dropped uint64 // atomic
+}
+
+// Subscribe registers sink to receive every captured audio chunk on its own
+// goroutine, fed through a bounded channel so a slow sink drops chunks
+// (tracked in SinkStats) instead of ever blocking the audio callback or
+// other sinks. The returned SubscriptionID is used to Unsubscribe later.
+func (a *AudioService) Subscribe(sink AudioSink) SubscriptionID {
+	a.streamMutex.Lock()
+	a.nextSubID++
+	sub := &subscription{
+		id:   a.nextSubID,
+		sink: sink,
+		ch:   make(chan sinkChunk, sinkChannelCapacity),
+		done: make(chan struct{}),
+	}
+	a.subscriptions = append(a.subscriptions, sub)
+	a.streamMutex.Unlock()
+
+	go runSink(sub)
+	return sub.id
+}
+
+// Unsubscribe stops delivering audio to the sink registered under id and
+// waits for its goroutine to drain and exit.
+func (a *AudioService) Unsubscribe(id SubscriptionID) {
+	a.streamMutex.Lock()
+	var found *subscription
+	kept := a.subscriptions[:0]
+	for _, sub := range a.subscriptions {
+		if sub.id == id {
+			found = sub
+			continue
+		}
+		kept = append(kept, sub)
+	}
+	a.subscriptions = kept
+	a.streamMutex.Unlock()
+
+	if found == nil {
+		return
+	}
+	close(found.ch)
+	<-found.done
+}
+
+// SinkStats reports the drop count for every currently subscribed sink.
+func (a *AudioService) SinkStats() []SinkStats {
+	a.streamMutex.RLock()
+	defer a.streamMutex.RUnlock()
+
+	stats := make([]SinkStats, len(a.subscriptions))
+	for i, sub := range a.subscriptions {
+		stats[i] = SinkStats{ID: sub.id, Dropped: atomic.LoadUint64(&sub.dropped)}
+	}
+	return stats
+}
+
+// runSink drains a subscription's channel into its sink until Unsubscribe
+// closes the channel.
+func runSink(sub *subscription) {
+	defer close(sub.done)
+	for chunk := range sub.ch {
+		if err := sub.sink.Write(chunk.samples, chunk.ts); err != nil {
+			slog.Error("audio sink write failed", "error", err)
+		}
+	}
+}
+
+// publishToSinks fans a captured chunk out to every subscribed sink,
+// dropping (and counting) it for any sink whose channel is currently full
+// rather than blocking the audio callback.
+func (a *AudioService) publishToSinks(samples []float32, ts time.Time) {
+	a.streamMutex.RLock()
+	subs := a.subscriptions
+	a.streamMutex.RUnlock()
+
+	for _, sub := range subs {
+		samplesCopy := make([]float32, len(samples))
+		copy(samplesCopy, samples)
+		select {
+		case sub.ch <- sinkChunk{samples: samplesCopy, ts: ts}:
+		default:
+			atomic.AddUint64(&sub.dropped, 1)
+		}
+	}
+}
+
+// EncoderSink writes captured audio to disk through an Encoder - WAV, FLAC,
+// Opus, or MP3 (see encoders.go) - so recording can target any of them
+// through the same fan-out subscription mechanism.
+type EncoderSink struct {
+	Encoder Encoder
+}
+
+// NewWAVSink opens filename for writing as 16-bit PCM WAV and returns a
+// sink that appends every chunk it receives to it. Kept as a convenience
+// for the common case; StartRecordingAs builds an EncoderSink directly for
+// other codecs.
+func NewWAVSink(filename string, sampleRate int) (*EncoderSink, error) {
+	writer, err := NewWAVWriter(filename, sampleRate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create WAV writer: %w", err)
+	}
+	return &EncoderSink{Encoder: writer}, nil
+}
+
+// Write forwards samples to the underlying encoder.
+func (s *EncoderSink) Write(samples []float32, _ time.Time) error {
+	return s.Encoder.WriteSamples(samples)
+}
+
+// Close finalizes the underlying encoder and closes its output.
+func (s *EncoderSink) Close() error {
+	return s.Encoder.Close()
+}
+
+// CallbackSink adapts a plain StreamCallback to the AudioSink interface, for
+// callers that just want a function called with each chunk (e.g. VAD).
+type CallbackSink struct {
+	Callback StreamCallback
+}
+
+// Write invokes the wrapped callback with samples.
+func (s CallbackSink) Write(samples []float32, _ time.Time) error {
+	if s.Callback != nil {
+		s.Callback(samples)
+	}
+	return nil
+}
+
+// RingBufferSink accumulates chunks into a growable buffer for later,
+// GetSamples-style pulls via Drain.
+type RingBufferSink struct {
+	mutex  sync.Mutex
+	buffer []float32
+}
+
+// NewRingBufferSink returns an empty RingBufferSink.
+func NewRingBufferSink() *RingBufferSink {
+	return &RingBufferSink{}
+}
+
+// Write appends samples to the internal buffer.
+func (s *RingBufferSink) Write(samples []float32, _ time.Time) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.buffer = append(s.buffer, samples...)
+	return nil
+}
+
+// Drain returns a copy of everything accumulated so far and clears the
+// buffer, mirroring AudioService.GetSamples.
+func (s *RingBufferSink) Drain() []float32 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(s.buffer) == 0 {
+		return nil
+	}
+	out := make([]float32, len(s.buffer))
+	copy(out, s.buffer)
+	s.buffer = s.buffer[:0]
+	return out
+}
+
+// TeeSink broadcasts every chunk to a fixed list of sinks, useful when a
+// single Subscribe slot should fan out further (e.g. pairing an EncoderSink with
+// a CallbackSink under one subscription).
+type TeeSink struct {
+	Sinks []AudioSink
+}
+
+// NewTeeSink returns a TeeSink broadcasting to the given sinks in order.
+func NewTeeSink(sinks ...AudioSink) *TeeSink {
+	return &TeeSink{Sinks: sinks}
+}
+
+// Write calls Write on every wrapped sink, continuing past individual
+// errors and returning the first one encountered, if any.
+func (t *TeeSink) Write(samples []float32, ts time.Time) error {
+	var firstErr error
+	for _, sink := range t.Sinks {
+		if err := sink.Write(samples, ts); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,266 @@
+package audio
+
+import (
+	"fmt"
+	"math"
+	"sync"
+)
+
+// UtteranceCallback is invoked once a complete utterance - pre-roll, speech,
+// and the trailing silence that confirmed it ended - has been captured.
+// startMs/endMs are offsets from when the VADSegmenter started running.
+type UtteranceCallback func(samples []float32, startMs, endMs int64)
+
+// VADConfig tunes VADSegmenter's frame classification and segmentation.
+type VADConfig struct {
+	// SampleRate must match the capture rate audio is arriving at. Zero
+	// means "use the AudioService's configured sample rate" when passed to
+	// StartVAD.
+	SampleRate uint32
+	// FrameLengthMs is the frame size voice activity is classified at - 10,
+	// 20, or 30ms, matching WebRTC VAD's supported frame sizes. Zero
+	// defaults to 20ms.
+	FrameLengthMs int
+	// Aggressiveness is a WebRTC-VAD-style knob from 0 (least aggressive,
+	// classifies more frames as speech) to 3 (most aggressive, requires a
+	// louder signal before a frame counts as speech). It scales the energy
+	// threshold used for classification.
+	Aggressiveness int
+	// SpeechHangoverMs is how long a run of silence must last, once speech
+	// has been detected, before the utterance is considered finished. Zero
+	// defaults to 300ms.
+	SpeechHangoverMs int
+	// PreRollMs is how much audio captured immediately before the
+	// speech-start frame is prepended to the emitted utterance. Zero
+	// defaults to 300ms.
+	PreRollMs int
+}
+
+// withDefaults fills in zero fields with the segmenter's defaults.
+func (cfg VADConfig) withDefaults() VADConfig {
+	if cfg.SampleRate == 0 {
+		cfg.SampleRate = 16000
+	}
+	if cfg.FrameLengthMs == 0 {
+		cfg.FrameLengthMs = 20
+	}
+	if cfg.SpeechHangoverMs == 0 {
+		cfg.SpeechHangoverMs = 300
+	}
+	if cfg.PreRollMs == 0 {
+		cfg.PreRollMs = 300
+	}
+	return cfg
+}
+
+// validate rejects frame lengths WebRTC VAD doesn't support and
+// aggressiveness levels outside its 0-3 range.
+func (cfg VADConfig) validate() error {
+	switch cfg.FrameLengthMs {
+	case 10, 20, 30:
+	default:
+		return fmt.Errorf("unsupported VAD frame length %dms - must be 10, 20, or 30", cfg.FrameLengthMs)
+	}
+	if cfg.Aggressiveness < 0 || cfg.Aggressiveness > 3 {
+		return fmt.Errorf("VAD aggressiveness must be 0-3, got %d", cfg.Aggressiveness)
+	}
+	return nil
+}
+
+// aggressivenessThresholds maps VADConfig.Aggressiveness (0-3) to an RMS
+// energy threshold - higher aggressiveness requires a louder signal before a
+// frame counts as speech, mirroring WebRTC VAD's own aggressiveness modes.
+var aggressivenessThresholds = [4]float32{0.010, 0.015, 0.020, 0.030}
+
+// VADSegmenter classifies a stream of audio frames as speech or silence and
+// emits complete utterances - including a fixed amount of pre-roll audio
+// captured before speech was detected - via its UtteranceCallback. It's
+// driven by registering Process as an AudioService StreamCallback; see
+// AudioService.StartVAD for the usual way to wire it up.
+type VADSegmenter struct {
+	cfg       VADConfig
+	threshold float32
+	frameSize int
+
+	preRoll    []float32
+	preRollCap int
+
+	frameBuf       []float32
+	speaking       bool
+	speechBuf      []float32
+	silenceFrames  int
+	hangoverFrames int
+	startSample    int64
+	totalSamples   int64
+
+	onUtterance UtteranceCallback
+	mutex       sync.Mutex
+}
+
+// NewVADSegmenter builds a segmenter from cfg, applying defaults for any
+// zero fields. Callers that want config validation (e.g. rejecting an
+// unsupported frame length) should call cfg.validate() first - StartVAD
+// does this for the AudioService-managed case.
+func NewVADSegmenter(cfg VADConfig, onUtterance UtteranceCallback) *VADSegmenter {
+	cfg = cfg.withDefaults()
+
+	aggressiveness := cfg.Aggressiveness
+	if aggressiveness < 0 || aggressiveness > 3 {
+		aggressiveness = 1
+	}
+
+	frameSize := int(cfg.SampleRate) * cfg.FrameLengthMs / 1000
+	preRollCap := int(cfg.SampleRate) * cfg.PreRollMs / 1000
+	hangoverFrames := cfg.SpeechHangoverMs / cfg.FrameLengthMs
+	if hangoverFrames < 1 {
+		hangoverFrames = 1
+	}
+
+	return &VADSegmenter{
+		cfg:            cfg,
+		threshold:      aggressivenessThresholds[aggressiveness],
+		frameSize:      frameSize,
+		preRollCap:     preRollCap,
+		hangoverFrames: hangoverFrames,
+		onUtterance:    onUtterance,
+	}
+}
+
+// Process implements StreamCallback: it slices the incoming chunk into
+// fixed-size frames (buffering any remainder across calls) and classifies
+// each one in turn.
+func (s *VADSegmenter) Process(samples []float32) {
+	s.mutex.Lock()
+	s.frameBuf = append(s.frameBuf, samples...)
+	for len(s.frameBuf) >= s.frameSize {
+		frame := s.frameBuf[:s.frameSize]
+		s.frameBuf = s.frameBuf[s.frameSize:]
+		s.processFrame(frame)
+	}
+	s.mutex.Unlock()
+}
+
+// processFrame classifies a single frame and advances segmentation state.
+// Must be called with mutex held; may release and re-acquire it to fire
+// onUtterance without blocking other callers.
+func (s *VADSegmenter) processFrame(frame []float32) {
+	isSpeech := rmsEnergy(frame) > s.threshold
+	frameStart := s.totalSamples
+	s.totalSamples += int64(len(frame))
+
+	if !s.speaking {
+		s.appendPreRoll(frame)
+		if isSpeech {
+			s.speaking = true
+			s.silenceFrames = 0
+			s.startSample = frameStart - int64(len(s.preRoll)-len(frame))
+			s.speechBuf = append(s.speechBuf[:0], s.preRoll...)
+		}
+		return
+	}
+
+	s.speechBuf = append(s.speechBuf, frame...)
+	if isSpeech {
+		s.silenceFrames = 0
+		return
+	}
+
+	s.silenceFrames++
+	if s.silenceFrames >= s.hangoverFrames {
+		s.emitUtterance()
+	}
+}
+
+// emitUtterance fires onUtterance with the accumulated buffer and resets
+// segmentation state, ready for the next utterance. Must be called with
+// mutex held.
+func (s *VADSegmenter) emitUtterance() {
+	samples := make([]float32, len(s.speechBuf))
+	copy(samples, s.speechBuf)
+	startMs := s.startSample * 1000 / int64(s.cfg.SampleRate)
+	endMs := s.totalSamples * 1000 / int64(s.cfg.SampleRate)
+
+	s.speaking = false
+	s.speechBuf = s.speechBuf[:0]
+	s.silenceFrames = 0
+	s.preRoll = s.preRoll[:0]
+
+	cb := s.onUtterance
+	if cb == nil {
+		return
+	}
+	s.mutex.Unlock()
+	cb(samples, startMs, endMs)
+	s.mutex.Lock()
+}
+
+// appendPreRoll keeps a rolling window of the most recent preRollCap
+// samples seen while not speaking. Must be called with mutex held.
+func (s *VADSegmenter) appendPreRoll(frame []float32) {
+	s.preRoll = append(s.preRoll, frame...)
+	if excess := len(s.preRoll) - s.preRollCap; excess > 0 {
+		s.preRoll = s.preRoll[excess:]
+	}
+}
+
+// Reset clears all segmentation state, used when starting a fresh capture
+// session so a prior session's tail doesn't bleed into the next one.
+func (s *VADSegmenter) Reset() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.frameBuf = s.frameBuf[:0]
+	s.preRoll = s.preRoll[:0]
+	s.speechBuf = s.speechBuf[:0]
+	s.speaking = false
+	s.silenceFrames = 0
+	s.totalSamples = 0
+	s.startSample = 0
+}
+
+// rmsEnergy computes the root-mean-square energy of a frame.
+func rmsEnergy(samples []float32) float32 {
+	if len(samples) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, s := range samples {
+		sum += float64(s) * float64(s)
+	}
+	return float32(math.Sqrt(sum / float64(len(samples))))
+}
+
+// StartVAD builds a VADSegmenter from cfg and registers it as this
+// service's stream callback, so complete utterances are delivered via cb
+// automatically - no need to poll GetSamples or run VAD classification
+// yourself.
+func (a *AudioService) StartVAD(cfg VADConfig, cb UtteranceCallback) error {
+	a.mutex.Lock()
+	if cfg.SampleRate == 0 {
+		cfg.SampleRate = a.sampleRate
+	}
+	a.mutex.Unlock()
+
+	cfg = cfg.withDefaults()
+	if err := cfg.validate(); err != nil {
+		return fmt.Errorf("invalid VAD config: %w", err)
+	}
+
+	segmenter := NewVADSegmenter(cfg, cb)
+
+	a.mutex.Lock()
+	a.vadSegmenter = segmenter
+	a.mutex.Unlock()
+
+	a.SetStreamCallback(segmenter.Process)
+	return nil
+}
+
+// StopVAD tears down a VADSegmenter started by StartVAD and clears the
+// stream callback.
+func (a *AudioService) StopVAD() {
+	a.ClearStreamCallback()
+
+	a.mutex.Lock()
+	a.vadSegmenter = nil
+	a.mutex.Unlock()
+}
@@ -97,17 +97,29 @@ type WAVWriter struct {
 	sampleRate  int
 	numChannels int
 	dataSize    uint32
+	floatFormat bool // WAVE_FORMAT_IEEE_FLOAT (32-bit) instead of 16-bit PCM
 }
 
-// NewWAVWriter creates a new WAV writer
+// NewWAVWriter creates a new 16-bit PCM WAV writer
 func NewWAVWriter(filename string, sampleRate int) (*WAVWriter, error) {
+	return newWAVWriter(filename, sampleRate, false)
+}
+
+// NewFloatWAVWriter creates a WAV writer that stores samples as 32-bit IEEE
+// float (WAVE_FORMAT_IEEE_FLOAT) instead of quantizing them to 16-bit PCM,
+// preserving the original capture precision.
+func NewFloatWAVWriter(filename string, sampleRate int) (*WAVWriter, error) {
+	return newWAVWriter(filename, sampleRate, true)
+}
+
+func newWAVWriter(filename string, sampleRate int, floatFormat bool) (*WAVWriter, error) {
 	file, err := os.Create(filename)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create file: %w", err)
 	}
 
 	// Write placeholder header
-	if err := writeWAVHeader(file, sampleRate, 0); err != nil {
+	if err := writeWAVHeaderFormat(file, sampleRate, 0, floatFormat); err != nil {
 		file.Close()
 		return nil, fmt.Errorf("failed to write header: %w", err)
 	}
@@ -117,11 +129,21 @@ func NewWAVWriter(filename string, sampleRate int) (*WAVWriter, error) {
 		sampleRate:  sampleRate,
 		numChannels: 1,
 		dataSize:    0,
+		floatFormat: floatFormat,
 	}, nil
 }
 
-// WriteSamples writes float32 samples to the WAV file
+// WriteSamples writes float32 samples to the WAV file, quantizing to 16-bit
+// PCM unless this writer was created with NewFloatWAVWriter.
 func (w *WAVWriter) WriteSamples(samples []float32) error {
+	if w.floatFormat {
+		if err := binary.Write(w.file, binary.LittleEndian, samples); err != nil {
+			return err
+		}
+		w.dataSize += uint32(len(samples) * 4) // 4 bytes per sample (32-bit float)
+		return nil
+	}
+
 	int16Samples := make([]int16, len(samples))
 	for i, s := range samples {
 		// Clamp to -1.0 to 1.0
@@ -150,7 +172,7 @@ func (w *WAVWriter) Close() error {
 		return fmt.Errorf("failed to seek to beginning of file: %w", err)
 	}
 
-	if err := writeWAVHeader(w.file, w.sampleRate, w.dataSize); err != nil {
+	if err := writeWAVHeaderFormat(w.file, w.sampleRate, w.dataSize, w.floatFormat); err != nil {
 		w.file.Close()
 		return fmt.Errorf("failed to update header: %w", err)
 	}
@@ -158,10 +180,22 @@ func (w *WAVWriter) Close() error {
 	return w.file.Close()
 }
 
-// writeWAVHeader writes the WAV header
+// writeWAVHeader writes a 16-bit PCM WAV header
 func writeWAVHeader(w io.Writer, sampleRate int, dataSize uint32) error {
+	return writeWAVHeaderFormat(w, sampleRate, dataSize, false)
+}
+
+// writeWAVHeaderFormat writes the WAV header, using WAVE_FORMAT_IEEE_FLOAT
+// (format code 3, 32 bits/sample) when floatFormat is set, otherwise PCM
+// (format code 1, 16 bits/sample).
+func writeWAVHeaderFormat(w io.Writer, sampleRate int, dataSize uint32, floatFormat bool) error {
 	numChannels := 1
+	audioFormat := int16(1)
 	bitsPerSample := 16
+	if floatFormat {
+		audioFormat = 3
+		bitsPerSample = 32
+	}
 	byteRate := sampleRate * numChannels * (bitsPerSample / 8)
 	blockAlign := numChannels * (bitsPerSample / 8)
 	chunkSize := 36 + dataSize
@@ -181,10 +215,10 @@ func writeWAVHeader(w io.Writer, sampleRate int, dataSize uint32) error {
 	if _, err := io.WriteString(w, "fmt "); err != nil {
 		return err
 	}
-	if err := binary.Write(w, binary.LittleEndian, int32(16)); err != nil { // SubChunk1Size (16 for PCM)
+	if err := binary.Write(w, binary.LittleEndian, int32(16)); err != nil { // SubChunk1Size (16 for PCM/IEEE float)
 		return err
 	}
-	if err := binary.Write(w, binary.LittleEndian, int16(1)); err != nil { // AudioFormat (1 for PCM)
+	if err := binary.Write(w, binary.LittleEndian, audioFormat); err != nil {
 		return err
 	}
 	if err := binary.Write(w, binary.LittleEndian, int16(numChannels)); err != nil {
@@ -214,88 +248,5 @@ func writeWAVHeader(w io.Writer, sampleRate int, dataSize uint32) error {
 	return nil
 }
 
-// ReadWAV reads a WAV file into float32 samples
-func ReadWAV(filename string) ([]float32, error) {
-	file, err := os.Open(filename)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open file: %w", err)
-	}
-	defer file.Close()
-
-	// Read RIFF header
-	header := make([]byte, 12)
-	if _, err := io.ReadFull(file, header); err != nil {
-		return nil, fmt.Errorf("failed to read RIFF header: %w", err)
-	}
-
-	if string(header[0:4]) != "RIFF" || string(header[8:12]) != "WAVE" {
-		return nil, fmt.Errorf("invalid WAV file")
-	}
-
-	// Iterate through chunks to find "data"
-	for {
-		chunkHeader := make([]byte, 8)
-		if _, err := io.ReadFull(file, chunkHeader); err != nil {
-			if err == io.EOF {
-				return nil, fmt.Errorf("data chunk not found")
-			}
-			return nil, fmt.Errorf("failed to read chunk header: %w", err)
-		}
-
-		chunkID := string(chunkHeader[0:4])
-		chunkSize := binary.LittleEndian.Uint32(chunkHeader[4:8])
-
-		if chunkID == "data" {
-			// Found data chunk
-			numSamples := int(chunkSize) / 2
-			samples := make([]float32, numSamples)
-
-			buf := make([]byte, chunkSize)
-			if _, err := io.ReadFull(file, buf); err != nil {
-				return nil, fmt.Errorf("failed to read data chunk: %w", err)
-			}
-
-			// Convert int16 to float32
-			for i := 0; i < numSamples; i++ {
-				idx := i * 2
-				val := int16(uint16(buf[idx]) | uint16(buf[idx+1])<<8)
-				samples[i] = float32(val) / 32768.0
-			}
-
-			return samples, nil
-		} else {
-			// Skip chunk
-			if _, err := file.Seek(int64(chunkSize), io.SeekCurrent); err != nil {
-				return nil, fmt.Errorf("failed to seek to beginning of next chunk: %w", err)
-			}
-		}
-	}
-}
-
-// GetWAVDuration returns duration of WAV file in seconds
-func GetWAVDuration(filename string) (float64, error) {
-    file, err := os.Open(filename)
-    if err != nil {
-        return 0, err
-    }
-    defer file.Close()
-
-    header := make([]byte, 44)
-    if _, err := io.ReadFull(file, header); err != nil {
-        return 0, err
-    }
-    
-    // Parse SampleRate (bytes 24-28)
-    sampleRate := binary.LittleEndian.Uint32(header[24:28])
-    // Parse ByteRate (bytes 28-32)
-    byteRate := binary.LittleEndian.Uint32(header[28:32])
-    // Parse DataSize (bytes 40-44)
-    dataSize := binary.LittleEndian.Uint32(header[40:44])
-
-    if byteRate == 0 || sampleRate == 0 {
-         return 0, fmt.Errorf("invalid WAV header")
-    }
-
-    duration := float64(dataSize) / float64(byteRate)
-    return duration, nil
-}
+// ReadWAV and GetWAVDuration live in wav_reader.go, alongside the
+// streaming WAVReader type.
@@ -0,0 +1,397 @@
+package audio
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+)
+
+// WAV AudioFormat codes this reader understands. WAVE_FORMAT_EXTENSIBLE
+// doesn't carry the real format itself - it's resolved to one of the
+// others via the first two bytes of its SubFormat GUID.
+const (
+	wavFormatPCM        = 1
+	wavFormatIEEEFloat  = 3
+	wavFormatALaw       = 6
+	wavFormatMULaw      = 7
+	wavFormatExtensible = 0xFFFE
+)
+
+// wavFmtChunk holds everything ReadWAV/WAVReader need out of a WAV file's
+// "fmt " chunk.
+type wavFmtChunk struct {
+	AudioFormat   uint16
+	NumChannels   uint16
+	SampleRate    uint32
+	ByteRate      uint32
+	BlockAlign    uint16
+	BitsPerSample uint16
+}
+
+// parseFmtChunk decodes a "fmt " chunk's body, resolving
+// WAVE_FORMAT_EXTENSIBLE to the codec named in its SubFormat GUID.
+func parseFmtChunk(data []byte) (wavFmtChunk, error) {
+	if len(data) < 16 {
+		return wavFmtChunk{}, fmt.Errorf("fmt chunk too short: %d bytes", len(data))
+	}
+
+	f := wavFmtChunk{
+		AudioFormat:   binary.LittleEndian.Uint16(data[0:2]),
+		NumChannels:   binary.LittleEndian.Uint16(data[2:4]),
+		SampleRate:    binary.LittleEndian.Uint32(data[4:8]),
+		ByteRate:      binary.LittleEndian.Uint32(data[8:12]),
+		BlockAlign:    binary.LittleEndian.Uint16(data[12:14]),
+		BitsPerSample: binary.LittleEndian.Uint16(data[14:16]),
+	}
+
+	if f.AudioFormat == wavFormatExtensible {
+		// WAVE_FORMAT_EXTENSIBLE's extension starts at byte 18 (after
+		// CbSize at 16:18); the first two bytes of the 16-byte SubFormat
+		// GUID carry the real format code.
+		if len(data) < 26 {
+			return wavFmtChunk{}, fmt.Errorf("extensible fmt chunk too short: %d bytes", len(data))
+		}
+		f.AudioFormat = binary.LittleEndian.Uint16(data[24:26])
+	}
+
+	if f.NumChannels == 0 {
+		return wavFmtChunk{}, fmt.Errorf("invalid WAV fmt chunk: zero channels")
+	}
+
+	return f, nil
+}
+
+// WAVReader streams float32 samples out of a WAV file without loading the
+// whole thing into memory, handling PCM (8/16/24/32-bit), IEEE float
+// (32/64-bit), and A-law/u-law source formats, downmixing multi-channel
+// audio to mono by averaging when mono is requested.
+type WAVReader struct {
+	file       *os.File
+	fmt        wavFmtChunk
+	dataSize   uint32
+	bytesLeft  uint32
+	mono       bool
+	frameBuf   []byte
+}
+
+// NewWAVReader opens filename and parses its RIFF/fmt/data chunks. mono
+// selects whether ReadSamples downmixes multi-channel audio (by averaging)
+// into a single channel - the shape Whisper and the rest of this package
+// expect.
+func NewWAVReader(filename string, mono bool) (*WAVReader, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+
+	header := make([]byte, 12)
+	if _, err := io.ReadFull(file, header); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to read RIFF header: %w", err)
+	}
+	if string(header[0:4]) != "RIFF" || string(header[8:12]) != "WAVE" {
+		file.Close()
+		return nil, fmt.Errorf("invalid WAV file")
+	}
+
+	var fc wavFmtChunk
+	var haveFmt bool
+	var dataSize uint32
+	var haveData bool
+
+	for !haveData {
+		chunkHeader := make([]byte, 8)
+		if _, err := io.ReadFull(file, chunkHeader); err != nil {
+			file.Close()
+			if err == io.EOF {
+				return nil, fmt.Errorf("data chunk not found")
+			}
+			return nil, fmt.Errorf("failed to read chunk header: %w", err)
+		}
+
+		chunkID := string(chunkHeader[0:4])
+		chunkSize := binary.LittleEndian.Uint32(chunkHeader[4:8])
+
+		if chunkID == "fmt " {
+			buf := make([]byte, chunkSize)
+			if _, err := io.ReadFull(file, buf); err != nil {
+				file.Close()
+				return nil, fmt.Errorf("failed to read fmt chunk: %w", err)
+			}
+			fc, err = parseFmtChunk(buf)
+			if err != nil {
+				file.Close()
+				return nil, err
+			}
+			haveFmt = true
+			if err := skipPadByte(file, chunkSize); err != nil {
+				file.Close()
+				return nil, err
+			}
+			continue
+		}
+
+		if chunkID == "data" {
+			if !haveFmt {
+				file.Close()
+				return nil, fmt.Errorf("data chunk encountered before fmt chunk")
+			}
+			dataSize = chunkSize
+			haveData = true
+			continue
+		}
+
+		// Unknown/uninteresting chunk (LIST, fact, ...) - skip it, RIFF
+		// pads odd-sized chunks to an even boundary.
+		if _, err := file.Seek(int64(chunkSize), io.SeekCurrent); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to seek past chunk %q: %w", chunkID, err)
+		}
+		if err := skipPadByte(file, chunkSize); err != nil {
+			file.Close()
+			return nil, err
+		}
+	}
+
+	if fc.BlockAlign == 0 {
+		file.Close()
+		return nil, fmt.Errorf("invalid WAV fmt chunk: zero block align")
+	}
+
+	return &WAVReader{
+		file:      file,
+		fmt:       fc,
+		dataSize:  dataSize,
+		bytesLeft: dataSize,
+		mono:      mono,
+		frameBuf:  make([]byte, fc.BlockAlign),
+	}, nil
+}
+
+// skipPadByte consumes the single padding byte RIFF adds after an
+// odd-sized chunk.
+func skipPadByte(file *os.File, chunkSize uint32) error {
+	if chunkSize%2 == 0 {
+		return nil
+	}
+	_, err := file.Seek(1, io.SeekCurrent)
+	return err
+}
+
+// SampleRate returns the file's sample rate in Hz.
+func (r *WAVReader) SampleRate() uint32 { return r.fmt.SampleRate }
+
+// Channels returns the number of channels in the source file (regardless
+// of whether this reader downmixes to mono).
+func (r *WAVReader) Channels() int { return int(r.fmt.NumChannels) }
+
+// Duration returns the file's playback duration in seconds.
+func (r *WAVReader) Duration() float64 {
+	frames := r.dataSize / uint32(r.fmt.BlockAlign)
+	if r.fmt.SampleRate == 0 {
+		return 0
+	}
+	return float64(frames) / float64(r.fmt.SampleRate)
+}
+
+// ReadSamples fills dst with up to len(dst) samples - one per frame when
+// mono (downmixing multi-channel source audio by averaging), or
+// Channels()-interleaved otherwise - and returns how many frames were read
+// (equal to the sample count when mono; multiply by Channels() otherwise). It
+// returns io.EOF once the data chunk is exhausted, matching io.Reader's own
+// convention.
+func (r *WAVReader) ReadSamples(dst []float32) (int, error) {
+	channels := int(r.fmt.NumChannels)
+	frameSize := int(r.fmt.BlockAlign)
+
+	framesWanted := len(dst)
+	if !r.mono {
+		framesWanted = len(dst) / channels
+	}
+
+	n := 0
+	for n < framesWanted {
+		if r.bytesLeft < uint32(frameSize) {
+			if n == 0 {
+				return 0, io.EOF
+			}
+			return n, nil
+		}
+
+		if _, err := io.ReadFull(r.file, r.frameBuf); err != nil {
+			if n == 0 {
+				return 0, fmt.Errorf("failed to read WAV frame: %w", err)
+			}
+			return n, nil
+		}
+		r.bytesLeft -= uint32(frameSize)
+
+		frame, err := decodeFrame(r.frameBuf, r.fmt, channels)
+		if err != nil {
+			return n, err
+		}
+
+		if r.mono {
+			dst[n] = downmix(frame)
+			n++
+		} else {
+			copy(dst[n*channels:(n+1)*channels], frame)
+			n++
+		}
+	}
+
+	return n, nil
+}
+
+// Close closes the underlying file.
+func (r *WAVReader) Close() error {
+	return r.file.Close()
+}
+
+// downmix averages a frame's channels into a single sample.
+func downmix(frame []float32) float32 {
+	var sum float32
+	for _, s := range frame {
+		sum += s
+	}
+	return sum / float32(len(frame))
+}
+
+// decodeFrame decodes one frame (BlockAlign bytes) into per-channel
+// -1.0..1.0 float32 samples, dispatching on fc.AudioFormat/BitsPerSample.
+func decodeFrame(raw []byte, fc wavFmtChunk, channels int) ([]float32, error) {
+	bytesPerSample := len(raw) / channels
+	out := make([]float32, channels)
+
+	for ch := 0; ch < channels; ch++ {
+		sample := raw[ch*bytesPerSample : (ch+1)*bytesPerSample]
+
+		switch fc.AudioFormat {
+		case wavFormatPCM:
+			v, err := decodePCMSample(sample, fc.BitsPerSample)
+			if err != nil {
+				return nil, err
+			}
+			out[ch] = v
+		case wavFormatIEEEFloat:
+			switch fc.BitsPerSample {
+			case 32:
+				bits := binary.LittleEndian.Uint32(sample)
+				out[ch] = math.Float32frombits(bits)
+			case 64:
+				bits := binary.LittleEndian.Uint64(sample)
+				out[ch] = float32(math.Float64frombits(bits))
+			default:
+				return nil, fmt.Errorf("unsupported IEEE float bit depth: %d", fc.BitsPerSample)
+			}
+		case wavFormatALaw:
+			out[ch] = float32(decodeALawSample(sample[0])) / 32768.0
+		case wavFormatMULaw:
+			out[ch] = float32(decodeMULawSample(sample[0])) / 32768.0
+		default:
+			return nil, fmt.Errorf("unsupported WAV audio format: 0x%04x", fc.AudioFormat)
+		}
+	}
+
+	return out, nil
+}
+
+// decodePCMSample decodes a single little-endian signed-integer PCM sample
+// (8-bit PCM is the one WAV exception stored as unsigned) into -1.0..1.0.
+func decodePCMSample(b []byte, bitsPerSample uint16) (float32, error) {
+	switch bitsPerSample {
+	case 8:
+		// 8-bit PCM is unsigned, centered at 128.
+		return (float32(b[0]) - 128) / 128.0, nil
+	case 16:
+		v := int16(uint16(b[0]) | uint16(b[1])<<8)
+		return float32(v) / 32768.0, nil
+	case 24:
+		v := int32(uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16)
+		if v&0x800000 != 0 {
+			v |= ^int32(0xFFFFFF) // sign-extend
+		}
+		return float32(v) / 8388608.0, nil
+	case 32:
+		v := int32(uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16 | uint32(b[3])<<24)
+		return float32(v) / 2147483648.0, nil
+	default:
+		return 0, fmt.Errorf("unsupported PCM bit depth: %d", bitsPerSample)
+	}
+}
+
+// decodeALawSample decodes one G.711 A-law byte into a 16-bit linear PCM
+// sample.
+func decodeALawSample(b byte) int16 {
+	b ^= 0x55
+	sign := b & 0x80
+	exponent := (b >> 4) & 0x07
+	mantissa := b & 0x0F
+
+	sample := int32(mantissa) << 4
+	sample += 8
+	if exponent != 0 {
+		sample += 0x100
+		sample <<= exponent - 1
+	}
+	if sign == 0 {
+		sample = -sample
+	}
+	return int16(sample)
+}
+
+// decodeMULawSample decodes one G.711 u-law byte into a 16-bit linear PCM
+// sample.
+func decodeMULawSample(b byte) int16 {
+	b = ^b
+	sign := b & 0x80
+	exponent := (b >> 4) & 0x07
+	mantissa := b & 0x0F
+
+	sample := (int32(mantissa) << 3) + 0x84
+	sample <<= exponent
+	sample -= 0x84
+	if sign != 0 {
+		sample = -sample
+	}
+	return int16(sample)
+}
+
+// ReadWAV reads an entire WAV file into mono float32 samples, handling any
+// format WAVReader understands (PCM, IEEE float, A-law/u-law, and
+// WAVE_FORMAT_EXTENSIBLE), downmixing multi-channel audio by averaging.
+func ReadWAV(filename string) ([]float32, error) {
+	reader, err := NewWAVReader(filename, true)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	samples := make([]float32, 0, int(reader.Duration()*float64(reader.SampleRate())))
+	chunk := make([]float32, 4096)
+	for {
+		n, err := reader.ReadSamples(chunk)
+		samples = append(samples, chunk[:n]...)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return samples, nil
+}
+
+// GetWAVDuration returns a WAV file's duration in seconds by parsing its
+// chunks, rather than assuming a fixed 44-byte header.
+func GetWAVDuration(filename string) (float64, error) {
+	reader, err := NewWAVReader(filename, true)
+	if err != nil {
+		return 0, err
+	}
+	defer reader.Close()
+	return reader.Duration(), nil
+}
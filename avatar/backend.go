@@ -0,0 +1,99 @@
+package avatar
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// BackendConfig is passed to a BackendFactory when AvatarService constructs
+// a backend.
+type BackendConfig struct {
+	// AppDir is the directory passed to AvatarService.Initialize.
+	AppDir string
+	// DataDir is AppDir's "avatars" subdirectory, where generated avatars
+	// and any backend-private state (e.g. a worker's Unix socket) live.
+	DataDir string
+}
+
+// GenerateRequest carries the inputs to a single avatar generation call.
+type GenerateRequest struct {
+	PhotoPath     string
+	TemplatePath  string
+	OutputPath    string
+	ThumbnailPath string
+}
+
+// GenerateResult is returned by a Backend once generation finishes.
+type GenerateResult struct {
+	GLBPath       string
+	ThumbnailPath string
+}
+
+// Backend generates a custom avatar GLB and thumbnail from a photo. Backends
+// are registered by name (see RegisterBackend) and selected via
+// Settings.AvatarBackend, so alternative implementations - a future native
+// Go/ONNX face-landmarker, for instance - can be swapped in without
+// AvatarService knowing which one it's talking to.
+type Backend interface {
+	// Name identifies the backend, matching the string stored in
+	// Settings.AvatarBackend.
+	Name() string
+	// GenerateAvatar runs the full photo -> GLB + thumbnail pipeline.
+	GenerateAvatar(req GenerateRequest) (GenerateResult, error)
+	// Close releases any resources backing the backend, e.g. a long-lived
+	// worker process.
+	Close() error
+}
+
+// BackendFactory constructs a Backend from its configuration. Implementations
+// register a factory under a unique name via RegisterBackend, typically from
+// an init() function in the file that defines them.
+type BackendFactory func(cfg BackendConfig) (Backend, error)
+
+var (
+	backendFactoriesMu sync.Mutex
+	backendFactories   = map[string]BackendFactory{}
+)
+
+// RegisterBackend makes a Backend factory available under name. Panics on a
+// duplicate name, since that can only indicate two backend implementations
+// colliding at compile time.
+func RegisterBackend(name string, factory BackendFactory) {
+	backendFactoriesMu.Lock()
+	defer backendFactoriesMu.Unlock()
+
+	if _, exists := backendFactories[name]; exists {
+		panic(fmt.Sprintf("avatar: backend %q already registered", name))
+	}
+	backendFactories[name] = factory
+}
+
+// ListBackends returns the names of every registered backend, sorted for
+// stable display in a settings UI.
+func ListBackends() []string {
+	backendFactoriesMu.Lock()
+	defer backendFactoriesMu.Unlock()
+
+	names := make([]string, 0, len(backendFactories))
+	for name := range backendFactories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// newBackend builds the named backend, or the default one if name is empty.
+func newBackend(name string, cfg BackendConfig) (Backend, error) {
+	if name == "" {
+		name = DefaultBackendName
+	}
+
+	backendFactoriesMu.Lock()
+	factory, ok := backendFactories[name]
+	backendFactoriesMu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown avatar backend: %s", name)
+	}
+	return factory(cfg)
+}
@@ -1,89 +1,124 @@
 package avatar
 
 import (
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"regexp"
 	"sort"
-	"strings"
+	"strconv"
+	"sync"
 	"time"
+
+	"super-characters/metrics"
 )
 
 // Default Ready Player Me avatar used as template (has Armature, morph targets, skeleton).
 const defaultTemplateURL = "https://models.readyplayer.me/64bfa15f0e72c63d7c3934a6.glb?morphTargets=ARKit,Oculus+Visemes,mouthOpen,mouthSmile,eyesClosed,eyesLookUp,eyesLookDown&textureSizeLimit=1024&textureFormat=png"
 
+// generationScriptVersion is folded into an avatar's content-addressed ID.
+// Bump it whenever the generation pipeline (template, backend script, post
+// processing) changes in a way that should invalidate cached avatars for
+// photos already generated against the old pipeline.
+const generationScriptVersion = "1"
+
+// indexFileName is the manifest of all generated avatars, stored alongside
+// the per-avatar directories in dataDir.
+const indexFileName = "index.json"
+
+// oldIDPattern matches the pre-content-addressing ID scheme
+// ("avatar_<unix-ms>", all digits), used by migrateLegacyLayout to find
+// directories that predate index.json.
+var oldIDPattern = regexp.MustCompile(`^avatar_[0-9]+$`)
+
 // AvatarInfo represents a generated custom avatar.
 type AvatarInfo struct {
-	ID        string `json:"id"`
-	Path      string `json:"path"`
-	Thumbnail string `json:"thumbnail"` // base64 PNG
-	CreatedAt int64  `json:"createdAt"`
+	ID         string `json:"id"`
+	Path       string `json:"path"`
+	Thumbnail  string `json:"thumbnail"` // base64 PNG
+	CreatedAt  int64  `json:"createdAt"`
+	LastUsedAt int64  `json:"lastUsedAt"`
+	SizeBytes  int64  `json:"sizeBytes"`
+}
+
+// Stats summarizes AvatarService's current storage usage.
+type Stats struct {
+	Count     int   `json:"count"`
+	DiskBytes int64 `json:"diskBytes"`
 }
 
-// AvatarService manages custom avatar generation and storage.
+// AvatarService manages custom avatar generation and storage. Avatars are
+// content-addressed: GenerateFromPhoto derives an avatar's ID from the
+// input photo and generation parameters, so regenerating from the same
+// photo returns the cached result instead of re-running the (slow)
+// backend. An in-memory manifest (backed by dataDir/index.json) lets
+// GetAvatars answer without touching the disk; it still copies and sorts
+// the manifest on every call, so its cost grows with the number of avatars.
 type AvatarService struct {
 	dataDir      string // ~/.super-characters/avatars/
-	scriptPath   string // path to generate_avatar.py
-	pythonPath   string // path to venv python binary
 	templatePath string // path to cached template GLB
+	backend      Backend
+	metrics      *metrics.Registry
+
+	maxCount     int
+	maxDiskBytes int64
+
+	mu    sync.RWMutex
+	index map[string]*AvatarInfo
 }
 
 // NewAvatarService creates a new AvatarService.
 func NewAvatarService() *AvatarService {
-	return &AvatarService{}
-}
-
-// Initialize sets up the avatar data directory and locates the Python script and venv.
-func (s *AvatarService) Initialize(appDir string) error {
-	s.dataDir = filepath.Join(appDir, "avatars")
-	if err := os.MkdirAll(s.dataDir, 0755); err != nil {
-		return fmt.Errorf("failed to create avatars directory: %w", err)
-	}
-
-	// Look for the Python script relative to the executable or in common locations
-	candidates := []string{
-		filepath.Join(appDir, "scripts", "generate_avatar.py"),
-		"scripts/generate_avatar.py",
+	return &AvatarService{
+		index: make(map[string]*AvatarInfo),
 	}
+}
 
-	// Also check relative to the working directory
-	if wd, err := os.Getwd(); err == nil {
-		candidates = append(candidates, filepath.Join(wd, "scripts", "generate_avatar.py"))
-	}
+// SetMetrics attaches a metrics.Registry that records
+// avatar_generation_duration_seconds and avatar_generations_total for every
+// GenerateFromPhoto call that gets past the cache lookup; cache hits and
+// photos that fail to decode are not recorded. Passing nil disables metrics
+// recording.
+func (s *AvatarService) SetMetrics(m *metrics.Registry) {
+	s.metrics = m
+}
 
-	for _, path := range candidates {
-		if _, err := os.Stat(path); err == nil {
-			s.scriptPath = path
-			slog.Info("[Avatar] Found script", "path", path)
-			break
-		}
-	}
+// SetRetentionLimits configures eviction thresholds (see
+// Settings.AvatarMaxCount / Settings.AvatarMaxDiskBytes). Zero or negative
+// means no limit on that dimension.
+func (s *AvatarService) SetRetentionLimits(maxCount int, maxDiskBytes int64) {
+	s.mu.Lock()
+	s.maxCount = maxCount
+	s.maxDiskBytes = maxDiskBytes
+	s.mu.Unlock()
 
-	if s.scriptPath == "" {
-		slog.Warn("[Avatar] generate_avatar.py not found, avatar generation will be unavailable")
-	}
+	s.enforceRetention()
+}
 
-	// Locate the venv python binary next to the script
-	if s.scriptPath != "" {
-		scriptDir := filepath.Dir(s.scriptPath)
-		venvPython := filepath.Join(scriptDir, ".venv", "bin", "python3")
-		if _, err := os.Stat(venvPython); err == nil {
-			s.pythonPath = venvPython
-			slog.Info("[Avatar] Using venv Python", "path", venvPython)
-		}
+// Initialize sets up the avatar data directory, loads (or migrates) the
+// avatar manifest, and starts the generation backend named by backendName
+// (see Settings.AvatarBackend); an empty backendName selects
+// DefaultBackendName. The backend stays alive for the lifetime of the
+// service - see Close.
+func (s *AvatarService) Initialize(appDir string, backendName string) error {
+	s.dataDir = filepath.Join(appDir, "avatars")
+	if err := os.MkdirAll(s.dataDir, 0755); err != nil {
+		return fmt.Errorf("failed to create avatars directory: %w", err)
 	}
 
-	// Fallback to system python3
-	if s.pythonPath == "" {
-		if p, err := exec.LookPath("python3"); err == nil {
-			s.pythonPath = p
-			slog.Info("[Avatar] Using system Python", "path", p)
-		}
+	backend, err := newBackend(backendName, BackendConfig{AppDir: appDir, DataDir: s.dataDir})
+	if err != nil {
+		slog.Warn("[Avatar] Backend unavailable, avatar generation will be unavailable", "backend", backendName, "error", err)
+	} else {
+		s.backend = backend
+		slog.Info("[Avatar] Backend started", "backend", backend.Name())
 	}
 
 	// Download and cache the template GLB if not present
@@ -98,99 +133,102 @@ func (s *AvatarService) Initialize(appDir string) error {
 		}
 	}
 
-	slog.Info("[Avatar] Service initialized", "dataDir", s.dataDir)
-	return nil
-}
-
-// GenerateFromPhoto takes a base64-encoded photo, generates a custom avatar GLB,
-// and returns the avatar info.
-func (s *AvatarService) GenerateFromPhoto(photoBase64 string) (*AvatarInfo, error) {
-	if s.scriptPath == "" {
-		return nil, fmt.Errorf("avatar generation script not found")
+	if err := s.loadIndex(); err != nil {
+		return fmt.Errorf("failed to load avatar index: %w", err)
 	}
-	if s.pythonPath == "" {
-		return nil, fmt.Errorf("python3 not found")
+	if err := s.migrateLegacyLayout(); err != nil {
+		slog.Warn("[Avatar] Legacy layout migration incomplete", "error", err)
 	}
 
-	// Create unique ID based on timestamp
-	id := fmt.Sprintf("avatar_%d", time.Now().UnixMilli())
-	avatarDir := filepath.Join(s.dataDir, id)
-	if err := os.MkdirAll(avatarDir, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create avatar directory: %w", err)
+	slog.Info("[Avatar] Service initialized", "dataDir", s.dataDir, "avatars", len(s.index))
+	return nil
+}
+
+// Close stops the active backend (e.g. the long-lived Python worker
+// process). Safe to call even if Initialize's backend failed to start.
+func (s *AvatarService) Close() error {
+	if s.backend == nil {
+		return nil
 	}
+	return s.backend.Close()
+}
 
-	// Decode and save the photo
-	photoData, err := base64.StdEncoding.DecodeString(photoBase64)
+// loadIndex reads dataDir/index.json into memory, if present.
+func (s *AvatarService) loadIndex() error {
+	data, err := os.ReadFile(filepath.Join(s.dataDir, indexFileName))
 	if err != nil {
-		os.RemoveAll(avatarDir)
-		return nil, fmt.Errorf("failed to decode photo: %w", err)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
 	}
 
-	photoPath := filepath.Join(avatarDir, "photo.jpg")
-	if err := os.WriteFile(photoPath, photoData, 0644); err != nil {
-		os.RemoveAll(avatarDir)
-		return nil, fmt.Errorf("failed to write photo: %w", err)
+	var entries []*AvatarInfo
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("failed to parse index: %w", err)
 	}
 
-	outputPath := filepath.Join(avatarDir, "avatar.glb")
-	thumbnailPath := filepath.Join(avatarDir, "thumbnail.png")
-
-	// Run the Python script
-	args := []string{
-		s.scriptPath,
-		"--input", photoPath,
-		"--output", outputPath,
-		"--thumbnail", thumbnailPath,
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, entry := range entries {
+		s.index[entry.ID] = entry
 	}
-	if s.templatePath != "" {
-		args = append(args, "--template", s.templatePath)
+	return nil
+}
+
+// persistIndexLocked writes the in-memory manifest to dataDir/index.json
+// atomically (write to a temp file, then rename over the real path), so a
+// crash mid-write can't corrupt the manifest. Caller must hold s.mu.
+func (s *AvatarService) persistIndexLocked() error {
+	entries := make([]*AvatarInfo, 0, len(s.index))
+	for _, entry := range s.index {
+		entries = append(entries, entry)
 	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].CreatedAt > entries[j].CreatedAt })
 
-	slog.Info("[Avatar] Running generation script", "id", id)
-	cmd := exec.Command(s.pythonPath, args...)
-	output, err := cmd.CombinedOutput()
+	data, err := json.MarshalIndent(entries, "", "  ")
 	if err != nil {
-		slog.Error("[Avatar] Script failed", "error", err, "output", string(output))
-		os.RemoveAll(avatarDir)
-		return nil, fmt.Errorf("avatar generation failed: %s", string(output))
+		return fmt.Errorf("failed to marshal index: %w", err)
 	}
-	slog.Info("[Avatar] Generation complete", "id", id, "output", string(output))
 
-	// Read thumbnail as base64
-	thumbnailBase64 := ""
-	if thumbData, err := os.ReadFile(thumbnailPath); err == nil {
-		thumbnailBase64 = base64.StdEncoding.EncodeToString(thumbData)
+	indexPath := filepath.Join(s.dataDir, indexFileName)
+	tmpPath := indexPath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write index: %w", err)
 	}
-
-	return &AvatarInfo{
-		ID:        id,
-		Path:      outputPath,
-		Thumbnail: thumbnailBase64,
-		CreatedAt: time.Now().Unix(),
-	}, nil
+	if err := os.Rename(tmpPath, indexPath); err != nil {
+		return fmt.Errorf("failed to finalize index: %w", err)
+	}
+	return nil
 }
 
-// GetAvatars returns all saved custom avatars.
-func (s *AvatarService) GetAvatars() []AvatarInfo {
-	var avatars []AvatarInfo
-
+// migrateLegacyLayout finds avatar directories created before this service
+// tracked an index.json (keyed "avatar_<unix-ms>") and adds a manifest
+// entry for each one not already indexed, so they remain visible through
+// GetAvatars. It deliberately leaves the directories under their old names
+// rather than rehashing them to new content-addressed IDs, since the
+// original photo may no longer be present to hash.
+func (s *AvatarService) migrateLegacyLayout() error {
 	entries, err := os.ReadDir(s.dataDir)
 	if err != nil {
-		slog.Warn("[Avatar] Failed to read avatars directory", "error", err)
-		return avatars
+		return fmt.Errorf("failed to read avatars directory: %w", err)
 	}
 
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	migrated := 0
 	for _, entry := range entries {
-		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), "avatar_") {
+		if !entry.IsDir() || !oldIDPattern.MatchString(entry.Name()) {
 			continue
 		}
-
 		id := entry.Name()
+		if _, ok := s.index[id]; ok {
+			continue
+		}
+
 		avatarDir := filepath.Join(s.dataDir, id)
 		glbPath := filepath.Join(avatarDir, "avatar.glb")
-		thumbnailPath := filepath.Join(avatarDir, "thumbnail.png")
-
-		// Check that GLB exists
 		if _, err := os.Stat(glbPath); err != nil {
 			continue
 		}
@@ -200,59 +238,271 @@ func (s *AvatarService) GetAvatars() []AvatarInfo {
 			continue
 		}
 
-		// Read thumbnail
 		thumbnailBase64 := ""
-		if thumbData, err := os.ReadFile(thumbnailPath); err == nil {
+		if thumbData, err := os.ReadFile(filepath.Join(avatarDir, "thumbnail.png")); err == nil {
 			thumbnailBase64 = base64.StdEncoding.EncodeToString(thumbData)
 		}
 
-		avatars = append(avatars, AvatarInfo{
-			ID:        id,
-			Path:      glbPath,
-			Thumbnail: thumbnailBase64,
-			CreatedAt: info.ModTime().Unix(),
-		})
+		s.index[id] = &AvatarInfo{
+			ID:         id,
+			Path:       glbPath,
+			Thumbnail:  thumbnailBase64,
+			CreatedAt:  info.ModTime().Unix(),
+			LastUsedAt: info.ModTime().Unix(),
+			SizeBytes:  dirSize(avatarDir),
+		}
+		migrated++
+	}
+
+	if migrated == 0 {
+		return nil
 	}
+	slog.Info("[Avatar] Migrated legacy avatars into index", "count", migrated)
+	return s.persistIndexLocked()
+}
+
+// GenerateFromPhoto takes a base64-encoded photo, generates a custom avatar
+// GLB, and returns the avatar info. The avatar's ID is derived from the
+// photo and the active generation parameters, so calling this again with
+// the same photo returns the cached result without invoking the backend.
+func (s *AvatarService) GenerateFromPhoto(photoBase64 string) (avatarInfo *AvatarInfo, err error) {
+	backendName := "none"
+	if s.backend != nil {
+		backendName = s.backend.Name()
+	}
+
+	photoData, decodeErr := base64.StdEncoding.DecodeString(photoBase64)
+	if decodeErr != nil {
+		return nil, fmt.Errorf("failed to decode photo: %w", decodeErr)
+	}
+	id := contentAddressedID(photoData, s.templatePath)
 
-	// Sort by creation time, newest first
+	if cached, ok := s.touchCached(id); ok {
+		return cached, nil
+	}
+
+	if s.metrics != nil {
+		start := time.Now()
+		defer func() {
+			success := strconv.FormatBool(err == nil)
+			s.metrics.ObserveHistogram("avatar_generation_duration_seconds", time.Since(start).Seconds(), backendName, success)
+			s.metrics.IncCounter("avatar_generations_total", backendName, success)
+		}()
+	}
+
+	if s.backend == nil {
+		return nil, fmt.Errorf("avatar backend not available")
+	}
+
+	avatarDir := filepath.Join(s.dataDir, id)
+	if err := os.MkdirAll(avatarDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create avatar directory: %w", err)
+	}
+
+	photoPath := filepath.Join(avatarDir, "photo.jpg")
+	if err := os.WriteFile(photoPath, photoData, 0644); err != nil {
+		os.RemoveAll(avatarDir)
+		return nil, fmt.Errorf("failed to write photo: %w", err)
+	}
+
+	outputPath := filepath.Join(avatarDir, "avatar.glb")
+	thumbnailPath := filepath.Join(avatarDir, "thumbnail.png")
+
+	slog.Info("[Avatar] Generating avatar", "id", id, "backend", s.backend.Name())
+	result, err := s.backend.GenerateAvatar(GenerateRequest{
+		PhotoPath:     photoPath,
+		TemplatePath:  s.templatePath,
+		OutputPath:    outputPath,
+		ThumbnailPath: thumbnailPath,
+	})
+	if err != nil {
+		slog.Error("[Avatar] Generation failed", "id", id, "error", err)
+		os.RemoveAll(avatarDir)
+		return nil, fmt.Errorf("avatar generation failed: %w", err)
+	}
+	slog.Info("[Avatar] Generation complete", "id", id)
+
+	// Read thumbnail as base64
+	thumbnailBase64 := ""
+	if thumbData, err := os.ReadFile(result.ThumbnailPath); err == nil {
+		thumbnailBase64 = base64.StdEncoding.EncodeToString(thumbData)
+	}
+
+	now := time.Now().Unix()
+	info := &AvatarInfo{
+		ID:         id,
+		Path:       result.GLBPath,
+		Thumbnail:  thumbnailBase64,
+		CreatedAt:  now,
+		LastUsedAt: now,
+		SizeBytes:  dirSize(avatarDir),
+	}
+
+	s.mu.Lock()
+	s.index[id] = info
+	persistErr := s.persistIndexLocked()
+	s.mu.Unlock()
+	if persistErr != nil {
+		slog.Warn("[Avatar] Failed to persist index", "error", persistErr)
+	}
+
+	s.enforceRetention()
+	return info, nil
+}
+
+// touchCached returns the indexed AvatarInfo for id with LastUsedAt bumped
+// to now, if one already exists.
+func (s *AvatarService) touchCached(id string) (*AvatarInfo, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.index[id]
+	if !ok {
+		return nil, false
+	}
+	existing.LastUsedAt = time.Now().Unix()
+	if err := s.persistIndexLocked(); err != nil {
+		slog.Warn("[Avatar] Failed to persist index", "error", err)
+	}
+	cached := *existing
+	return &cached, true
+}
+
+// GetAvatars returns all saved custom avatars from the in-memory manifest,
+// newest first.
+func (s *AvatarService) GetAvatars() []AvatarInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	avatars := make([]AvatarInfo, 0, len(s.index))
+	for _, info := range s.index {
+		avatars = append(avatars, *info)
+	}
 	sort.Slice(avatars, func(i, j int) bool {
 		return avatars[i].CreatedAt > avatars[j].CreatedAt
 	})
-
 	return avatars
 }
 
+// Stats returns the current avatar count and total disk usage.
+func (s *AvatarService) Stats() Stats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stats := Stats{Count: len(s.index)}
+	for _, info := range s.index {
+		stats.DiskBytes += info.SizeBytes
+	}
+	return stats
+}
+
 // DeleteAvatar removes a custom avatar by ID.
 func (s *AvatarService) DeleteAvatar(id string) error {
-	if !strings.HasPrefix(id, "avatar_") {
-		return fmt.Errorf("invalid avatar ID")
-	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.deleteLocked(id)
+}
 
-	avatarDir := filepath.Join(s.dataDir, id)
-	if _, err := os.Stat(avatarDir); err != nil {
+// deleteLocked removes id's directory and manifest entry. Caller must hold
+// s.mu.
+func (s *AvatarService) deleteLocked(id string) error {
+	if _, ok := s.index[id]; !ok {
 		return fmt.Errorf("avatar not found: %s", id)
 	}
 
-	if err := os.RemoveAll(avatarDir); err != nil {
+	if err := os.RemoveAll(filepath.Join(s.dataDir, id)); err != nil {
 		return fmt.Errorf("failed to delete avatar: %w", err)
 	}
+	delete(s.index, id)
+	if err := s.persistIndexLocked(); err != nil {
+		return fmt.Errorf("failed to persist index: %w", err)
+	}
 
 	slog.Info("[Avatar] Deleted avatar", "id", id)
 	return nil
 }
 
-// GetAvatarPath returns the GLB file path for an avatar ID.
-func (s *AvatarService) GetAvatarPath(id string) (string, error) {
-	if !strings.HasPrefix(id, "avatar_") {
-		return "", fmt.Errorf("invalid avatar ID")
+// enforceRetention evicts the oldest-unused avatars until both
+// maxCount and maxDiskBytes are satisfied. No-op when both are <= 0.
+func (s *AvatarService) enforceRetention() {
+	s.mu.Lock()
+	maxCount, maxDiskBytes := s.maxCount, s.maxDiskBytes
+	if maxCount <= 0 && maxDiskBytes <= 0 {
+		s.mu.Unlock()
+		return
 	}
 
-	glbPath := filepath.Join(s.dataDir, id, "avatar.glb")
-	if _, err := os.Stat(glbPath); err != nil {
+	entries := make([]*AvatarInfo, 0, len(s.index))
+	var totalBytes int64
+	for _, info := range s.index {
+		entries = append(entries, info)
+		totalBytes += info.SizeBytes
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].LastUsedAt < entries[j].LastUsedAt })
+
+	var toEvict []string
+	for _, info := range entries {
+		overCount := maxCount > 0 && len(entries)-len(toEvict) > maxCount
+		overBytes := maxDiskBytes > 0 && totalBytes > maxDiskBytes
+		if !overCount && !overBytes {
+			break
+		}
+		toEvict = append(toEvict, info.ID)
+		totalBytes -= info.SizeBytes
+	}
+	s.mu.Unlock()
+
+	for _, id := range toEvict {
+		s.mu.Lock()
+		err := s.deleteLocked(id)
+		s.mu.Unlock()
+		if err != nil {
+			slog.Warn("[Avatar] Failed to evict avatar", "id", id, "error", err)
+		} else {
+			slog.Info("[Avatar] Evicted avatar over retention limit", "id", id)
+		}
+	}
+}
+
+// GetAvatarPath returns the GLB file path for an avatar ID.
+func (s *AvatarService) GetAvatarPath(id string) (string, error) {
+	s.mu.RLock()
+	info, ok := s.index[id]
+	s.mu.RUnlock()
+	if !ok {
 		return "", fmt.Errorf("avatar not found: %s", id)
 	}
+	return info.Path, nil
+}
 
-	return glbPath, nil
+// contentAddressedID derives a stable avatar ID from the input photo and
+// the current generation parameters (template + pipeline version), so
+// regenerating from the same photo against the same pipeline reuses the
+// same ID.
+func contentAddressedID(photoData []byte, templatePath string) string {
+	h := sha256.New()
+	h.Write(photoData)
+	h.Write([]byte(templatePath))
+	h.Write([]byte(generationScriptVersion))
+	return "avatar_" + hex.EncodeToString(h.Sum(nil))[:24]
+}
+
+// dirSize sums the size of every regular file under dir.
+func dirSize(dir string) int64 {
+	var total int64
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		if info, err := entry.Info(); err == nil {
+			total += info.Size()
+		}
+	}
+	return total
 }
 
 // downloadFile fetches a URL and saves it to the given path.
@@ -280,26 +530,23 @@ func downloadFile(url, destPath string) error {
 	return nil
 }
 
-// CheckPythonDependencies verifies that the venv Python and required packages are available.
+// CheckPythonDependencies verifies that the active backend's Python
+// interpreter and required packages are available, without starting the
+// (slow) worker itself.
 func (s *AvatarService) CheckPythonDependencies() error {
-	if s.pythonPath == "" {
-		return fmt.Errorf("python3 not found")
-	}
-
-	// Check required packages using find_spec (avoids triggering broken transitive imports)
-	packages := []string{"mediapipe", "cv2", "numpy", "PIL", "pygltflib"}
-	var missing []string
-	for _, pkg := range packages {
-		check := fmt.Sprintf("import importlib.util; exit(0 if importlib.util.find_spec('%s') else 1)", pkg)
-		cmd := exec.Command(s.pythonPath, "-c", check)
-		if err := cmd.Run(); err != nil {
-			missing = append(missing, pkg)
-		}
+	if s.backend == nil {
+		return fmt.Errorf("avatar backend not available")
 	}
-
-	if len(missing) > 0 {
-		return fmt.Errorf("missing Python packages: %s", strings.Join(missing, ", "))
+	checker, ok := s.backend.(interface{ CheckDependencies() error })
+	if !ok {
+		return nil
 	}
+	return checker.CheckDependencies()
+}
 
-	return nil
+// ListBackends returns the names of every registered avatar backend, for
+// populating a settings picker.
+func (s *AvatarService) ListBackends() []string {
+	return ListBackends()
 }
+
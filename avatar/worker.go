@@ -0,0 +1,266 @@
+package avatar
+
+import (
+	"fmt"
+	"log/slog"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+func init() {
+	RegisterBackend(DefaultBackendName, newWorkerBackend)
+}
+
+// DefaultBackendName is the backend selected when Settings.AvatarBackend is
+// empty, and the only one shipped today.
+const DefaultBackendName = "mediapipe-worker"
+
+// workerScriptName is the long-lived worker script, looked up the same way
+// the predecessor per-call implementation looked up generate_avatar.py.
+const workerScriptName = "avatar_worker.py"
+
+// workerDialTimeout bounds how long we wait for the freshly spawned worker
+// to start listening on its socket (it pays MediaPipe/OpenCV's import cost
+// once here, which is the whole reason this backend exists).
+const workerDialTimeout = 30 * time.Second
+
+// workerRestartDelay avoids a hot crash loop pegging a CPU core if the
+// worker keeps dying immediately (e.g. a missing model file).
+const workerRestartDelay = 2 * time.Second
+
+// pythonWorkerBackend keeps a single "python3 avatar_worker.py" process
+// alive across calls, talking to it over a Unix domain socket. This is the
+// long-lived counterpart to the old per-call exec.Command(generate_avatar.py):
+// the worker pays its import cost once, not on every GenerateFromPhoto call,
+// and a crash is detected and restarted rather than surfacing as the next
+// call's error.
+//
+// The wire format is net/rpc's JSON codec rather than gRPC: this module has
+// no go.mod to vendor google.golang.org/grpc into, and no protoc available
+// to generate stubs from avatar/proto/avatar_backend.proto. That proto file
+// documents the service contract implemented here; regenerate real gRPC
+// stubs from it and swap this backend's transport once the build gains
+// dependency management.
+type pythonWorkerBackend struct {
+	mu         sync.Mutex
+	cmd        *exec.Cmd
+	client     *rpc.Client
+	socketPath string
+	scriptPath string
+	pythonPath string
+	closed     bool
+}
+
+func newWorkerBackend(cfg BackendConfig) (Backend, error) {
+	scriptPath, err := findWorkerScript(cfg.AppDir)
+	if err != nil {
+		return nil, err
+	}
+	pythonPath := findWorkerPython(scriptPath)
+	if pythonPath == "" {
+		return nil, fmt.Errorf("python3 not found")
+	}
+	if cfg.DataDir == "" {
+		return nil, fmt.Errorf("avatar data directory not set")
+	}
+
+	b := &pythonWorkerBackend{
+		socketPath: filepath.Join(cfg.DataDir, "avatar_worker.sock"),
+		scriptPath: scriptPath,
+		pythonPath: pythonPath,
+	}
+	if err := b.spawn(); err != nil {
+		return nil, err
+	}
+	go b.monitor()
+	return b, nil
+}
+
+// Name identifies this backend in Settings.AvatarBackend.
+func (b *pythonWorkerBackend) Name() string { return DefaultBackendName }
+
+// spawn launches the worker process and dials its Unix socket, retrying the
+// dial since the process needs a moment to import MediaPipe and start
+// listening.
+func (b *pythonWorkerBackend) spawn() error {
+	os.Remove(b.socketPath)
+
+	cmd := exec.Command(b.pythonPath, b.scriptPath, "--socket", b.socketPath)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start avatar worker: %w", err)
+	}
+
+	conn, err := dialWorkerSocket(b.socketPath, workerDialTimeout)
+	if err != nil {
+		cmd.Process.Kill()
+		return fmt.Errorf("avatar worker did not start listening: %w", err)
+	}
+
+	b.mu.Lock()
+	b.cmd = cmd
+	b.client = jsonrpc.NewClient(conn)
+	b.mu.Unlock()
+
+	slog.Info("[Avatar] Worker started", "pid", cmd.Process.Pid, "socket", b.socketPath)
+	return nil
+}
+
+func dialWorkerSocket(socketPath string, timeout time.Duration) (net.Conn, error) {
+	deadline := time.Now().Add(timeout)
+	var lastErr error
+	for time.Now().Before(deadline) {
+		conn, err := net.Dial("unix", socketPath)
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+		time.Sleep(100 * time.Millisecond)
+	}
+	return nil, lastErr
+}
+
+// monitor respawns the worker if its process exits, until Close is called.
+func (b *pythonWorkerBackend) monitor() {
+	for {
+		b.mu.Lock()
+		cmd := b.cmd
+		b.mu.Unlock()
+		if cmd == nil {
+			return
+		}
+
+		waitErr := cmd.Wait()
+
+		b.mu.Lock()
+		closed := b.closed
+		b.mu.Unlock()
+		if closed {
+			return
+		}
+
+		slog.Warn("[Avatar] Worker exited unexpectedly, restarting", "error", waitErr)
+		time.Sleep(workerRestartDelay)
+
+		if err := b.spawn(); err != nil {
+			slog.Error("[Avatar] Failed to restart avatar worker", "error", err)
+			return
+		}
+	}
+}
+
+// workerGenerateArgs/workerGenerateReply mirror GenerateAvatarRequest and
+// GenerateAvatarResponse in avatar/proto/avatar_backend.proto.
+type workerGenerateArgs struct {
+	PhotoPath     string `json:"photo_path"`
+	TemplatePath  string `json:"template_path"`
+	OutputPath    string `json:"output_path"`
+	ThumbnailPath string `json:"thumbnail_path"`
+}
+
+type workerGenerateReply struct {
+	GLBPath       string `json:"glb_path"`
+	ThumbnailPath string `json:"thumbnail_path"`
+}
+
+// GenerateAvatar calls the worker's AvatarBackend.GenerateAvatar method.
+func (b *pythonWorkerBackend) GenerateAvatar(req GenerateRequest) (GenerateResult, error) {
+	b.mu.Lock()
+	client := b.client
+	b.mu.Unlock()
+	if client == nil {
+		return GenerateResult{}, fmt.Errorf("avatar worker is not running")
+	}
+
+	args := workerGenerateArgs{
+		PhotoPath:     req.PhotoPath,
+		TemplatePath:  req.TemplatePath,
+		OutputPath:    req.OutputPath,
+		ThumbnailPath: req.ThumbnailPath,
+	}
+	var reply workerGenerateReply
+	if err := client.Call("AvatarBackend.GenerateAvatar", args, &reply); err != nil {
+		return GenerateResult{}, fmt.Errorf("avatar generation failed: %w", err)
+	}
+	return GenerateResult{GLBPath: reply.GLBPath, ThumbnailPath: reply.ThumbnailPath}, nil
+}
+
+// CheckDependencies verifies the worker's Python interpreter has the
+// packages avatar_worker.py needs, without starting the (slow) worker
+// itself. AvatarService.CheckPythonDependencies type-asserts for this.
+func (b *pythonWorkerBackend) CheckDependencies() error {
+	packages := []string{"mediapipe", "cv2", "numpy", "PIL", "pygltflib"}
+	var missing []string
+	for _, pkg := range packages {
+		check := fmt.Sprintf("import importlib.util; exit(0 if importlib.util.find_spec('%s') else 1)", pkg)
+		cmd := exec.Command(b.pythonPath, "-c", check)
+		if err := cmd.Run(); err != nil {
+			missing = append(missing, pkg)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing Python packages: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// Close stops the worker process and releases its socket.
+func (b *pythonWorkerBackend) Close() error {
+	b.mu.Lock()
+	b.closed = true
+	client := b.client
+	cmd := b.cmd
+	b.mu.Unlock()
+
+	if client != nil {
+		client.Close()
+	}
+	if cmd != nil && cmd.Process != nil {
+		cmd.Process.Kill()
+		cmd.Wait()
+	}
+	os.Remove(b.socketPath)
+	return nil
+}
+
+// findWorkerScript locates avatar_worker.py the same way the predecessor
+// per-call implementation located generate_avatar.py: relative to the app
+// directory, the working directory, or a "scripts/" subdirectory of either.
+func findWorkerScript(appDir string) (string, error) {
+	var candidates []string
+	if appDir != "" {
+		candidates = append(candidates, filepath.Join(appDir, "scripts", workerScriptName))
+	}
+	candidates = append(candidates, filepath.Join("scripts", workerScriptName))
+	if wd, err := os.Getwd(); err == nil {
+		candidates = append(candidates, filepath.Join(wd, "scripts", workerScriptName))
+	}
+
+	for _, path := range candidates {
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("%s not found", workerScriptName)
+}
+
+// findWorkerPython prefers a venv next to the script, falling back to the
+// system python3.
+func findWorkerPython(scriptPath string) string {
+	venvPython := filepath.Join(filepath.Dir(scriptPath), ".venv", "bin", "python3")
+	if _, err := os.Stat(venvPython); err == nil {
+		return venvPython
+	}
+	if p, err := exec.LookPath("python3"); err == nil {
+		return p
+	}
+	return ""
+}
@@ -0,0 +1,192 @@
+package characters
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// eventBufferSize bounds each subscriber's channel. It's small: a subscriber
+// only ever cares about the latest pipeline status, so dropping stale
+// events in favor of fresher ones (see subscriber.send) is preferable to
+// growing the buffer.
+const eventBufferSize = 16
+
+// Event describes a single pipeline status transition for a character.
+type Event struct {
+	ID              string         `json:"id"`
+	OldStatus       PipelineStatus `json:"oldStatus,omitempty"`
+	NewStatus       PipelineStatus `json:"newStatus,omitempty"`
+	Error           string         `json:"error,omitempty"`
+	UpdatedAt       time.Time      `json:"updatedAt"`
+	ResourceVersion uint64         `json:"resourceVersion"`
+}
+
+// subscriber is one Subscribe/SubscribeAll registration. An empty id means
+// the subscriber wants events for every character.
+type subscriber struct {
+	id string
+	ch chan Event
+}
+
+// send delivers ev to the subscriber, dropping the oldest queued event
+// instead of blocking when the channel is full - a slow SSE client must
+// never stall the pipeline goroutine that's publishing the event.
+func (sub *subscriber) send(ev Event) {
+	select {
+	case sub.ch <- ev:
+		return
+	default:
+	}
+	select {
+	case <-sub.ch:
+	default:
+	}
+	select {
+	case sub.ch <- ev:
+	default:
+	}
+}
+
+// Subscribe returns a channel of pipeline events for a single character,
+// plus an unsubscribe func the caller must call when done listening.
+func (s *Service) Subscribe(id string) (<-chan Event, func()) {
+	return s.subscribe(id)
+}
+
+// SubscribeAll returns a channel of pipeline events for every character,
+// plus an unsubscribe func the caller must call when done listening.
+func (s *Service) SubscribeAll() (<-chan Event, func()) {
+	return s.subscribe("")
+}
+
+func (s *Service) subscribe(id string) (<-chan Event, func()) {
+	sub := &subscriber{id: id, ch: make(chan Event, eventBufferSize)}
+
+	s.subMu.Lock()
+	s.subscribers = append(s.subscribers, sub)
+	s.subMu.Unlock()
+
+	unsubscribe := func() {
+		s.subMu.Lock()
+		defer s.subMu.Unlock()
+		for i, existing := range s.subscribers {
+			if existing == sub {
+				s.subscribers = append(s.subscribers[:i], s.subscribers[i+1:]...)
+				break
+			}
+		}
+	}
+	return sub.ch, unsubscribe
+}
+
+// publish fans ev out to every subscriber watching its character, and to
+// every SubscribeAll subscriber.
+func (s *Service) publish(ev Event) {
+	s.subMu.RLock()
+	defer s.subMu.RUnlock()
+	for _, sub := range s.subscribers {
+		if sub.id != "" && sub.id != ev.ID {
+			continue
+		}
+		sub.send(ev)
+	}
+}
+
+// snapshotEvents returns the current status of id as an Event, or of every
+// character when id is empty - used to seed a freshly-opened SSE stream so
+// a client doesn't have to wait for the next transition to see where a
+// character's pipeline currently stands.
+func (s *Service) snapshotEvents(id string) []Event {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if id != "" {
+		char, ok := s.characters[id]
+		if !ok {
+			return nil
+		}
+		return []Event{eventFromCharacter(char)}
+	}
+
+	events := make([]Event, 0, len(s.characters))
+	for _, char := range s.characters {
+		events = append(events, eventFromCharacter(char))
+	}
+	return events
+}
+
+func eventFromCharacter(c *CustomCharacter) Event {
+	return Event{
+		ID:              c.ID,
+		NewStatus:       c.Status,
+		Error:           c.Error,
+		UpdatedAt:       c.UpdatedAt,
+		ResourceVersion: c.ResourceVersion,
+	}
+}
+
+// SSEHandler returns an http.Handler that streams pipeline events as
+// Server-Sent Events. With no "id" query parameter it streams every
+// character's transitions; with one, only that character's. A freshly
+// connected client first receives the current snapshot, then forwards
+// subsequent transitions until the request context is canceled.
+func (s *Service) SSEHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+			return
+		}
+
+		id := r.URL.Query().Get("id")
+
+		var ch <-chan Event
+		var unsubscribe func()
+		if id != "" {
+			ch, unsubscribe = s.Subscribe(id)
+		} else {
+			ch, unsubscribe = s.SubscribeAll()
+		}
+		defer unsubscribe()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.WriteHeader(http.StatusOK)
+
+		writeEvent := func(ev Event) bool {
+			body, err := json.Marshal(ev)
+			if err != nil {
+				return true
+			}
+			if _, err := fmt.Fprintf(w, "data: %s\n\n", body); err != nil {
+				return false
+			}
+			flusher.Flush()
+			return true
+		}
+
+		for _, ev := range s.snapshotEvents(id) {
+			if !writeEvent(ev) {
+				return
+			}
+		}
+
+		ctx := r.Context()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case ev, ok := <-ch:
+				if !ok {
+					return
+				}
+				if !writeEvent(ev) {
+					return
+				}
+			}
+		}
+	})
+}
@@ -2,12 +2,15 @@ package characters
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"sync"
 	"time"
+
+	"super-characters/logging"
 )
 
 // CustomCharacter represents a user-created character with its metadata.
@@ -32,6 +35,11 @@ type CustomCharacter struct {
 	Error     string         `json:"error,omitempty"`
 	CreatedAt time.Time      `json:"createdAt"`
 	UpdatedAt time.Time      `json:"updatedAt"`
+
+	// ResourceVersion increases by one on every successful update - see
+	// Service.update. Concurrent pipeline workers use it to detect that
+	// they're about to clobber a peer's write instead of silently doing so.
+	ResourceVersion uint64 `json:"resourceVersion"`
 }
 
 // PipelineStatus tracks the character creation pipeline progress.
@@ -47,11 +55,24 @@ const (
 	StatusBasic      PipelineStatus = "basic"        // Basic character (no 3D pipeline, uses default avatar)
 )
 
+// maxUpdateAttempts bounds the CAS retry loop in update before giving up -
+// see update.
+const maxUpdateAttempts = 5
+
+// ErrVersionConflict is returned by update (and so by Update and the Set*
+// helpers) when a character couldn't be CAS-swapped within maxUpdateAttempts
+// because peers kept advancing its ResourceVersion first.
+var ErrVersionConflict = errors.New("characters: resource version conflict")
+
 // Service manages custom character storage and retrieval.
 type Service struct {
 	dataDir    string
 	characters map[string]*CustomCharacter
 	mu         sync.RWMutex
+	log        logging.Logger
+
+	subMu       sync.RWMutex
+	subscribers []*subscriber
 }
 
 // NewService creates a new character service with storage at ~/.super-characters/characters/
@@ -69,6 +90,7 @@ func NewService() (*Service, error) {
 	s := &Service{
 		dataDir:    dataDir,
 		characters: make(map[string]*CustomCharacter),
+		log:        logging.Discard,
 	}
 
 	if err := s.loadAll(); err != nil {
@@ -78,6 +100,17 @@ func NewService() (*Service, error) {
 	return s, nil
 }
 
+// SetLogger configures where Service's pipeline stage-transition events go
+// - it defaults to logging.Discard, so this is opt-in.
+func (s *Service) SetLogger(l logging.Logger) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if l == nil {
+		l = logging.Discard
+	}
+	s.log = l
+}
+
 // characterDir returns the directory for a specific character's files.
 func (s *Service) characterDir(id string) string {
 	return filepath.Join(s.dataDir, id)
@@ -149,7 +182,17 @@ func (s *Service) Create(char *CustomCharacter) error {
 	s.characters[char.ID] = char
 	s.mu.Unlock()
 
-	return s.saveIndex()
+	if err := s.saveIndex(); err != nil {
+		return err
+	}
+	s.publish(Event{
+		ID:              char.ID,
+		NewStatus:       char.Status,
+		Error:           char.Error,
+		UpdatedAt:       char.UpdatedAt,
+		ResourceVersion: char.ResourceVersion,
+	})
+	return nil
 }
 
 // Get returns a character by ID.
@@ -176,27 +219,82 @@ func (s *Service) List() []*CustomCharacter {
 	return chars
 }
 
-// Update modifies an existing character's metadata.
-func (s *Service) Update(char *CustomCharacter) error {
-	s.mu.Lock()
-	existing, ok := s.characters[char.ID]
-	if !ok {
+// update performs a compare-and-swap update of the character identified by
+// id: it hands tryUpdate a private copy of the current record, and only
+// installs the result if ResourceVersion hasn't changed underneath it since
+// the copy was taken. On a conflict - a concurrent pipeline stage having
+// written its own update in between - it re-reads and retries tryUpdate,
+// bounded by maxUpdateAttempts, so stage workers converge instead of
+// clobbering each other's fields.
+func (s *Service) update(id string, tryUpdate func(*CustomCharacter) error) error {
+	for attempt := 0; attempt < maxUpdateAttempts; attempt++ {
+		s.mu.Lock()
+		existing, ok := s.characters[id]
+		if !ok {
+			s.mu.Unlock()
+			return fmt.Errorf("character not found: %s", id)
+		}
+		readVersion := existing.ResourceVersion
+		updated := *existing
 		s.mu.Unlock()
-		return fmt.Errorf("character not found: %s", char.ID)
-	}
 
-	char.CreatedAt = existing.CreatedAt
-	char.UpdatedAt = time.Now()
-	s.characters[char.ID] = char
-	s.mu.Unlock()
+		if err := tryUpdate(&updated); err != nil {
+			return err
+		}
+		updated.UpdatedAt = time.Now()
+
+		s.mu.Lock()
+		current, ok := s.characters[id]
+		if !ok {
+			s.mu.Unlock()
+			return fmt.Errorf("character not found: %s", id)
+		}
+		if current.ResourceVersion != readVersion {
+			s.mu.Unlock()
+			continue // peer updated it first - re-read and retry
+		}
+		updated.ResourceVersion = readVersion + 1
+		s.characters[id] = &updated
+		s.mu.Unlock()
 
-	return s.saveIndex()
+		if err := s.saveIndex(); err != nil {
+			return err
+		}
+		s.publish(Event{
+			ID:              id,
+			OldStatus:       current.Status,
+			NewStatus:       updated.Status,
+			Error:           updated.Error,
+			UpdatedAt:       updated.UpdatedAt,
+			ResourceVersion: updated.ResourceVersion,
+		})
+		return nil
+	}
+	return ErrVersionConflict
+}
+
+// Update replaces an existing character's metadata, preserving its ID,
+// CreatedAt and ResourceVersion. It rejects char if its ResourceVersion is
+// older than the one currently stored, so a caller working from stale data
+// can't silently clobber a newer write.
+func (s *Service) Update(char *CustomCharacter) error {
+	return s.update(char.ID, func(existing *CustomCharacter) error {
+		if char.ResourceVersion != 0 && char.ResourceVersion < existing.ResourceVersion {
+			return fmt.Errorf("%w: character %s has version %d, update targets stale version %d",
+				ErrVersionConflict, char.ID, existing.ResourceVersion, char.ResourceVersion)
+		}
+		id, createdAt := existing.ID, existing.CreatedAt
+		*existing = *char
+		existing.ID = id
+		existing.CreatedAt = createdAt
+		return nil
+	})
 }
 
 // Delete removes a character and its files from disk.
 func (s *Service) Delete(id string) error {
 	s.mu.Lock()
-	_, ok := s.characters[id]
+	char, ok := s.characters[id]
 	if !ok {
 		s.mu.Unlock()
 		return fmt.Errorf("character not found: %s", id)
@@ -208,7 +306,16 @@ func (s *Service) Delete(id string) error {
 	dir := s.characterDir(id)
 	os.RemoveAll(dir)
 
-	return s.saveIndex()
+	if err := s.saveIndex(); err != nil {
+		return err
+	}
+	s.publish(Event{
+		ID:              id,
+		OldStatus:       char.Status,
+		UpdatedAt:       time.Now(),
+		ResourceVersion: char.ResourceVersion,
+	})
+	return nil
 }
 
 // SaveImage saves an uploaded image to the character's directory.
@@ -234,15 +341,16 @@ func (s *Service) SaveImage(id string, filename string, reader io.Reader) (strin
 	}
 
 	// Update character record
-	s.mu.Lock()
-	if char, ok := s.characters[id]; ok {
+	err = s.update(id, func(char *CustomCharacter) error {
 		char.OriginalImage = "original" + ext
 		char.Status = StatusUploaded
-		char.UpdatedAt = time.Now()
+		return nil
+	})
+	if err != nil {
+		return "", err
 	}
-	s.mu.Unlock()
 
-	return savePath, s.saveIndex()
+	return savePath, nil
 }
 
 // GetImagePath returns the absolute path to a character's image file.
@@ -262,63 +370,55 @@ func (s *Service) GetModelPath(id string) string {
 
 // SetPipelineStatus updates the pipeline status for a character.
 func (s *Service) SetPipelineStatus(id string, status PipelineStatus, errMsg string) error {
-	s.mu.Lock()
-	char, ok := s.characters[id]
-	if !ok {
-		s.mu.Unlock()
-		return fmt.Errorf("character not found: %s", id)
+	err := s.update(id, func(char *CustomCharacter) error {
+		char.Status = status
+		char.Error = errMsg
+		return nil
+	})
+	if err != nil {
+		return err
 	}
-	char.Status = status
-	char.Error = errMsg
-	char.UpdatedAt = time.Now()
-	s.mu.Unlock()
-
-	return s.saveIndex()
+	s.log.Info("pipeline status changed", "id", id, "status", status, "error", errMsg)
+	return nil
 }
 
 // SetModelFile records the generated 3D model file for a character.
 func (s *Service) SetModelFile(id, filename string) error {
-	s.mu.Lock()
-	char, ok := s.characters[id]
-	if !ok {
-		s.mu.Unlock()
-		return fmt.Errorf("character not found: %s", id)
+	err := s.update(id, func(char *CustomCharacter) error {
+		char.ModelGLB = filename
+		return nil
+	})
+	if err != nil {
+		return err
 	}
-	char.ModelGLB = filename
-	char.UpdatedAt = time.Now()
-	s.mu.Unlock()
-
-	return s.saveIndex()
+	s.log.Info("model file set", "id", id, "filename", filename)
+	return nil
 }
 
 // SetNanoBananaImage records the generated Nano Banana image for a character.
 func (s *Service) SetNanoBananaImage(id, filename string) error {
-	s.mu.Lock()
-	char, ok := s.characters[id]
-	if !ok {
-		s.mu.Unlock()
-		return fmt.Errorf("character not found: %s", id)
+	err := s.update(id, func(char *CustomCharacter) error {
+		char.NanoBanana = filename
+		return nil
+	})
+	if err != nil {
+		return err
 	}
-	char.NanoBanana = filename
-	char.UpdatedAt = time.Now()
-	s.mu.Unlock()
-
-	return s.saveIndex()
+	s.log.Info("nano banana image set", "id", id, "filename", filename)
+	return nil
 }
 
 // SetThumbnail records the thumbnail image for a character.
 func (s *Service) SetThumbnail(id, filename string) error {
-	s.mu.Lock()
-	char, ok := s.characters[id]
-	if !ok {
-		s.mu.Unlock()
-		return fmt.Errorf("character not found: %s", id)
+	err := s.update(id, func(char *CustomCharacter) error {
+		char.Thumbnail = filename
+		return nil
+	})
+	if err != nil {
+		return err
 	}
-	char.Thumbnail = filename
-	char.UpdatedAt = time.Now()
-	s.mu.Unlock()
-
-	return s.saveIndex()
+	s.log.Info("thumbnail set", "id", id, "filename", filename)
+	return nil
 }
 
 // GetDataDir returns the base data directory for all characters.
@@ -0,0 +1,39 @@
+// Package config loads git-friendly, user-editable character packs that
+// customize conversation mode's persona without recompiling the app.
+//
+// This is a distinct concept from characters.CustomCharacter: that package
+// stores the 3D avatar-generation pipeline's per-character image/model
+// files and index.json under ~/.super-characters/characters/. A Character
+// here is a much lighter-weight persona description (system prompt, TTS
+// choice, history length) meant to be hand-written and checked into a
+// dotfiles repo, so it's loaded from its own directory - see
+// CharacterConfigLoader.
+package config
+
+// Character describes a single conversation-mode persona loaded from a
+// character pack file.
+type Character struct {
+	// Name identifies the character; defaults to the file's base name (sans
+	// extension) if left blank.
+	Name string
+	// AvatarID is the avatar.AvatarInfo.ID to display for this character.
+	AvatarID string
+	// SystemPrompt overrides gemini.ConversationSystemPrompt for this
+	// character. Empty means "use the default".
+	SystemPrompt string
+	// TTSProvider overrides Settings.TTSProvider while this character is
+	// active. Empty means "use whatever's configured".
+	TTSProvider string
+	// TTSVoice is passed as the voice override to tts.Provider.Synthesize.
+	// Empty means "use the provider's configured default voice".
+	TTSVoice string
+	// Temperature overrides the LLM sampling temperature for this
+	// character. Zero means "use the provider's default".
+	Temperature float64
+	// MaxHistoryTurns overrides gemini.MaxConversationTurns for this
+	// character. Zero or negative means "use the default".
+	MaxHistoryTurns int
+	// ToolAllowlist restricts which Pipedream apps/tools this character may
+	// invoke. Empty means "no restriction".
+	ToolAllowlist []string
+}
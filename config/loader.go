@@ -0,0 +1,201 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// characterPacksDirName is the directory scanned for character pack YAML
+// files, relative to the user's config directory (~/.super-characters).
+// This is deliberately not "characters": that name is already owned by
+// characters.Service's image/model/index.json storage for the 3D
+// avatar-generation pipeline, an unrelated feature that happens to share
+// the word "character".
+const characterPacksDirName = "character-packs"
+
+// pollInterval is how often Watch re-scans the character packs directory
+// for changes. This stands in for real filesystem-change notification (see
+// CharacterConfigLoader's doc comment).
+const pollInterval = 2 * time.Second
+
+// CharacterConfigLoader loads Character packs from
+// configDir/character-packs/*.yaml (see characterPacksDirName) and keeps
+// them up to date.
+//
+// Real filesystem-change notification (fsnotify) needs inotify/kqueue/
+// ReadDirectoryChangesW bindings that this repo has no dependency manager
+// to vendor. CharacterConfigLoader polls the directory's file mtimes on a
+// timer instead via Watch; swap that for real fsnotify once the build
+// gains dependency management.
+type CharacterConfigLoader struct {
+	dir string
+
+	mu         sync.RWMutex
+	characters map[string]*Character
+	mtimes     map[string]time.Time
+
+	stopCh chan struct{}
+}
+
+// NewCharacterConfigLoader creates a loader scanning
+// configDir/character-packs/*.yaml (configDir is usually
+// ~/.super-characters).
+func NewCharacterConfigLoader(configDir string) *CharacterConfigLoader {
+	return &CharacterConfigLoader{
+		dir:        filepath.Join(configDir, characterPacksDirName),
+		characters: make(map[string]*Character),
+		mtimes:     make(map[string]time.Time),
+	}
+}
+
+// Reload scans the character packs directory and (re)loads every *.yaml
+// file, replacing the loader's in-memory character set. A file that fails
+// to parse is logged and skipped rather than failing the whole reload.
+func (l *CharacterConfigLoader) Reload() error {
+	if err := os.MkdirAll(l.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create character packs directory: %w", err)
+	}
+
+	entries, err := os.ReadDir(l.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read character packs directory: %w", err)
+	}
+
+	characters := make(map[string]*Character)
+	mtimes := make(map[string]time.Time)
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".yaml") {
+			continue
+		}
+		path := filepath.Join(l.dir, entry.Name())
+
+		info, err := entry.Info()
+		if err != nil {
+			slog.Warn("[Config] Failed to stat character pack", "path", path, "error", err)
+			continue
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			slog.Warn("[Config] Failed to read character pack", "path", path, "error", err)
+			continue
+		}
+
+		character, err := parseCharacter(data)
+		if err != nil {
+			slog.Warn("[Config] Failed to parse character pack", "path", path, "error", err)
+			continue
+		}
+		if character.Name == "" {
+			character.Name = strings.TrimSuffix(entry.Name(), ".yaml")
+		}
+
+		characters[character.Name] = character
+		mtimes[path] = info.ModTime()
+	}
+
+	l.mu.Lock()
+	l.characters = characters
+	l.mtimes = mtimes
+	l.mu.Unlock()
+
+	slog.Info("[Config] Loaded character packs", "count", len(characters), "dir", l.dir)
+	return nil
+}
+
+// GetCharacter returns the named character, or false if no such character
+// is loaded.
+func (l *CharacterConfigLoader) GetCharacter(name string) (*Character, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	c, ok := l.characters[name]
+	return c, ok
+}
+
+// ListCharacters returns every loaded character's name, sorted.
+func (l *CharacterConfigLoader) ListCharacters() []string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	names := make([]string, 0, len(l.characters))
+	for name := range l.characters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Watch starts polling the character packs directory for changes, calling
+// Reload whenever a file is added, removed, or modified. Safe to call more
+// than once; later calls are no-ops until StopWatch is called.
+func (l *CharacterConfigLoader) Watch() {
+	if l.stopCh != nil {
+		return
+	}
+	stop := make(chan struct{})
+	l.stopCh = stop
+	go l.watchLoop(stop)
+}
+
+// StopWatch halts the polling goroutine started by Watch.
+func (l *CharacterConfigLoader) StopWatch() {
+	if l.stopCh == nil {
+		return
+	}
+	close(l.stopCh)
+	l.stopCh = nil
+}
+
+func (l *CharacterConfigLoader) watchLoop(stop chan struct{}) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if l.hasChanged() {
+				if err := l.Reload(); err != nil {
+					slog.Warn("[Config] Failed to reload character packs", "error", err)
+				}
+			}
+		}
+	}
+}
+
+// hasChanged reports whether the character packs directory's *.yaml file
+// set or any file's mtime differs from what Reload last saw.
+func (l *CharacterConfigLoader) hasChanged() bool {
+	entries, err := os.ReadDir(l.dir)
+	if err != nil {
+		return false
+	}
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	seen := 0
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".yaml") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		seen++
+		path := filepath.Join(l.dir, entry.Name())
+		if mtime, ok := l.mtimes[path]; !ok || !mtime.Equal(info.ModTime()) {
+			return true
+		}
+	}
+	return seen != len(l.mtimes)
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseCharacter parses a Character from a minimal YAML subset: flat
+// "key: value" pairs, plus one level of "- item" list entries following a
+// list-valued key. This covers everything a character pack needs without a
+// real YAML library, which this repo has no dependency manager to vendor
+// (the same tradeoff CharacterConfigLoader makes for fsnotify, see its doc
+// comment). Swap this for gopkg.in/yaml.v3 once the build gains dependency
+// management.
+func parseCharacter(data []byte) (*Character, error) {
+	c := &Character{}
+	var currentList *[]string
+
+	lines := strings.Split(string(data), "\n")
+	for i, raw := range lines {
+		line := strings.TrimRight(raw, " \t\r")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "- ") || trimmed == "-" {
+			if currentList == nil {
+				return nil, fmt.Errorf("line %d: list item with no preceding key", i+1)
+			}
+			item := strings.TrimSpace(strings.TrimPrefix(trimmed, "-"))
+			*currentList = append(*currentList, unquoteYAML(item))
+			continue
+		}
+
+		key, value, ok := strings.Cut(trimmed, ":")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected \"key: value\", got %q", i+1, trimmed)
+		}
+		key = strings.TrimSpace(key)
+		value = unquoteYAML(strings.TrimSpace(value))
+		currentList = nil
+
+		switch key {
+		case "name":
+			c.Name = value
+		case "avatarId":
+			c.AvatarID = value
+		case "systemPrompt":
+			c.SystemPrompt = value
+		case "ttsProvider":
+			c.TTSProvider = value
+		case "ttsVoice":
+			c.TTSVoice = value
+		case "temperature":
+			if value != "" {
+				t, err := strconv.ParseFloat(value, 64)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: invalid temperature %q: %w", i+1, value, err)
+				}
+				c.Temperature = t
+			}
+		case "maxHistoryTurns":
+			if value != "" {
+				n, err := strconv.Atoi(value)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: invalid maxHistoryTurns %q: %w", i+1, value, err)
+				}
+				c.MaxHistoryTurns = n
+			}
+		case "toolAllowlist":
+			currentList = &c.ToolAllowlist
+		default:
+			// Unknown keys are ignored, so character packs stay forward
+			// compatible with fields this parser doesn't understand yet.
+		}
+	}
+
+	return c, nil
+}
+
+// unquoteYAML strips a single layer of matching double or single quotes.
+func unquoteYAML(s string) string {
+	if len(s) >= 2 {
+		if (s[0] == '"' && s[len(s)-1] == '"') || (s[0] == '\'' && s[len(s)-1] == '\'') {
+			return s[1 : len(s)-1]
+		}
+	}
+	return s
+}
@@ -2,18 +2,29 @@ package elevenlabs
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"time"
+
+	"super-characters/metrics"
+	"super-characters/tts"
 )
 
-// ElevenLabsService handles communication with the ElevenLabs TTS API.
+// ProviderName is the name ElevenLabsService registers under in a
+// tts.Registry, matching the string stored in Settings.TTSProvider.
+const ProviderName = "elevenlabs"
+
+// ElevenLabsService handles communication with the ElevenLabs TTS API. It
+// implements tts.Provider.
 type ElevenLabsService struct {
 	apiKey  string
 	voiceID string
 	model   string
+	metrics *metrics.Registry
 }
 
 // NewElevenLabsService creates a new ElevenLabs service.
@@ -29,6 +40,44 @@ func (s *ElevenLabsService) SetAPIKey(key string) {
 	s.apiKey = key
 }
 
+// SetMetrics attaches a metrics.Registry that records
+// tts_synthesize_duration_seconds, tts_bytes_total, and
+// tts_errors_total{provider,status} for this service's API calls. Passing
+// nil disables metrics recording.
+func (s *ElevenLabsService) SetMetrics(m *metrics.Registry) {
+	s.metrics = m
+}
+
+// metricsRoundTripper wraps an http.RoundTripper and records
+// tts_errors_total{provider,status} for 4xx/5xx responses and transport
+// errors, without altering the request/response.
+type metricsRoundTripper struct {
+	next    http.RoundTripper
+	metrics *metrics.Registry
+}
+
+func (t *metricsRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if t.metrics == nil {
+		return resp, err
+	}
+	if err != nil {
+		t.metrics.IncCounter("tts_errors_total", ProviderName, "transport_error")
+	} else if resp.StatusCode >= 400 {
+		t.metrics.IncCounter("tts_errors_total", ProviderName, strconv.Itoa(resp.StatusCode))
+	}
+	return resp, err
+}
+
+// httpClient returns an *http.Client whose transport records status-code
+// metrics when s.metrics is configured.
+func (s *ElevenLabsService) httpClient() *http.Client {
+	return &http.Client{
+		Timeout:   30 * time.Second,
+		Transport: &metricsRoundTripper{next: http.DefaultTransport, metrics: s.metrics},
+	}
+}
+
 // SetVoiceID sets the voice ID.
 func (s *ElevenLabsService) SetVoiceID(voiceID string) {
 	if voiceID != "" {
@@ -41,15 +90,35 @@ func (s *ElevenLabsService) GetVoiceID() string {
 	return s.voiceID
 }
 
+// Name identifies this provider in Settings.TTSProvider.
+func (s *ElevenLabsService) Name() string { return ProviderName }
+
 // IsConfigured returns true if the API key is set.
 func (s *ElevenLabsService) IsConfigured() bool {
 	return s.apiKey != ""
 }
 
-// Synthesize converts text to speech and returns MP3 bytes.
-func (s *ElevenLabsService) Synthesize(text string) ([]byte, error) {
+// SpeaksAloud is always false: ElevenLabs returns audio bytes for the
+// caller to play, it doesn't play them itself.
+func (s *ElevenLabsService) SpeaksAloud() bool { return false }
+
+// Synthesize converts text to speech and returns MP3 bytes. voice overrides
+// the configured voice ID for this call when non-empty.
+func (s *ElevenLabsService) Synthesize(ctx context.Context, text string, voice string) ([]byte, string, error) {
 	if s.apiKey == "" {
-		return nil, fmt.Errorf("elevenlabs API key not configured")
+		return nil, "", fmt.Errorf("elevenlabs API key not configured")
+	}
+
+	voiceID := s.voiceID
+	if voice != "" {
+		voiceID = voice
+	}
+
+	if s.metrics != nil {
+		start := time.Now()
+		defer func() {
+			s.metrics.ObserveHistogram("tts_synthesize_duration_seconds", time.Since(start).Seconds(), ProviderName, voiceID)
+		}()
 	}
 
 	reqBody := map[string]interface{}{
@@ -63,36 +132,184 @@ func (s *ElevenLabsService) Synthesize(text string) ([]byte, error) {
 
 	jsonBody, err := json.Marshal(reqBody)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
+		return nil, "", fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	url := fmt.Sprintf("%s/text-to-speech/%s", BaseURL, s.voiceID)
+	url := fmt.Sprintf("%s/text-to-speech/%s", BaseURL, voiceID)
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonBody))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonBody))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, "", fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("xi-api-key", s.apiKey)
 	req.Header.Set("Accept", "audio/mpeg")
 
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(req)
+	resp, err := s.httpClient().Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to call ElevenLabs API: %w", err)
+		return nil, "", fmt.Errorf("failed to call ElevenLabs API: %w", err)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read ElevenLabs response: %w", err)
+		return nil, "", fmt.Errorf("failed to read ElevenLabs response: %w", err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("elevenlabs API returned status %d: %s", resp.StatusCode, string(body))
+		return nil, "", fmt.Errorf("elevenlabs API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	if s.metrics != nil {
+		s.metrics.AddCounter("tts_bytes_total", float64(len(body)), ProviderName)
 	}
 
 	fmt.Printf("[ElevenLabs] Synthesized %d bytes of audio\n", len(body))
-	return body, nil
+	return body, "audio/mpeg", nil
+}
+
+// streamChunkBytes is how much of the response body SynthesizeStream reads
+// per chunk before forwarding it - small enough to start playback quickly,
+// large enough not to thrash the channel.
+const streamChunkBytes = 4096
+
+// SynthesizeStream calls ElevenLabs' streaming endpoint
+// (POST .../text-to-speech/{voice}/stream) and forwards the response body
+// in streamChunkBytes pieces as they arrive, rather than buffering the
+// whole synthesis before returning anything.
+func (s *ElevenLabsService) SynthesizeStream(ctx context.Context, text string, voice string) (<-chan []byte, <-chan error) {
+	chunks := make(chan []byte)
+	errc := make(chan error, 1)
+
+	go func() {
+		defer close(chunks)
+
+		if s.apiKey == "" {
+			errc <- fmt.Errorf("elevenlabs API key not configured")
+			return
+		}
+
+		voiceID := s.voiceID
+		if voice != "" {
+			voiceID = voice
+		}
+
+		start := time.Now()
+		if s.metrics != nil {
+			defer func() {
+				s.metrics.ObserveHistogram("tts_synthesize_duration_seconds", time.Since(start).Seconds(), ProviderName, voiceID)
+			}()
+		}
+
+		reqBody := map[string]interface{}{
+			"text":     text,
+			"model_id": s.model,
+			"voice_settings": map[string]interface{}{
+				"stability":        0.5,
+				"similarity_boost": 0.75,
+			},
+		}
+		jsonBody, err := json.Marshal(reqBody)
+		if err != nil {
+			errc <- fmt.Errorf("failed to marshal request: %w", err)
+			return
+		}
+
+		url := fmt.Sprintf("%s/text-to-speech/%s/stream", BaseURL, voiceID)
+		req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonBody))
+		if err != nil {
+			errc <- fmt.Errorf("failed to create request: %w", err)
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("xi-api-key", s.apiKey)
+		req.Header.Set("Accept", "audio/mpeg")
+
+		resp, err := s.httpClient().Do(req)
+		if err != nil {
+			errc <- fmt.Errorf("failed to call ElevenLabs streaming API: %w", err)
+			return
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			errc <- fmt.Errorf("elevenlabs streaming API returned status %d: %s", resp.StatusCode, string(body))
+			return
+		}
+
+		var total int
+		buf := make([]byte, streamChunkBytes)
+		for {
+			n, err := resp.Body.Read(buf)
+			if n > 0 {
+				chunk := make([]byte, n)
+				copy(chunk, buf[:n])
+				select {
+				case chunks <- chunk:
+					total += n
+				case <-ctx.Done():
+					errc <- ctx.Err()
+					return
+				}
+			}
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				errc <- fmt.Errorf("failed to read ElevenLabs stream: %w", err)
+				return
+			}
+		}
+
+		if s.metrics != nil {
+			s.metrics.AddCounter("tts_bytes_total", float64(total), ProviderName)
+		}
+	}()
+
+	return chunks, errc
+}
+
+// elevenLabsVoice is the subset of ElevenLabs' voice object we care about.
+type elevenLabsVoice struct {
+	VoiceID string `json:"voice_id"`
+	Name    string `json:"name"`
+}
+
+// ListVoices fetches the account's available voices.
+func (s *ElevenLabsService) ListVoices(ctx context.Context) ([]tts.Voice, error) {
+	if s.apiKey == "" {
+		return nil, fmt.Errorf("elevenlabs API key not configured")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", BaseURL+"/voices", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("xi-api-key", s.apiKey)
+
+	resp, err := s.httpClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call ElevenLabs API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("elevenlabs API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var parsed struct {
+		Voices []elevenLabsVoice `json:"voices"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse ElevenLabs response: %w", err)
+	}
+
+	voices := make([]tts.Voice, 0, len(parsed.Voices))
+	for _, v := range parsed.Voices {
+		voices = append(voices, tts.Voice{ID: v.VoiceID, Name: v.Name})
+	}
+	return voices, nil
 }
@@ -0,0 +1,355 @@
+// Package forwarder streams listening state and transcribed text to remote
+// clients over TCP and WebSocket, so a user can dictate into a machine that
+// isn't running Super Characters itself - a retro computer, a headless
+// workstation, or a VM on the same network. Both listeners default to
+// loopback-only, the same default App.handleMetrics uses for /metrics;
+// Server.SetBindLAN opts into listening on all interfaces, and
+// Server.SetAuthToken gates admission either way - see Server for both.
+package forwarder
+
+import (
+	"crypto/subtle"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// MsgType identifies the payload carried by one frame of the wire protocol.
+type MsgType byte
+
+const (
+	// ListenStateMsg carries the current listening state as UTF-8 text
+	// (e.g. "idle", "listening", "processing", "speaking").
+	ListenStateMsg MsgType = 1
+	// ListenTextMsg carries a finalized transcription as UTF-8 text.
+	ListenTextMsg MsgType = 2
+	// ListenAudioMsg carries a raw little-endian PCM16 mono frame from the
+	// VAD. Only sent when Server.ForwardAudio is enabled.
+	ListenAudioMsg MsgType = 3
+)
+
+// DefaultPort is the TCP port Server listens on when Start is given a port
+// <= 0. WebSocket clients connect on DefaultPort+1.
+const DefaultPort = 19026
+
+// client is a connected forwarder client, addressed over either TCP or
+// WebSocket.
+type client interface {
+	write(frame []byte) error
+	Close() error
+}
+
+type tcpClient struct{ conn net.Conn }
+
+// write sends frame as a 4-byte big-endian length prefix followed by the
+// frame itself, since a bare TCP stream has no message boundaries of its
+// own.
+func (c *tcpClient) write(frame []byte) error {
+	var lenBuf [4]byte
+	binary.BigEndian.PutUint32(lenBuf[:], uint32(len(frame)))
+	if _, err := c.conn.Write(lenBuf[:]); err != nil {
+		return err
+	}
+	_, err := c.conn.Write(frame)
+	return err
+}
+
+func (c *tcpClient) Close() error { return c.conn.Close() }
+
+type wsClient struct{ conn *websocket.Conn }
+
+// write sends frame as a single WebSocket binary message - WebSocket
+// already delimits messages, so no length prefix is needed here.
+func (c *wsClient) write(frame []byte) error {
+	return c.conn.WriteMessage(websocket.BinaryMessage, frame)
+}
+
+func (c *wsClient) Close() error { return c.conn.Close() }
+
+// authHandshakeTimeout bounds how long acceptTCP waits for a newly
+// connected client to send its auth frame before giving up and closing the
+// connection, so a client that never sends anything can't tie up a slot
+// forever.
+const authHandshakeTimeout = 5 * time.Second
+
+// wsUpgrader's CheckOrigin always accepts: origin checking defends against
+// a browser page embedding a WebSocket connection on a victim's behalf,
+// which the auth token (checked before the upgrade completes, see
+// handleWebSocket) already closes off more directly than an origin
+// allowlist would.
+var wsUpgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// Server streams ListenStateMsg, ListenTextMsg, and (optionally)
+// ListenAudioMsg frames to every connected client. It listens for raw TCP
+// clients on one port and WebSocket clients (at "/ws") on port+1, since the
+// two transports can't share a listener. Not safe to Start twice
+// concurrently without an intervening Stop.
+type Server struct {
+	// ForwardAudio enables BroadcastAudio. Off by default: most clients
+	// only want state and text, and raw audio is a lot more bandwidth.
+	ForwardAudio bool
+
+	mu        sync.Mutex
+	clients   map[client]struct{}
+	listener  net.Listener
+	wsServer  *http.Server
+	authToken string
+	bindLAN   bool
+}
+
+// SetAuthToken updates the token required from every client before it's
+// admitted to the broadcast set: a WebSocket client supplies it as an HTTP
+// Basic Auth password (any username) on the upgrade request, the same
+// convention App.handleMetrics uses for /metrics; a raw TCP client sends it
+// as its first length-prefixed frame, since there's no HTTP request to
+// carry it on. Empty disables authentication - only safe with BindLAN also
+// left false. Takes effect immediately, including for connections already
+// in flight that haven't completed their handshake yet, so tightening it
+// while the server is running doesn't wait for a restart.
+func (s *Server) SetAuthToken(token string) {
+	s.mu.Lock()
+	s.authToken = token
+	s.mu.Unlock()
+}
+
+func (s *Server) getAuthToken() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.authToken
+}
+
+// SetBindLAN opts into binding both listeners to all interfaces instead of
+// loopback-only, so a client on another machine on the same network can
+// connect. Off by default, matching the metrics endpoint's loopback-only
+// default (see app.metricsAddr). Only takes effect on the next Start -
+// changing it while already running doesn't rebind the live listeners.
+func (s *Server) SetBindLAN(allow bool) {
+	s.mu.Lock()
+	s.bindLAN = allow
+	s.mu.Unlock()
+}
+
+// NewServer creates a Server. Call Start to begin listening.
+func NewServer() *Server {
+	return &Server{clients: make(map[client]struct{})}
+}
+
+// Start begins listening for TCP clients on port and WebSocket clients on
+// port+1. port <= 0 selects DefaultPort. Both listeners bind loopback-only
+// unless SetBindLAN(true) was called first.
+func (s *Server) Start(port int) error {
+	if port <= 0 {
+		port = DefaultPort
+	}
+
+	s.mu.Lock()
+	if s.listener != nil {
+		s.mu.Unlock()
+		return fmt.Errorf("forwarder: already running")
+	}
+
+	host := "127.0.0.1"
+	if s.bindLAN {
+		host = ""
+	}
+
+	ln, err := net.Listen("tcp", fmt.Sprintf("%s:%d", host, port))
+	if err != nil {
+		s.mu.Unlock()
+		return fmt.Errorf("forwarder: failed to listen on port %d: %w", port, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ws", s.handleWebSocket)
+	wsServer := &http.Server{Addr: fmt.Sprintf("%s:%d", host, port+1), Handler: mux}
+
+	s.listener = ln
+	s.wsServer = wsServer
+	s.mu.Unlock()
+
+	go s.acceptTCP(ln)
+	go func() {
+		if err := wsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			slog.Warn("[Forwarder] WebSocket server stopped", "error", err)
+		}
+	}()
+
+	slog.Info("[Forwarder] Listening", "tcpPort", port, "wsPort", port+1, "lan", host == "")
+	return nil
+}
+
+// Stop closes both listeners and every connected client. Safe to call when
+// not running.
+func (s *Server) Stop() error {
+	s.mu.Lock()
+	ln := s.listener
+	wsServer := s.wsServer
+	s.listener = nil
+	s.wsServer = nil
+	clients := make([]client, 0, len(s.clients))
+	for c := range s.clients {
+		clients = append(clients, c)
+	}
+	s.clients = make(map[client]struct{})
+	s.mu.Unlock()
+
+	if ln == nil {
+		return nil
+	}
+
+	for _, c := range clients {
+		c.Close()
+	}
+
+	err := ln.Close()
+	if wsErr := wsServer.Close(); wsErr != nil && err == nil {
+		err = wsErr
+	}
+	slog.Info("[Forwarder] Stopped")
+	return err
+}
+
+// IsRunning reports whether Start has been called without a matching Stop.
+func (s *Server) IsRunning() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.listener != nil
+}
+
+// BroadcastState sends state to every connected client as a ListenStateMsg.
+func (s *Server) BroadcastState(state string) {
+	s.broadcast(ListenStateMsg, []byte(state))
+}
+
+// BroadcastText sends text to every connected client as a ListenTextMsg.
+func (s *Server) BroadcastText(text string) {
+	s.broadcast(ListenTextMsg, []byte(text))
+}
+
+// BroadcastAudio sends a raw PCM16 frame to every connected client as a
+// ListenAudioMsg, if ForwardAudio is enabled.
+func (s *Server) BroadcastAudio(pcm []byte) {
+	if !s.ForwardAudio {
+		return
+	}
+	s.broadcast(ListenAudioMsg, pcm)
+}
+
+func (s *Server) acceptTCP(ln net.Listener) {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			// Listener was closed by Stop.
+			return
+		}
+		go s.handleTCP(conn)
+	}
+}
+
+// handleTCP runs the auth handshake (if a token was set via SetAuthToken) before admitting
+// conn to the broadcast set, on its own goroutine so a slow or silent
+// client can't stall acceptTCP from accepting the next connection.
+func (s *Server) handleTCP(conn net.Conn) {
+	if !s.checkTCPAuth(conn) {
+		conn.Close()
+		return
+	}
+	s.addClient(&tcpClient{conn: conn})
+}
+
+// checkTCPAuth reads conn's first length-prefixed frame as the client's
+// auth token, since a raw TCP stream has no request/header to carry one on
+// the way a WebSocket upgrade does - see handleWebSocket. No-op (always
+// true) when no token is set.
+func (s *Server) checkTCPAuth(conn net.Conn) bool {
+	token := s.getAuthToken()
+	if token == "" {
+		return true
+	}
+
+	conn.SetReadDeadline(time.Now().Add(authHandshakeTimeout))
+	defer conn.SetReadDeadline(time.Time{})
+
+	var lenBuf [4]byte
+	if _, err := io.ReadFull(conn, lenBuf[:]); err != nil {
+		slog.Warn("[Forwarder] TCP client auth handshake failed", "error", err)
+		return false
+	}
+	frameLen := binary.BigEndian.Uint32(lenBuf[:])
+	if frameLen > 4096 {
+		slog.Warn("[Forwarder] TCP client auth frame too large", "len", frameLen)
+		return false
+	}
+	got := make([]byte, frameLen)
+	if _, err := io.ReadFull(conn, got); err != nil {
+		slog.Warn("[Forwarder] TCP client auth handshake failed", "error", err)
+		return false
+	}
+	// Compare against the token as it is now, so a SetAuthToken call made
+	// while this client was still sending its frame already applies.
+	if current := s.getAuthToken(); current != "" && subtle.ConstantTimeCompare(got, []byte(current)) != 1 {
+		slog.Warn("[Forwarder] TCP client supplied an invalid auth token")
+		return false
+	}
+	return true
+}
+
+func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	if token := s.getAuthToken(); token != "" {
+		_, password, ok := r.BasicAuth()
+		if !ok || subtle.ConstantTimeCompare([]byte(password), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Basic realm="forwarder"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	conn, err := wsUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		slog.Warn("[Forwarder] WebSocket upgrade failed", "error", err)
+		return
+	}
+	s.addClient(&wsClient{conn: conn})
+}
+
+func (s *Server) addClient(c client) {
+	s.mu.Lock()
+	s.clients[c] = struct{}{}
+	s.mu.Unlock()
+	slog.Info("[Forwarder] Client connected")
+}
+
+func (s *Server) removeClient(c client) {
+	s.mu.Lock()
+	delete(s.clients, c)
+	s.mu.Unlock()
+	c.Close()
+}
+
+func (s *Server) broadcast(msgType MsgType, payload []byte) {
+	frame := make([]byte, 1+len(payload))
+	frame[0] = byte(msgType)
+	copy(frame[1:], payload)
+
+	s.mu.Lock()
+	clients := make([]client, 0, len(s.clients))
+	for c := range s.clients {
+		clients = append(clients, c)
+	}
+	s.mu.Unlock()
+
+	for _, c := range clients {
+		if err := c.write(frame); err != nil {
+			s.removeClient(c)
+		}
+	}
+}
@@ -1,12 +1,17 @@
 package gemini
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
+
+	"super-characters/metrics"
 )
 
 // ChatMessage represents a single message in a conversation.
@@ -17,8 +22,9 @@ type ChatMessage struct {
 
 // GeminiService handles communication with the Gemini API.
 type GeminiService struct {
-	apiKey string
-	model  string
+	apiKey  string
+	model   string
+	metrics *metrics.Registry
 }
 
 // NewGeminiService creates a new Gemini service.
@@ -33,6 +39,13 @@ func (s *GeminiService) SetAPIKey(key string) {
 	s.apiKey = key
 }
 
+// SetMetrics attaches a metrics.Registry that records
+// gemini_request_duration_seconds for every API call. Passing nil disables
+// metrics recording.
+func (s *GeminiService) SetMetrics(m *metrics.Registry) {
+	s.metrics = m
+}
+
 // IsConfigured returns true if the API key is set.
 func (s *GeminiService) IsConfigured() bool {
 	return s.apiKey != ""
@@ -45,13 +58,21 @@ type geminiContent struct {
 }
 
 type geminiPart struct {
-	Text string `json:"text"`
+	Text       string            `json:"text,omitempty"`
+	InlineData *geminiInlineData `json:"inlineData,omitempty"`
+}
+
+// geminiInlineData carries base64-encoded binary content (e.g. audio) in a
+// request part, as an alternative to geminiPart.Text.
+type geminiInlineData struct {
+	MimeType string `json:"mimeType"`
+	Data     string `json:"data"`
 }
 
 type geminiRequest struct {
-	Contents          []geminiContent  `json:"contents"`
-	SystemInstruction *geminiContent   `json:"systemInstruction,omitempty"`
-	GenerationConfig  geminiGenConfig  `json:"generationConfig"`
+	Contents          []geminiContent `json:"contents"`
+	SystemInstruction *geminiContent  `json:"systemInstruction,omitempty"`
+	GenerationConfig  geminiGenConfig `json:"generationConfig"`
 }
 
 type geminiGenConfig struct {
@@ -99,6 +120,184 @@ func (s *GeminiService) Chat(messages []ChatMessage) (string, error) {
 		},
 	}
 
+	return s.generateContent(reqBody)
+}
+
+// ChatStream is Chat's streaming counterpart: it sends the same conversation
+// to Gemini's streamGenerateContent endpoint (server-sent events) and yields
+// each incremental text delta on the returned channel as it arrives,
+// instead of blocking until the whole reply is ready. The delta channel is
+// closed when the stream ends, successfully or not; the error channel is
+// buffered with capacity 1 and receives a value only on failure, which the
+// caller should check after the delta channel closes.
+func (s *GeminiService) ChatStream(ctx context.Context, messages []ChatMessage) (<-chan string, <-chan error) {
+	deltas := make(chan string)
+	errc := make(chan error, 1)
+
+	if s.apiKey == "" {
+		close(deltas)
+		errc <- fmt.Errorf("gemini API key not configured")
+		return deltas, errc
+	}
+
+	var systemInstruction *geminiContent
+	var contents []geminiContent
+
+	for _, msg := range messages {
+		switch msg.Role {
+		case "system":
+			systemInstruction = &geminiContent{
+				Role: "user",
+				Parts: []geminiPart{{Text: msg.Content}},
+			}
+		case "user":
+			contents = append(contents, geminiContent{
+				Role:  "user",
+				Parts: []geminiPart{{Text: msg.Content}},
+			})
+		case "assistant":
+			contents = append(contents, geminiContent{
+				Role:  "model",
+				Parts: []geminiPart{{Text: msg.Content}},
+			})
+		}
+	}
+
+	reqBody := geminiRequest{
+		Contents:          contents,
+		SystemInstruction: systemInstruction,
+		GenerationConfig: geminiGenConfig{
+			MaxOutputTokens: 256,
+			Temperature:     0.7,
+			TopP:            0.9,
+		},
+	}
+
+	go func() {
+		defer close(deltas)
+
+		start := time.Now()
+		if s.metrics != nil {
+			defer func() {
+				s.metrics.ObserveHistogram("gemini_request_duration_seconds", time.Since(start).Seconds(), s.model)
+			}()
+		}
+
+		jsonBody, err := json.Marshal(reqBody)
+		if err != nil {
+			errc <- fmt.Errorf("failed to marshal request: %w", err)
+			return
+		}
+
+		url := fmt.Sprintf("%s/models/%s:streamGenerateContent?alt=sse&key=%s", BaseURL, s.model, s.apiKey)
+		req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonBody))
+		if err != nil {
+			errc <- fmt.Errorf("failed to create request: %w", err)
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "text/event-stream")
+
+		client := &http.Client{Timeout: 60 * time.Second}
+		resp, err := client.Do(req)
+		if err != nil {
+			errc <- fmt.Errorf("failed to call Gemini API: %w", err)
+			return
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			errc <- fmt.Errorf("gemini API returned status %d: %s", resp.StatusCode, string(body))
+			return
+		}
+
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if !strings.HasPrefix(line, "data: ") {
+				continue
+			}
+
+			var chunk struct {
+				Candidates []struct {
+					Content struct {
+						Parts []struct {
+							Text string `json:"text"`
+						} `json:"parts"`
+					} `json:"content"`
+				} `json:"candidates"`
+			}
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &chunk); err != nil {
+				continue
+			}
+			if len(chunk.Candidates) == 0 || len(chunk.Candidates[0].Content.Parts) == 0 {
+				continue
+			}
+
+			select {
+			case deltas <- chunk.Candidates[0].Content.Parts[0].Text:
+			case <-ctx.Done():
+				errc <- ctx.Err()
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			errc <- fmt.Errorf("failed to read Gemini stream: %w", err)
+		}
+	}()
+
+	return deltas, errc
+}
+
+// TranscribeAudio sends audioBase64 (encoded as mimeType, e.g. "audio/wav")
+// to Gemini and returns a plain-text transcript. lang is a BCP-47 language
+// hint (e.g. "en"); empty means let Gemini auto-detect the spoken language.
+func (s *GeminiService) TranscribeAudio(ctx context.Context, audioBase64 string, mimeType string, lang string) (string, error) {
+	if s.apiKey == "" {
+		return "", fmt.Errorf("gemini API key not configured")
+	}
+
+	prompt := "Transcribe the following audio verbatim. Return only the transcript text, with no commentary."
+	if lang != "" {
+		prompt += fmt.Sprintf(" The spoken language is %s.", lang)
+	}
+
+	reqBody := geminiRequest{
+		Contents: []geminiContent{{
+			Role: "user",
+			Parts: []geminiPart{
+				{Text: prompt},
+				{InlineData: &geminiInlineData{MimeType: mimeType, Data: audioBase64}},
+			},
+		}},
+		GenerationConfig: geminiGenConfig{
+			MaxOutputTokens: 1024,
+			Temperature:     0,
+			TopP:            0.9,
+		},
+	}
+
+	return s.generateContentCtx(ctx, reqBody)
+}
+
+// generateContent calls generateContentCtx with context.Background(), for
+// callers (like Chat) that predate context-aware Gemini calls.
+func (s *GeminiService) generateContent(reqBody geminiRequest) (string, error) {
+	return s.generateContentCtx(context.Background(), reqBody)
+}
+
+// generateContentCtx posts reqBody to the Gemini generateContent endpoint
+// and extracts the first candidate's text.
+func (s *GeminiService) generateContentCtx(ctx context.Context, reqBody geminiRequest) (string, error) {
+	if s.metrics != nil {
+		start := time.Now()
+		defer func() {
+			s.metrics.ObserveHistogram("gemini_request_duration_seconds", time.Since(start).Seconds(), s.model)
+		}()
+	}
+
 	jsonBody, err := json.Marshal(reqBody)
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal request: %w", err)
@@ -106,8 +305,14 @@ func (s *GeminiService) Chat(messages []ChatMessage) (string, error) {
 
 	url := fmt.Sprintf("%s/models/%s:generateContent?key=%s", BaseURL, s.model, s.apiKey)
 
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
 	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Post(url, "application/json", bytes.NewBuffer(jsonBody))
+	resp, err := client.Do(req)
 	if err != nil {
 		return "", fmt.Errorf("failed to call Gemini API: %w", err)
 	}
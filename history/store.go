@@ -0,0 +1,249 @@
+// Package history persists conversation turns to a local SQLite database,
+// so a conversation survives App.StopConversation and app restarts and can
+// later be listed, searched, resumed, or exported.
+package history
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"super-characters/gemini"
+)
+
+// DBFileName is the database file created inside the app support
+// directory (see App.historyStore).
+const DBFileName = "history.db"
+
+// ConversationSummary describes one stored conversation for a list view.
+type ConversationSummary struct {
+	ID        int64     `json:"id"`
+	StartedAt time.Time `json:"startedAt"`
+	Character string    `json:"character"`
+	Model     string    `json:"model"`
+	TurnCount int       `json:"turnCount"`
+}
+
+// Hit is one FTS5 search result.
+type Hit struct {
+	ConversationID int64     `json:"conversationId"`
+	Role           string    `json:"role"`
+	Snippet        string    `json:"snippet"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+// Turn is one persisted message, carrying the metadata gemini.ChatMessage
+// doesn't: when it was said, which model produced it, a reference to any
+// synthesized audio, and (when known) a token count.
+type Turn struct {
+	Role       string
+	Content    string
+	CreatedAt  time.Time
+	Model      string
+	AudioRef   string
+	TokenCount int
+}
+
+// Store persists conversations to a SQLite database, with an FTS5 index
+// over turn content for Search.
+type Store struct {
+	db *sql.DB
+}
+
+// Open creates or opens the database at dbPath, creating its schema on
+// first use.
+func Open(dbPath string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
+		return nil, fmt.Errorf("history: failed to create directory: %w", err)
+	}
+
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("history: failed to open database: %w", err)
+	}
+
+	s := &Store{db: db}
+	if err := s.migrate(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Store) migrate() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS conversations (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			started_at DATETIME NOT NULL,
+			character TEXT NOT NULL DEFAULT '',
+			model TEXT NOT NULL DEFAULT ''
+		);
+		CREATE TABLE IF NOT EXISTS turns (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			conversation_id INTEGER NOT NULL REFERENCES conversations(id),
+			role TEXT NOT NULL,
+			content TEXT NOT NULL,
+			created_at DATETIME NOT NULL,
+			model TEXT NOT NULL DEFAULT '',
+			audio_ref TEXT NOT NULL DEFAULT '',
+			token_count INTEGER NOT NULL DEFAULT 0
+		);
+		CREATE VIRTUAL TABLE IF NOT EXISTS turns_fts USING fts5(
+			content, content='turns', content_rowid='id'
+		);
+		CREATE TRIGGER IF NOT EXISTS turns_ai AFTER INSERT ON turns BEGIN
+			INSERT INTO turns_fts(rowid, content) VALUES (new.id, new.content);
+		END;
+	`)
+	if err != nil {
+		return fmt.Errorf("history: failed to migrate schema: %w", err)
+	}
+	return nil
+}
+
+// Close closes the underlying database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// StartConversation inserts a new conversation row and returns its id.
+func (s *Store) StartConversation(character, model string) (int64, error) {
+	res, err := s.db.Exec(
+		`INSERT INTO conversations (started_at, character, model) VALUES (?, ?, ?)`,
+		time.Now(), character, model,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("history: failed to start conversation: %w", err)
+	}
+	return res.LastInsertId()
+}
+
+// AppendTurn persists one turn of conversationID.
+func (s *Store) AppendTurn(conversationID int64, turn Turn) error {
+	_, err := s.db.Exec(`
+		INSERT INTO turns (conversation_id, role, content, created_at, model, audio_ref, token_count)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		conversationID, turn.Role, turn.Content, turn.CreatedAt, turn.Model, turn.AudioRef, turn.TokenCount,
+	)
+	if err != nil {
+		return fmt.Errorf("history: failed to append turn: %w", err)
+	}
+	return nil
+}
+
+// ListConversations returns every stored conversation, most recent first.
+func (s *Store) ListConversations() ([]ConversationSummary, error) {
+	rows, err := s.db.Query(`
+		SELECT c.id, c.started_at, c.character, c.model, COUNT(t.id)
+		FROM conversations c
+		LEFT JOIN turns t ON t.conversation_id = c.id
+		GROUP BY c.id
+		ORDER BY c.started_at DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("history: failed to list conversations: %w", err)
+	}
+	defer rows.Close()
+
+	var out []ConversationSummary
+	for rows.Next() {
+		var c ConversationSummary
+		if err := rows.Scan(&c.ID, &c.StartedAt, &c.Character, &c.Model, &c.TurnCount); err != nil {
+			return nil, fmt.Errorf("history: failed to scan conversation: %w", err)
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+// GetConversation returns every turn of conversationID as ChatMessages, in
+// the order they were recorded.
+func (s *Store) GetConversation(conversationID int64) ([]gemini.ChatMessage, error) {
+	rows, err := s.db.Query(`
+		SELECT role, content FROM turns
+		WHERE conversation_id = ?
+		ORDER BY id ASC`, conversationID)
+	if err != nil {
+		return nil, fmt.Errorf("history: failed to load conversation: %w", err)
+	}
+	defer rows.Close()
+
+	var out []gemini.ChatMessage
+	for rows.Next() {
+		var m gemini.ChatMessage
+		if err := rows.Scan(&m.Role, &m.Content); err != nil {
+			return nil, fmt.Errorf("history: failed to scan turn: %w", err)
+		}
+		out = append(out, m)
+	}
+	return out, rows.Err()
+}
+
+// Search runs an FTS5 MATCH query over every turn's content and returns up
+// to 50 hits, most recent first.
+func (s *Store) Search(query string) ([]Hit, error) {
+	rows, err := s.db.Query(`
+		SELECT t.conversation_id, t.role, snippet(turns_fts, 0, '[', ']', '...', 8), t.created_at
+		FROM turns_fts
+		JOIN turns t ON t.id = turns_fts.rowid
+		WHERE turns_fts MATCH ?
+		ORDER BY t.created_at DESC
+		LIMIT 50`, query)
+	if err != nil {
+		return nil, fmt.Errorf("history: search failed: %w", err)
+	}
+	defer rows.Close()
+
+	var out []Hit
+	for rows.Next() {
+		var h Hit
+		if err := rows.Scan(&h.ConversationID, &h.Role, &h.Snippet, &h.CreatedAt); err != nil {
+			return nil, fmt.Errorf("history: failed to scan hit: %w", err)
+		}
+		out = append(out, h)
+	}
+	return out, rows.Err()
+}
+
+// Export renders conversationID as Markdown ("markdown", the default) or
+// JSON ("json").
+func (s *Store) Export(conversationID int64, format string) (string, error) {
+	messages, err := s.GetConversation(conversationID)
+	if err != nil {
+		return "", err
+	}
+	if len(messages) == 0 {
+		return "", fmt.Errorf("history: conversation %d not found", conversationID)
+	}
+
+	switch strings.ToLower(format) {
+	case "json":
+		data, err := json.MarshalIndent(messages, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("history: failed to marshal JSON: %w", err)
+		}
+		return string(data), nil
+	case "", "markdown":
+		var sb strings.Builder
+		for _, m := range messages {
+			fmt.Fprintf(&sb, "**%s**: %s\n\n", capitalize(m.Role), m.Content)
+		}
+		return sb.String(), nil
+	default:
+		return "", fmt.Errorf("history: unsupported export format: %q", format)
+	}
+}
+
+// capitalize upper-cases a role name's first letter for Markdown export
+// ("user" -> "User").
+func capitalize(role string) string {
+	if role == "" {
+		return role
+	}
+	return strings.ToUpper(role[:1]) + role[1:]
+}
@@ -0,0 +1,466 @@
+package hotkey
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Action names for the hotkey bindings this app exposes today. New actions
+// should be added here as the app grows more hotkey-driven commands, with a
+// corresponding entry in defaultBindings.
+const (
+	ActionPushToTalk   = "push_to_talk_start"
+	ActionRecordToggle = "record_toggle"
+)
+
+// BindingsFileName is the keybinding config file created inside the app
+// support directory (usually ~/.super-characters), loaded by
+// LoadBindingsFile/NewBindingManager.
+const BindingsFileName = "keybindings.conf"
+
+// defaultBindings are the bindings a fresh BindingManager starts from before
+// its keybindings file is ever loaded, and what Reload falls back to for any
+// action the file doesn't mention. An empty string means "no default
+// hotkey" rather than "unset key" - ActionRecordToggle has historically been
+// configured through settings.SettingsService instead, so its default here
+// is deliberately empty to let that value keep taking effect until the user
+// sets an explicit override in the keybindings file.
+var defaultBindings = map[string]string{
+	ActionPushToTalk:   "Ctrl+Option+Cmd",
+	ActionRecordToggle: "",
+}
+
+// validModifierNames is the canonical modifier vocabulary a binding's
+// hotkey string is checked against - the same names ParseHotkey recognizes.
+var validModifierNames = map[string]bool{
+	"cmd": true, "command": true, "super": true, "win": true, "meta": true,
+	"shift": true, "ctrl": true, "control": true, "alt": true, "option": true,
+}
+
+// validKeyNames is the canonical key-name vocabulary a binding's final
+// "+"-separated part is checked against. It mirrors keycodes_darwin.go's
+// keyNameMap (kept as its own list here since validation has to run on
+// every platform, not just whichever keycode table is actually compiled
+// in) plus the media key names keycodes_darwin.go registers in its init.
+var validKeyNames = buildValidKeyNames()
+
+func buildValidKeyNames() map[string]bool {
+	names := []string{
+		"A", "B", "C", "D", "E", "F", "G", "H", "I", "J", "K", "L", "M",
+		"N", "O", "P", "Q", "R", "S", "T", "U", "V", "W", "X", "Y", "Z",
+		"0", "1", "2", "3", "4", "5", "6", "7", "8", "9",
+		"Equal", "=", "Minus", "-", "LeftBracket", "[", "RightBracket", "]",
+		"Quote", "'", "Semicolon", ";", "Backslash", "\\", "Comma", ",",
+		"Slash", "/", "Period", ".", "Grave", "`",
+		"Space", "Enter", "Return", "Tab", "Esc", "Escape", "Delete",
+		"Backspace", "ForwardDelete", "Home", "End", "PageUp", "PageDown",
+		"Left", "Right", "Up", "Down",
+		"F1", "F2", "F3", "F4", "F5", "F6", "F7", "F8", "F9", "F10",
+		"F11", "F12", "F13", "F14", "F15", "F16", "F17", "F18", "F19", "F20",
+		"CapsLock", "Function", "Fn",
+		"MediaPlayPause", "MediaNext", "MediaPrevious", "MediaPrev",
+		"MediaFast", "MediaRewind", "MediaVolumeUp", "MediaVolumeDown", "MediaMute",
+	}
+	m := make(map[string]bool, len(names))
+	for _, n := range names {
+		m[strings.ToLower(n)] = true
+	}
+	return m
+}
+
+// ValidateHotkeyString checks hotkeyStr against the canonical modifier/key
+// vocabulary, including every step of a chord sequence (see
+// splitChordSteps), so a typo in a hand-edited keybindings file is reported
+// at load time instead of silently failing to grab at the OS level.
+func ValidateHotkeyString(hotkeyStr string) error {
+	steps := splitChordSteps(hotkeyStr)
+	if len(steps) == 0 {
+		return fmt.Errorf("empty hotkey string")
+	}
+
+	for _, step := range steps {
+		parts := strings.Split(step, "+")
+		for i, p := range parts {
+			name := strings.ToLower(strings.TrimSpace(p))
+			if name == "" {
+				return fmt.Errorf("empty key part in %q", step)
+			}
+			if i < len(parts)-1 {
+				if !validModifierNames[name] {
+					return fmt.Errorf("unrecognized modifier %q in %q", p, step)
+				}
+				continue
+			}
+			// The final part is usually a regular key, but a modifier-only
+			// hotkey like "Ctrl+Option+Cmd" ends in a modifier name too.
+			if !validKeyNames[name] && !validModifierNames[name] {
+				return fmt.Errorf("unrecognized key %q in %q", p, step)
+			}
+		}
+	}
+	return nil
+}
+
+// BindingStore is the interface a KeyRecorder writes a freshly captured
+// HotkeyString back into once recording completes, so a "record a new
+// shortcut for this action" flow doesn't need its own persistence logic.
+// BindingManager implements it - see DarwinKeyRecorder.SetBindingStore.
+type BindingStore interface {
+	Rebind(action, hotkeyStr string) error
+}
+
+// LoadBindingsFile reads and parses the [keys] section of a TOML/INI-like
+// keybindings file at path into an action -> hotkey-string map.
+func LoadBindingsFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keybindings file: %w", err)
+	}
+	return parseBindingsINI(data)
+}
+
+// parseBindingsINI parses a minimal TOML/INI subset: "[section]" headers
+// and "key = value" pairs, tolerant of single/double-quoted values and
+// backslash-escaped characters the way a shell would treat them - so a
+// hotkey string containing "+" or a literal space (e.g. `"Ctrl+Shift+Space"`
+// or the chord `Ctrl+K\ Ctrl+R`) round-trips correctly. Only the [keys] section's
+// entries are returned; other sections are ignored, so this file can grow
+// unrelated sections later without this parser needing to change - the
+// same forward-compatibility posture config.parseCharacter takes for
+// unknown keys.
+func parseBindingsINI(data []byte) (map[string]string, error) {
+	bindings := make(map[string]string)
+	section := ""
+
+	lines := strings.Split(string(data), "\n")
+	for i, raw := range lines {
+		line := strings.TrimSpace(raw)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("line %d: malformed section header %q", i+1, line)
+			}
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected \"key = value\", got %q", i+1, line)
+		}
+		if section != "keys" {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		bindings[key] = unquoteShellLike(strings.TrimSpace(value))
+	}
+
+	return bindings, nil
+}
+
+// unquoteShellLike strips a matching pair of surrounding quotes (resolving
+// backslash escapes of that quote character and of backslash itself), or,
+// for an unquoted value, resolves backslash escapes in place (so
+// `Cmd+\ Space` reads as "Cmd+ Space") - the same two conventions a POSIX
+// shell uses for a quoted vs. escaped argument.
+func unquoteShellLike(value string) string {
+	if len(value) >= 2 {
+		quote := value[0]
+		if (quote == '"' || quote == '\'') && value[len(value)-1] == quote {
+			inner := value[1 : len(value)-1]
+			var sb strings.Builder
+			for i := 0; i < len(inner); i++ {
+				if inner[i] == '\\' && i+1 < len(inner) && (inner[i+1] == quote || inner[i+1] == '\\') {
+					i++
+				}
+				sb.WriteByte(inner[i])
+			}
+			return sb.String()
+		}
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] == '\\' && i+1 < len(value) {
+			i++
+		}
+		sb.WriteByte(value[i])
+	}
+	return sb.String()
+}
+
+// quoteBindingValue wraps hotkeyStr in double quotes when it contains a
+// space or quote, so a round-tripped binding parses back unambiguously
+// instead of relying on backslash-escapes.
+func quoteBindingValue(hotkeyStr string) string {
+	if strings.ContainsAny(hotkeyStr, " \t\"") {
+		return `"` + strings.ReplaceAll(hotkeyStr, `"`, `\"`) + `"`
+	}
+	return hotkeyStr
+}
+
+// defaultBindingsPollInterval mirrors transcription.VocabLoader's polling
+// cadence - this repo has no dependency manager to vendor fsnotify, so
+// Watch polls the file's mtime on a timer instead of reacting to a real
+// filesystem-change notification.
+const defaultBindingsPollInterval = 2 * time.Second
+
+// BindingManager loads the user's action -> hotkey bindings from a
+// TOML/INI-like keybindings file, keeps them up to date (Reload, Watch),
+// and persists Rebind calls back to disk. Same Reload/Watch/StopWatch shape
+// as transcription.VocabLoader, for the same reason.
+//
+// BindingManager intentionally doesn't touch a HotkeyRegistry itself -
+// SetOnChange lets the caller (see App.RegisterHotkeys) decide how to
+// react when an action's effective hotkey changes, the same way
+// VocabLoader hands transcription.ApplyVocab a config rather than calling
+// into the transcription pipeline directly.
+type BindingManager struct {
+	path string
+
+	mu       sync.RWMutex
+	bindings map[string]string
+	mtime    time.Time
+	onChange func(action, hotkeyStr string)
+
+	stopCh chan struct{}
+}
+
+// NewBindingManager creates a loader for the keybindings file at path,
+// seeded with defaultBindings until the first Reload. The file doesn't need
+// to exist yet - Reload treats a missing file as "use the defaults" rather
+// than an error, since most users won't have one.
+func NewBindingManager(path string) *BindingManager {
+	return &BindingManager{path: path, bindings: cloneDefaultBindings()}
+}
+
+func cloneDefaultBindings() map[string]string {
+	out := make(map[string]string, len(defaultBindings))
+	for action, hotkeyStr := range defaultBindings {
+		out[action] = hotkeyStr
+	}
+	return out
+}
+
+// SetOnChange registers a callback fired once per action whose effective
+// hotkey string changes as of a Reload or Rebind call, so the caller can
+// re-register that action's OS-level hotkey. Not retroactive - it only
+// fires for changes observed after it's set.
+func (m *BindingManager) SetOnChange(fn func(action, hotkeyStr string)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onChange = fn
+}
+
+// Reload re-reads and re-parses the keybindings file, replacing the
+// manager's in-memory bindings (merged over defaultBindings) and firing
+// SetOnChange's callback for every action whose effective hotkey changed. A
+// missing file resets to defaultBindings instead of failing.
+func (m *BindingManager) Reload() error {
+	bindings := cloneDefaultBindings()
+
+	info, err := os.Stat(m.path)
+	switch {
+	case os.IsNotExist(err):
+		m.mu.Lock()
+		m.mtime = time.Time{}
+		m.mu.Unlock()
+	case err != nil:
+		return fmt.Errorf("failed to stat keybindings file: %w", err)
+	default:
+		loaded, err := LoadBindingsFile(m.path)
+		if err != nil {
+			return err
+		}
+		for action, hotkeyStr := range loaded {
+			if hotkeyStr == "" {
+				// An explicit empty value unbinds the action, overriding
+				// its default.
+				bindings[action] = ""
+				continue
+			}
+			if err := ValidateHotkeyString(hotkeyStr); err != nil {
+				return fmt.Errorf("action %q: %w", action, err)
+			}
+			bindings[action] = hotkeyStr
+		}
+		m.mu.Lock()
+		m.mtime = info.ModTime()
+		m.mu.Unlock()
+	}
+
+	m.setBindings(bindings)
+	slog.Info("[Hotkey] Loaded keybindings file", "path", m.path, "actions", len(bindings))
+	return nil
+}
+
+// Lookup returns action's currently effective hotkey string, or "" if it
+// has neither a configured nor a default binding.
+func (m *BindingManager) Lookup(action string) string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.bindings[action]
+}
+
+// ListActions returns every action this BindingManager knows about,
+// alongside its currently effective hotkey string, for the frontend's
+// rebind UI.
+func (m *BindingManager) ListActions() map[string]string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := make(map[string]string, len(m.bindings))
+	for action, hotkeyStr := range m.bindings {
+		out[action] = hotkeyStr
+	}
+	return out
+}
+
+// Rebind updates action's hotkey to hotkeyStr, persisting the change to the
+// keybindings file and firing SetOnChange's callback. Implements
+// BindingStore, so a KeyRecorder can call this directly once it captures a
+// complete hotkey string. hotkeyStr == "" unbinds the action.
+func (m *BindingManager) Rebind(action, hotkeyStr string) error {
+	if hotkeyStr != "" {
+		if err := ValidateHotkeyString(hotkeyStr); err != nil {
+			return err
+		}
+	}
+
+	m.mu.RLock()
+	bindings := make(map[string]string, len(m.bindings)+1)
+	for a, hk := range m.bindings {
+		bindings[a] = hk
+	}
+	m.mu.RUnlock()
+	bindings[action] = hotkeyStr
+
+	if err := m.save(bindings); err != nil {
+		return err
+	}
+	m.setBindings(bindings)
+	return nil
+}
+
+// setBindings replaces the in-memory bindings and fires onChange for every
+// action whose effective hotkey differs from before.
+func (m *BindingManager) setBindings(newBindings map[string]string) {
+	m.mu.Lock()
+	old := m.bindings
+	m.bindings = newBindings
+	onChange := m.onChange
+	m.mu.Unlock()
+
+	if onChange == nil {
+		return
+	}
+	for action, hotkeyStr := range newBindings {
+		if old[action] != hotkeyStr {
+			onChange(action, hotkeyStr)
+		}
+	}
+}
+
+// save writes bindings to the keybindings file as a "[keys]" section,
+// actions sorted alphabetically for a deterministic diff.
+func (m *BindingManager) save(bindings map[string]string) error {
+	if err := os.MkdirAll(filepath.Dir(m.path), 0755); err != nil {
+		return fmt.Errorf("failed to create keybindings directory: %w", err)
+	}
+
+	actions := make([]string, 0, len(bindings))
+	for action := range bindings {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+
+	var sb strings.Builder
+	sb.WriteString("[keys]\n")
+	for _, action := range actions {
+		hotkeyStr := bindings[action]
+		if hotkeyStr == "" {
+			if defaultBindings[action] != "" {
+				// Written out so Reload doesn't restore the default.
+				fmt.Fprintf(&sb, "%s = \"\"\n", action)
+			}
+			continue
+		}
+		fmt.Fprintf(&sb, "%s = %s\n", action, quoteBindingValue(hotkeyStr))
+	}
+
+	if err := os.WriteFile(m.path, []byte(sb.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write keybindings file: %w", err)
+	}
+
+	if info, err := os.Stat(m.path); err == nil {
+		m.mu.Lock()
+		m.mtime = info.ModTime()
+		m.mu.Unlock()
+	}
+	return nil
+}
+
+// Watch starts polling the keybindings file for changes, calling Reload
+// whenever its mtime changes (including the file appearing or
+// disappearing). Safe to call more than once; later calls are no-ops until
+// StopWatch is called.
+func (m *BindingManager) Watch() {
+	if m.stopCh != nil {
+		return
+	}
+	stop := make(chan struct{})
+	m.stopCh = stop
+	go m.watchLoop(stop)
+}
+
+// StopWatch halts the polling goroutine started by Watch.
+func (m *BindingManager) StopWatch() {
+	if m.stopCh == nil {
+		return
+	}
+	close(m.stopCh)
+	m.stopCh = nil
+}
+
+func (m *BindingManager) watchLoop(stop chan struct{}) {
+	ticker := time.NewTicker(defaultBindingsPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if m.hasChanged() {
+				if err := m.Reload(); err != nil {
+					slog.Warn("[Hotkey] Failed to reload keybindings file", "error", err)
+				}
+			}
+		}
+	}
+}
+
+// hasChanged reports whether the keybindings file's mtime differs from
+// what Reload last saw.
+func (m *BindingManager) hasChanged() bool {
+	info, err := os.Stat(m.path)
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if os.IsNotExist(err) {
+		return !m.mtime.IsZero()
+	}
+	if err != nil {
+		return false
+	}
+	return !info.ModTime().Equal(m.mtime)
+}
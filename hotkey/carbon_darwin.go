@@ -0,0 +1,133 @@
+package hotkey
+
+/*
+#cgo LDFLAGS: -framework Carbon
+#include <Carbon/Carbon.h>
+
+extern void goOnCarbonHotkeyEvent(int slot, int eventType);
+
+// Mirrors MAX_HOTKEYS in hotkey_darwin.go's cgo preamble (each file's cgo
+// preamble is compiled independently, so the #define can't be shared).
+#define MAX_HOTKEYS 32
+
+#define CARBON_EVENT_PRESS 1
+#define CARBON_EVENT_RELEASE 2
+
+static EventHandlerRef carbonHandlerRef = NULL;
+static EventHotKeyRef carbonRefs[MAX_HOTKEYS];
+
+static OSStatus carbonEventHandler(EventHandlerCallRef nextHandler, EventRef event, void *userData) {
+    EventHotKeyID hkID;
+    GetEventParameter(event, kEventParamDirectObject, typeEventHotKeyID, NULL, sizeof(hkID), NULL, &hkID);
+
+    int slot = (int)hkID.id;
+    UInt32 kind = GetEventKind(event);
+    if (kind == kEventHotKeyPressed) {
+        goOnCarbonHotkeyEvent(slot, CARBON_EVENT_PRESS);
+    } else if (kind == kEventHotKeyReleased) {
+        goOnCarbonHotkeyEvent(slot, CARBON_EVENT_RELEASE);
+    }
+    return noErr;
+}
+
+static void ensureCarbonHandlerC() {
+    if (carbonHandlerRef != NULL) {
+        return;
+    }
+    EventTypeSpec eventTypes[2];
+    eventTypes[0].eventClass = kEventClassKeyboard;
+    eventTypes[0].eventKind = kEventHotKeyPressed;
+    eventTypes[1].eventClass = kEventClassKeyboard;
+    eventTypes[1].eventKind = kEventHotKeyReleased;
+
+    InstallApplicationEventHandler(NewEventHandlerUPP(carbonEventHandler), 2, eventTypes, NULL, &carbonHandlerRef);
+}
+
+// registerCarbonSlotC registers a system-wide hotkey via RegisterEventHotKey,
+// which (unlike CGEventTapCreate) does not require Accessibility permission
+// for ordinary, non-modifier-only key combinations.
+static int registerCarbonSlotC(int slot, int keyCode, UInt32 carbonModifiers) {
+    ensureCarbonHandlerC();
+
+    if (carbonRefs[slot] != NULL) {
+        UnregisterEventHotKey(carbonRefs[slot]);
+        carbonRefs[slot] = NULL;
+    }
+
+    EventHotKeyID hkID;
+    hkID.signature = 'SChk';
+    hkID.id = (UInt32)slot;
+
+    OSStatus status = RegisterEventHotKey((UInt32)keyCode, carbonModifiers, hkID,
+        GetApplicationEventTarget(), 0, &carbonRefs[slot]);
+    if (status != noErr) {
+        return -1;
+    }
+    return 0;
+}
+
+static void unregisterCarbonSlotC(int slot) {
+    if (carbonRefs[slot] != NULL) {
+        UnregisterEventHotKey(carbonRefs[slot]);
+        carbonRefs[slot] = NULL;
+    }
+}
+*/
+import "C"
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrModifierOnlyRequiresAccessibility is returned when a modifier-only
+// hotkey (e.g. a bare Control or Fn tap) can't be registered because the
+// event tap backend failed and the Carbon fallback has no way to observe
+// modifier-only combinations (RegisterEventHotKey requires a real key code).
+var ErrModifierOnlyRequiresAccessibility = errors.New("modifier-only hotkeys require Accessibility permission to be granted")
+
+// carbonModifierFlags converts our CGEventFlagMask-based flags into the
+// Carbon modifier bitmask RegisterEventHotKey expects.
+func carbonModifierFlags(flags uint64) uint32 {
+	var out uint32
+	if flags&uint64(C.kCGEventFlagMaskCommand) != 0 {
+		out |= uint32(C.cmdKey)
+	}
+	if flags&uint64(C.kCGEventFlagMaskShift) != 0 {
+		out |= uint32(C.shiftKey)
+	}
+	if flags&uint64(C.kCGEventFlagMaskAlternate) != 0 {
+		out |= uint32(C.optionKey)
+	}
+	if flags&uint64(C.kCGEventFlagMaskControl) != 0 {
+		out |= uint32(C.controlKey)
+	}
+	return out
+}
+
+// registerCarbonSlot installs slot as a Carbon global hotkey and records the
+// id so events can be dispatched by name, mirroring registerSlot's contract
+// for the event-tap backend.
+func registerCarbonSlot(slot int, id string, keyCode int, flags uint64) error {
+	bindSlotID(slot, id)
+
+	res := C.registerCarbonSlotC(C.int(slot), C.int(keyCode), C.UInt32(carbonModifierFlags(flags)))
+	if res != 0 {
+		unbindSlotID(slot)
+		return fmt.Errorf("failed to register Carbon hotkey")
+	}
+	return nil
+}
+
+// unregisterCarbonSlot tears down a slot previously installed via
+// registerCarbonSlot.
+func unregisterCarbonSlot(slot int) {
+	C.unregisterCarbonSlotC(C.int(slot))
+	unbindSlotID(slot)
+}
+
+//export goOnCarbonHotkeyEvent
+func goOnCarbonHotkeyEvent(slot C.int, eventType C.int) {
+	// Carbon's press/release event kinds map 1:1 onto our HotkeyEventType
+	// values, so we can reuse the same dispatch path as the event tap.
+	goOnHotkeyEvent(slot, eventType)
+}
@@ -0,0 +1,408 @@
+// Package eventqueue implements a small mmap-backed ring buffer used to
+// deliver hotkey and key-recording events without losing any under normal
+// operation, including across a crash in the middle of a write.
+//
+// The ring lives in a fixed-size file: a 64-byte header (write/read/consumer
+// cursors and a dropped-frame counter, updated with atomic stores so a
+// partially applied header write is never observed) followed by a
+// byte-addressable data region holding length-prefixed, JSON-encoded
+// frames. Push's commit protocol is: write the frame bytes, sync the data
+// region, store the new write cursor, sync the header. A crash between any
+// of those steps leaves the previously committed write cursor - and
+// therefore everything a reader can see - untouched; the half-written
+// frame is simply never reached. The consumer cursor is persisted the same
+// way as it advances, so a normal restart resumes dispatch where it left
+// off instead of replaying already-delivered frames.
+//
+// When the ring is full, Push overwrites the oldest unread frames rather
+// than block the producer, advancing the read cursor past them and counting
+// them in Dropped; that new read cursor is stored and synced before the
+// frame bytes overwrite the evicted data. That's a deliberate departure from textbook SPSC
+// semantics (there the consumer alone owns the read cursor); it's the only
+// way to satisfy "the producer never blocks" once the ring is full. Push
+// itself also takes a mutex rather than being lock-free - the callers that
+// feed this queue already dispatch each event from its own goroutine rather
+// than a single dedicated producer thread, so there's no true single
+// producer to exploit for a lock-free fast path.
+package eventqueue
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+)
+
+const (
+	headerSize = 64
+
+	offMagic       = 0
+	offCapacity    = 8
+	offWritePos    = 16
+	offReadPos     = 24
+	offDropped     = 32
+	offConsumerPos = 40
+
+	queueMagic = uint64(0x53435148514b5631) // arbitrary "SCHQKV1" tag
+
+	// subscriberBuffer bounds how many decoded events Subscribe's channel
+	// holds before deliverPending starts dropping rather than blocking the
+	// dispatch loop.
+	subscriberBuffer = 256
+
+	// pollInterval is the dispatch loop's fallback wake-up when Push's
+	// notify signal is missed (e.g. coalesced while already awake).
+	pollInterval = 50 * time.Millisecond
+)
+
+// storage abstracts the byte-addressable backing store a Queue reads and
+// writes, so the real mmap+msync implementation (darwin) and the in-memory
+// fallback (everywhere else) can share all of the ring logic below. See
+// mmap_darwin.go and mmap_other.go.
+type storage interface {
+	bytes() []byte
+	sync() error
+	close() error
+}
+
+// Queue is a single-producer/single-consumer ring buffer of T, persisted to
+// a fixed-size file so events survive a crash between Push calls. Use Open
+// to create one and Subscribe to read from it.
+type Queue[T any] struct {
+	mu       sync.Mutex
+	store    storage
+	capacity uint64 // size of the data region in bytes, excluding the header
+
+	// writePos/readPos/dropped/consumerPos mirror the header's atomics;
+	// they're cached here so Push and deliverPending don't need an atomic
+	// load per access while holding mu. consumerPos is this process's
+	// dispatch cursor - it's persisted to offConsumerPos as it advances
+	// (see persistConsumerPos) and seeded from there at Open time, so a
+	// normal restart resumes dispatch where it left off instead of
+	// replaying every frame that was already delivered. readPos only
+	// tracks how far Push has evicted; it's the floor consumerPos can't
+	// fall below, since data before it no longer exists.
+	writePos    uint64
+	readPos     uint64
+	dropped     uint64
+	consumerPos uint64
+
+	subCh   chan T
+	notify  chan struct{}
+	drainCh chan struct{}
+	closeCh chan struct{}
+	closeOnce sync.Once
+}
+
+// Open opens or creates the ring buffer file at path with the given data
+// capacity in bytes. If the file already exists with a matching header, its
+// contents (including any events left over from a prior run) are kept and
+// replayed once through Subscribe; otherwise the header is (re)initialized.
+func Open[T any](path string, capacity uint64) (*Queue[T], error) {
+	if capacity == 0 {
+		return nil, fmt.Errorf("eventqueue: capacity must be positive")
+	}
+
+	store, err := newStorage(path, headerSize+capacity)
+	if err != nil {
+		return nil, fmt.Errorf("eventqueue: failed to open backing store %s: %w", path, err)
+	}
+
+	buf := store.bytes()
+	q := &Queue[T]{
+		store:    store,
+		capacity: capacity,
+		subCh:    make(chan T, subscriberBuffer),
+		notify:   make(chan struct{}, 1),
+		drainCh:  make(chan struct{}, 1),
+		closeCh:  make(chan struct{}),
+	}
+
+	if loadU64(buf, offMagic) != queueMagic || loadU64(buf, offCapacity) != capacity {
+		storeU64(buf, offCapacity, capacity)
+		storeU64(buf, offWritePos, 0)
+		storeU64(buf, offReadPos, 0)
+		storeU64(buf, offDropped, 0)
+		storeU64(buf, offConsumerPos, 0)
+		storeU64(buf, offMagic, queueMagic)
+		if err := store.sync(); err != nil {
+			store.close()
+			return nil, fmt.Errorf("eventqueue: failed to initialize header for %s: %w", path, err)
+		}
+	}
+
+	q.writePos = loadU64(buf, offWritePos)
+	q.readPos = loadU64(buf, offReadPos)
+	q.dropped = loadU64(buf, offDropped)
+	q.consumerPos = loadU64(buf, offConsumerPos)
+	if q.consumerPos < q.readPos {
+		// Those frames have been evicted; there's nothing left to replay
+		// before the eviction boundary.
+		q.consumerPos = q.readPos
+	}
+
+	go q.dispatchLoop()
+	return q, nil
+}
+
+// Push encodes v as JSON and appends it to the ring, evicting the oldest
+// unread frames (and counting them in Dropped) if there isn't enough room.
+// Push never blocks waiting for a consumer.
+func (q *Queue[T]) Push(v T) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		slog.Warn("eventqueue: failed to encode value, dropping", "error", err)
+		return
+	}
+	frameLen := uint64(8 + len(payload))
+
+	q.mu.Lock()
+	if frameLen > q.capacity {
+		q.dropped++
+		q.mu.Unlock()
+		slog.Warn("eventqueue: frame larger than queue capacity, dropping", "size", frameLen, "capacity", q.capacity)
+		return
+	}
+
+	prevReadPos := q.readPos
+	for q.writePos-q.readPos+frameLen > q.capacity {
+		oldLen := binary.LittleEndian.Uint64(q.readAt(q.readPos, 8))
+		if oldLen == 0 || oldLen > q.capacity {
+			// Corrupt or uninitialized ring state - jump forward rather
+			// than spin forever trying to make room.
+			q.readPos = q.writePos
+			break
+		}
+		q.readPos += 8 + oldLen
+		q.dropped++
+	}
+	if q.readPos != prevReadPos {
+		// Commit the eviction before the new frame overwrites the evicted
+		// bytes, so a crash mid-write never leaves the stored read cursor
+		// pointing into a half-overwritten frame.
+		storeU64(q.store.bytes(), offReadPos, q.readPos)
+		storeU64(q.store.bytes(), offDropped, q.dropped)
+		if err := q.store.sync(); err != nil {
+			slog.Warn("eventqueue: failed to sync header", "error", err)
+		}
+	}
+
+	lenPrefix := make([]byte, 8)
+	binary.LittleEndian.PutUint64(lenPrefix, uint64(len(payload)))
+	q.writeAt(q.writePos, lenPrefix)
+	q.writeAt(q.writePos+8, payload)
+	if err := q.store.sync(); err != nil {
+		slog.Warn("eventqueue: failed to sync data region", "error", err)
+	}
+
+	q.writePos += frameLen
+	buf := q.store.bytes()
+	storeU64(buf, offReadPos, q.readPos)
+	storeU64(buf, offDropped, q.dropped)
+	storeU64(buf, offWritePos, q.writePos)
+	if err := q.store.sync(); err != nil {
+		slog.Warn("eventqueue: failed to sync header", "error", err)
+	}
+	q.mu.Unlock()
+
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Subscribe returns a channel of decoded events. It's a façade over the
+// persisted ring: existing callers that expect a plain receive-only channel
+// don't need to change.
+func (q *Queue[T]) Subscribe() <-chan T {
+	return q.subCh
+}
+
+// Depth returns how many bytes of unconsumed data are currently queued for
+// this process's subscriber.
+func (q *Queue[T]) Depth() uint64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.writePos - q.consumerPos
+}
+
+// Dropped returns how many frames have been evicted before being read, plus
+// frames Push discarded for being larger than the ring, across the lifetime
+// of the backing file (including prior runs).
+func (q *Queue[T]) Dropped() uint64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.dropped
+}
+
+// Capacity returns the data region size in bytes.
+func (q *Queue[T]) Capacity() uint64 {
+	return q.capacity
+}
+
+// Drain discards any events currently queued for delivery without reading
+// them, useful when a consumer is about to stop listening and stale events
+// would otherwise be misleading (e.g. hotkey events queued while a tap was
+// being torn down).
+func (q *Queue[T]) Drain() {
+	select {
+	case q.drainCh <- struct{}{}:
+	default:
+	}
+	for {
+		select {
+		case <-q.subCh:
+		default:
+			return
+		}
+	}
+}
+
+// Close stops the dispatch loop and releases the backing store. The file
+// itself, and anything committed to it, is left on disk.
+func (q *Queue[T]) Close() error {
+	var err error
+	q.closeOnce.Do(func() {
+		close(q.closeCh)
+		err = q.store.close()
+	})
+	return err
+}
+
+// dispatchLoop decodes newly committed frames and publishes them to subCh.
+// It wakes on Push's notify signal, falling back to pollInterval in case a
+// signal is coalesced while the loop is already awake handling a prior one.
+func (q *Queue[T]) dispatchLoop() {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-q.closeCh:
+			return
+		case <-q.drainCh:
+			q.mu.Lock()
+			q.consumerPos = q.writePos
+			q.persistConsumerPos()
+			q.mu.Unlock()
+			continue
+		case <-q.notify:
+		case <-ticker.C:
+		}
+		q.deliverPending()
+	}
+}
+
+// deliverPending decodes and publishes every frame between consumerPos and
+// writePos, skipping ahead to readPos first if the producer has evicted
+// frames this consumer hadn't gotten to yet.
+//
+// consumerPos is only persisted once a frame's outcome is actually decided
+// (delivered, or deliberately dropped because the subscriber is full) -
+// never before. Persisting first and sending second would durably record a
+// frame as dispatched before it ever reached subCh, so a crash in that
+// window would lose it silently on the next restart instead of replaying
+// it.
+func (q *Queue[T]) deliverPending() {
+	for {
+		q.mu.Lock()
+		if q.consumerPos < q.readPos {
+			q.consumerPos = q.readPos
+		}
+		if q.consumerPos >= q.writePos {
+			q.mu.Unlock()
+			return
+		}
+
+		payloadLen := binary.LittleEndian.Uint64(q.readAt(q.consumerPos, 8))
+		if payloadLen == 0 || payloadLen > q.capacity {
+			// Shouldn't happen against a well-formed ring; skip to the
+			// write cursor rather than loop forever on corrupt state.
+			// There's no payload to deliver here, so there's nothing lost
+			// by persisting immediately.
+			q.consumerPos = q.writePos
+			q.persistConsumerPos()
+			q.mu.Unlock()
+			return
+		}
+		payload := q.readAt(q.consumerPos+8, int(payloadLen))
+		nextPos := q.consumerPos + 8 + payloadLen
+		q.mu.Unlock()
+
+		var v T
+		if err := json.Unmarshal(payload, &v); err != nil {
+			slog.Warn("eventqueue: failed to decode frame, skipping", "error", err)
+			q.mu.Lock()
+			q.consumerPos = nextPos
+			q.persistConsumerPos()
+			q.mu.Unlock()
+			continue
+		}
+		select {
+		case q.subCh <- v:
+		default:
+			slog.Warn("eventqueue: subscriber channel full, dropping delivery")
+		}
+		q.mu.Lock()
+		q.consumerPos = nextPos
+		q.persistConsumerPos()
+		q.mu.Unlock()
+	}
+}
+
+// persistConsumerPos durably records how far the consumer has actually
+// dispatched, so Open resumes from here on a normal restart instead of
+// replaying frames that were already delivered in a prior run. Caller must
+// hold q.mu.
+func (q *Queue[T]) persistConsumerPos() {
+	buf := q.store.bytes()
+	storeU64(buf, offConsumerPos, q.consumerPos)
+	if err := q.store.sync(); err != nil {
+		slog.Warn("eventqueue: failed to sync consumer position", "error", err)
+	}
+}
+
+// readAt copies n bytes starting at logical offset pos out of the data
+// region, wrapping around the end of the ring if necessary. Caller must
+// hold q.mu.
+func (q *Queue[T]) readAt(pos uint64, n int) []byte {
+	buf := q.store.bytes()
+	out := make([]byte, n)
+	off := headerSize + pos%q.capacity
+	end := off + uint64(n)
+	if end <= headerSize+q.capacity {
+		copy(out, buf[off:end])
+		return out
+	}
+	first := headerSize + q.capacity - off
+	copy(out[:first], buf[off:headerSize+q.capacity])
+	copy(out[first:], buf[headerSize:headerSize+uint64(n)-first])
+	return out
+}
+
+// writeAt copies b into the data region starting at logical offset pos,
+// wrapping around the end of the ring if necessary. Caller must hold q.mu.
+func (q *Queue[T]) writeAt(pos uint64, b []byte) {
+	buf := q.store.bytes()
+	off := headerSize + pos%q.capacity
+	end := off + uint64(len(b))
+	if end <= headerSize+q.capacity {
+		copy(buf[off:end], b)
+		return
+	}
+	first := headerSize + q.capacity - off
+	copy(buf[off:headerSize+q.capacity], b[:first])
+	copy(buf[headerSize:headerSize+uint64(len(b))-first], b[first:])
+}
+
+func loadU64(buf []byte, off uint64) uint64 {
+	return atomic.LoadUint64((*uint64)(unsafe.Pointer(&buf[off])))
+}
+
+func storeU64(buf []byte, off uint64, v uint64) {
+	atomic.StoreUint64((*uint64)(unsafe.Pointer(&buf[off])), v)
+}
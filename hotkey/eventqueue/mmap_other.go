@@ -0,0 +1,20 @@
+//go:build !darwin
+
+package eventqueue
+
+// memStorage is the non-darwin fallback backing store: a plain in-memory
+// buffer with no real persistence. This app only ships for macOS, so losing
+// the mmap-backed crash-durability guarantee here - events don't survive a
+// process restart on these platforms - is an accepted, honest limitation
+// rather than a second native implementation worth maintaining.
+type memStorage struct {
+	data []byte
+}
+
+func newStorage(path string, size uint64) (storage, error) {
+	return &memStorage{data: make([]byte, size)}, nil
+}
+
+func (s *memStorage) bytes() []byte { return s.data }
+func (s *memStorage) sync() error   { return nil }
+func (s *memStorage) close() error  { return nil }
@@ -6,15 +6,29 @@ import (
 	"time"
 )
 
+// defaultChordTimeout is how long HandleKeyDown waits for the next chord
+// step before resetting back to the first step of the sequence.
+const defaultChordTimeout = 800 * time.Millisecond
+
 // ModeHandler encapsulates the mode-specific logic for hotkey handling
 // This separates PTT (Push-to-Talk) vs Toggle mode behavior from the service
 type ModeHandler struct {
-	mode           HotkeyMode
+	mode           Mode
 	isActive       bool      // Whether transcription is currently active
 	isHeldDown     bool      // Whether the hotkey is currently being held
 	holdStartTime  time.Time // When the key was pressed
 	handsFreeMode  bool      // Whether hands-free mode is active
 
+	// Chord (ModeChord) state: chordStep counts steps matched so far
+	// against chordTotal, resetting to 0 either on completion or when
+	// chordTimer fires without a next step arriving in time.
+	chordStep      int
+	chordTotal     int
+	chordTimeout   time.Duration
+	chordTimer     *time.Timer
+	onChordProgress func(step, total int)
+	onChordTimeout  func()
+
 	// Callbacks for actions
 	onStartAction      func()
 	onStopAction       func()
@@ -24,21 +38,74 @@ type ModeHandler struct {
 }
 
 // NewModeHandler creates a new mode handler with the specified mode
-func NewModeHandler(mode HotkeyMode) *ModeHandler {
+func NewModeHandler(mode Mode) *ModeHandler {
 	return &ModeHandler{
-		mode: mode,
+		mode:         mode,
+		chordTimeout: defaultChordTimeout,
+	}
+}
+
+// SetChordSteps configures the number of steps in the chord sequence this
+// handler tracks progress through, resetting any in-progress match. Pass 0
+// to disable chord tracking.
+func (h *ModeHandler) SetChordSteps(total int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.chordTotal = total
+	h.chordStep = 0
+	if h.chordTimer != nil {
+		h.chordTimer.Stop()
+		h.chordTimer = nil
+	}
+}
+
+// SetChordTimeout configures how long HandleKeyDown waits for the next
+// chord step before resetting back to the first step. Default 800ms.
+func (h *ModeHandler) SetChordTimeout(timeout time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if timeout > 0 {
+		h.chordTimeout = timeout
+	}
+}
+
+// SetChordCallbacks sets the chord progress and timeout callbacks.
+// onProgress is called after each step short of completion with the
+// 1-indexed step just matched and the total step count, so the UI can show
+// a "waiting for next key..." hint. onTimeout is called when chordTimeout
+// elapses between steps without the next one arriving, so the caller can
+// reset the underlying hotkey registration back to the first step.
+func (h *ModeHandler) SetChordCallbacks(onProgress func(step, total int), onTimeout func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.onChordProgress = onProgress
+	h.onChordTimeout = onTimeout
+}
+
+// handleChordTimeout fires when chordTimer elapses without the next chord
+// step arriving in time, resetting progress back to the first step.
+func (h *ModeHandler) handleChordTimeout() {
+	h.mu.Lock()
+	h.chordStep = 0
+	h.chordTimer = nil
+	onTimeout := h.onChordTimeout
+	h.mu.Unlock()
+
+	slog.Info("chord sequence timed out, resetting to first step")
+	if onTimeout != nil {
+		onTimeout()
 	}
 }
 
 // SetMode updates the hotkey behavior mode
-func (h *ModeHandler) SetMode(mode HotkeyMode) {
+func (h *ModeHandler) SetMode(mode Mode) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 	h.mode = mode
 }
 
 // GetMode returns the current hotkey mode
-func (h *ModeHandler) GetMode() HotkeyMode {
+func (h *ModeHandler) GetMode() Mode {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 	return h.mode
@@ -97,7 +164,7 @@ func (h *ModeHandler) HandleKeyDown(bindingID string) {
 	}
 
 	switch mode {
-	case ModeHoldToTalk:
+	case ModePushToTalk:
 		if !wasHeldDown {
 			h.mu.Lock()
 			h.isHeldDown = true
@@ -128,6 +195,36 @@ func (h *ModeHandler) HandleKeyDown(bindingID string) {
 				}
 			}
 		}
+
+	case ModeChord:
+		// Every key-down received here already matched the step currently
+		// grabbed at the OS level (see HotkeyRegistry.AdvanceChordStep), so
+		// there's no real "mismatch" case to detect - only a timeout
+		// between matched steps resets progress.
+		h.mu.Lock()
+		h.chordStep++
+		step := h.chordStep
+		total := h.chordTotal
+		onProgress := h.onChordProgress
+		if h.chordTimer != nil {
+			h.chordTimer.Stop()
+			h.chordTimer = nil
+		}
+
+		completed := total > 0 && step >= total
+		if completed {
+			h.chordStep = 0
+			h.mu.Unlock()
+			if onStart != nil {
+				onStart()
+			}
+		} else {
+			h.chordTimer = time.AfterFunc(h.chordTimeout, h.handleChordTimeout)
+			h.mu.Unlock()
+			if onProgress != nil {
+				onProgress(step, total)
+			}
+		}
 	}
 }
 
@@ -147,7 +244,7 @@ func (h *ModeHandler) HandleKeyUp(bindingID string) {
 	}
 
 	switch mode {
-	case ModeHoldToTalk:
+	case ModePushToTalk:
 		if wasHeldDown {
 			if onStop != nil {
 				onStop()
@@ -156,6 +253,9 @@ func (h *ModeHandler) HandleKeyUp(bindingID string) {
 
 	case ModeToggle:
 		// In toggle mode, key up does nothing (state was toggled on key down)
+
+	case ModeChord:
+		// Chord progress is keydown-driven; key up does nothing.
 	}
 }
 
@@ -182,4 +282,9 @@ func (h *ModeHandler) Reset() {
 	h.isActive = false
 	h.isHeldDown = false
 	h.handsFreeMode = false
+	h.chordStep = 0
+	if h.chordTimer != nil {
+		h.chordTimer.Stop()
+		h.chordTimer = nil
+	}
 }
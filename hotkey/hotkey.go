@@ -0,0 +1,279 @@
+package hotkey
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"super-characters/hotkey/eventqueue"
+)
+
+// ErrUnsupported is returned by backends that can't honor a given
+// registration on the current platform/session (e.g. XGrabKey under a
+// Wayland compositor, which doesn't expose global key grabs to clients).
+var ErrUnsupported = errors.New("hotkeys are not supported in this environment")
+
+// maxHotkeySlots bounds how many simultaneous bindings a platform backend
+// tracks. HotkeyRegistry hands out indices below this bound.
+const maxHotkeySlots = 32
+
+// HotkeyEventType represents the type of hotkey event.
+//
+// For an ordinary binding, a press always pairs with a later release.
+//
+// For a modifier-only binding registered with a tap/hold threshold (see
+// RegisterTapHold), EventPress still fires immediately on key-down so
+// push-to-talk stays responsive, but the matching release is reported as
+// either EventTap (held for less than the threshold - treat as a toggle,
+// undoing any transient action the speculative EventPress started) or
+// EventHold (held at least the threshold - treat as a normal PTT release).
+// This lets a single binding serve as Slack/Zoom-style "hold to talk, tap
+// to toggle mute".
+type HotkeyEventType int
+
+const (
+	EventPress HotkeyEventType = iota + 1
+	EventRelease
+	EventTap
+	EventHold
+	// EventChordProgress reports that one step of a ModeChord binding's
+	// sequence matched but the sequence isn't complete yet, so the UI can
+	// show a "waiting for next key..." hint. See HotkeyEvent.Step.
+	EventChordProgress
+)
+
+// String returns a string representation of the event type
+func (t HotkeyEventType) String() string {
+	switch t {
+	case EventPress:
+		return "Press"
+	case EventRelease:
+		return "Release"
+	case EventTap:
+		return "Tap"
+	case EventHold:
+		return "Hold"
+	case EventChordProgress:
+		return "ChordProgress"
+	default:
+		return "Unknown"
+	}
+}
+
+// HotkeyEvent represents an event from the hotkey backend, identified by the
+// registry ID of the binding that fired rather than a fixed binding enum.
+type HotkeyEvent struct {
+	ID        string
+	Type      HotkeyEventType
+	Timestamp time.Time
+	// Step is the 1-indexed chord step just matched, set only on
+	// EventChordProgress events. Zero for every other event type.
+	Step int
+}
+
+// hotkeyQueueCapacity sizes the persisted ring buffer backing hotkey event
+// delivery. Events are tiny (an ID, a type, a timestamp, a step number), so
+// this comfortably holds several seconds of bursty chord/tap activity.
+const hotkeyQueueCapacity = 64 * 1024
+
+var (
+	// hotkeyQueue durably delivers HotkeyEvents from platform backends (and
+	// from HotkeyService's own chord-progress callback) to Go callers,
+	// surviving a crash between events and replaying anything left over
+	// from a prior run. See package eventqueue for the durability story.
+	hotkeyQueue     *eventqueue.Queue[HotkeyEvent]
+	hotkeyQueueOnce sync.Once
+
+	// slotIDs maps a backend slot index back to the registry ID that owns it
+	slotIDs   [maxHotkeySlots]string
+	slotIDsMu sync.RWMutex
+)
+
+// getHotkeyQueue opens the shared hotkey event queue on first use. Every
+// platform backend and HotkeyService itself share this single instance.
+func getHotkeyQueue() *eventqueue.Queue[HotkeyEvent] {
+	hotkeyQueueOnce.Do(func() {
+		path := hotkeyQueueFilePath()
+		q, err := eventqueue.Open[HotkeyEvent](path, hotkeyQueueCapacity)
+		if err != nil {
+			slog.Error("failed to open durable hotkey event queue, falling back to a temp file", "path", path, "error", err)
+			fallback := filepath.Join(os.TempDir(), fmt.Sprintf("super-characters-hotkey-events-%d.queue", os.Getpid()))
+			q, err = eventqueue.Open[HotkeyEvent](fallback, hotkeyQueueCapacity)
+			if err != nil {
+				slog.Error("failed to open fallback hotkey event queue, hotkey events will not be delivered", "error", err)
+				return
+			}
+		}
+		hotkeyQueue = q
+	})
+	return hotkeyQueue
+}
+
+// hotkeyQueueFilePath returns the path of the persisted ring buffer file,
+// alongside this app's other per-user state.
+func hotkeyQueueFilePath() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "super-characters-hotkey-events.queue")
+	}
+	dir := filepath.Join(homeDir, ".super-characters", "hotkey")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return filepath.Join(os.TempDir(), "super-characters-hotkey-events.queue")
+	}
+	return filepath.Join(dir, "events.queue")
+}
+
+// GetEventChannel returns the channel for receiving hotkey events. The same
+// channel surface is shared by every platform backend.
+func GetEventChannel() <-chan HotkeyEvent {
+	q := getHotkeyQueue()
+	if q == nil {
+		return nil
+	}
+	return q.Subscribe()
+}
+
+// publishHotkeyEvent pushes event onto the shared durable queue. Platform
+// backends go through dispatchHotkeyEvent below; HotkeyService's own
+// chord-progress callback (which doesn't originate from a slot) calls this
+// directly.
+func publishHotkeyEvent(event HotkeyEvent) {
+	if q := getHotkeyQueue(); q != nil {
+		q.Push(event)
+	}
+}
+
+// drainEventChannel discards any hotkey events currently queued for
+// delivery, used when tearing down the tap so stale events (e.g. a chord
+// progress hint for a binding that's about to be unregistered) don't reach
+// a consumer that's no longer expecting them.
+func drainEventChannel() {
+	if q := getHotkeyQueue(); q != nil {
+		q.Drain()
+	}
+}
+
+// clearSlotIDs resets the slot->id mapping, used when a backend tears down
+// its whole tap/grab set.
+func clearSlotIDs() {
+	slotIDsMu.Lock()
+	defer slotIDsMu.Unlock()
+	for i := range slotIDs {
+		slotIDs[i] = ""
+	}
+}
+
+// bindSlotID records which registry ID owns a backend slot index.
+func bindSlotID(slot int, id string) {
+	slotIDsMu.Lock()
+	slotIDs[slot] = id
+	slotIDsMu.Unlock()
+}
+
+// unbindSlotID clears a single slot's registry ID.
+func unbindSlotID(slot int) {
+	slotIDsMu.Lock()
+	slotIDs[slot] = ""
+	slotIDsMu.Unlock()
+}
+
+// dispatchHotkeyEvent looks up the registry ID for slot and, if bound, sends
+// a HotkeyEvent on the shared channel. Every platform backend calls this
+// from its native event callback instead of duplicating the lookup/send.
+func dispatchHotkeyEvent(slot int, eventType HotkeyEventType) {
+	slotIDsMu.RLock()
+	id := slotIDs[slot]
+	slotIDsMu.RUnlock()
+	if id == "" {
+		slog.Warn("hotkey event for unknown slot, dropping", "slot", slot)
+		return
+	}
+
+	event := HotkeyEvent{ID: id, Type: eventType, Timestamp: time.Now()}
+	slog.Info("hotkey event dispatched", "id", event.ID, "eventType", event.Type)
+	publishHotkeyEvent(event)
+}
+
+// Modifier is a platform-neutral modifier key flag.
+type Modifier int
+
+const (
+	ModShift Modifier = 1 << iota
+	ModControl
+	ModAlt
+	// ModSuper is Cmd on macOS, the Windows key on Windows, and Super/Meta on Linux.
+	ModSuper
+)
+
+// Hotkey is a platform-neutral description of a key combination, produced
+// by ParseHotkey. Each platform backend resolves KeyName to its own native
+// key code via Lookup, backed by its own keycode table (see
+// keycodes_darwin.go, keycodes_windows.go, keycodes_linux.go). Together,
+// ParseHotkey and Lookup are the one hotkey grammar callers use regardless
+// of OS.
+type Hotkey struct {
+	Modifiers Modifier
+	KeyName   string
+}
+
+// ParseHotkey parses a "+"-joined hotkey string (e.g. "Ctrl+Shift+A") into
+// its platform-neutral modifier and key-name parts.
+func ParseHotkey(hotkeyStr string) Hotkey {
+	parts := strings.Split(hotkeyStr, "+")
+	var mods Modifier
+	for _, p := range parts[:max(len(parts)-1, 0)] {
+		switch strings.ToLower(strings.TrimSpace(p)) {
+		case "cmd", "command", "super", "win", "meta":
+			mods |= ModSuper
+		case "shift":
+			mods |= ModShift
+		case "ctrl", "control":
+			mods |= ModControl
+		case "alt", "option":
+			mods |= ModAlt
+		}
+	}
+
+	keyName := ""
+	if len(parts) > 0 {
+		keyName = strings.TrimSpace(parts[len(parts)-1])
+	}
+
+	return Hotkey{Modifiers: mods, KeyName: keyName}
+}
+
+// ParseHotkeySequence parses a chord sequence into its per-step Hotkey
+// parts. Steps may be separated by whitespace ("Ctrl+K Ctrl+R", in the
+// style of Helix/VS Code keymaps) or by a comma ("Cmd+K, T", in the style
+// of Emacs keymaps) - see splitChordSteps. A single-step string parses to
+// a one-element slice, same as calling ParseHotkey directly.
+func ParseHotkeySequence(sequenceStr string) []Hotkey {
+	steps := splitChordSteps(sequenceStr)
+	out := make([]Hotkey, 0, len(steps))
+	for _, step := range steps {
+		out = append(out, ParseHotkey(step))
+	}
+	return out
+}
+
+// splitChordSteps splits a chord/sequence string into its per-step hotkey
+// strings, treating both whitespace and commas as step separators so
+// "Ctrl+X Ctrl+S" and "Cmd+K, T" both split into two steps.
+func splitChordSteps(sequenceStr string) []string {
+	return strings.FieldsFunc(sequenceStr, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,385 @@
+package hotkey
+
+/*
+#cgo LDFLAGS: -framework CoreGraphics -framework CoreFoundation
+#include <ApplicationServices/ApplicationServices.h>
+
+// Forward declaration
+extern void goOnHotkeyEvent(int slot, int eventType);
+
+// Media keys are identified by negative "key codes" so they don't collide
+// with the regular CGKeyCode space (which is 0-127ish).
+#define MEDIA_KEYCODE_BASE -1000
+
+// Maximum number of hotkeys the tap can track simultaneously. Callers
+// (HotkeyRegistry) hand out slot indices below this bound.
+#define MAX_HOTKEYS 32
+
+typedef struct {
+    int active;
+    int keyCode;
+    uint64_t flags;
+    int isModifierOnly;
+    int isMediaKey;
+    int pressed; // current down/up state, used to suppress autorepeat and detect edges
+
+    // Tap-vs-hold support for modifier-only hotkeys (e.g. a bare Fn or
+    // Control key used as both tap-to-toggle and push-to-talk). Zero
+    // disables the distinction and preserves the old immediate-release behavior.
+    uint64_t tapHoldThresholdNanos;
+    CGEventTimestamp pressedAt;
+} HotkeySlot;
+
+static HotkeySlot slots[MAX_HOTKEYS];
+
+static CFMachPortRef eventTap = NULL;
+static CFRunLoopSourceRef runLoopSource = NULL;
+
+// Event types for Go callback
+#define EVENT_PRESS 1
+#define EVENT_RELEASE 2
+#define EVENT_TAP 3
+#define EVENT_HOLD 4
+
+// System-defined events (media keys) aren't part of the public CGEventType
+// enum, but the tap still sees them at type 14 with this subtype.
+#define NX_SYSDEFINED 14
+#define NX_SUBTYPE_AUX_CONTROL_BUTTONS 8
+
+// Get the modifier flag for a given modifier keycode
+static uint64_t getModifierFlag(int keyCode) {
+    switch (keyCode) {
+        case 0x38: case 0x3C: return kCGEventFlagMaskShift;    // Shift
+        case 0x3B: case 0x3E: return kCGEventFlagMaskControl;  // Control
+        case 0x3A: case 0x3D: return kCGEventFlagMaskAlternate; // Option
+        case 0x37: return kCGEventFlagMaskCommand;              // Command
+        default: return 0;
+    }
+}
+
+// Decode an NX_SYSDEFINED event's data1 field into an NX_KEYTYPE_* media key
+// code and whether it's a key-down.
+static int decodeMediaKey(CGEventRef event, int *isKeyDown) {
+    int64_t data1 = CGEventGetIntegerValueField(event, 149); // kCGSystemDefinedEventData1 private field id
+    int keyCode = (data1 & 0xFFFF0000) >> 16;
+    int keyState = (data1 & 0xFF00) >> 8;
+    *isKeyDown = (keyState == 0x0A); // bit pattern for "down" in NX_SUBTYPE_AUX_CONTROL_BUTTONS
+    return keyCode;
+}
+
+// Callback - iterates the registered slots instead of hardcoding two bindings
+static CGEventRef eventCallback(CGEventTapProxy proxy, CGEventType type, CGEventRef event, void *refcon) {
+    if (type == kCGEventTapDisabledByTimeout) {
+        CGEventTapEnable(eventTap, true);
+        return event;
+    }
+
+    // NX_SYSDEFINED carries media keys (play/pause, next, previous, mute, ...)
+    // as NSEventTypeSystemDefined / NX_SUBTYPE_AUX_CONTROL_BUTTONS.
+    if (type == NX_SYSDEFINED) {
+        int64_t subtype = CGEventGetIntegerValueField(event, 150); // kCGSystemDefinedEventSubtype
+        if (subtype != NX_SUBTYPE_AUX_CONTROL_BUTTONS) {
+            return event;
+        }
+
+        int isKeyDown = 0;
+        int nxKeyType = decodeMediaKey(event, &isKeyDown);
+        int mediaKeyCode = MEDIA_KEYCODE_BASE - nxKeyType;
+
+        for (int i = 0; i < MAX_HOTKEYS; i++) {
+            if (!slots[i].active || !slots[i].isMediaKey || slots[i].keyCode != mediaKeyCode) {
+                continue;
+            }
+            if (slots[i].pressed == isKeyDown) {
+                continue; // no state change, avoid repeat delivery
+            }
+            slots[i].pressed = isKeyDown;
+            goOnHotkeyEvent(i, isKeyDown ? EVENT_PRESS : EVENT_RELEASE);
+            return NULL; // Suppress, this media key is bound
+        }
+        return event; // Not bound, let other apps see it
+    }
+
+    uint64_t mask = kCGEventFlagMaskCommand | kCGEventFlagMaskShift | kCGEventFlagMaskAlternate | kCGEventFlagMaskControl;
+    CGEventFlags flags = CGEventGetFlags(event);
+    uint64_t currentFlags = flags & mask;
+
+    // Handle modifier-only hotkeys via flagsChanged events
+    if (type == kCGEventFlagsChanged) {
+        for (int i = 0; i < MAX_HOTKEYS; i++) {
+            if (!slots[i].active || !slots[i].isModifierOnly) {
+                continue;
+            }
+            int allFlagsMatch = (currentFlags & slots[i].flags) == slots[i].flags;
+            if (allFlagsMatch && !slots[i].pressed) {
+                slots[i].pressed = 1;
+                slots[i].pressedAt = CGEventGetTimestamp(event);
+                goOnHotkeyEvent(i, EVENT_PRESS);
+                return NULL; // Suppress
+            } else if (!allFlagsMatch && slots[i].pressed) {
+                slots[i].pressed = 0;
+
+                if (slots[i].tapHoldThresholdNanos == 0) {
+                    goOnHotkeyEvent(i, EVENT_RELEASE);
+                    return NULL; // Suppress
+                }
+
+                // Tap-vs-hold: EVENT_PRESS above already fired speculatively
+                // so PTT stays responsive; on release we tell the caller
+                // whether it was a quick tap (toggle semantics) or a
+                // genuine hold (push-to-talk semantics) so it can react.
+                uint64_t heldNanos = CGEventGetTimestamp(event) - slots[i].pressedAt;
+                if (heldNanos < slots[i].tapHoldThresholdNanos) {
+                    goOnHotkeyEvent(i, EVENT_TAP);
+                } else {
+                    goOnHotkeyEvent(i, EVENT_HOLD);
+                }
+                return NULL; // Suppress
+            }
+        }
+        return event;
+    }
+
+    // Handle regular key events (keyDown/keyUp)
+    if (type != kCGEventKeyDown && type != kCGEventKeyUp) {
+        return event;
+    }
+
+    CGKeyCode keyCode = (CGKeyCode)CGEventGetIntegerValueField(event, kCGKeyboardEventKeycode);
+
+    // Check for autorepeat
+    int64_t isRepeat = CGEventGetIntegerValueField(event, kCGKeyboardEventAutorepeat);
+    if (isRepeat != 0) {
+        for (int i = 0; i < MAX_HOTKEYS; i++) {
+            if (slots[i].active && !slots[i].isModifierOnly && keyCode == slots[i].keyCode && currentFlags == slots[i].flags) {
+                return NULL; // Suppress repeat, no callback
+            }
+        }
+        return event;
+    }
+
+    for (int i = 0; i < MAX_HOTKEYS; i++) {
+        if (!slots[i].active || slots[i].isModifierOnly || slots[i].isMediaKey) {
+            continue;
+        }
+        if (keyCode != slots[i].keyCode || currentFlags != slots[i].flags) {
+            continue;
+        }
+        if (type == kCGEventKeyDown) {
+            goOnHotkeyEvent(i, EVENT_PRESS);
+        } else {
+            goOnHotkeyEvent(i, EVENT_RELEASE);
+        }
+        return NULL; // Suppress event
+    }
+
+    return event;
+}
+
+static void stopTap() {
+    for (int i = 0; i < MAX_HOTKEYS; i++) {
+        slots[i].active = 0;
+        slots[i].pressed = 0;
+    }
+
+    if (runLoopSource) {
+        CFRunLoopRemoveSource(CFRunLoopGetMain(), runLoopSource, kCFRunLoopCommonModes);
+        CFRelease(runLoopSource);
+        runLoopSource = NULL;
+    }
+    if (eventTap) {
+        CFRelease(eventTap);
+        eventTap = NULL;
+    }
+}
+
+// ensureTapC creates the event tap if it isn't already running. Hotkeys are
+// registered/unregistered independently via registerSlotC/unregisterSlotC.
+static int ensureTapC() {
+    if (eventTap != NULL) return 0; // Already running
+
+    CGEventMask eventMask = CGEventMaskBit(kCGEventKeyDown) | CGEventMaskBit(kCGEventKeyUp) |
+        CGEventMaskBit(kCGEventFlagsChanged) | CGEventMaskBit(NX_SYSDEFINED);
+
+    // kCGSessionEventTap puts us at the session level (like user input)
+    // kCGHeadInsertEventTap puts us at the start of the chain
+    eventTap = CGEventTapCreate(kCGSessionEventTap, kCGHeadInsertEventTap, 0, eventMask, eventCallback, NULL);
+
+    if (!eventTap) {
+        return -1; // Failed (likely permissions)
+    }
+
+    runLoopSource = CFMachPortCreateRunLoopSource(kCFAllocatorDefault, eventTap, 0);
+    CFRunLoopAddSource(CFRunLoopGetMain(), runLoopSource, kCFRunLoopCommonModes);
+    CGEventTapEnable(eventTap, true);
+
+    return 0;
+}
+
+// registerSlotC fills in slot `slot` with a hotkey spec and marks it active.
+// tapHoldThresholdNanos is only meaningful for modifier-only hotkeys; pass 0
+// to keep the legacy immediate press/release behavior.
+static void registerSlotC(int slot, int keyCode, uint64_t flags, int isModOnly, int isMediaKey, uint64_t tapHoldThresholdNanos) {
+    slots[slot].active = 1;
+    slots[slot].keyCode = keyCode;
+    slots[slot].flags = flags;
+    slots[slot].isModifierOnly = isModOnly;
+    slots[slot].isMediaKey = isMediaKey;
+    slots[slot].pressed = 0;
+    slots[slot].tapHoldThresholdNanos = tapHoldThresholdNanos;
+    slots[slot].pressedAt = 0;
+}
+
+// unregisterSlotC clears slot `slot`.
+static void unregisterSlotC(int slot) {
+    slots[slot].active = 0;
+    slots[slot].pressed = 0;
+}
+*/
+import "C"
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+)
+
+//export goOnHotkeyEvent
+func goOnHotkeyEvent(slot C.int, eventType C.int) {
+	dispatchHotkeyEvent(int(slot), HotkeyEventType(eventType))
+}
+
+func parseModifiers(hotkeyStr string) (uint64, error) {
+	var flags uint64
+	parts := strings.Split(hotkeyStr, "+")
+	for _, p := range parts {
+		switch strings.ToLower(strings.TrimSpace(p)) {
+		case "cmd", "command":
+			flags |= uint64(C.kCGEventFlagMaskCommand)
+		case "shift":
+			flags |= uint64(C.kCGEventFlagMaskShift)
+		case "ctrl", "control":
+			flags |= uint64(C.kCGEventFlagMaskControl)
+		case "alt", "option":
+			flags |= uint64(C.kCGEventFlagMaskAlternate)
+		}
+	}
+	return flags, nil
+}
+
+func getKeyName(hotkeyStr string) string {
+	parts := strings.Split(hotkeyStr, "+")
+	if len(parts) > 0 {
+		return strings.TrimSpace(parts[len(parts)-1])
+	}
+	return ""
+}
+
+// isMediaKey reports whether keyCode refers to a system media key (encoded
+// as a negative value in keyNameMap rather than a real CGKeyCode).
+func isMediaKey(keyCode int) bool {
+	return keyCode < 0
+}
+
+// isModifierKey checks if the given keycode is a modifier key
+func isModifierKey(keyCode int) bool {
+	switch keyCode {
+	case kVK_Shift, kVK_RightShift,
+		kVK_Control, kVK_RightControl,
+		kVK_Option, kVK_RightOption,
+		kVK_Command:
+		return true
+	default:
+		return false
+	}
+}
+
+// Lookup resolves a platform-neutral key name (case-insensitively) to this
+// OS's native key code via keyNameMap - here, a Carbon kVK_* virtual key
+// code (or a negative synthetic code for media keys, see isMediaKey).
+// Combined with ParseHotkey, this is the single hotkey grammar callers use
+// across all three platform backends.
+func Lookup(keyName string) (int, bool) {
+	if c, ok := keyNameMap[keyName]; ok {
+		return c, true
+	}
+	if c, ok := keyNameMap[strings.ToUpper(keyName)]; ok {
+		return c, true
+	}
+	for name, c := range keyNameMap {
+		if strings.EqualFold(name, keyName) {
+			return c, true
+		}
+	}
+	return 0, false
+}
+
+// ensureTap makes sure the shared event tap is running. Safe to call
+// multiple times; registering hotkeys is independent of tap lifecycle.
+func ensureTap() error {
+	getHotkeyQueue()
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	if res := C.ensureTapC(); res != 0 {
+		return fmt.Errorf("failed to create event tap (check permissions)")
+	}
+	return nil
+}
+
+// registerSlot parses hotkeyStr and installs it into the given C slot,
+// recording the slot->id mapping so events can be dispatched by name.
+func registerSlot(slot int, id string, hotkeyStr string) error {
+	return registerSlotTapHold(slot, id, hotkeyStr, 0)
+}
+
+// registerSlotTapHold is like registerSlot but additionally installs a
+// tap/hold threshold for modifier-only hotkeys (see RegisterTapHold). A
+// zero threshold keeps the legacy immediate press/release behavior.
+func registerSlotTapHold(slot int, id string, hotkeyStr string, tapHoldThreshold time.Duration) error {
+	flags, err := parseModifiers(hotkeyStr)
+	if err != nil {
+		return err
+	}
+	keyName := getKeyName(hotkeyStr)
+	keyCode, ok := Lookup(keyName)
+	if !ok {
+		return fmt.Errorf("unknown key: %s", keyName)
+	}
+
+	isModOnly := 0
+	if isModifierKey(keyCode) {
+		isModOnly = 1
+	}
+	isMedia := 0
+	if isMediaKey(keyCode) {
+		isMedia = 1
+	}
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	bindSlotID(slot, id)
+
+	C.registerSlotC(C.int(slot), C.int(keyCode), C.uint64_t(flags), C.int(isModOnly), C.int(isMedia),
+		C.uint64_t(tapHoldThreshold.Nanoseconds()))
+	return nil
+}
+
+// unregisterSlot clears a previously registered C slot.
+func unregisterSlot(slot int) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	C.unregisterSlotC(C.int(slot))
+	unbindSlotID(slot)
+}
+
+func stopTap() {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	C.stopTap()
+	drainEventChannel()
+	clearSlotIDs()
+}
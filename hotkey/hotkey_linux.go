@@ -0,0 +1,295 @@
+package hotkey
+
+/*
+#cgo LDFLAGS: -lX11
+#include <X11/Xlib.h>
+#include <X11/Xutil.h>
+#include <string.h>
+#include <stdlib.h>
+#include <time.h>
+
+extern void goOnHotkeyEvent(int slot, int eventType);
+
+#define MAX_HOTKEYS 32
+
+#define EVENT_PRESS 1
+#define EVENT_RELEASE 2
+
+typedef struct {
+    int active;
+    KeyCode keyCode;
+    unsigned int modifiers;
+} HotkeySlot;
+
+static Display *display = NULL;
+static Window rootWindow;
+static HotkeySlot slots[MAX_HOTKEYS];
+static volatile int lastGrabFailed = 0;
+
+// XGrabKey signals a conflicting grab (another client already owns this
+// key+modifier combo) by raising a BadAccess error rather than returning a
+// status code, so we have to catch it via the error handler.
+static int xErrorHandler(Display *d, XErrorEvent *e) {
+    if (e->error_code == BadAccess) {
+        lastGrabFailed = 1;
+    }
+    return 0;
+}
+
+// Ignoring the lock/numlock modifier state means a grab for "Ctrl+A" would
+// only fire when NumLock/CapsLock happen to be off. Grab every combination
+// of the common lock modifiers so the hotkey fires regardless of their state.
+static const unsigned int lockMasksC[4] = {0, LockMask, Mod2Mask, LockMask | Mod2Mask};
+
+static int openDisplayC() {
+    if (display != NULL) {
+        return 0;
+    }
+    display = XOpenDisplay(NULL);
+    if (display == NULL) {
+        return -1;
+    }
+    rootWindow = DefaultRootWindow(display);
+    XSetErrorHandler(xErrorHandler);
+    return 0;
+}
+
+static int registerSlotC(int slot, KeyCode keyCode, unsigned int modifiers) {
+    slots[slot].active = 1;
+    slots[slot].keyCode = keyCode;
+    slots[slot].modifiers = modifiers;
+
+    lastGrabFailed = 0;
+    for (int i = 0; i < 4; i++) {
+        XGrabKey(display, keyCode, modifiers | lockMasksC[i], rootWindow, True, GrabModeAsync, GrabModeAsync);
+    }
+    XSync(display, False);
+    if (lastGrabFailed) {
+        for (int i = 0; i < 4; i++) {
+            XUngrabKey(display, keyCode, modifiers | lockMasksC[i], rootWindow);
+        }
+        slots[slot].active = 0;
+        return -1;
+    }
+    return 0;
+}
+
+static void unregisterSlotC(int slot) {
+    if (!slots[slot].active) {
+        return;
+    }
+    for (int i = 0; i < 4; i++) {
+        XUngrabKey(display, slots[slot].keyCode, slots[slot].modifiers | lockMasksC[i], rootWindow);
+    }
+    slots[slot].active = 0;
+}
+
+static void stopTapC() {
+    for (int i = 0; i < MAX_HOTKEYS; i++) {
+        unregisterSlotC(i);
+    }
+}
+
+// runEventLoopC blocks processing XGrabKey events until stopRequested is set
+// by the Go side. Meant to run on its own goroutine.
+static volatile int stopRequested = 0;
+
+static void runEventLoopC() {
+    XEvent event;
+    while (!stopRequested) {
+        if (XPending(display) == 0) {
+            // Avoid a busy loop; XNextEvent would otherwise block forever
+            // even after stopTap, since we have no event to wake it with.
+            struct timespec ts = {0, 10 * 1000 * 1000};
+            nanosleep(&ts, NULL);
+            continue;
+        }
+        XNextEvent(display, &event);
+        if (event.type != KeyPress && event.type != KeyRelease) {
+            continue;
+        }
+        XKeyEvent *ke = (XKeyEvent *)&event;
+        for (int i = 0; i < MAX_HOTKEYS; i++) {
+            if (!slots[i].active || slots[i].keyCode != ke->keycode) {
+                continue;
+            }
+            unsigned int ignoredLocks = LockMask | Mod2Mask;
+            if ((ke->state & ~ignoredLocks) != slots[i].modifiers) {
+                continue;
+            }
+            goOnHotkeyEvent(i, event.type == KeyPress ? EVENT_PRESS : EVENT_RELEASE);
+            break;
+        }
+    }
+}
+
+static void requestStopC() {
+    stopRequested = 1;
+}
+*/
+import "C"
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+//export goOnHotkeyEvent
+func goOnHotkeyEvent(slot C.int, eventType C.int) {
+	dispatchHotkeyEvent(int(slot), HotkeyEventType(eventType))
+}
+
+var (
+	tapOnce sync.Once
+	tapErr  error
+)
+
+// ensureTap opens the X display and starts the event loop goroutine. Under a
+// Wayland session without XWayland (no DISPLAY), or when the compositor
+// doesn't run an X server at all, this fails with ErrUnsupported: Wayland
+// gives clients no equivalent of XGrabKey, so there is no fallback to offer.
+func ensureTap() error {
+	getHotkeyQueue()
+
+	tapOnce.Do(func() {
+		if os.Getenv("DISPLAY") == "" {
+			tapErr = ErrUnsupported
+			return
+		}
+
+		runtime.LockOSThread()
+		if C.openDisplayC() != 0 {
+			runtime.UnlockOSThread()
+			tapErr = ErrUnsupported
+			return
+		}
+		runtime.UnlockOSThread()
+
+		go func() {
+			runtime.LockOSThread()
+			defer runtime.UnlockOSThread()
+			C.runEventLoopC()
+		}()
+	})
+	return tapErr
+}
+
+// registerSlot parses hotkeyStr and installs it as an X11 XGrabKey grab.
+func registerSlot(slot int, id string, hotkeyStr string) error {
+	return registerSlotTapHold(slot, id, hotkeyStr, 0)
+}
+
+// registerSlotTapHold exists for API symmetry with the darwin backend;
+// tapHoldThreshold has no effect. X11 delivers a real KeyRelease event for
+// every grab (unlike Windows' RegisterHotKey), but this package doesn't yet
+// have a caller that wants tap/hold semantics on Linux.
+func registerSlotTapHold(slot int, id string, hotkeyStr string, _ time.Duration) error {
+	mods, err := parseModifiers(hotkeyStr)
+	if err != nil {
+		return err
+	}
+	keyName := getKeyName(hotkeyStr)
+	keysym, ok := Lookup(keyName)
+	if !ok {
+		return fmt.Errorf("unknown key: %s", keyName)
+	}
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	keyCode := C.XKeysymToKeycode(C.display, C.KeySym(keysym))
+	if keyCode == 0 {
+		return fmt.Errorf("no keycode mapped for key: %s", keyName)
+	}
+
+	bindSlotID(slot, id)
+
+	if C.registerSlotC(C.int(slot), keyCode, C.uint(mods)) != 0 {
+		unbindSlotID(slot)
+		return fmt.Errorf("failed to grab hotkey %q (likely already bound by another application)", hotkeyStr)
+	}
+	return nil
+}
+
+// unregisterSlot releases a previously grabbed slot.
+func unregisterSlot(slot int) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	C.unregisterSlotC(C.int(slot))
+	unbindSlotID(slot)
+}
+
+// stopTap releases every grab and stops the event loop goroutine.
+func stopTap() {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	C.stopTapC()
+	C.requestStopC()
+	drainEventChannel()
+	clearSlotIDs()
+}
+
+// parseModifiers converts a "+"-joined hotkey string into an X11 modifier mask.
+func parseModifiers(hotkeyStr string) (uint64, error) {
+	var mods uint64
+	parts := strings.Split(hotkeyStr, "+")
+	for _, p := range parts[:max(len(parts)-1, 0)] {
+		switch strings.ToLower(strings.TrimSpace(p)) {
+		case "shift":
+			mods |= uint64(C.ShiftMask)
+		case "ctrl", "control":
+			mods |= uint64(C.ControlMask)
+		case "alt", "option":
+			mods |= uint64(C.Mod1Mask)
+		case "cmd", "command", "super", "win", "meta":
+			mods |= uint64(C.Mod4Mask)
+		}
+	}
+	return mods, nil
+}
+
+func getKeyName(hotkeyStr string) string {
+	parts := strings.Split(hotkeyStr, "+")
+	if len(parts) > 0 {
+		return strings.TrimSpace(parts[len(parts)-1])
+	}
+	return ""
+}
+
+// isMediaKey reports whether keysym (from keyNameMap) is an XF86 media key.
+func isMediaKey(keysym int) bool {
+	return keysym >= 0x1008ff00 && keysym <= 0x1008ffff
+}
+
+// isModifierKey reports whether keysym is a bare modifier key.
+func isModifierKey(keysym int) bool {
+	switch keysym {
+	case 0xffe1, 0xffe3, 0xffe9, 0xffeb, 0xffe7:
+		return true
+	default:
+		return false
+	}
+}
+
+// Lookup resolves a platform-neutral key name (case-insensitively) to its
+// X11 keysym via keyNameMap. registerSlotTapHold runs the result through
+// XKeysymToKeycode to get the keycode XGrabKey actually wants.
+func Lookup(keyName string) (int, bool) {
+	if c, ok := keyNameMap[keyName]; ok {
+		return c, true
+	}
+	if c, ok := keyNameMap[strings.ToUpper(keyName)]; ok {
+		return c, true
+	}
+	for name, c := range keyNameMap {
+		if strings.EqualFold(name, keyName) {
+			return c, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,226 @@
+package hotkey
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// Windows has no equivalent to a tap/hold distinction on a modifier-only
+// RegisterHotKey binding (the API only ever delivers WM_HOTKEY on press), so
+// tapHoldThreshold is accepted for API symmetry with the darwin backend but
+// otherwise ignored: every binding fires EVENT_PRESS immediately followed by
+// EVENT_RELEASE, since Windows gives us no separate key-up signal for it.
+const (
+	wmHotkey    = 0x0312
+	pmRemove    = 0x0001
+	modAlt      = 0x0001
+	modControl  = 0x0002
+	modShift    = 0x0004
+	modWin      = 0x0008
+	modNoRepeat = 0x4000
+)
+
+var (
+	user32                 = syscall.NewLazyDLL("user32.dll")
+	kernel32               = syscall.NewLazyDLL("kernel32.dll")
+	procRegisterHotKey     = user32.NewProc("RegisterHotKey")
+	procUnregisterHotKey   = user32.NewProc("UnregisterHotKey")
+	procPeekMessageW       = user32.NewProc("PeekMessageW")
+	procGetCurrentThreadID = kernel32.NewProc("GetCurrentThreadId")
+)
+
+type win32Msg struct {
+	hwnd    uintptr
+	message uint32
+	wParam  uintptr
+	lParam  uintptr
+	time    uint32
+	pt      struct{ x, y int32 }
+}
+
+var (
+	pumpOnce  sync.Once
+	pumpReady = make(chan struct{})
+	pumpWork  = make(chan func(), 1)
+)
+
+// ensureTap starts the dedicated message pump goroutine that RegisterHotKey
+// needs: hotkeys registered with a NULL window handle deliver WM_HOTKEY to
+// the registering thread's message queue, so that thread must pump messages
+// for the lifetime of the process. registerSlot/unregisterSlot hand their
+// Win32 calls to the pump goroutine over pumpWork so they always run on the
+// thread that will actually receive the hotkey.
+func ensureTap() error {
+	getHotkeyQueue()
+	pumpOnce.Do(func() {
+		go messagePump()
+		<-pumpReady
+	})
+	return nil
+}
+
+func messagePump() {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	// Touch GetCurrentThreadId so the thread has a message queue before we
+	// signal ready; Win32 creates a thread's queue lazily on first use.
+	procGetCurrentThreadID.Call()
+	close(pumpReady)
+
+	var m win32Msg
+	for {
+		for {
+			ret, _, _ := procPeekMessageW.Call(uintptr(unsafe.Pointer(&m)), 0, 0, 0, pmRemove)
+			if ret == 0 {
+				break
+			}
+			if m.message == wmHotkey {
+				slot := int(m.wParam)
+				dispatchHotkeyEvent(slot, EventPress)
+				dispatchHotkeyEvent(slot, EventRelease)
+			}
+		}
+
+		select {
+		case fn := <-pumpWork:
+			fn()
+		case <-time.After(15 * time.Millisecond):
+		}
+	}
+}
+
+// runOnPump runs fn on the message pump's OS thread and waits for it to
+// finish.
+func runOnPump(fn func()) {
+	done := make(chan struct{})
+	pumpWork <- func() {
+		fn()
+		close(done)
+	}
+	<-done
+}
+
+// registerSlot parses hotkeyStr and registers it with RegisterHotKey, using
+// slot as the hotkey's id so WM_HOTKEY's wParam tells us which binding fired.
+func registerSlot(slot int, id string, hotkeyStr string) error {
+	return registerSlotTapHold(slot, id, hotkeyStr, 0)
+}
+
+// registerSlotTapHold exists for API symmetry with the darwin backend;
+// tapHoldThreshold has no effect (see the comment on the constants at the
+// top of this file).
+func registerSlotTapHold(slot int, id string, hotkeyStr string, _ time.Duration) error {
+	mods, keyCode, err := parseHotkeyWin(hotkeyStr)
+	if err != nil {
+		return err
+	}
+
+	bindSlotID(slot, id)
+
+	var ok uintptr
+	runOnPump(func() {
+		ok, _, _ = procRegisterHotKey.Call(0, uintptr(slot), uintptr(mods|modNoRepeat), uintptr(keyCode))
+	})
+	if ok == 0 {
+		unbindSlotID(slot)
+		return fmt.Errorf("failed to register hotkey %q", hotkeyStr)
+	}
+	return nil
+}
+
+// unregisterSlot tears down a previously registered hotkey.
+func unregisterSlot(slot int) {
+	runOnPump(func() {
+		procUnregisterHotKey.Call(0, uintptr(slot))
+	})
+	unbindSlotID(slot)
+}
+
+// stopTap resets shared bookkeeping once the last binding is gone. The
+// message pump goroutine is left running, ready for the next registerSlot
+// call.
+func stopTap() {
+	drainEventChannel()
+	clearSlotIDs()
+}
+
+// parseHotkeyWin converts a "+"-joined hotkey string into a MOD_* bitmask and
+// a virtual-key code.
+func parseHotkeyWin(hotkeyStr string) (mods uint32, keyCode int, err error) {
+	parts := strings.Split(hotkeyStr, "+")
+	for _, p := range parts[:max(len(parts)-1, 0)] {
+		switch strings.ToLower(strings.TrimSpace(p)) {
+		case "cmd", "command", "super", "win":
+			mods |= modWin
+		case "shift":
+			mods |= modShift
+		case "ctrl", "control":
+			mods |= modControl
+		case "alt", "option":
+			mods |= modAlt
+		}
+	}
+
+	keyName := getKeyName(hotkeyStr)
+	code, ok := Lookup(keyName)
+	if !ok {
+		return 0, 0, fmt.Errorf("unknown key: %s", keyName)
+	}
+	return mods, code, nil
+}
+
+func parseModifiers(hotkeyStr string) (uint64, error) {
+	mods, _, err := parseHotkeyWin(hotkeyStr)
+	return uint64(mods), err
+}
+
+func getKeyName(hotkeyStr string) string {
+	parts := strings.Split(hotkeyStr, "+")
+	if len(parts) > 0 {
+		return strings.TrimSpace(parts[len(parts)-1])
+	}
+	return ""
+}
+
+// isMediaKey reports whether keyCode refers to a system media key.
+func isMediaKey(keyCode int) bool {
+	switch keyCode {
+	case vkVolumeMute, vkVolumeDown, vkVolumeUp, vkMediaNext, vkMediaPrev, vkMediaPlay:
+		return true
+	default:
+		return false
+	}
+}
+
+// isModifierKey reports whether keyCode is a bare modifier key.
+func isModifierKey(keyCode int) bool {
+	switch keyCode {
+	case vkShift, vkControl, vkMenu, vkLWin, vkRWin:
+		return true
+	default:
+		return false
+	}
+}
+
+// Lookup resolves a platform-neutral key name (case-insensitively) to its
+// Windows VK_* virtual-key code via keyNameMap.
+func Lookup(keyName string) (int, bool) {
+	if c, ok := keyNameMap[keyName]; ok {
+		return c, true
+	}
+	if c, ok := keyNameMap[strings.ToUpper(keyName)]; ok {
+		return c, true
+	}
+	for name, c := range keyNameMap {
+		if strings.EqualFold(name, keyName) {
+			return c, true
+		}
+	}
+	return 0, false
+}
@@ -160,3 +160,37 @@ var keyNameMap = map[string]int{
 	"Command": kVK_Command, "Cmd": kVK_Command, "RightCommand": kVK_RightCommand,
 	"CapsLock": kVK_CapsLock, "Function": kVK_Function, "Fn": kVK_Function,
 }
+
+// NX_KEYTYPE_* codes from <IOKit/hidsystem/ev_keymap.h>, for system media keys
+// delivered via NX_SYSDEFINED events rather than regular CGKeyCodes.
+const (
+	nxKeyTypeSoundUp   = 0
+	nxKeyTypeSoundDown = 1
+	nxKeyTypeMute      = 7
+	nxKeyTypePlay      = 10
+	nxKeyTypeNext      = 17
+	nxKeyTypePrevious  = 18
+	nxKeyTypeFast      = 19
+	nxKeyTypeRewind    = 20
+)
+
+// mediaKeyCodeBase maps an NX_KEYTYPE_* code into our keyCode space as a
+// negative number, keeping it disjoint from real CGKeyCode values (which are
+// all non-negative) without needing a separate "is this a media key" map.
+const mediaKeyCodeBase = -1000
+
+func init() {
+	for name, nxType := range map[string]int{
+		"MediaPlayPause": nxKeyTypePlay,
+		"MediaNext":      nxKeyTypeNext,
+		"MediaPrevious":  nxKeyTypePrevious,
+		"MediaPrev":      nxKeyTypePrevious,
+		"MediaFast":      nxKeyTypeFast,
+		"MediaRewind":    nxKeyTypeRewind,
+		"MediaVolumeUp":  nxKeyTypeSoundUp,
+		"MediaVolumeDown": nxKeyTypeSoundDown,
+		"MediaMute":      nxKeyTypeMute,
+	} {
+		keyNameMap[name] = mediaKeyCodeBase - nxType
+	}
+}
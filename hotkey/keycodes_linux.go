@@ -0,0 +1,36 @@
+package hotkey
+
+// keyNameMap maps a hotkey key name to an X11 keysym (from
+// <X11/keysymdef.h>). XGrabKey wants a keycode, not a keysym, so
+// Lookup's caller (registerSlot) runs these through XKeysymToKeycode at
+// registration time.
+var keyNameMap = map[string]int{
+	"A": 0x0061, "B": 0x0062, "C": 0x0063, "D": 0x0064, "E": 0x0065,
+	"F": 0x0066, "G": 0x0067, "H": 0x0068, "I": 0x0069, "J": 0x006a,
+	"K": 0x006b, "L": 0x006c, "M": 0x006d, "N": 0x006e, "O": 0x006f,
+	"P": 0x0070, "Q": 0x0071, "R": 0x0072, "S": 0x0073, "T": 0x0074,
+	"U": 0x0075, "V": 0x0076, "W": 0x0077, "X": 0x0078, "Y": 0x0079,
+	"Z": 0x007a,
+	"0": 0x0030, "1": 0x0031, "2": 0x0032, "3": 0x0033, "4": 0x0034,
+	"5": 0x0035, "6": 0x0036, "7": 0x0037, "8": 0x0038, "9": 0x0039,
+	// Navigation and editing
+	"Space": 0x0020, "Enter": 0xff0d, "Return": 0xff0d,
+	"Tab": 0xff09, "Esc": 0xff1b, "Escape": 0xff1b,
+	"Delete": 0xffff, "Backspace": 0xff08,
+	"Home": 0xff50, "End": 0xff57,
+	"PageUp": 0xff55, "PageDown": 0xff56,
+	"Left": 0xff51, "Right": 0xff53, "Up": 0xff52, "Down": 0xff54,
+	// Function keys
+	"F1": 0xffbe, "F2": 0xffbf, "F3": 0xffc0, "F4": 0xffc1, "F5": 0xffc2,
+	"F6": 0xffc3, "F7": 0xffc4, "F8": 0xffc5, "F9": 0xffc6, "F10": 0xffc7,
+	"F11": 0xffc8, "F12": 0xffc9, "F13": 0xffca, "F14": 0xffcb, "F15": 0xffcc,
+	"F16": 0xffcd, "F17": 0xffce, "F18": 0xffcf, "F19": 0xffd0, "F20": 0xffd1,
+	// Modifier keys (for modifier-only hotkeys)
+	"Shift": 0xffe1, "Control": 0xffe3, "Ctrl": 0xffe3,
+	"Option": 0xffe9, "Alt": 0xffe9,
+	"Command": 0xffeb, "Cmd": 0xffeb, "Super": 0xffeb, "Meta": 0xffe7,
+	// Media keys (XF86 vendor keysyms)
+	"MediaPlayPause": 0x1008ff14, "MediaNext": 0x1008ff17, "MediaPrevious": 0x1008ff16,
+	"MediaPrev": 0x1008ff16, "MediaVolumeUp": 0x1008ff13, "MediaVolumeDown": 0x1008ff11,
+	"MediaMute": 0x1008ff12,
+}
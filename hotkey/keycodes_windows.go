@@ -0,0 +1,84 @@
+package hotkey
+
+// Virtual-key codes from <winuser.h>, used with RegisterHotKey.
+const (
+	vkBack   = 0x08
+	vkTab    = 0x09
+	vkReturn = 0x0D
+	vkEscape = 0x1B
+	vkSpace  = 0x20
+	vkPrior  = 0x21 // Page Up
+	vkNext   = 0x22 // Page Down
+	vkEnd    = 0x23
+	vkHome   = 0x24
+	vkLeft   = 0x25
+	vkUp     = 0x26
+	vkRight  = 0x27
+	vkDown   = 0x28
+	vkDelete = 0x2E
+
+	vkF1  = 0x70
+	vkF2  = 0x71
+	vkF3  = 0x72
+	vkF4  = 0x73
+	vkF5  = 0x74
+	vkF6  = 0x75
+	vkF7  = 0x76
+	vkF8  = 0x77
+	vkF9  = 0x78
+	vkF10 = 0x79
+	vkF11 = 0x7A
+	vkF12 = 0x7B
+	vkF13 = 0x7C
+	vkF14 = 0x7D
+	vkF15 = 0x7E
+	vkF16 = 0x7F
+	vkF17 = 0x80
+	vkF18 = 0x81
+	vkF19 = 0x82
+	vkF20 = 0x83
+
+	vkShift   = 0x10
+	vkControl = 0x11
+	vkMenu    = 0x12 // Alt
+	vkLWin    = 0x5B
+	vkRWin    = 0x5C
+
+	vkVolumeMute = 0xAD
+	vkVolumeDown = 0xAE
+	vkVolumeUp   = 0xAF
+	vkMediaNext  = 0xB0
+	vkMediaPrev  = 0xB1
+	vkMediaPlay  = 0xB3
+)
+
+// keyNameMap maps a hotkey key name to its Windows virtual-key code. Letters
+// and digits use their ASCII values directly, matching VK_0..VK_9/VK_A..VK_Z.
+var keyNameMap = map[string]int{
+	"A": 'A', "B": 'B', "C": 'C', "D": 'D', "E": 'E', "F": 'F', "G": 'G',
+	"H": 'H', "I": 'I', "J": 'J', "K": 'K', "L": 'L', "M": 'M', "N": 'N',
+	"O": 'O', "P": 'P', "Q": 'Q', "R": 'R', "S": 'S', "T": 'T', "U": 'U',
+	"V": 'V', "W": 'W', "X": 'X', "Y": 'Y', "Z": 'Z',
+	"0": '0', "1": '1', "2": '2', "3": '3', "4": '4',
+	"5": '5', "6": '6', "7": '7', "8": '8', "9": '9',
+	// Navigation and editing
+	"Space": vkSpace, "Enter": vkReturn, "Return": vkReturn,
+	"Tab": vkTab, "Esc": vkEscape, "Escape": vkEscape,
+	"Delete": vkDelete, "Backspace": vkBack,
+	"Home": vkHome, "End": vkEnd,
+	"PageUp": vkPrior, "PageDown": vkNext,
+	"Left": vkLeft, "Right": vkRight, "Up": vkUp, "Down": vkDown,
+	// Function keys
+	"F1": vkF1, "F2": vkF2, "F3": vkF3, "F4": vkF4, "F5": vkF5,
+	"F6": vkF6, "F7": vkF7, "F8": vkF8, "F9": vkF9, "F10": vkF10,
+	"F11": vkF11, "F12": vkF12, "F13": vkF13, "F14": vkF14, "F15": vkF15,
+	"F16": vkF16, "F17": vkF17, "F18": vkF18, "F19": vkF19, "F20": vkF20,
+	// Modifier keys (for modifier-only hotkeys)
+	"Shift": vkShift, "Control": vkControl, "Ctrl": vkControl,
+	"Option": vkMenu, "Alt": vkMenu,
+	"Command": vkLWin, "Cmd": vkLWin, "Super": vkLWin, "Win": vkLWin,
+	// Media keys
+	"MediaPlayPause": vkMediaPlay, "MediaNext": vkMediaNext, "MediaPrevious": vkMediaPrev,
+	"MediaPrev": vkMediaPrev, "MediaVolumeUp": vkVolumeUp, "MediaVolumeDown": vkVolumeDown,
+	"MediaMute": vkVolumeMute,
+}
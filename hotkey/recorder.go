@@ -1,5 +1,91 @@
 package hotkey
 
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"super-characters/hotkey/eventqueue"
+)
+
+// defaultChordRecordingTimeout matches ModeHandler's default chord timeout,
+// so a recorded chord binding behaves the same way once it's active.
+const defaultChordRecordingTimeout = 800 * time.Millisecond
+
+// recorderQueueCapacity sizes the persisted ring buffer backing recorded key
+// events - smaller than the hotkey queue since recording sessions are short,
+// interactive, and produce events at human typing speed.
+const recorderQueueCapacity = 16 * 1024
+
+var (
+	// recorderQueue durably delivers RecordedKeyEvents, shared across
+	// recording sessions in this process (and across whichever platform
+	// KeyRecorder is compiled in) the same way hotkeyQueue is shared across
+	// hotkey bindings. See package eventqueue for the durability story.
+	recorderQueue     *eventqueue.Queue[RecordedKeyEvent]
+	recorderQueueOnce sync.Once
+)
+
+// getRecorderQueue opens the shared recorder event queue on first use.
+func getRecorderQueue() *eventqueue.Queue[RecordedKeyEvent] {
+	recorderQueueOnce.Do(func() {
+		path := recorderQueueFilePath()
+		q, err := eventqueue.Open[RecordedKeyEvent](path, recorderQueueCapacity)
+		if err != nil {
+			slog.Error("failed to open durable recorder event queue, falling back to a temp file", "path", path, "error", err)
+			fallback := filepath.Join(os.TempDir(), fmt.Sprintf("super-characters-recorder-events-%d.queue", os.Getpid()))
+			q, err = eventqueue.Open[RecordedKeyEvent](fallback, recorderQueueCapacity)
+			if err != nil {
+				slog.Error("failed to open fallback recorder event queue, recorded events will not be delivered", "error", err)
+				return
+			}
+		}
+		recorderQueue = q
+	})
+	return recorderQueue
+}
+
+// recorderQueueFilePath returns the path of the persisted ring buffer file,
+// alongside this app's other per-user state.
+func recorderQueueFilePath() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "super-characters-recorder-events.queue")
+	}
+	dir := filepath.Join(homeDir, ".super-characters", "hotkey")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return filepath.Join(os.TempDir(), "super-characters-recorder-events.queue")
+	}
+	return filepath.Join(dir, "recording.queue")
+}
+
+// publishRecordedEvent pushes event onto the shared durable recorder queue.
+func publishRecordedEvent(event RecordedKeyEvent) {
+	if q := getRecorderQueue(); q != nil {
+		q.Push(event)
+	}
+}
+
+// writeBackBinding persists a completed recording into store under action,
+// if both are set - shared by every platform KeyRecorder's single-step and
+// chord completion paths. Runs the write in its own goroutine since
+// BindingManager.Rebind does file I/O and this is called from
+// latency-sensitive event-handling paths.
+func writeBackBinding(store BindingStore, action, hotkeyStr string) {
+	if store == nil || action == "" {
+		return
+	}
+	go func() {
+		if err := store.Rebind(action, hotkeyStr); err != nil {
+			slog.Warn("failed to persist recorded hotkey binding", "action", action, "error", err)
+		}
+	}()
+}
+
 // EventEmitter is an interface for emitting events to the frontend
 // This allows the recorder to emit events directly without going through a channel
 // The bool return value matches Wails EventManager.Emit signature
@@ -21,6 +107,27 @@ type RecordedKeyEvent struct {
 	HotkeyString string `json:"hotkeyString"`
 	// IsComplete indicates if this represents a complete hotkey (all keys released)
 	IsComplete bool `json:"isComplete"`
+	// IsChordStep indicates this event completed one step of a multi-step
+	// chord sequence rather than the sequence as a whole. Only set when
+	// chord recording mode is enabled via SetChordMode. HotkeyString holds
+	// the accumulated "step1 step2 ..." sequence so far; IsComplete is
+	// still false until the final step (or the chord timeout) lands.
+	IsChordStep bool `json:"isChordStep,omitempty"`
+	// Steps lists each chord step's modifier/key parts (e.g. [["Ctrl","X"],
+	// ["Ctrl","S"]] for "Ctrl+X Ctrl+S"), in the same order as the
+	// space-separated steps in HotkeyString. Only set alongside IsChordStep.
+	Steps [][]string `json:"steps,omitempty"`
+}
+
+// chordStepsToKeyLists splits each accumulated step string (e.g. "Ctrl+X")
+// into its modifier/key parts, for RecordedKeyEvent.Steps - BuildHotkeyString
+// joins parts with "+" the same way, so this is its inverse per step.
+func chordStepsToKeyLists(steps []string) [][]string {
+	out := make([][]string, len(steps))
+	for i, step := range steps {
+		out[i] = strings.Split(step, "+")
+	}
+	return out
 }
 
 // KeyRecorder defines the interface for native keyboard event recording
@@ -31,6 +138,21 @@ type KeyRecorder interface {
 	Stop() error
 	// Events returns a channel that receives recorded key events
 	Events() <-chan RecordedKeyEvent
+	// SetChordMode toggles multi-step chord recording. When enabled, a
+	// completed hotkey no longer ends recording immediately - it's
+	// accumulated as one step and recording continues until either the
+	// chord timeout elapses or the caller stops recording, emitting an
+	// IsChordStep event (and a "hotkey:recording:chord-step" frontend event)
+	// per completed step.
+	SetChordMode(enabled bool)
+	// SetChordTimeout configures how long to wait for the next chord step
+	// before treating the accumulated sequence as complete. Default 800ms.
+	SetChordTimeout(timeout time.Duration)
+	// SetEmitter sets the event emitter for direct frontend communication.
+	SetEmitter(emitter EventEmitter)
+	// SetBindingStore configures the recorder to write a captured complete
+	// HotkeyString back into store under action once recording finishes.
+	SetBindingStore(action string, store BindingStore)
 }
 
 // FormatModifiers converts modifier flags to a slice of modifier names
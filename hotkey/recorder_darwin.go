@@ -136,12 +136,20 @@ import (
 	"fmt"
 	"log/slog"
 	"runtime"
+	"strings"
 	"sync"
+	"time"
 )
 
+// NewKeyRecorder creates this platform's KeyRecorder - see
+// recorder_linux.go and recorder_windows.go for the other platforms'
+// NewKeyRecorder.
+func NewKeyRecorder() KeyRecorder {
+	return NewDarwinKeyRecorder()
+}
+
 // DarwinKeyRecorder implements KeyRecorder for macOS
 type DarwinKeyRecorder struct {
-	events    chan RecordedKeyEvent
 	running   bool
 	mu        sync.Mutex
 
@@ -153,8 +161,20 @@ type DarwinKeyRecorder struct {
 	// Track the "peak" hotkey - the most complete hotkey pressed before release
 	lastValidHotkey string
 
+	// Chord recording: when chordMode is true, a completed hotkey is
+	// accumulated as a step instead of ending recording immediately.
+	chordMode     bool
+	chordTimeout  time.Duration
+	chordSteps    []string
+	chordTimer    *time.Timer
+
 	// Optional event emitter for direct frontend communication
 	emitter EventEmitter
+
+	// Optional binding store this recorder writes a completed capture back
+	// into under bindingAction - see SetBindingStore.
+	bindingStore  BindingStore
+	bindingAction string
 }
 
 var (
@@ -166,8 +186,8 @@ var (
 // NewDarwinKeyRecorder creates a new macOS key recorder
 func NewDarwinKeyRecorder() *DarwinKeyRecorder {
 	return &DarwinKeyRecorder{
-		events:        make(chan RecordedKeyEvent, 100),
 		heldModifiers: make(map[string]bool),
+		chordTimeout:  defaultChordRecordingTimeout,
 	}
 }
 
@@ -178,6 +198,81 @@ func (r *DarwinKeyRecorder) SetEmitter(emitter EventEmitter) {
 	r.emitter = emitter
 }
 
+// SetBindingStore configures the recorder to write a captured complete
+// HotkeyString back into store under action once recording finishes, so a
+// "rebind this shortcut" flow doesn't need to separately listen for the
+// completion event and persist it - see BindingManager.Rebind.
+func (r *DarwinKeyRecorder) SetBindingStore(action string, store BindingStore) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.bindingAction = action
+	r.bindingStore = store
+}
+
+// SetChordMode toggles multi-step chord recording. See KeyRecorder.
+func (r *DarwinKeyRecorder) SetChordMode(enabled bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.chordMode = enabled
+	r.chordSteps = nil
+	if !enabled && r.chordTimer != nil {
+		r.chordTimer.Stop()
+		r.chordTimer = nil
+	}
+}
+
+// SetChordTimeout configures how long to wait for the next chord step. See
+// KeyRecorder.
+func (r *DarwinKeyRecorder) SetChordTimeout(timeout time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if timeout > 0 {
+		r.chordTimeout = timeout
+	}
+}
+
+// finalizeChordSequence fires when chordTimeout elapses between steps,
+// emitting the accumulated sequence as a completed chord binding.
+func (r *DarwinKeyRecorder) finalizeChordSequence() {
+	r.mu.Lock()
+	if len(r.chordSteps) == 0 {
+		r.mu.Unlock()
+		return
+	}
+	sequence := strings.Join(r.chordSteps, " ")
+	steps := chordStepsToKeyLists(r.chordSteps)
+	r.chordSteps = nil
+	r.chordTimer = nil
+	emitter := r.emitter
+	store := r.bindingStore
+	action := r.bindingAction
+	r.mu.Unlock()
+
+	event := RecordedKeyEvent{
+		HotkeyString: sequence,
+		IsComplete:   true,
+		IsChordStep:  true,
+		Steps:        steps,
+	}
+
+	if emitter != nil {
+		emitter.Emit("hotkey:recording:event", map[string]interface{}{
+			"hotkeyString": event.HotkeyString,
+			"isComplete":   event.IsComplete,
+			"isChordStep":  event.IsChordStep,
+			"steps":        event.Steps,
+		})
+		emitter.Emit("hotkey:recording:chord-step", map[string]interface{}{
+			"hotkeyString": event.HotkeyString,
+			"isComplete":   event.IsComplete,
+			"steps":        event.Steps,
+		})
+	}
+
+	publishRecordedEvent(event)
+	writeBackBinding(store, action, event.HotkeyString)
+}
+
 //export goRecorderOnKeyEvent
 func goRecorderOnKeyEvent(keyCode C.int, flags C.uint64_t, isDown C.int) {
 	globalRecorderLock.Lock()
@@ -261,6 +356,23 @@ func (r *DarwinKeyRecorder) handleKeyEvent(keyCode int, flags uint64, isDown boo
 		}
 	}
 
+	isChordStep := false
+	var chordStepsSnapshot [][]string
+	if isComplete && r.chordMode && finalHotkeyString != "" {
+		// In chord mode, a completed hotkey is a step, not the end of
+		// recording - accumulate it and keep listening for the next one.
+		isComplete = false
+		isChordStep = true
+		r.chordSteps = append(r.chordSteps, finalHotkeyString)
+		finalHotkeyString = strings.Join(r.chordSteps, " ")
+		chordStepsSnapshot = chordStepsToKeyLists(r.chordSteps)
+
+		if r.chordTimer != nil {
+			r.chordTimer.Stop()
+		}
+		r.chordTimer = time.AfterFunc(r.chordTimeout, r.finalizeChordSequence)
+	}
+
 	event := RecordedKeyEvent{
 		Modifiers:    modifiers,
 		Key:          eventKey,
@@ -268,10 +380,12 @@ func (r *DarwinKeyRecorder) handleKeyEvent(keyCode int, flags uint64, isDown boo
 		IsKeyDown:    isDown,
 		HotkeyString: finalHotkeyString,
 		IsComplete:   isComplete,
+		IsChordStep:  isChordStep,
+		Steps:        chordStepsSnapshot,
 	}
 
 	// Reset lastValidHotkey after sending complete event
-	if isComplete {
+	if isComplete || isChordStep {
 		r.lastValidHotkey = ""
 	}
 
@@ -284,17 +398,23 @@ func (r *DarwinKeyRecorder) handleKeyEvent(keyCode int, flags uint64, isDown boo
 			"isKeyDown":    event.IsKeyDown,
 			"hotkeyString": event.HotkeyString,
 			"isComplete":   event.IsComplete,
+			"isChordStep":  event.IsChordStep,
+			"steps":        event.Steps,
 		})
+		if isChordStep {
+			r.emitter.Emit("hotkey:recording:chord-step", map[string]interface{}{
+				"hotkeyString": event.HotkeyString,
+				"isComplete":   event.IsComplete,
+				"steps":        event.Steps,
+			})
+		}
 	}
 
-	// Also send to channel for programmatic use (non-blocking)
-	select {
-	case r.events <- event:
-	default:
-		// Channel full, skip event (frontend is getting events via emitter anyway)
-		if r.emitter == nil {
-			slog.Warn("recorder event channel full, dropping event")
-		}
+	// Also publish to the durable queue for programmatic use
+	publishRecordedEvent(event)
+
+	if isComplete {
+		writeBackBinding(r.bindingStore, r.bindingAction, event.HotkeyString)
 	}
 }
 
@@ -317,10 +437,15 @@ func (r *DarwinKeyRecorder) Start() error {
 	r.heldKey = ""
 	r.heldKeyCode = 0
 	r.lastValidHotkey = ""
+	r.chordSteps = nil
+	if r.chordTimer != nil {
+		r.chordTimer.Stop()
+		r.chordTimer = nil
+	}
 
-	// Drain any old events
-	for len(r.events) > 0 {
-		<-r.events
+	// Drain any events left queued from a prior recording session
+	if q := getRecorderQueue(); q != nil {
+		q.Drain()
 	}
 
 	runtime.LockOSThread()
@@ -353,6 +478,11 @@ func (r *DarwinKeyRecorder) Stop() error {
 
 	C.stopRecorderTap()
 
+	if r.chordTimer != nil {
+		r.chordTimer.Stop()
+		r.chordTimer = nil
+	}
+	r.chordSteps = nil
 	r.running = false
 
 	globalRecorderLock.Lock()
@@ -365,7 +495,11 @@ func (r *DarwinKeyRecorder) Stop() error {
 
 // Events returns a channel that receives recorded key events
 func (r *DarwinKeyRecorder) Events() <-chan RecordedKeyEvent {
-	return r.events
+	q := getRecorderQueue()
+	if q == nil {
+		return nil
+	}
+	return q.Subscribe()
 }
 
 // isModifierKeyCode checks if the keycode represents a modifier key
@@ -0,0 +1,495 @@
+package hotkey
+
+/*
+#cgo LDFLAGS: -lX11 -lXtst
+#include <X11/Xlib.h>
+#include <X11/extensions/record.h>
+#include <stdlib.h>
+#include <string.h>
+
+extern void goLinuxRecorderOnEvent(int type, int detail);
+
+static Display *recorderCtrlDisplay = NULL;
+static Display *recorderDataDisplay = NULL;
+static XRecordContext recorderCtx = 0;
+static volatile int recorderRunning = 0;
+
+// recordCallback is XRecordEnableContext's callback, invoked on
+// recorderDataDisplay's connection for every intercepted core-protocol
+// event once recording is enabled. Each XRecordInterceptData's payload
+// starts with the same minimal event header every X core event shares:
+// byte 0 is the event type (KeyPress=2, KeyRelease=3), byte 1 is the
+// detail (keycode).
+static void recordCallback(XPointer closure, XRecordInterceptData *data) {
+    if (data->category == XRecordFromServer && data->data != NULL) {
+        unsigned char *event = (unsigned char *)data->data;
+        int type = event[0] & 0x7f;
+        int detail = event[1];
+        if (type == KeyPress || type == KeyRelease) {
+            goLinuxRecorderOnEvent(type, detail);
+        }
+    }
+    XRecordFreeData(data);
+}
+
+static int startLinuxRecordTap() {
+    if (recorderRunning) {
+        return 0;
+    }
+
+    recorderCtrlDisplay = XOpenDisplay(NULL);
+    if (!recorderCtrlDisplay) {
+        return -1;
+    }
+    recorderDataDisplay = XOpenDisplay(NULL);
+    if (!recorderDataDisplay) {
+        XCloseDisplay(recorderCtrlDisplay);
+        recorderCtrlDisplay = NULL;
+        return -1;
+    }
+
+    XRecordClientSpec clients = XRecordAllClients;
+    XRecordRange *range = XRecordAllocRange();
+    if (!range) {
+        XCloseDisplay(recorderDataDisplay);
+        XCloseDisplay(recorderCtrlDisplay);
+        recorderDataDisplay = NULL;
+        recorderCtrlDisplay = NULL;
+        return -1;
+    }
+    memset(range, 0, sizeof(XRecordRange));
+    range->device_events.first = KeyPress;
+    range->device_events.last = KeyRelease;
+
+    recorderCtx = XRecordCreateContext(recorderCtrlDisplay, 0, &clients, 1, &range, 1);
+    XFree(range);
+    if (!recorderCtx) {
+        XCloseDisplay(recorderDataDisplay);
+        XCloseDisplay(recorderCtrlDisplay);
+        recorderDataDisplay = NULL;
+        recorderCtrlDisplay = NULL;
+        return -1;
+    }
+
+    recorderRunning = 1;
+    // Blocks the calling thread, delivering callbacks on recorderDataDisplay
+    // until XRecordDisableContext is called (from recorderCtrlDisplay, on
+    // another thread) - see Start, which runs this on its own locked OS
+    // thread.
+    XRecordEnableContext(recorderDataDisplay, recorderCtx, recordCallback, NULL);
+    return 0;
+}
+
+static void stopLinuxRecordTap() {
+    if (!recorderRunning) {
+        return;
+    }
+    recorderRunning = 0;
+    if (recorderCtrlDisplay && recorderCtx) {
+        XRecordDisableContext(recorderCtrlDisplay, recorderCtx);
+        XSync(recorderCtrlDisplay, False);
+        XRecordFreeContext(recorderCtrlDisplay, recorderCtx);
+    }
+    recorderCtx = 0;
+    if (recorderDataDisplay) {
+        XCloseDisplay(recorderDataDisplay);
+        recorderDataDisplay = NULL;
+    }
+    if (recorderCtrlDisplay) {
+        XCloseDisplay(recorderCtrlDisplay);
+        recorderCtrlDisplay = NULL;
+    }
+}
+
+static KeySym keycodeToKeysymC(int keycode) {
+    if (!recorderCtrlDisplay) {
+        return NoSymbol;
+    }
+    return XkbKeycodeToKeysym(recorderCtrlDisplay, (KeyCode)keycode, 0, 0);
+}
+*/
+import "C"
+import (
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NewKeyRecorder creates this platform's KeyRecorder - see
+// recorder_darwin.go and recorder_windows.go for the other platforms'
+// NewKeyRecorder.
+func NewKeyRecorder() KeyRecorder {
+	return NewLinuxKeyRecorder()
+}
+
+// LinuxKeyRecorder implements KeyRecorder for Linux via the X11 XRecord
+// extension, run on a secondary "data" display connection the same way
+// HotkeyRegistry's own XGrabKey-based tap (see hotkey_linux.go) needs its
+// own display connection - like that backend, this requires an X server
+// (DISPLAY set) and fails to start under plain Wayland.
+type LinuxKeyRecorder struct {
+	running bool
+	mu      sync.Mutex
+
+	// Track currently held modifiers and keys for building complete hotkey
+	heldModifiers map[string]bool
+	heldKey       string
+	heldKeyCode   int
+
+	// Track the "peak" hotkey - the most complete hotkey pressed before release
+	lastValidHotkey string
+
+	// Chord recording: when chordMode is true, a completed hotkey is
+	// accumulated as a step instead of ending recording immediately.
+	chordMode    bool
+	chordTimeout time.Duration
+	chordSteps   []string
+	chordTimer   *time.Timer
+
+	// Optional event emitter for direct frontend communication
+	emitter EventEmitter
+
+	// Optional binding store this recorder writes a completed capture back
+	// into under bindingAction - see SetBindingStore.
+	bindingStore  BindingStore
+	bindingAction string
+}
+
+var (
+	globalLinuxRecorder     *LinuxKeyRecorder
+	globalLinuxRecorderLock sync.Mutex
+)
+
+// NewLinuxKeyRecorder creates a new Linux key recorder.
+func NewLinuxKeyRecorder() *LinuxKeyRecorder {
+	return &LinuxKeyRecorder{
+		heldModifiers: make(map[string]bool),
+		chordTimeout:  defaultChordRecordingTimeout,
+	}
+}
+
+// SetEmitter sets the event emitter for direct frontend communication
+func (r *LinuxKeyRecorder) SetEmitter(emitter EventEmitter) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.emitter = emitter
+}
+
+// SetBindingStore configures the recorder to write a captured complete
+// HotkeyString back into store under action once recording finishes - see
+// DarwinKeyRecorder.SetBindingStore.
+func (r *LinuxKeyRecorder) SetBindingStore(action string, store BindingStore) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.bindingAction = action
+	r.bindingStore = store
+}
+
+// SetChordMode toggles multi-step chord recording. See KeyRecorder.
+func (r *LinuxKeyRecorder) SetChordMode(enabled bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.chordMode = enabled
+	r.chordSteps = nil
+	if !enabled && r.chordTimer != nil {
+		r.chordTimer.Stop()
+		r.chordTimer = nil
+	}
+}
+
+// SetChordTimeout configures how long to wait for the next chord step. See
+// KeyRecorder.
+func (r *LinuxKeyRecorder) SetChordTimeout(timeout time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if timeout > 0 {
+		r.chordTimeout = timeout
+	}
+}
+
+// finalizeChordSequence fires when chordTimeout elapses between steps,
+// emitting the accumulated sequence as a completed chord binding.
+func (r *LinuxKeyRecorder) finalizeChordSequence() {
+	r.mu.Lock()
+	if len(r.chordSteps) == 0 {
+		r.mu.Unlock()
+		return
+	}
+	sequence := strings.Join(r.chordSteps, " ")
+	steps := chordStepsToKeyLists(r.chordSteps)
+	r.chordSteps = nil
+	r.chordTimer = nil
+	emitter := r.emitter
+	store := r.bindingStore
+	action := r.bindingAction
+	r.mu.Unlock()
+
+	event := RecordedKeyEvent{
+		HotkeyString: sequence,
+		IsComplete:   true,
+		IsChordStep:  true,
+		Steps:        steps,
+	}
+
+	if emitter != nil {
+		emitter.Emit("hotkey:recording:event", map[string]interface{}{
+			"hotkeyString": event.HotkeyString,
+			"isComplete":   event.IsComplete,
+			"isChordStep":  event.IsChordStep,
+			"steps":        event.Steps,
+		})
+		emitter.Emit("hotkey:recording:chord-step", map[string]interface{}{
+			"hotkeyString": event.HotkeyString,
+			"isComplete":   event.IsComplete,
+			"steps":        event.Steps,
+		})
+	}
+
+	publishRecordedEvent(event)
+	writeBackBinding(store, action, event.HotkeyString)
+}
+
+//export goLinuxRecorderOnEvent
+func goLinuxRecorderOnEvent(eventType C.int, detail C.int) {
+	globalLinuxRecorderLock.Lock()
+	recorder := globalLinuxRecorder
+	globalLinuxRecorderLock.Unlock()
+
+	if recorder == nil {
+		return
+	}
+
+	keycode := int(detail)
+	isDown := int(eventType) == C.KeyPress
+	keysym := int(C.keycodeToKeysymC(C.int(keycode)))
+
+	// XRecord callbacks run on the data-display connection's own thread,
+	// same caveat cgo callbacks always have - hop to a goroutine before
+	// touching Go-managed state or calling the emitter.
+	go recorder.handleKeyEvent(keycode, keysym, isDown)
+}
+
+func (r *LinuxKeyRecorder) handleKeyEvent(keyCode, keysym int, isDown bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.running {
+		return
+	}
+
+	keyName := getLinuxKeyName(keysym)
+	isModifierKey := isModifierKey(keysym)
+
+	switch keysym {
+	case Must(Lookup("Ctrl")):
+		r.heldModifiers["Ctrl"] = isDown
+	case Must(Lookup("Shift")):
+		r.heldModifiers["Shift"] = isDown
+	case Must(Lookup("Alt")):
+		r.heldModifiers["Alt"] = isDown
+	case Must(Lookup("Cmd")):
+		r.heldModifiers["Cmd"] = isDown
+	}
+
+	if !isModifierKey {
+		if isDown {
+			r.heldKey = keyName
+			r.heldKeyCode = keyCode
+		} else {
+			r.heldKey = ""
+			r.heldKeyCode = 0
+		}
+	}
+
+	modifiers := FormatModifiers(r.heldModifiers["Ctrl"], r.heldModifiers["Shift"], r.heldModifiers["Alt"], r.heldModifiers["Cmd"])
+
+	var eventKey string
+	if !isModifierKey {
+		eventKey = keyName
+	}
+
+	hotkeyString := BuildHotkeyString(modifiers, eventKey)
+
+	if isDown && len(modifiers) > 0 {
+		r.lastValidHotkey = hotkeyString
+	}
+
+	isComplete := false
+	finalHotkeyString := hotkeyString
+
+	if !isDown {
+		if !r.heldModifiers["Ctrl"] && !r.heldModifiers["Shift"] && !r.heldModifiers["Alt"] && !r.heldModifiers["Cmd"] && r.heldKey == "" {
+			isComplete = true
+			if r.lastValidHotkey != "" {
+				finalHotkeyString = r.lastValidHotkey
+			}
+		}
+	}
+
+	isChordStep := false
+	var chordStepsSnapshot [][]string
+	if isComplete && r.chordMode && finalHotkeyString != "" {
+		isComplete = false
+		isChordStep = true
+		r.chordSteps = append(r.chordSteps, finalHotkeyString)
+		finalHotkeyString = strings.Join(r.chordSteps, " ")
+		chordStepsSnapshot = chordStepsToKeyLists(r.chordSteps)
+
+		if r.chordTimer != nil {
+			r.chordTimer.Stop()
+		}
+		r.chordTimer = time.AfterFunc(r.chordTimeout, r.finalizeChordSequence)
+	}
+
+	event := RecordedKeyEvent{
+		Modifiers:    modifiers,
+		Key:          eventKey,
+		KeyCode:      keyCode,
+		IsKeyDown:    isDown,
+		HotkeyString: finalHotkeyString,
+		IsComplete:   isComplete,
+		IsChordStep:  isChordStep,
+		Steps:        chordStepsSnapshot,
+	}
+
+	if isComplete || isChordStep {
+		r.lastValidHotkey = ""
+	}
+
+	if r.emitter != nil {
+		r.emitter.Emit("hotkey:recording:event", map[string]interface{}{
+			"modifiers":    event.Modifiers,
+			"key":          event.Key,
+			"keyCode":      event.KeyCode,
+			"isKeyDown":    event.IsKeyDown,
+			"hotkeyString": event.HotkeyString,
+			"isComplete":   event.IsComplete,
+			"isChordStep":  event.IsChordStep,
+			"steps":        event.Steps,
+		})
+		if isChordStep {
+			r.emitter.Emit("hotkey:recording:chord-step", map[string]interface{}{
+				"hotkeyString": event.HotkeyString,
+				"isComplete":   event.IsComplete,
+				"steps":        event.Steps,
+			})
+		}
+	}
+
+	publishRecordedEvent(event)
+
+	if isComplete {
+		writeBackBinding(r.bindingStore, r.bindingAction, event.HotkeyString)
+	}
+}
+
+// Start begins recording keyboard events
+func (r *LinuxKeyRecorder) Start() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.running {
+		return fmt.Errorf("recorder already running")
+	}
+
+	globalLinuxRecorderLock.Lock()
+	globalLinuxRecorder = r
+	globalLinuxRecorderLock.Unlock()
+
+	r.heldModifiers = make(map[string]bool)
+	r.heldKey = ""
+	r.heldKeyCode = 0
+	r.lastValidHotkey = ""
+	r.chordSteps = nil
+	if r.chordTimer != nil {
+		r.chordTimer.Stop()
+		r.chordTimer = nil
+	}
+
+	if q := getRecorderQueue(); q != nil {
+		q.Drain()
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+
+		if C.startLinuxRecordTap() != 0 {
+			errCh <- fmt.Errorf("failed to start X11 XRecord context (is DISPLAY set?)")
+			return
+		}
+		errCh <- nil
+		// startLinuxRecordTap blocks here (inside XRecordEnableContext)
+		// until stopLinuxRecordTap is called from Stop.
+	}()
+
+	if err := <-errCh; err != nil {
+		globalLinuxRecorderLock.Lock()
+		globalLinuxRecorder = nil
+		globalLinuxRecorderLock.Unlock()
+		return err
+	}
+
+	r.running = true
+	slog.Info("native hotkey recorder started (X11 XRecord)")
+	return nil
+}
+
+// Stop ends recording and cleans up resources
+func (r *LinuxKeyRecorder) Stop() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.running {
+		return nil
+	}
+
+	C.stopLinuxRecordTap()
+
+	if r.chordTimer != nil {
+		r.chordTimer.Stop()
+		r.chordTimer = nil
+	}
+	r.chordSteps = nil
+	r.running = false
+
+	globalLinuxRecorderLock.Lock()
+	globalLinuxRecorder = nil
+	globalLinuxRecorderLock.Unlock()
+
+	slog.Info("native hotkey recorder stopped")
+	return nil
+}
+
+// Events returns a channel that receives recorded key events
+func (r *LinuxKeyRecorder) Events() <-chan RecordedKeyEvent {
+	q := getRecorderQueue()
+	if q == nil {
+		return nil
+	}
+	return q.Subscribe()
+}
+
+// getLinuxKeyName converts an X11 keysym to the platform-neutral key name
+// keyNameMap (keycodes_linux.go) uses, via reverse lookup.
+func getLinuxKeyName(keysym int) string {
+	for name, sym := range keyNameMap {
+		if sym == keysym {
+			return name
+		}
+	}
+	return fmt.Sprintf("Key%d", keysym)
+}
+
+// Must panics if Lookup doesn't resolve name - only used here for the fixed
+// set of modifier names keyNameMap is guaranteed to contain.
+func Must(keysym int, ok bool) int {
+	if !ok {
+		panic("hotkey: missing required keysym in keyNameMap")
+	}
+	return keysym
+}
@@ -0,0 +1,447 @@
+package hotkey
+
+import (
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// NewKeyRecorder creates this platform's KeyRecorder - see
+// recorder_darwin.go and recorder_linux.go for the other platforms'
+// NewKeyRecorder.
+func NewKeyRecorder() KeyRecorder {
+	return NewWindowsKeyRecorder()
+}
+
+const (
+	whKeyboardLL = 13
+	hcAction     = 0
+	wmKeyDown    = 0x0100
+	wmKeyUp      = 0x0101
+	wmSysKeyDown = 0x0104
+	wmSysKeyUp   = 0x0105
+	wmQuit       = 0x0012
+)
+
+type kbdllhookstruct struct {
+	vkCode      uint32
+	scanCode    uint32
+	flags       uint32
+	time        uint32
+	dwExtraInfo uintptr
+}
+
+// user32 and procGetCurrentThreadID are declared in hotkey_windows.go and
+// shared with this file.
+var (
+	procSetWindowsHookExW   = user32.NewProc("SetWindowsHookExW")
+	procUnhookWindowsHookEx = user32.NewProc("UnhookWindowsHookEx")
+	procCallNextHookEx      = user32.NewProc("CallNextHookEx")
+	procGetMessageW         = user32.NewProc("GetMessageW")
+	procPostThreadMessageW  = user32.NewProc("PostThreadMessageW")
+)
+
+// WindowsKeyRecorder implements KeyRecorder for Windows via a WH_KEYBOARD_LL
+// low-level keyboard hook. Unlike HotkeyRegistry's RegisterHotKey-based tap
+// (see hotkey_windows.go), a low-level hook observes every key regardless of
+// binding, which is what recording a new hotkey combination needs. Hook
+// callbacks are delivered on the thread that installed the hook and require
+// that thread to pump messages for the hook's lifetime, so Start runs its own
+// dedicated, OS-thread-locked message pump goroutine - the same discipline
+// hotkey_windows.go's messagePump uses for RegisterHotKey.
+type WindowsKeyRecorder struct {
+	running bool
+	mu      sync.Mutex
+
+	// Track currently held modifiers and keys for building complete hotkey
+	heldModifiers map[string]bool
+	heldKey       string
+	heldKeyCode   int
+
+	// Track the "peak" hotkey - the most complete hotkey pressed before release
+	lastValidHotkey string
+
+	// Chord recording: when chordMode is true, a completed hotkey is
+	// accumulated as a step instead of ending recording immediately.
+	chordMode    bool
+	chordTimeout time.Duration
+	chordSteps   []string
+	chordTimer   *time.Timer
+
+	// Optional event emitter for direct frontend communication
+	emitter EventEmitter
+
+	// Optional binding store this recorder writes a completed capture back
+	// into under bindingAction - see SetBindingStore.
+	bindingStore  BindingStore
+	bindingAction string
+
+	pumpThreadID uintptr
+	stopped      chan struct{}
+}
+
+var (
+	globalWindowsRecorder     *WindowsKeyRecorder
+	globalWindowsRecorderLock sync.Mutex
+)
+
+// NewWindowsKeyRecorder creates a new Windows key recorder.
+func NewWindowsKeyRecorder() *WindowsKeyRecorder {
+	return &WindowsKeyRecorder{
+		heldModifiers: make(map[string]bool),
+		chordTimeout:  defaultChordRecordingTimeout,
+	}
+}
+
+// SetEmitter sets the event emitter for direct frontend communication
+func (r *WindowsKeyRecorder) SetEmitter(emitter EventEmitter) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.emitter = emitter
+}
+
+// SetBindingStore configures the recorder to write a captured complete
+// HotkeyString back into store under action once recording finishes - see
+// DarwinKeyRecorder.SetBindingStore.
+func (r *WindowsKeyRecorder) SetBindingStore(action string, store BindingStore) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.bindingAction = action
+	r.bindingStore = store
+}
+
+// SetChordMode toggles multi-step chord recording. See KeyRecorder.
+func (r *WindowsKeyRecorder) SetChordMode(enabled bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.chordMode = enabled
+	r.chordSteps = nil
+	if !enabled && r.chordTimer != nil {
+		r.chordTimer.Stop()
+		r.chordTimer = nil
+	}
+}
+
+// SetChordTimeout configures how long to wait for the next chord step. See
+// KeyRecorder.
+func (r *WindowsKeyRecorder) SetChordTimeout(timeout time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if timeout > 0 {
+		r.chordTimeout = timeout
+	}
+}
+
+// finalizeChordSequence fires when chordTimeout elapses between steps,
+// emitting the accumulated sequence as a completed chord binding.
+func (r *WindowsKeyRecorder) finalizeChordSequence() {
+	r.mu.Lock()
+	if len(r.chordSteps) == 0 {
+		r.mu.Unlock()
+		return
+	}
+	sequence := strings.Join(r.chordSteps, " ")
+	steps := chordStepsToKeyLists(r.chordSteps)
+	r.chordSteps = nil
+	r.chordTimer = nil
+	emitter := r.emitter
+	store := r.bindingStore
+	action := r.bindingAction
+	r.mu.Unlock()
+
+	event := RecordedKeyEvent{
+		HotkeyString: sequence,
+		IsComplete:   true,
+		IsChordStep:  true,
+		Steps:        steps,
+	}
+
+	if emitter != nil {
+		emitter.Emit("hotkey:recording:event", map[string]interface{}{
+			"hotkeyString": event.HotkeyString,
+			"isComplete":   event.IsComplete,
+			"isChordStep":  event.IsChordStep,
+			"steps":        event.Steps,
+		})
+		emitter.Emit("hotkey:recording:chord-step", map[string]interface{}{
+			"hotkeyString": event.HotkeyString,
+			"isComplete":   event.IsComplete,
+			"steps":        event.Steps,
+		})
+	}
+
+	publishRecordedEvent(event)
+	writeBackBinding(store, action, event.HotkeyString)
+}
+
+// lowLevelKeyboardProc is the WH_KEYBOARD_LL hook procedure, called by
+// Windows on the pump thread for every keyboard event system-wide. It must
+// return quickly and always forward to CallNextHookEx so other hooks (and the
+// key itself) keep working.
+func lowLevelKeyboardProc(nCode int, wParam uintptr, lParam uintptr) uintptr {
+	if nCode == hcAction {
+		globalWindowsRecorderLock.Lock()
+		recorder := globalWindowsRecorder
+		globalWindowsRecorderLock.Unlock()
+
+		if recorder != nil {
+			kb := (*kbdllhookstruct)(unsafe.Pointer(lParam))
+			isDown := wParam == wmKeyDown || wParam == wmSysKeyDown
+			isUp := wParam == wmKeyUp || wParam == wmSysKeyUp
+			if isDown || isUp {
+				vkCode := int(kb.vkCode)
+				// The hook proc runs on the pump thread - hop to a goroutine
+				// before touching Go-managed state or calling the emitter.
+				go recorder.handleKeyEvent(vkCode, isDown)
+			}
+		}
+	}
+
+	ret, _, _ := procCallNextHookEx.Call(0, uintptr(nCode), wParam, lParam)
+	return ret
+}
+
+func (r *WindowsKeyRecorder) handleKeyEvent(keyCode int, isDown bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.running {
+		return
+	}
+
+	keyName := getWindowsKeyName(keyCode)
+	isModifier := isModifierKey(keyCode)
+
+	switch keyCode {
+	case vkControl:
+		r.heldModifiers["Ctrl"] = isDown
+	case vkShift:
+		r.heldModifiers["Shift"] = isDown
+	case vkMenu:
+		r.heldModifiers["Alt"] = isDown
+	case vkLWin, vkRWin:
+		r.heldModifiers["Cmd"] = isDown
+	}
+
+	if !isModifier {
+		if isDown {
+			r.heldKey = keyName
+			r.heldKeyCode = keyCode
+		} else {
+			r.heldKey = ""
+			r.heldKeyCode = 0
+		}
+	}
+
+	modifiers := FormatModifiers(r.heldModifiers["Ctrl"], r.heldModifiers["Shift"], r.heldModifiers["Alt"], r.heldModifiers["Cmd"])
+
+	var eventKey string
+	if !isModifier {
+		eventKey = keyName
+	}
+
+	hotkeyString := BuildHotkeyString(modifiers, eventKey)
+
+	if isDown && len(modifiers) > 0 {
+		r.lastValidHotkey = hotkeyString
+	}
+
+	isComplete := false
+	finalHotkeyString := hotkeyString
+
+	if !isDown {
+		if !r.heldModifiers["Ctrl"] && !r.heldModifiers["Shift"] && !r.heldModifiers["Alt"] && !r.heldModifiers["Cmd"] && r.heldKey == "" {
+			isComplete = true
+			if r.lastValidHotkey != "" {
+				finalHotkeyString = r.lastValidHotkey
+			}
+		}
+	}
+
+	isChordStep := false
+	var chordStepsSnapshot [][]string
+	if isComplete && r.chordMode && finalHotkeyString != "" {
+		isComplete = false
+		isChordStep = true
+		r.chordSteps = append(r.chordSteps, finalHotkeyString)
+		finalHotkeyString = strings.Join(r.chordSteps, " ")
+		chordStepsSnapshot = chordStepsToKeyLists(r.chordSteps)
+
+		if r.chordTimer != nil {
+			r.chordTimer.Stop()
+		}
+		r.chordTimer = time.AfterFunc(r.chordTimeout, r.finalizeChordSequence)
+	}
+
+	event := RecordedKeyEvent{
+		Modifiers:    modifiers,
+		Key:          eventKey,
+		KeyCode:      keyCode,
+		IsKeyDown:    isDown,
+		HotkeyString: finalHotkeyString,
+		IsComplete:   isComplete,
+		IsChordStep:  isChordStep,
+		Steps:        chordStepsSnapshot,
+	}
+
+	if isComplete || isChordStep {
+		r.lastValidHotkey = ""
+	}
+
+	if r.emitter != nil {
+		r.emitter.Emit("hotkey:recording:event", map[string]interface{}{
+			"modifiers":    event.Modifiers,
+			"key":          event.Key,
+			"keyCode":      event.KeyCode,
+			"isKeyDown":    event.IsKeyDown,
+			"hotkeyString": event.HotkeyString,
+			"isComplete":   event.IsComplete,
+			"isChordStep":  event.IsChordStep,
+			"steps":        event.Steps,
+		})
+		if isChordStep {
+			r.emitter.Emit("hotkey:recording:chord-step", map[string]interface{}{
+				"hotkeyString": event.HotkeyString,
+				"isComplete":   event.IsComplete,
+				"steps":        event.Steps,
+			})
+		}
+	}
+
+	publishRecordedEvent(event)
+
+	if isComplete {
+		writeBackBinding(r.bindingStore, r.bindingAction, event.HotkeyString)
+	}
+}
+
+// Start begins recording keyboard events
+func (r *WindowsKeyRecorder) Start() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.running {
+		return fmt.Errorf("recorder already running")
+	}
+
+	globalWindowsRecorderLock.Lock()
+	globalWindowsRecorder = r
+	globalWindowsRecorderLock.Unlock()
+
+	r.heldModifiers = make(map[string]bool)
+	r.heldKey = ""
+	r.heldKeyCode = 0
+	r.lastValidHotkey = ""
+	r.chordSteps = nil
+	if r.chordTimer != nil {
+		r.chordTimer.Stop()
+		r.chordTimer = nil
+	}
+
+	if q := getRecorderQueue(); q != nil {
+		q.Drain()
+	}
+
+	r.stopped = make(chan struct{})
+	readyCh := make(chan error, 1)
+	go r.pump(readyCh)
+
+	if err := <-readyCh; err != nil {
+		globalWindowsRecorderLock.Lock()
+		globalWindowsRecorder = nil
+		globalWindowsRecorderLock.Unlock()
+		return err
+	}
+
+	r.running = true
+	slog.Info("native hotkey recorder started (WH_KEYBOARD_LL)")
+	return nil
+}
+
+// pump installs the low-level keyboard hook and runs the dedicated message
+// loop the hook requires, on its own locked OS thread, until Stop requests
+// it to exit via PostThreadMessageW(WM_QUIT).
+func (r *WindowsKeyRecorder) pump(readyCh chan error) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	tid, _, _ := procGetCurrentThreadID.Call()
+	r.mu.Lock()
+	r.pumpThreadID = tid
+	r.mu.Unlock()
+
+	callback := syscall.NewCallback(lowLevelKeyboardProc)
+	hook, _, _ := procSetWindowsHookExW.Call(uintptr(whKeyboardLL), callback, 0, 0)
+	if hook == 0 {
+		readyCh <- fmt.Errorf("failed to install WH_KEYBOARD_LL hook")
+		return
+	}
+	readyCh <- nil
+
+	var m win32Msg
+	for {
+		ret, _, _ := procGetMessageW.Call(uintptr(unsafe.Pointer(&m)), 0, 0, 0)
+		if ret == 0 || m.message == wmQuit {
+			break
+		}
+	}
+
+	procUnhookWindowsHookEx.Call(hook)
+	close(r.stopped)
+}
+
+// Stop ends recording and cleans up resources
+func (r *WindowsKeyRecorder) Stop() error {
+	r.mu.Lock()
+	if !r.running {
+		r.mu.Unlock()
+		return nil
+	}
+	tid := r.pumpThreadID
+	stopped := r.stopped
+	r.mu.Unlock()
+
+	procPostThreadMessageW.Call(tid, wmQuit, 0, 0)
+	<-stopped
+
+	r.mu.Lock()
+	if r.chordTimer != nil {
+		r.chordTimer.Stop()
+		r.chordTimer = nil
+	}
+	r.chordSteps = nil
+	r.running = false
+	r.mu.Unlock()
+
+	globalWindowsRecorderLock.Lock()
+	globalWindowsRecorder = nil
+	globalWindowsRecorderLock.Unlock()
+
+	slog.Info("native hotkey recorder stopped")
+	return nil
+}
+
+// Events returns a channel that receives recorded key events
+func (r *WindowsKeyRecorder) Events() <-chan RecordedKeyEvent {
+	q := getRecorderQueue()
+	if q == nil {
+		return nil
+	}
+	return q.Subscribe()
+}
+
+// getWindowsKeyName converts a Windows VK_* code to the platform-neutral key
+// name keyNameMap (keycodes_windows.go) uses, via reverse lookup.
+func getWindowsKeyName(keyCode int) string {
+	for name, code := range keyNameMap {
+		if code == keyCode {
+			return name
+		}
+	}
+	return fmt.Sprintf("Key%d", keyCode)
+}
@@ -0,0 +1,255 @@
+package hotkey
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Mode describes how a registered hotkey should be interpreted by whoever
+// consumes its press/release events off the shared event channel.
+type Mode int
+
+const (
+	// ModePressRelease fires a single action on every press; release is ignored.
+	ModePressRelease Mode = iota
+	// ModeToggle flips a logical on/off state on each press.
+	ModeToggle
+	// ModePushToTalk starts an action on press and stops it on release.
+	ModePushToTalk
+	// ModeChord fires an action only once a multi-step key sequence (e.g.
+	// "Ctrl+K Ctrl+R") completes. See Binding.ChordSteps and
+	// HotkeyRegistry.RegisterChord.
+	ModeChord
+)
+
+// String returns a human-readable name for the mode.
+func (m Mode) String() string {
+	switch m {
+	case ModePressRelease:
+		return "PressRelease"
+	case ModeToggle:
+		return "Toggle"
+	case ModePushToTalk:
+		return "PushToTalk"
+	case ModeChord:
+		return "Chord"
+	default:
+		return "Unknown"
+	}
+}
+
+// Binding describes a single registered hotkey.
+type Binding struct {
+	ID        string
+	HotkeyStr string
+	Mode      Mode
+
+	// TapHoldThreshold distinguishes a quick tap from a held press on
+	// modifier-only hotkeys (see RegisterTapHold). Zero means disabled.
+	TapHoldThreshold time.Duration
+
+	// ChordSteps holds the parsed per-step hotkey strings when Mode is
+	// ModeChord (e.g. ["Ctrl+K", "Ctrl+R"]); empty for every other mode.
+	// Only the step currently being waited on is actually grabbed at the
+	// OS level at any one time - see RegisterChord/AdvanceChordStep.
+	ChordSteps []string
+}
+
+// HotkeyRegistry manages an arbitrary number of named global hotkeys backed
+// by the shared OS event tap. It replaces the old fixed "main"/"hands-free"
+// two-slot model: callers register as many bindings as they like (start/stop
+// dictation, cancel, insert last transcript, open overlay, ...) and dispatch
+// on the resulting HotkeyEvent.ID rather than a binding enum.
+type HotkeyRegistry struct {
+	mu       sync.Mutex
+	bindings map[string]*registeredBinding
+	slotUsed [maxHotkeySlots]bool
+}
+
+// registeredBinding's backend field records which OS mechanism the binding
+// actually ended up using, so Unregister can tear it down the right way.
+// backendKind, registerBinding and teardown are platform-specific (see
+// registry_darwin.go, registry_windows.go, registry_linux.go).
+type registeredBinding struct {
+	Binding
+	slot    int
+	backend backendKind
+
+	// stepIndex is the position within Binding.ChordSteps currently
+	// grabbed at the OS level. Unused outside ModeChord bindings.
+	stepIndex int
+}
+
+// NewHotkeyRegistry creates an empty registry.
+func NewHotkeyRegistry() *HotkeyRegistry {
+	return &HotkeyRegistry{
+		bindings: make(map[string]*registeredBinding),
+	}
+}
+
+// Register installs a new hotkey under id, starting the shared event tap on
+// first use. Registering an id that's already bound replaces it.
+func (r *HotkeyRegistry) Register(id string, hotkeyStr string, mode Mode) error {
+	return r.RegisterTapHold(id, hotkeyStr, mode, 0)
+}
+
+// RegisterTapHold is like Register but additionally installs a tap/hold
+// threshold for modifier-only hotkeys: a release under threshold is
+// reported as EventTap (toggle semantics), a release at or above it as
+// EventHold (push-to-talk semantics). See HotkeyEventType for details.
+// Threshold is ignored for non-modifier-only bindings.
+func (r *HotkeyRegistry) RegisterTapHold(id string, hotkeyStr string, mode Mode, threshold time.Duration) error {
+	if id == "" {
+		return fmt.Errorf("hotkey id must not be empty")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.bindings[id]; ok {
+		existing.teardown()
+		r.slotUsed[existing.slot] = false
+		delete(r.bindings, id)
+	}
+
+	slot := r.allocSlot()
+	if slot < 0 {
+		return fmt.Errorf("hotkey registry full (max %d bindings)", maxHotkeySlots)
+	}
+
+	backend, err := r.registerBinding(slot, id, hotkeyStr, threshold)
+	if err != nil {
+		r.slotUsed[slot] = false
+		return err
+	}
+
+	r.bindings[id] = &registeredBinding{
+		Binding: Binding{ID: id, HotkeyStr: hotkeyStr, Mode: mode, TapHoldThreshold: threshold},
+		slot:    slot,
+		backend: backend,
+	}
+	return nil
+}
+
+// RegisterChord installs a multi-step chord binding under id: only the
+// first step's hotkey is actually grabbed at the OS level at first: use
+// AdvanceChordStep to move the grab to the next step as each one matches,
+// and ResetChordStep to rewind back to the first step (e.g. on timeout).
+func (r *HotkeyRegistry) RegisterChord(id string, steps []string, mode Mode) error {
+	if len(steps) < 2 {
+		return fmt.Errorf("chord sequence must have at least 2 steps")
+	}
+
+	if err := r.Register(id, steps[0], mode); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if b, ok := r.bindings[id]; ok {
+		b.ChordSteps = append([]string(nil), steps...)
+		b.stepIndex = 0
+	}
+	return nil
+}
+
+// AdvanceChordStep moves id's OS-level grab to the next step in its chord
+// sequence. When the step just matched was the last one, it instead rewinds
+// the grab back to the first step (ready for the next attempt) and reports
+// completed=true.
+func (r *HotkeyRegistry) AdvanceChordStep(id string) (completed bool, err error) {
+	r.mu.Lock()
+	b, ok := r.bindings[id]
+	if !ok || len(b.ChordSteps) == 0 {
+		r.mu.Unlock()
+		return false, fmt.Errorf("no chord registered for id %q", id)
+	}
+	steps := b.ChordSteps
+	mode := b.Mode
+	threshold := b.TapHoldThreshold
+	nextIndex := b.stepIndex + 1
+	r.mu.Unlock()
+
+	if nextIndex >= len(steps) {
+		return true, r.reregisterChordStep(id, steps, mode, threshold, 0)
+	}
+	return false, r.reregisterChordStep(id, steps, mode, threshold, nextIndex)
+}
+
+// ResetChordStep rewinds id's OS-level grab back to the first step of its
+// chord sequence, e.g. after the inter-step timeout elapses.
+func (r *HotkeyRegistry) ResetChordStep(id string) error {
+	r.mu.Lock()
+	b, ok := r.bindings[id]
+	if !ok || len(b.ChordSteps) == 0 {
+		r.mu.Unlock()
+		return fmt.Errorf("no chord registered for id %q", id)
+	}
+	steps := b.ChordSteps
+	mode := b.Mode
+	threshold := b.TapHoldThreshold
+	r.mu.Unlock()
+
+	return r.reregisterChordStep(id, steps, mode, threshold, 0)
+}
+
+// reregisterChordStep re-grabs id's hotkey at steps[index], preserving the
+// full chord step list and recording the new cursor position.
+func (r *HotkeyRegistry) reregisterChordStep(id string, steps []string, mode Mode, threshold time.Duration, index int) error {
+	if err := r.RegisterTapHold(id, steps[index], mode, threshold); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	if b, ok := r.bindings[id]; ok {
+		b.ChordSteps = steps
+		b.stepIndex = index
+	}
+	r.mu.Unlock()
+	return nil
+}
+
+// Unregister removes a previously registered hotkey. It's a no-op if id
+// isn't currently registered. The shared tap is torn down once the last
+// binding is removed.
+func (r *HotkeyRegistry) Unregister(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.bindings[id]
+	if !ok {
+		return
+	}
+	existing.teardown()
+	r.slotUsed[existing.slot] = false
+	delete(r.bindings, id)
+
+	if len(r.bindings) == 0 {
+		stopTap()
+	}
+}
+
+// List returns all currently registered bindings.
+func (r *HotkeyRegistry) List() []Binding {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	out := make([]Binding, 0, len(r.bindings))
+	for _, b := range r.bindings {
+		out = append(out, b.Binding)
+	}
+	return out
+}
+
+// allocSlot finds a free C-side slot index, or -1 if the registry is full.
+// Caller must hold r.mu.
+func (r *HotkeyRegistry) allocSlot() int {
+	for i := range r.slotUsed {
+		if !r.slotUsed[i] {
+			r.slotUsed[i] = true
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,56 @@
+package hotkey
+
+import (
+	"fmt"
+	"time"
+)
+
+// backendKind records which OS mechanism a binding actually ended up using,
+// so Unregister can tear it down the right way. macOS has two: the event
+// tap (needs Accessibility permission, the only way to see modifier-only
+// combinations) and Carbon's RegisterEventHotKey (no permission needed, but
+// can't observe modifier-only combinations).
+type backendKind int
+
+const (
+	backendEventTap backendKind = iota
+	backendCarbon
+)
+
+func (b *registeredBinding) teardown() {
+	if b.backend == backendCarbon {
+		unregisterCarbonSlot(b.slot)
+	} else {
+		unregisterSlot(b.slot)
+	}
+}
+
+// registerBinding installs slot via the event-tap backend, falling back to
+// the Carbon RegisterEventHotKey backend when the tap can't be created
+// (typically because Accessibility permission hasn't been granted). The
+// Carbon backend can only observe ordinary key combinations, not
+// modifier-only ones, so that case surfaces a typed error instead.
+func (r *HotkeyRegistry) registerBinding(slot int, id, hotkeyStr string, threshold time.Duration) (backendKind, error) {
+	if err := ensureTap(); err == nil {
+		if err := registerSlotTapHold(slot, id, hotkeyStr, threshold); err == nil {
+			return backendEventTap, nil
+		}
+	}
+
+	flags, err := parseModifiers(hotkeyStr)
+	if err != nil {
+		return backendEventTap, err
+	}
+	keyCode, ok := Lookup(getKeyName(hotkeyStr))
+	if !ok {
+		return backendEventTap, fmt.Errorf("unknown key: %s", getKeyName(hotkeyStr))
+	}
+	if isModifierKey(keyCode) {
+		return backendEventTap, ErrModifierOnlyRequiresAccessibility
+	}
+
+	if err := registerCarbonSlot(slot, id, keyCode, flags); err != nil {
+		return backendEventTap, err
+	}
+	return backendCarbon, nil
+}
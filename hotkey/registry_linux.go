@@ -0,0 +1,26 @@
+package hotkey
+
+import "time"
+
+// backendKind is a single constant on Linux: there's only one mechanism
+// (X11's XGrabKey), unlike darwin's event-tap-with-Carbon-fallback.
+type backendKind int
+
+const backendNative backendKind = 0
+
+func (b *registeredBinding) teardown() {
+	unregisterSlot(b.slot)
+}
+
+// registerBinding installs slot via XGrabKey. Under Wayland (no DISPLAY, or
+// no X server reachable) ensureTap fails with ErrUnsupported, since there's
+// no fallback global-grab mechanism to offer a Wayland client.
+func (r *HotkeyRegistry) registerBinding(slot int, id, hotkeyStr string, threshold time.Duration) (backendKind, error) {
+	if err := ensureTap(); err != nil {
+		return backendNative, err
+	}
+	if err := registerSlotTapHold(slot, id, hotkeyStr, threshold); err != nil {
+		return backendNative, err
+	}
+	return backendNative, nil
+}
@@ -0,0 +1,26 @@
+package hotkey
+
+import "time"
+
+// backendKind is a single constant on Windows: there's only one mechanism
+// (RegisterHotKey), unlike darwin's event-tap-with-Carbon-fallback.
+type backendKind int
+
+const backendNative backendKind = 0
+
+func (b *registeredBinding) teardown() {
+	unregisterSlot(b.slot)
+}
+
+// registerBinding installs slot via RegisterHotKey. There's no fallback
+// backend to try on Windows, so any failure (e.g. the combination is already
+// claimed by another application) is returned as-is.
+func (r *HotkeyRegistry) registerBinding(slot int, id, hotkeyStr string, threshold time.Duration) (backendKind, error) {
+	if err := ensureTap(); err != nil {
+		return backendNative, err
+	}
+	if err := registerSlotTapHold(slot, id, hotkeyStr, threshold); err != nil {
+		return backendNative, err
+	}
+	return backendNative, nil
+}
@@ -5,21 +5,12 @@ import (
 	"fmt"
 	"log/slog"
 	"sync"
+	"time"
 )
 
 // HotkeyCallback is the function signature for hotkey press callbacks
 type HotkeyCallback func()
 
-// HotkeyMode defines how the hotkey behaves
-type HotkeyMode int
-
-const (
-	// ModeToggle - press to start, press again to stop
-	ModeToggle HotkeyMode = iota
-	// ModeHoldToTalk - hold to record, release to stop
-	ModeHoldToTalk
-)
-
 // HotkeyService manages global hotkey registration
 type HotkeyService struct {
 	ctx        context.Context
@@ -30,33 +21,49 @@ type HotkeyService struct {
 	handler *ModeHandler
 
 	// Legacy callbacks (used by handler)
-	onPress    HotkeyCallback
-	onRelease  HotkeyCallback
+	onPress           HotkeyCallback
+	onRelease         HotkeyCallback
 	onHandsFreeToggle func(enabled bool)
 
 	// Recording mode
-	recorder     KeyRecorder
-	isRecording  bool
-	recordingMu  sync.Mutex
+	recorder    KeyRecorder
+	isRecording bool
+	recordingMu sync.Mutex
+
+	// registry backs the two bindings this service exposes ("main" and
+	// "handsFree") on top of the general-purpose multi-hotkey tap.
+	registry *HotkeyRegistry
 
 	mu sync.Mutex
 }
 
+const (
+	bindingMainID      = "main"
+	bindingHandsFreeID = "handsFree"
+)
+
 // NewHotkeyService creates a new hotkey service
 func NewHotkeyService() *HotkeyService {
-	handler := NewModeHandler(ModeHoldToTalk)
+	handler := NewModeHandler(ModePushToTalk)
 	return &HotkeyService{
-		handler: handler,
+		handler:  handler,
+		registry: NewHotkeyRegistry(),
 	}
 }
 
 // SetMode sets the hotkey behavior mode
-func (s *HotkeyService) SetMode(mode HotkeyMode) {
+func (s *HotkeyService) SetMode(mode Mode) {
 	s.handler.SetMode(mode)
 }
 
+// SetChordTimeout configures how long a ModeChord binding waits for its
+// next step before resetting back to the first one. Default 800ms.
+func (s *HotkeyService) SetChordTimeout(timeout time.Duration) {
+	s.handler.SetChordTimeout(timeout)
+}
+
 // GetMode returns the current hotkey mode
-func (s *HotkeyService) GetMode() HotkeyMode {
+func (s *HotkeyService) GetMode() Mode {
 	return s.handler.GetMode()
 }
 
@@ -99,9 +106,26 @@ func (s *HotkeyService) StartWithRelease(ctx context.Context, hotkeyStr string,
 	// Configure the handler with callbacks
 	s.handler.SetCallbacks(onPress, onRelease, s.onHandsFreeToggle)
 
-	// Start the event tap with channel-based approach
-	if err := startTapWithChannel(hotkeyStr, handsFreeHotkeyStr); err != nil {
-		return fmt.Errorf("failed to start event tap: %w", err)
+	mode := s.handler.GetMode()
+	steps := splitChordSteps(hotkeyStr)
+
+	if mode == ModeChord && len(steps) > 1 {
+		s.handler.SetChordSteps(len(steps))
+		s.handler.SetChordCallbacks(s.emitChordProgress, s.resetChord)
+		if err := s.registry.RegisterChord(bindingMainID, steps, mode); err != nil {
+			return fmt.Errorf("failed to register chord hotkey: %w", err)
+		}
+	} else {
+		// Register the main binding via the shared multi-hotkey registry
+		if err := s.registry.Register(bindingMainID, hotkeyStr, mode); err != nil {
+			return fmt.Errorf("failed to register hotkey: %w", err)
+		}
+	}
+	if handsFreeHotkeyStr != "" {
+		if err := s.registry.Register(bindingHandsFreeID, handsFreeHotkeyStr, ModeToggle); err != nil {
+			s.registry.Unregister(bindingMainID)
+			return fmt.Errorf("failed to register hands-free hotkey: %w", err)
+		}
 	}
 
 	// Start the event processing loop
@@ -129,18 +153,44 @@ func (s *HotkeyService) eventLoop(ctx context.Context) {
 	}
 }
 
-// processEvent handles a single hotkey event
+// processEvent handles a single hotkey event, dispatching by registry ID
+// now that the tap can carry events for arbitrarily many bindings.
 func (s *HotkeyService) processEvent(event HotkeyEvent) {
-	switch event.Type {
-	case EventPress:
-		s.handler.HandleKeyDown("main")
+	switch event.ID {
+	case bindingMainID:
+		switch event.Type {
+		case EventPress:
+			s.handler.HandleKeyDown("main")
+			if s.handler.GetMode() == ModeChord {
+				if _, err := s.registry.AdvanceChordStep(bindingMainID); err != nil {
+					slog.Warn("failed to advance chord step", "error", err)
+				}
+			}
+		case EventRelease:
+			s.handler.HandleKeyUp("main")
+		}
+
+	case bindingHandsFreeID:
+		// Hands-free is toggle-only: trigger on press, ignore release.
+		if event.Type == EventPress {
+			go s.handler.HandleHandsFreeToggle()
+		}
+	}
+}
 
-	case EventRelease:
-		s.handler.HandleKeyUp("main")
+// emitChordProgress is wired as the ModeHandler's chord-progress callback,
+// publishing an EventChordProgress on the shared event channel so the UI
+// can render a "waiting for next key..." hint.
+func (s *HotkeyService) emitChordProgress(step, total int) {
+	event := HotkeyEvent{ID: bindingMainID, Type: EventChordProgress, Timestamp: time.Now(), Step: step}
+	publishHotkeyEvent(event)
+}
 
-	case EventHandsFreeToggle:
-		// Execute on a separate goroutine to avoid blocking
-		go s.handler.HandleHandsFreeToggle()
+// resetChord is wired as the ModeHandler's chord-timeout callback,
+// re-registering the underlying hotkey back to the sequence's first step.
+func (s *HotkeyService) resetChord() {
+	if err := s.registry.ResetChordStep(bindingMainID); err != nil {
+		slog.Warn("failed to reset chord step", "error", err)
 	}
 }
 
@@ -162,13 +212,44 @@ func (s *HotkeyService) Stop() {
 		s.cancel()
 	}
 
-	stopTap()
+	s.registry.Unregister(bindingMainID)
+	s.registry.Unregister(bindingHandsFreeID)
 	s.handler.Reset()
 
 	s.registered = false
 	slog.Info("global hotkey unregistered")
 }
 
+// QueueDepth returns how many bytes of hotkey events are currently queued
+// for delivery but not yet consumed, for diagnostics.
+func (s *HotkeyService) QueueDepth() uint64 {
+	q := getHotkeyQueue()
+	if q == nil {
+		return 0
+	}
+	return q.Depth()
+}
+
+// QueueDropped returns how many hotkey events have been evicted from the
+// durable queue before being read, or discarded for being larger than it,
+// across the lifetime of the queue file (including prior runs).
+func (s *HotkeyService) QueueDropped() uint64 {
+	q := getHotkeyQueue()
+	if q == nil {
+		return 0
+	}
+	return q.Dropped()
+}
+
+// QueueCapacity returns the hotkey event queue's data region size in bytes.
+func (s *HotkeyService) QueueCapacity() uint64 {
+	q := getHotkeyQueue()
+	if q == nil {
+		return 0
+	}
+	return q.Capacity()
+}
+
 // IsRegistered returns whether the hotkey is currently registered
 func (s *HotkeyService) IsRegistered() bool {
 	s.mu.Lock()
@@ -190,6 +271,33 @@ func (s *HotkeyService) StartRecording() error {
 // StartRecordingWithEmitter starts native keyboard recording mode with an event emitter
 // The emitter allows direct event emission to the frontend, bypassing the channel
 func (s *HotkeyService) StartRecordingWithEmitter(emitter EventEmitter) error {
+	return s.startRecording(emitter, false)
+}
+
+// StartChordRecording starts native keyboard recording mode in chord mode:
+// a completed hotkey is accumulated as one step instead of ending recording
+// immediately, so the settings UI can capture multi-step bindings like
+// "Ctrl+K Ctrl+R". Each step is emitted as a RecordedKeyEvent with
+// IsChordStep set; the final one (on timeout) additionally sets IsComplete.
+func (s *HotkeyService) StartChordRecording(emitter EventEmitter) error {
+	return s.startRecording(emitter, true)
+}
+
+func (s *HotkeyService) startRecording(emitter EventEmitter, chordMode bool) error {
+	return s.startRecordingForAction(emitter, chordMode, "", nil)
+}
+
+// StartRecordingForRebind starts native keyboard recording mode like
+// StartRecordingWithEmitter, but additionally writes the captured
+// HotkeyString back into store under action once recording completes - see
+// BindingManager.Rebind and DarwinKeyRecorder.SetBindingStore. This is the
+// entry point a "rebind this shortcut" settings UI should call instead of
+// StartRecordingWithEmitter.
+func (s *HotkeyService) StartRecordingForRebind(action string, store BindingStore, emitter EventEmitter) error {
+	return s.startRecordingForAction(emitter, false, action, store)
+}
+
+func (s *HotkeyService) startRecordingForAction(emitter EventEmitter, chordMode bool, action string, store BindingStore) error {
 	s.recordingMu.Lock()
 	defer s.recordingMu.Unlock()
 
@@ -197,14 +305,20 @@ func (s *HotkeyService) StartRecordingWithEmitter(emitter EventEmitter) error {
 		return fmt.Errorf("recording already in progress")
 	}
 
-	// Create a new recorder
-	recorder := NewDarwinKeyRecorder()
+	// Create a new recorder for whichever platform is compiled in - see
+	// NewKeyRecorder.
+	recorder := NewKeyRecorder()
+	recorder.SetChordMode(chordMode)
 
 	// Set the emitter if provided for direct frontend communication
 	if emitter != nil {
 		recorder.SetEmitter(emitter)
 	}
 
+	if store != nil {
+		recorder.SetBindingStore(action, store)
+	}
+
 	if err := recorder.Start(); err != nil {
 		return fmt.Errorf("failed to start recorder: %w", err)
 	}
@@ -0,0 +1,47 @@
+package main
+
+import "super-characters/hotkey"
+
+// ListHotkeyActions returns every action this app's hotkey bindings cover,
+// alongside its currently effective hotkey string - see
+// hotkey.BindingManager.ListActions.
+func (a *App) ListHotkeyActions() map[string]string {
+	if a.bindingManager == nil {
+		return map[string]string{}
+	}
+	return a.bindingManager.ListActions()
+}
+
+// RebindHotkey updates action's hotkey to hotkeyStr, persisting it to the
+// keybindings file and re-registering the OS-level hotkey immediately.
+// Returns an error string, or "" on success.
+func (a *App) RebindHotkey(action, hotkeyStr string) string {
+	if a.bindingManager == nil {
+		return "binding manager unavailable"
+	}
+	if err := a.bindingManager.Rebind(action, hotkeyStr); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+// StartHotkeyRecordingForRebind starts native keyboard recording mode for
+// action: once a complete hotkey is captured, it's written back into the
+// keybindings file automatically (see hotkey.BindingManager.Rebind and
+// hotkey.HotkeyService.StartRecordingForRebind), re-registering it the same
+// way RebindHotkey does. The frontend should listen for the
+// "hotkey:recording:event" event the same way it does for
+// StartRecordingWithEmitter. Returns an error string, or "" on success.
+func (a *App) StartHotkeyRecordingForRebind(action string) string {
+	if a.bindingManager == nil {
+		return "binding manager unavailable"
+	}
+	var emitter hotkey.EventEmitter
+	if a.app != nil {
+		emitter = a.app.Event
+	}
+	if err := a.hotkeyService.StartRecordingForRebind(action, a.bindingManager, emitter); err != nil {
+		return err.Error()
+	}
+	return ""
+}
@@ -0,0 +1,227 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// AnthropicProviderName is the name AnthropicProvider registers under in a
+// Registry, matching the string stored in Settings.LLMProvider.
+const AnthropicProviderName = "anthropic"
+
+// AnthropicDefaultModel is the model used when AnthropicProvider.model is
+// unset.
+const AnthropicDefaultModel = "claude-3-5-sonnet-20241022"
+
+// AnthropicBaseURL is Anthropic's Messages API endpoint.
+const AnthropicBaseURL = "https://api.anthropic.com/v1/messages"
+
+// AnthropicAPIVersion is the anthropic-version header value this package
+// was written against.
+const AnthropicAPIVersion = "2023-06-01"
+
+// AnthropicProvider talks to Anthropic's Messages API. It implements
+// Provider.
+type AnthropicProvider struct {
+	apiKey string
+	model  string
+}
+
+// NewAnthropicProvider creates an AnthropicProvider.
+func NewAnthropicProvider(apiKey string) *AnthropicProvider {
+	return &AnthropicProvider{apiKey: apiKey, model: AnthropicDefaultModel}
+}
+
+// SetAPIKey updates the API key.
+func (p *AnthropicProvider) SetAPIKey(key string) { p.apiKey = key }
+
+// Name identifies this provider in Settings.LLMProvider.
+func (p *AnthropicProvider) Name() string { return AnthropicProviderName }
+
+// IsConfigured returns true if the API key is set.
+func (p *AnthropicProvider) IsConfigured() bool { return p.apiKey != "" }
+
+// Capabilities reports that this provider streams natively.
+func (p *AnthropicProvider) Capabilities() Capabilities {
+	return Capabilities{Streaming: true}
+}
+
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type anthropicRequest struct {
+	Model     string             `json:"model"`
+	System    string             `json:"system,omitempty"`
+	Messages  []anthropicMessage `json:"messages"`
+	MaxTokens int                `json:"max_tokens"`
+	Stream    bool               `json:"stream"`
+}
+
+// anthropicMaxTokens is the max_tokens value Anthropic's API requires on
+// every request.
+const anthropicMaxTokens = 1024
+
+// splitSystem pulls out the (at most one) "system" message Anthropic
+// expects as a separate top-level field, rather than inline in Messages.
+func splitSystem(history []Message) (system string, messages []anthropicMessage) {
+	messages = make([]anthropicMessage, 0, len(history))
+	for _, m := range history {
+		if m.Role == "system" {
+			system = m.Content
+			continue
+		}
+		messages = append(messages, anthropicMessage{Role: m.Role, Content: m.Content})
+	}
+	return system, messages
+}
+
+func (p *AnthropicProvider) newRequest(ctx context.Context, reqBody anthropicRequest) (*http.Request, error) {
+	jsonBody, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", AnthropicBaseURL, bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", p.apiKey)
+	req.Header.Set("anthropic-version", AnthropicAPIVersion)
+	return req, nil
+}
+
+// Chat sends history to the Messages API and returns the first content
+// block's text.
+func (p *AnthropicProvider) Chat(ctx context.Context, history []Message) (string, error) {
+	if p.apiKey == "" {
+		return "", fmt.Errorf("anthropic API key not configured")
+	}
+
+	system, messages := splitSystem(history)
+	req, err := p.newRequest(ctx, anthropicRequest{
+		Model:     p.model,
+		System:    system,
+		Messages:  messages,
+		MaxTokens: anthropicMaxTokens,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to call Anthropic API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read Anthropic response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("anthropic API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var result struct {
+		Content []struct {
+			Text string `json:"text"`
+		} `json:"content"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("failed to decode Anthropic response: %w", err)
+	}
+	if len(result.Content) == 0 {
+		return "", fmt.Errorf("anthropic returned no content")
+	}
+	return result.Content[0].Text, nil
+}
+
+// ChatStream sends history to the Messages API with stream:true and yields
+// each content_block_delta event's text as it arrives.
+func (p *AnthropicProvider) ChatStream(ctx context.Context, history []Message) (<-chan Delta, <-chan error) {
+	deltas := make(chan Delta)
+	errc := make(chan error, 1)
+
+	if p.apiKey == "" {
+		close(deltas)
+		errc <- fmt.Errorf("anthropic API key not configured")
+		return deltas, errc
+	}
+
+	system, messages := splitSystem(history)
+
+	go func() {
+		defer close(deltas)
+
+		req, err := p.newRequest(ctx, anthropicRequest{
+			Model:     p.model,
+			System:    system,
+			Messages:  messages,
+			MaxTokens: anthropicMaxTokens,
+			Stream:    true,
+		})
+		if err != nil {
+			errc <- err
+			return
+		}
+		req.Header.Set("Accept", "text/event-stream")
+
+		client := &http.Client{Timeout: 60 * time.Second}
+		resp, err := client.Do(req)
+		if err != nil {
+			errc <- fmt.Errorf("failed to call Anthropic API: %w", err)
+			return
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			errc <- fmt.Errorf("anthropic API returned status %d: %s", resp.StatusCode, string(body))
+			return
+		}
+
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if !strings.HasPrefix(line, "data: ") {
+				continue
+			}
+
+			var event struct {
+				Type  string `json:"type"`
+				Delta struct {
+					Text string `json:"text"`
+				} `json:"delta"`
+			}
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &event); err != nil {
+				continue
+			}
+			if event.Type != "content_block_delta" || event.Delta.Text == "" {
+				continue
+			}
+
+			select {
+			case deltas <- Delta{Text: event.Delta.Text}:
+			case <-ctx.Done():
+				errc <- ctx.Err()
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			errc <- fmt.Errorf("failed to read Anthropic stream: %w", err)
+		}
+	}()
+
+	return deltas, errc
+}
@@ -0,0 +1,69 @@
+package llm
+
+import (
+	"context"
+
+	"super-characters/gemini"
+)
+
+// GeminiProviderName is the name GeminiProvider registers under in a
+// Registry, matching the string stored in Settings.LLMProvider.
+const GeminiProviderName = "gemini"
+
+// GeminiProvider adapts an existing gemini.GeminiService to Provider, so
+// the same service (and its API key) used for conversation today is also
+// reachable through the llm.Registry.
+type GeminiProvider struct {
+	service *gemini.GeminiService
+}
+
+// NewGeminiProvider wraps service for use as an llm.Provider.
+func NewGeminiProvider(service *gemini.GeminiService) *GeminiProvider {
+	return &GeminiProvider{service: service}
+}
+
+// Name identifies this provider in Settings.LLMProvider.
+func (p *GeminiProvider) Name() string { return GeminiProviderName }
+
+// IsConfigured returns true if the underlying Gemini API key is set.
+func (p *GeminiProvider) IsConfigured() bool {
+	return p.service != nil && p.service.IsConfigured()
+}
+
+// Capabilities reports that Gemini streams natively.
+func (p *GeminiProvider) Capabilities() Capabilities {
+	return Capabilities{Streaming: true}
+}
+
+// Chat sends history to Gemini and returns its reply.
+func (p *GeminiProvider) Chat(ctx context.Context, history []Message) (string, error) {
+	return p.service.Chat(toGeminiMessages(history))
+}
+
+// ChatStream sends history to Gemini's streaming endpoint.
+func (p *GeminiProvider) ChatStream(ctx context.Context, history []Message) (<-chan Delta, <-chan error) {
+	chunks, errc := p.service.ChatStream(ctx, toGeminiMessages(history))
+	return toDeltaChan(chunks), errc
+}
+
+// toGeminiMessages converts provider-agnostic messages to
+// gemini.ChatMessage, which has an identical shape.
+func toGeminiMessages(history []Message) []gemini.ChatMessage {
+	messages := make([]gemini.ChatMessage, len(history))
+	for i, m := range history {
+		messages[i] = gemini.ChatMessage{Role: m.Role, Content: m.Content}
+	}
+	return messages
+}
+
+// toDeltaChan wraps a plain-text delta channel as a Delta channel.
+func toDeltaChan(chunks <-chan string) <-chan Delta {
+	out := make(chan Delta)
+	go func() {
+		defer close(out)
+		for chunk := range chunks {
+			out <- Delta{Text: chunk}
+		}
+	}()
+	return out
+}
@@ -0,0 +1,198 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// OllamaProviderName is the name OllamaProvider registers under in a
+// Registry, matching the string stored in Settings.LLMProvider.
+const OllamaProviderName = "ollama"
+
+// OllamaDefaultBaseURL is where Ollama listens by default on the same
+// machine.
+const OllamaDefaultBaseURL = "http://localhost:11434"
+
+// OllamaDefaultModel is the model used when OllamaProvider.model is unset.
+const OllamaDefaultModel = "llama3.2"
+
+// OllamaProvider talks to a local Ollama install's /api/chat endpoint, for
+// fully offline conversation with no API key required. It implements
+// Provider.
+type OllamaProvider struct {
+	baseURL string
+	model   string
+}
+
+// NewOllamaProvider creates an OllamaProvider. An empty baseURL defaults to
+// OllamaDefaultBaseURL.
+func NewOllamaProvider(baseURL string) *OllamaProvider {
+	if baseURL == "" {
+		baseURL = OllamaDefaultBaseURL
+	}
+	return &OllamaProvider{baseURL: baseURL, model: OllamaDefaultModel}
+}
+
+// SetBaseURL updates the Ollama server address.
+func (p *OllamaProvider) SetBaseURL(baseURL string) {
+	if baseURL == "" {
+		baseURL = OllamaDefaultBaseURL
+	}
+	p.baseURL = baseURL
+}
+
+// Name identifies this provider in Settings.LLMProvider.
+func (p *OllamaProvider) Name() string { return OllamaProviderName }
+
+// IsConfigured always returns true: Ollama needs no API key, just a
+// reachable server, and a bad address fails at call time like any other
+// network error rather than being detectable up front.
+func (p *OllamaProvider) IsConfigured() bool { return p.baseURL != "" }
+
+// Capabilities reports that this provider streams natively.
+func (p *OllamaProvider) Capabilities() Capabilities {
+	return Capabilities{Streaming: true}
+}
+
+type ollamaMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type ollamaChatRequest struct {
+	Model    string          `json:"model"`
+	Messages []ollamaMessage `json:"messages"`
+	Stream   bool            `json:"stream"`
+}
+
+type ollamaChatResponse struct {
+	Message ollamaMessage `json:"message"`
+	Done    bool          `json:"done"`
+}
+
+// Chat sends history to /api/chat with stream:false and returns the
+// assistant message's content.
+func (p *OllamaProvider) Chat(ctx context.Context, history []Message) (string, error) {
+	reqBody := ollamaChatRequest{Model: p.model, Messages: toOllamaMessages(history)}
+	jsonBody, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/api/chat", bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: 60 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to call Ollama: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read Ollama response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("ollama returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var result ollamaChatResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("failed to decode Ollama response: %w", err)
+	}
+	return result.Message.Content, nil
+}
+
+// ChatStream sends history to /api/chat with stream:true and yields each
+// response line's message content - Ollama streams newline-delimited JSON
+// objects rather than server-sent events.
+func (p *OllamaProvider) ChatStream(ctx context.Context, history []Message) (<-chan Delta, <-chan error) {
+	deltas := make(chan Delta)
+	errc := make(chan error, 1)
+
+	reqBody := ollamaChatRequest{Model: p.model, Messages: toOllamaMessages(history), Stream: true}
+
+	go func() {
+		defer close(deltas)
+
+		jsonBody, err := json.Marshal(reqBody)
+		if err != nil {
+			errc <- fmt.Errorf("failed to marshal request: %w", err)
+			return
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/api/chat", bytes.NewBuffer(jsonBody))
+		if err != nil {
+			errc <- fmt.Errorf("failed to create request: %w", err)
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		client := &http.Client{Timeout: 120 * time.Second}
+		resp, err := client.Do(req)
+		if err != nil {
+			errc <- fmt.Errorf("failed to call Ollama: %w", err)
+			return
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			errc <- fmt.Errorf("ollama returned status %d: %s", resp.StatusCode, string(body))
+			return
+		}
+
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := scanner.Bytes()
+			if len(line) == 0 {
+				continue
+			}
+
+			var chunk ollamaChatResponse
+			if err := json.Unmarshal(line, &chunk); err != nil {
+				continue
+			}
+			if chunk.Message.Content == "" {
+				continue
+			}
+
+			select {
+			case deltas <- Delta{Text: chunk.Message.Content}:
+			case <-ctx.Done():
+				errc <- ctx.Err()
+				return
+			}
+
+			if chunk.Done {
+				break
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			errc <- fmt.Errorf("failed to read Ollama stream: %w", err)
+		}
+	}()
+
+	return deltas, errc
+}
+
+// toOllamaMessages converts provider-agnostic messages to Ollama's wire
+// format, which already matches {role, content}.
+func toOllamaMessages(history []Message) []ollamaMessage {
+	messages := make([]ollamaMessage, len(history))
+	for i, m := range history {
+		messages[i] = ollamaMessage{Role: m.Role, Content: m.Content}
+	}
+	return messages
+}
@@ -0,0 +1,231 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// OpenAIProviderName is the name OpenAIProvider registers under in a
+// Registry, matching the string stored in Settings.LLMProvider.
+const OpenAIProviderName = "openai"
+
+// OpenAIDefaultModel is the chat model used when OpenAIProvider.model is
+// unset.
+const OpenAIDefaultModel = "gpt-4o-mini"
+
+// OpenAIDefaultBaseURL is OpenAI's own API, also the default for
+// OpenAI-compatible endpoints (local or third-party) that don't need a
+// different one.
+const OpenAIDefaultBaseURL = "https://api.openai.com/v1"
+
+// OpenAIProvider talks to OpenAI's /chat/completions endpoint, or any
+// OpenAI-compatible endpoint reachable at a different baseURL. It
+// implements Provider.
+type OpenAIProvider struct {
+	apiKey  string
+	baseURL string
+	model   string
+}
+
+// NewOpenAIProvider creates an OpenAIProvider. An empty baseURL defaults to
+// OpenAIDefaultBaseURL.
+func NewOpenAIProvider(apiKey string, baseURL string) *OpenAIProvider {
+	if baseURL == "" {
+		baseURL = OpenAIDefaultBaseURL
+	}
+	return &OpenAIProvider{apiKey: apiKey, baseURL: baseURL, model: OpenAIDefaultModel}
+}
+
+// SetAPIKey updates the API key.
+func (p *OpenAIProvider) SetAPIKey(key string) { p.apiKey = key }
+
+// SetBaseURL updates the endpoint, for OpenAI-compatible servers other than
+// OpenAI itself. An empty value resets it to OpenAIDefaultBaseURL.
+func (p *OpenAIProvider) SetBaseURL(baseURL string) {
+	if baseURL == "" {
+		baseURL = OpenAIDefaultBaseURL
+	}
+	p.baseURL = baseURL
+}
+
+// Name identifies this provider in Settings.LLMProvider.
+func (p *OpenAIProvider) Name() string { return OpenAIProviderName }
+
+// IsConfigured returns true if the API key is set.
+func (p *OpenAIProvider) IsConfigured() bool { return p.apiKey != "" }
+
+// Capabilities reports that this provider streams natively.
+func (p *OpenAIProvider) Capabilities() Capabilities {
+	return Capabilities{Streaming: true}
+}
+
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openAIChatRequest struct {
+	Model    string          `json:"model"`
+	Messages []openAIMessage `json:"messages"`
+	Stream   bool            `json:"stream"`
+}
+
+// Chat sends history to the chat/completions endpoint and returns the
+// first choice's message content.
+func (p *OpenAIProvider) Chat(ctx context.Context, history []Message) (string, error) {
+	if p.apiKey == "" {
+		return "", fmt.Errorf("openai API key not configured")
+	}
+
+	reqBody := openAIChatRequest{
+		Model:    p.model,
+		Messages: toOpenAIMessages(history),
+	}
+	jsonBody, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/chat/completions", bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+p.apiKey)
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to call OpenAI API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read OpenAI response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("openai API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var result struct {
+		Choices []struct {
+			Message openAIMessage `json:"message"`
+		} `json:"choices"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("failed to decode OpenAI response: %w", err)
+	}
+	if len(result.Choices) == 0 {
+		return "", fmt.Errorf("openai returned no choices")
+	}
+	return result.Choices[0].Message.Content, nil
+}
+
+// ChatStream sends history to chat/completions with stream:true and yields
+// each choice's content delta as it arrives over server-sent events.
+func (p *OpenAIProvider) ChatStream(ctx context.Context, history []Message) (<-chan Delta, <-chan error) {
+	deltas := make(chan Delta)
+	errc := make(chan error, 1)
+
+	if p.apiKey == "" {
+		close(deltas)
+		errc <- fmt.Errorf("openai API key not configured")
+		return deltas, errc
+	}
+
+	reqBody := openAIChatRequest{
+		Model:    p.model,
+		Messages: toOpenAIMessages(history),
+		Stream:   true,
+	}
+
+	go func() {
+		defer close(deltas)
+
+		jsonBody, err := json.Marshal(reqBody)
+		if err != nil {
+			errc <- fmt.Errorf("failed to marshal request: %w", err)
+			return
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/chat/completions", bytes.NewBuffer(jsonBody))
+		if err != nil {
+			errc <- fmt.Errorf("failed to create request: %w", err)
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+		req.Header.Set("Accept", "text/event-stream")
+
+		client := &http.Client{Timeout: 60 * time.Second}
+		resp, err := client.Do(req)
+		if err != nil {
+			errc <- fmt.Errorf("failed to call OpenAI API: %w", err)
+			return
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			errc <- fmt.Errorf("openai API returned status %d: %s", resp.StatusCode, string(body))
+			return
+		}
+
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if !strings.HasPrefix(line, "data: ") {
+				continue
+			}
+			data := strings.TrimPrefix(line, "data: ")
+			if data == "[DONE]" {
+				break
+			}
+
+			var chunk struct {
+				Choices []struct {
+					Delta struct {
+						Content string `json:"content"`
+					} `json:"delta"`
+				} `json:"choices"`
+			}
+			if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+				continue
+			}
+			if len(chunk.Choices) == 0 || chunk.Choices[0].Delta.Content == "" {
+				continue
+			}
+
+			select {
+			case deltas <- Delta{Text: chunk.Choices[0].Delta.Content}:
+			case <-ctx.Done():
+				errc <- ctx.Err()
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			errc <- fmt.Errorf("failed to read OpenAI stream: %w", err)
+		}
+	}()
+
+	return deltas, errc
+}
+
+// toOpenAIMessages converts provider-agnostic messages to OpenAI's wire
+// format, which already matches {role, content}.
+func toOpenAIMessages(history []Message) []openAIMessage {
+	messages := make([]openAIMessage, len(history))
+	for i, m := range history {
+		messages[i] = openAIMessage{Role: m.Role, Content: m.Content}
+	}
+	return messages
+}
@@ -0,0 +1,55 @@
+// Package llm defines a provider-agnostic chat interface, so callers can
+// hold a conversation without depending on any one model vendor (see
+// Registry). It mirrors tts and stt's provider/registry split: swapping the
+// active backend is a Settings.LLMProvider change, not a code change.
+package llm
+
+import "context"
+
+// Message is one turn in a conversation, independent of any backend's wire
+// format.
+type Message struct {
+	Role    string // "system", "user", "assistant"
+	Content string
+}
+
+// Delta is one incremental piece of a streamed reply, yielded by
+// Provider.ChatStream.
+type Delta struct {
+	Text string
+}
+
+// Capabilities describes what a Provider can do beyond the baseline Chat
+// call, so callers can adapt (e.g. fall back to Chat when ChatStream isn't
+// supported) instead of guessing from the provider's name.
+type Capabilities struct {
+	// Streaming reports whether ChatStream is actually incremental. A
+	// provider with no streaming API of its own may still implement
+	// ChatStream by wrapping Chat and emitting one Delta, in which case it
+	// should report false here.
+	Streaming bool
+}
+
+// Provider generates a conversational reply from a single LLM backend (e.g.
+// Gemini, an OpenAI-compatible endpoint, Anthropic, or a local Ollama
+// install). Implementations are registered under a name via
+// Registry.Register and selected via Settings.LLMProvider.
+type Provider interface {
+	// Name identifies the provider, matching the string stored in
+	// Settings.LLMProvider.
+	Name() string
+	// IsConfigured reports whether the provider has what it needs to run
+	// (an API key, a reachable local server, etc).
+	IsConfigured() bool
+	// Capabilities reports what this provider supports.
+	Capabilities() Capabilities
+	// Chat sends history to the model and returns its full reply.
+	Chat(ctx context.Context, history []Message) (string, error)
+	// ChatStream is Chat's streaming counterpart: it yields each
+	// incremental text delta on the returned channel as it arrives instead
+	// of blocking for the whole reply. The delta channel is closed when the
+	// stream ends, successfully or not; the error channel is buffered with
+	// capacity 1 and receives a value only on failure, which the caller
+	// should check after the delta channel closes.
+	ChatStream(ctx context.Context, history []Message) (<-chan Delta, <-chan error)
+}
@@ -0,0 +1,66 @@
+package llm
+
+import (
+	"fmt"
+	"sort"
+)
+
+// DefaultProviderName is the provider selected when Settings.LLMProvider is
+// empty.
+const DefaultProviderName = "gemini"
+
+// Registry holds the LLM providers available to App and tracks which one is
+// active, mirroring tts.Registry and stt.Registry: the active provider is
+// per-app-instance state driven by Settings.LLMProvider, not a process-wide
+// default.
+type Registry struct {
+	providers map[string]Provider
+	active    string
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{providers: make(map[string]Provider)}
+}
+
+// Register makes provider available under name. Panics on a duplicate name,
+// since that can only indicate two providers colliding at setup time.
+func (r *Registry) Register(name string, provider Provider) {
+	if _, exists := r.providers[name]; exists {
+		panic(fmt.Sprintf("llm: provider %q already registered", name))
+	}
+	r.providers[name] = provider
+}
+
+// SetActive selects the provider used by Active. An empty name selects
+// DefaultProviderName.
+func (r *Registry) SetActive(name string) {
+	if name == "" {
+		name = DefaultProviderName
+	}
+	r.active = name
+}
+
+// Active returns the currently selected provider.
+func (r *Registry) Active() (Provider, error) {
+	name := r.active
+	if name == "" {
+		name = DefaultProviderName
+	}
+	provider, ok := r.providers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown llm provider: %s", name)
+	}
+	return provider, nil
+}
+
+// List returns the names of every registered provider, for populating a
+// settings picker.
+func (r *Registry) List() []string {
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
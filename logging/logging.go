@@ -0,0 +1,109 @@
+// Package logging provides a small pluggable structured-logging interface,
+// modeled on hashicorp/go-hclog, for packages (pipedream, characters) that
+// previously wrote debug traces to a hard-coded file path.
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Logger is a small structured-logging interface: each call takes a message
+// plus alternating key/value pairs, e.g. Debug("fetched token", "length", 42).
+type Logger interface {
+	Debug(msg string, kv ...any)
+	Info(msg string, kv ...any)
+	Warn(msg string, kv ...any)
+	Error(msg string, kv ...any)
+}
+
+// discardLogger is a Logger that drops every call.
+type discardLogger struct{}
+
+func (discardLogger) Debug(string, ...any) {}
+func (discardLogger) Info(string, ...any)  {}
+func (discardLogger) Warn(string, ...any)  {}
+func (discardLogger) Error(string, ...any) {}
+
+// Discard is the no-op Logger a Service should default to when the caller
+// hasn't configured one.
+var Discard Logger = discardLogger{}
+
+// kvFields collapses alternating key/value pairs into a map, skipping any
+// key that isn't a string and dropping a trailing unpaired value.
+func kvFields(kv []any) map[string]any {
+	fields := make(map[string]any, len(kv)/2)
+	for i := 0; i+1 < len(kv); i += 2 {
+		key, ok := kv[i].(string)
+		if !ok {
+			continue
+		}
+		fields[key] = kv[i+1]
+	}
+	return fields
+}
+
+// JSONLogger is a Logger that writes one JSON object per call.
+type JSONLogger struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewJSONLogger returns a Logger that writes newline-delimited JSON to w.
+func NewJSONLogger(w io.Writer) *JSONLogger {
+	return &JSONLogger{w: w}
+}
+
+func (l *JSONLogger) log(level, msg string, kv ...any) {
+	entry := kvFields(kv)
+	entry["level"] = level
+	entry["msg"] = msg
+	entry["time"] = time.Now().Format(time.RFC3339)
+
+	body, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.w.Write(body)
+	l.w.Write([]byte("\n"))
+}
+
+func (l *JSONLogger) Debug(msg string, kv ...any) { l.log("debug", msg, kv...) }
+func (l *JSONLogger) Info(msg string, kv ...any)  { l.log("info", msg, kv...) }
+func (l *JSONLogger) Warn(msg string, kv ...any)  { l.log("warn", msg, kv...) }
+func (l *JSONLogger) Error(msg string, kv ...any) { l.log("error", msg, kv...) }
+
+// StdLogger adapts a stdlib *log.Logger to Logger, prefixing each line with
+// its level and appending key/value pairs as "key=value".
+type StdLogger struct {
+	l *log.Logger
+}
+
+// NewStdLogger wraps l as a Logger.
+func NewStdLogger(l *log.Logger) *StdLogger {
+	return &StdLogger{l: l}
+}
+
+func (s *StdLogger) log(level, msg string, kv ...any) {
+	var b strings.Builder
+	b.WriteString(level)
+	b.WriteString(": ")
+	b.WriteString(msg)
+	for i := 0; i+1 < len(kv); i += 2 {
+		fmt.Fprintf(&b, " %v=%v", kv[i], kv[i+1])
+	}
+	s.l.Print(b.String())
+}
+
+func (s *StdLogger) Debug(msg string, kv ...any) { s.log("DEBUG", msg, kv...) }
+func (s *StdLogger) Info(msg string, kv ...any)  { s.log("INFO", msg, kv...) }
+func (s *StdLogger) Warn(msg string, kv ...any)  { s.log("WARN", msg, kv...) }
+func (s *StdLogger) Error(msg string, kv ...any) { s.log("ERROR", msg, kv...) }
@@ -30,75 +30,6 @@ func (a *App) RunAppleScript(script string) (string, error) {
 	return result, nil
 }
 
-// PlayPauseMusic toggles play/pause on Spotify or Apple Music (whichever is running).
-func (a *App) PlayPauseMusic() (string, error) {
-	script := `
-		if application "Spotify" is running then
-			tell application "Spotify" to playpause
-			return "Toggled Spotify playback"
-		else if application "Music" is running then
-			tell application "Music" to playpause
-			return "Toggled Apple Music playback"
-		else
-			tell application "Music"
-				activate
-				delay 1
-				play
-			end tell
-			return "Started Apple Music"
-		end if
-	`
-	return a.RunAppleScript(script)
-}
-
-// NextTrack skips to the next track on Spotify or Apple Music.
-func (a *App) NextTrack() (string, error) {
-	script := `
-		if application "Spotify" is running then
-			tell application "Spotify" to next track
-			return "Skipped to next track on Spotify"
-		else if application "Music" is running then
-			tell application "Music" to next track
-			return "Skipped to next track on Apple Music"
-		else
-			return "No music player is running"
-		end if
-	`
-	return a.RunAppleScript(script)
-}
-
-// PreviousTrack goes back to the previous track on Spotify or Apple Music.
-func (a *App) PreviousTrack() (string, error) {
-	script := `
-		if application "Spotify" is running then
-			tell application "Spotify" to previous track
-			return "Went to previous track on Spotify"
-		else if application "Music" is running then
-			tell application "Music" to previous track
-			return "Went to previous track on Apple Music"
-		else
-			return "No music player is running"
-		end if
-	`
-	return a.RunAppleScript(script)
-}
-
-// SetVolume sets the system output volume (0–100).
-func (a *App) SetVolume(level int) (string, error) {
-	if level < 0 {
-		level = 0
-	}
-	if level > 100 {
-		level = 100
-	}
-	script := fmt.Sprintf("set volume output volume %d", level)
-	_, err := a.RunAppleScript(script)
-	if err != nil {
-		return "", err
-	}
-	return fmt.Sprintf("Volume set to %d", level), nil
-}
-
 // OpenApplication opens (or activates) a macOS application by name.
 func (a *App) OpenApplication(name string) (string, error) {
 	script := fmt.Sprintf(`tell application %q to activate`, name)
@@ -109,36 +40,4 @@ func (a *App) OpenApplication(name string) (string, error) {
 	return fmt.Sprintf("Opened %s", name), nil
 }
 
-// GetNowPlaying returns information about the currently playing track.
-func (a *App) GetNowPlaying() (string, error) {
-	script := `
-		if application "Spotify" is running then
-			tell application "Spotify"
-				if player state is playing then
-					set trackName to name of current track
-					set artistName to artist of current track
-					set albumName to album of current track
-					return "Playing on Spotify: " & trackName & " by " & artistName & " from " & albumName
-				else
-					return "Spotify is paused"
-				end if
-			end tell
-		else if application "Music" is running then
-			tell application "Music"
-				if player state is playing then
-					set trackName to name of current track
-					set artistName to artist of current track
-					set albumName to album of current track
-					return "Playing on Apple Music: " & trackName & " by " & artistName & " from " & albumName
-				else
-					return "Apple Music is paused"
-				end if
-			end tell
-		else
-			return "No music player is running"
-		end if
-	`
-	return a.RunAppleScript(script)
-}
-
 // #endregion Mac Control API
@@ -0,0 +1,219 @@
+// Package metrics exposes counters and histograms in Prometheus text
+// exposition format for the services that talk to slow or quota-limited
+// external APIs (avatar generation, TTS, Gemini).
+//
+// This is a minimal hand-rolled stand-in for prometheus/client_golang,
+// which this repo has no dependency manager to vendor (the same tradeoff
+// config and avatar's worker IPC make elsewhere in this tree - see their
+// doc comments). Swap this package for the real client library once the
+// build gains dependency management; the label/metric names below were
+// chosen to match what client_golang would produce, so that migration is a
+// drop-in replacement rather than a rename.
+package metrics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// defaultHistogramBuckets are upper bounds, in seconds, for the duration
+// histograms below. They cover sub-second TTS calls through slow
+// multi-minute avatar generations.
+var defaultHistogramBuckets = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120}
+
+// Registry holds a fixed set of counter and histogram metrics and renders
+// them in Prometheus text exposition format.
+type Registry struct {
+	mu         sync.Mutex
+	counters   map[string]*counter
+	histograms map[string]*histogram
+}
+
+// NewRegistry creates a Registry with this package's fixed set of metrics
+// already registered:
+//
+//   - avatar_generations_total{backend,success}
+//   - avatar_generation_duration_seconds{backend,success}
+//   - tts_synthesize_duration_seconds{provider,voice}
+//   - tts_bytes_total{provider}
+//   - tts_errors_total{provider,status}
+//   - gemini_request_duration_seconds{model}
+func NewRegistry() *Registry {
+	r := &Registry{
+		counters:   make(map[string]*counter),
+		histograms: make(map[string]*histogram),
+	}
+	r.newCounter("avatar_generations_total", "Total avatar generation attempts.", "backend", "success")
+	r.newHistogram("avatar_generation_duration_seconds", "Avatar generation duration in seconds.", "backend", "success")
+	r.newHistogram("tts_synthesize_duration_seconds", "TTS synthesis duration in seconds.", "provider", "voice")
+	r.newCounter("tts_bytes_total", "Total bytes of synthesized audio returned.", "provider")
+	r.newCounter("tts_errors_total", "Total TTS synthesis errors.", "provider", "status")
+	r.newHistogram("gemini_request_duration_seconds", "Gemini API request duration in seconds.", "model")
+	return r
+}
+
+func (r *Registry) newCounter(name, help string, labels ...string) {
+	r.counters[name] = &counter{help: help, labelNames: labels, values: make(map[string]float64)}
+}
+
+func (r *Registry) newHistogram(name, help string, labels ...string) {
+	r.histograms[name] = &histogram{help: help, labelNames: labels, buckets: defaultHistogramBuckets, values: make(map[string]*histogramValue)}
+}
+
+// IncCounter adds 1 to the named counter for the given label values, in the
+// same order the metric declares its labels (see NewRegistry's list).
+func (r *Registry) IncCounter(name string, labelValues ...string) {
+	r.AddCounter(name, 1, labelValues...)
+}
+
+// AddCounter adds delta to the named counter for the given label values.
+func (r *Registry) AddCounter(name string, delta float64, labelValues ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.counters[name]
+	if !ok {
+		return
+	}
+	c.add(labelValues, delta)
+}
+
+// ObserveHistogram records an observation (typically a duration in seconds)
+// for the named histogram and label values.
+func (r *Registry) ObserveHistogram(name string, value float64, labelValues ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	h, ok := r.histograms[name]
+	if !ok {
+		return
+	}
+	h.observe(labelValues, value)
+}
+
+// Render writes every registered metric in Prometheus text exposition
+// format, suitable for serving from a /metrics HTTP handler.
+func (r *Registry) Render() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var names []string
+	for name := range r.counters {
+		names = append(names, name)
+	}
+	for name := range r.histograms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		if c, ok := r.counters[name]; ok {
+			c.render(name, &b)
+		}
+		if h, ok := r.histograms[name]; ok {
+			h.render(name, &b)
+		}
+	}
+	return b.String()
+}
+
+type counter struct {
+	help       string
+	labelNames []string
+	values     map[string]float64
+}
+
+func (c *counter) add(labelValues []string, delta float64) {
+	c.values[labelKey(labelValues)] += delta
+}
+
+func (c *counter) render(name string, b *strings.Builder) {
+	fmt.Fprintf(b, "# HELP %s %s\n", name, c.help)
+	fmt.Fprintf(b, "# TYPE %s counter\n", name)
+	for _, key := range sortedKeys(c.values) {
+		fmt.Fprintf(b, "%s%s %v\n", name, labelSet(c.labelNames, key), c.values[key])
+	}
+}
+
+type histogramValue struct {
+	bucketCounts []float64
+	sum          float64
+	count        float64
+}
+
+type histogram struct {
+	help       string
+	labelNames []string
+	buckets    []float64
+	values     map[string]*histogramValue
+}
+
+func (h *histogram) observe(labelValues []string, value float64) {
+	key := labelKey(labelValues)
+	v, ok := h.values[key]
+	if !ok {
+		v = &histogramValue{bucketCounts: make([]float64, len(h.buckets))}
+		h.values[key] = v
+	}
+	for i, upperBound := range h.buckets {
+		if value <= upperBound {
+			v.bucketCounts[i]++
+		}
+	}
+	v.sum += value
+	v.count++
+}
+
+func (h *histogram) render(name string, b *strings.Builder) {
+	fmt.Fprintf(b, "# HELP %s %s\n", name, h.help)
+	fmt.Fprintf(b, "# TYPE %s histogram\n", name)
+	for _, key := range sortedKeys(h.values) {
+		v := h.values[key]
+		for i, upperBound := range h.buckets {
+			fmt.Fprintf(b, "%s_bucket%s %v\n", name, labelSetWithLe(h.labelNames, key, fmt.Sprintf("%g", upperBound)), v.bucketCounts[i])
+		}
+		fmt.Fprintf(b, "%s_bucket%s %v\n", name, labelSetWithLe(h.labelNames, key, "+Inf"), v.count)
+		fmt.Fprintf(b, "%s_sum%s %v\n", name, labelSet(h.labelNames, key), v.sum)
+		fmt.Fprintf(b, "%s_count%s %v\n", name, labelSet(h.labelNames, key), v.count)
+	}
+}
+
+// labelKey joins label values into a stable map key. "\x1f" (unit
+// separator) can't appear in a label value supplied by this package's
+// callers, which only pass provider/backend/model names and status codes.
+func labelKey(labelValues []string) string {
+	return strings.Join(labelValues, "\x1f")
+}
+
+func labelSet(labelNames []string, key string) string {
+	return labelSetWithLe(labelNames, key, "")
+}
+
+// labelSetWithLe renders Prometheus label syntax, e.g. {provider="piper"},
+// optionally appending a le="..." label for histogram buckets.
+func labelSetWithLe(labelNames []string, key string, le string) string {
+	values := strings.Split(key, "\x1f")
+	var pairs []string
+	for i, name := range labelNames {
+		if i < len(values) {
+			pairs = append(pairs, fmt.Sprintf("%s=%q", name, values[i]))
+		}
+	}
+	if le != "" {
+		pairs = append(pairs, fmt.Sprintf("le=%q", le))
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
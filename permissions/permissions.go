@@ -1,87 +1,90 @@
+// Package permissions checks, requests, and opens System Settings for the
+// macOS TCC-guarded capabilities the app needs. The cgo/Objective-C
+// implementations of CheckX/RequestX/OpenXSettings live in
+// permissions_darwin.go (RequestMicrophonePermission in
+// permissions_mic_darwin.go); this file holds the OS-independent types and
+// aggregation logic built on top of them.
 package permissions
 
-/*
-#cgo CFLAGS: -x objective-c
-#cgo LDFLAGS: -framework Cocoa -framework AVFoundation -framework ApplicationServices
-
-#import <Cocoa/Cocoa.h>
-#import <AVFoundation/AVFoundation.h>
-#import <ApplicationServices/ApplicationServices.h>
-
-// Check if the application has accessibility permissions
-int checkAccessibilityPermission() {
-    // AXIsProcessTrusted returns true if the app has accessibility permissions
-    return AXIsProcessTrusted() ? 1 : 0;
-}
-
-// Open System Preferences to Accessibility pane
-void openAccessibilitySettings() {
-    NSURL *url = [NSURL URLWithString:@"x-apple.systempreferences:com.apple.preference.security?Privacy_Accessibility"];
-    [[NSWorkspace sharedWorkspace] openURL:url];
-}
-
-// Check microphone permission status
-// Returns: 0 = not determined, 1 = denied, 2 = authorized, 3 = restricted
-int checkMicrophonePermission() {
-    AVAuthorizationStatus status = [AVCaptureDevice authorizationStatusForMediaType:AVMediaTypeAudio];
-    switch (status) {
-        case AVAuthorizationStatusNotDetermined:
-            return 0;
-        case AVAuthorizationStatusDenied:
-            return 1;
-        case AVAuthorizationStatusAuthorized:
-            return 2;
-        case AVAuthorizationStatusRestricted:
-            return 3;
-        default:
-            return 0;
-    }
-}
-
-// Request microphone permission - this will trigger the system dialog
-void requestMicrophonePermission() {
-    [AVCaptureDevice requestAccessForMediaType:AVMediaTypeAudio completionHandler:^(BOOL granted) {
-        // Callback is handled asynchronously, we don't need to do anything here
-        // The frontend will poll for status changes
-    }];
-}
-
-// Open System Preferences to Microphone pane
-void openMicrophoneSettings() {
-    NSURL *url = [NSURL URLWithString:@"x-apple.systempreferences:com.apple.preference.security?Privacy_Microphone"];
-    [[NSWorkspace sharedWorkspace] openURL:url];
-}
-*/
-import "C"
-
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 )
 
 // PermissionStatus represents the state of a permission
 type PermissionStatus string
 
 const (
-	StatusUnknown   PermissionStatus = "unknown"
-	StatusGranted   PermissionStatus = "granted"
-	StatusDenied    PermissionStatus = "denied"
-	StatusNotAsked  PermissionStatus = "not_asked"
+	StatusUnknown    PermissionStatus = "unknown"
+	StatusGranted    PermissionStatus = "granted"
+	StatusDenied     PermissionStatus = "denied"
+	StatusNotAsked   PermissionStatus = "not_asked"
 	StatusRestricted PermissionStatus = "restricted"
 )
 
-// PermissionsState holds the current state of all required permissions
-type PermissionsState struct {
-	Accessibility PermissionStatus `json:"accessibility"`
-	Microphone    PermissionStatus `json:"microphone"`
+// PermissionType identifies one of the TCC-guarded capabilities this
+// package checks/requests, so callers (e.g. an onboarding flow) can iterate
+// over all of them via AllPermissionTypes instead of hardcoding each
+// CheckX/RequestX/OpenXSettings call.
+type PermissionType string
+
+const (
+	PermissionAccessibility   PermissionType = "accessibility"
+	PermissionMicrophone      PermissionType = "microphone"
+	PermissionCamera          PermissionType = "camera"
+	PermissionScreenRecording PermissionType = "screen_recording"
+	PermissionInputMonitoring PermissionType = "input_monitoring"
+	PermissionAutomation      PermissionType = "automation"
+	PermissionContacts        PermissionType = "contacts"
+)
+
+// AllPermissionTypes lists every PermissionType this package supports, in
+// the order they're typically requested during onboarding.
+var AllPermissionTypes = []PermissionType{
+	PermissionAccessibility,
+	PermissionMicrophone,
+	PermissionCamera,
+	PermissionScreenRecording,
+	PermissionInputMonitoring,
+	PermissionAutomation,
+	PermissionContacts,
 }
 
-// OnboardingConfig stores the onboarding completion state
-type OnboardingConfig struct {
-	OnboardingComplete bool `json:"onboarding_complete"`
+// PermissionInfo is the richer per-permission state returned by
+// GetPermissionsState: the live status plus enough history for the UI to
+// pick the right button. macOS's status APIs don't expose a true "not
+// determined" state for every permission (Accessibility and Screen
+// Recording both report "denied" whether or not the user was ever
+// prompted), so EverRequested - backed by our own PermissionsConfig, not
+// the OS - is what actually distinguishes "never asked" from "denied".
+type PermissionInfo struct {
+	Status        PermissionStatus `json:"status"`
+	EverRequested bool             `json:"everRequested"`
+	// Remediation is "request" when clicking a Request button can still
+	// trigger the system prompt, or "settings" when the user has to flip
+	// it in System Settings themselves (already asked and denied, or a
+	// permission type with no programmatic prompt at all).
+	Remediation string `json:"remediation"`
+}
+
+// PermissionRecord is what PermissionsConfig remembers about one
+// permission across launches.
+type PermissionRecord struct {
+	EverRequested bool             `json:"everRequested"`
+	LastStatus    PermissionStatus `json:"lastStatus"`
+	LastCheckedAt time.Time        `json:"lastCheckedAt"`
+}
+
+// PermissionsConfig is the on-disk record of onboarding completion and
+// per-permission request history, persisted at PermissionsService.configPath.
+type PermissionsConfig struct {
+	OnboardingComplete bool                                `json:"onboarding_complete"`
+	Permissions        map[PermissionType]PermissionRecord `json:"permissions"`
 }
 
 // PermissionsService manages permission checking and onboarding state
@@ -107,118 +110,225 @@ func NewPermissionsService() (*PermissionsService, error) {
 	}, nil
 }
 
-// CheckAccessibility checks if accessibility permission is granted
-func (s *PermissionsService) CheckAccessibility() PermissionStatus {
-	result := C.checkAccessibilityPermission()
-	if result == 1 {
-		return StatusGranted
+// GetPermissionsState returns the current state of every permission this
+// package tracks, keyed by PermissionType, along with remediation guidance
+// for the UI. Checking also refreshes PermissionsConfig's LastStatus/
+// LastCheckedAt for each permission.
+func (s *PermissionsService) GetPermissionsState() map[PermissionType]PermissionInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	config := s.loadConfig()
+
+	state := make(map[PermissionType]PermissionInfo, len(AllPermissionTypes))
+	for _, t := range AllPermissionTypes {
+		status := s.CheckPermission(t)
+		record := config.Permissions[t]
+		record.LastStatus = status
+		record.LastCheckedAt = time.Now()
+		config.Permissions[t] = record
+
+		state[t] = PermissionInfo{
+			Status:        status,
+			EverRequested: record.EverRequested,
+			Remediation:   remediationFor(t, status, record.EverRequested),
+		}
 	}
-	return StatusDenied
-}
 
-// CheckMicrophone checks the microphone permission status
-func (s *PermissionsService) CheckMicrophone() PermissionStatus {
-	result := C.checkMicrophonePermission()
-	switch result {
-	case 0:
-		return StatusNotAsked
-	case 1:
-		return StatusDenied
-	case 2:
-		return StatusGranted
-	case 3:
-		return StatusRestricted
-	default:
-		return StatusUnknown
+	if err := s.saveConfig(config); err != nil {
+		// Non-fatal: the live statuses above are still accurate, we just
+		// lose the refreshed LastStatus/LastCheckedAt for next launch.
+		fmt.Fprintf(os.Stderr, "permissions: failed to persist state: %v\n", err)
 	}
+
+	return state
 }
 
-// GetPermissionsState returns the current state of all permissions
-func (s *PermissionsService) GetPermissionsState() PermissionsState {
-	return PermissionsState{
-		Accessibility: s.CheckAccessibility(),
-		Microphone:    s.CheckMicrophone(),
+// remediationFor decides whether the UI should offer a "Request" button
+// (the system prompt can still fire) or send the user to Settings, for a
+// permission currently in the given status.
+func remediationFor(t PermissionType, status PermissionStatus, everRequested bool) string {
+	if status == StatusGranted {
+		return ""
 	}
+	if t == PermissionAccessibility {
+		// No programmatic prompt exists for Accessibility.
+		return "settings"
+	}
+	if everRequested {
+		return "settings"
+	}
+	return "request"
 }
 
-// OpenAccessibilitySettings opens System Preferences to the Accessibility pane
-func (s *PermissionsService) OpenAccessibilitySettings() {
-	C.openAccessibilitySettings()
+// CheckPermission checks the status of the given permission type, for
+// callers iterating over AllPermissionTypes instead of calling each CheckX
+// method directly.
+func (s *PermissionsService) CheckPermission(t PermissionType) PermissionStatus {
+	switch t {
+	case PermissionAccessibility:
+		return s.CheckAccessibility()
+	case PermissionMicrophone:
+		return s.CheckMicrophone()
+	case PermissionCamera:
+		return s.CheckCamera()
+	case PermissionScreenRecording:
+		return s.CheckScreenRecording()
+	case PermissionInputMonitoring:
+		return s.CheckInputMonitoring()
+	case PermissionAutomation:
+		return s.CheckAutomation()
+	case PermissionContacts:
+		return s.CheckContacts()
+	default:
+		return StatusUnknown
+	}
 }
 
-// RequestMicrophonePermission triggers the system microphone permission dialog
-func (s *PermissionsService) RequestMicrophonePermission() {
-	C.requestMicrophonePermission()
+// RequestPermission triggers the system permission dialog for the given
+// type, where the OS supports prompting directly, and records that it was
+// asked so WasEverRequested and GetPermissionsState's remediation hint
+// reflect it afterwards.
+func (s *PermissionsService) RequestPermission(t PermissionType) {
+	s.MarkRequested(t)
+
+	switch t {
+	case PermissionMicrophone:
+		// RequestMicrophonePermission is async and channel-based (the
+		// frontend awaits App.RequestMicrophonePermission directly for that);
+		// fire it and ignore the result here, consistent with the other
+		// fire-and-forget RequestX calls in this switch.
+		go func() { <-s.RequestMicrophonePermission(context.Background()) }()
+	case PermissionCamera:
+		s.RequestCamera()
+	case PermissionScreenRecording:
+		s.RequestScreenRecording()
+	case PermissionInputMonitoring:
+		s.RequestInputMonitoring()
+	case PermissionAutomation:
+		s.RequestAutomation()
+	case PermissionContacts:
+		s.RequestContacts()
+	case PermissionAccessibility:
+		// Accessibility has no programmatic prompt - OpenPermissionSettings
+		// is the only way to grant it.
+	}
 }
 
-// OpenMicrophoneSettings opens System Preferences to the Microphone pane
-func (s *PermissionsService) OpenMicrophoneSettings() {
-	C.openMicrophoneSettings()
+// OpenPermissionSettings opens System Settings to the pane for the given
+// permission type.
+func (s *PermissionsService) OpenPermissionSettings(t PermissionType) {
+	switch t {
+	case PermissionAccessibility:
+		s.OpenAccessibilitySettings()
+	case PermissionMicrophone:
+		s.OpenMicrophoneSettings()
+	case PermissionCamera:
+		s.OpenCameraSettings()
+	case PermissionScreenRecording:
+		s.OpenScreenRecordingSettings()
+	case PermissionInputMonitoring:
+		s.OpenInputMonitoringSettings()
+	case PermissionAutomation:
+		s.OpenAutomationSettings()
+	case PermissionContacts:
+		s.OpenContactsSettings()
+	}
 }
 
-// IsOnboardingComplete checks if the user has completed onboarding
-func (s *PermissionsService) IsOnboardingComplete() bool {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+// loadConfig reads PermissionsConfig from configPath, returning a
+// zero-value config (with an initialized Permissions map) if the file
+// doesn't exist yet or can't be parsed. Callers must hold s.mu.
+func (s *PermissionsService) loadConfig() PermissionsConfig {
+	config := PermissionsConfig{Permissions: make(map[PermissionType]PermissionRecord)}
 
 	data, err := os.ReadFile(s.configPath)
 	if err != nil {
-		// File doesn't exist or can't be read - onboarding not complete
-		return false
+		return config
 	}
-
-	var config OnboardingConfig
 	if err := json.Unmarshal(data, &config); err != nil {
-		return false
+		return PermissionsConfig{Permissions: make(map[PermissionType]PermissionRecord)}
 	}
-
-	return config.OnboardingComplete
-}
-
-// CompleteOnboarding marks the onboarding as complete
-func (s *PermissionsService) CompleteOnboarding() error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	config := OnboardingConfig{
-		OnboardingComplete: true,
+	if config.Permissions == nil {
+		config.Permissions = make(map[PermissionType]PermissionRecord)
 	}
+	return config
+}
 
+// saveConfig writes config to configPath as indented JSON. Callers must
+// hold s.mu.
+func (s *PermissionsService) saveConfig(config PermissionsConfig) error {
 	data, err := json.MarshalIndent(config, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
-
 	if err := os.WriteFile(s.configPath, data, 0644); err != nil {
 		return fmt.Errorf("failed to write config: %w", err)
 	}
-
 	return nil
 }
 
-// ResetOnboarding resets the onboarding state (for testing)
-func (s *PermissionsService) ResetOnboarding() error {
+// WasEverRequested reports whether the app has ever triggered the system
+// prompt for the given permission, as recorded by MarkRequested. This is
+// the reliable way to tell "never asked" from "denied" for permissions
+// whose status APIs don't distinguish the two (see PermissionInfo).
+func (s *PermissionsService) WasEverRequested(t PermissionType) bool {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	config := OnboardingConfig{
-		OnboardingComplete: false,
-	}
+	return s.loadConfig().Permissions[t].EverRequested
+}
 
-	data, err := json.MarshalIndent(config, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal config: %w", err)
-	}
+// MarkRequested records that the system prompt for the given permission
+// has been triggered at least once. RequestPermission calls this
+// automatically; call it directly if a CheckX/RequestX method is invoked
+// outside that dispatcher.
+func (s *PermissionsService) MarkRequested(t PermissionType) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	if err := os.WriteFile(s.configPath, data, 0644); err != nil {
-		return fmt.Errorf("failed to write config: %w", err)
+	config := s.loadConfig()
+	record := config.Permissions[t]
+	record.EverRequested = true
+	config.Permissions[t] = record
+
+	if err := s.saveConfig(config); err != nil {
+		fmt.Fprintf(os.Stderr, "permissions: failed to persist request state: %v\n", err)
 	}
+}
 
-	return nil
+// IsOnboardingComplete checks if the user has completed onboarding
+func (s *PermissionsService) IsOnboardingComplete() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.loadConfig().OnboardingComplete
+}
+
+// CompleteOnboarding marks the onboarding as complete
+func (s *PermissionsService) CompleteOnboarding() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	config := s.loadConfig()
+	config.OnboardingComplete = true
+	return s.saveConfig(config)
+}
+
+// ResetOnboarding resets the onboarding state (for testing)
+func (s *PermissionsService) ResetOnboarding() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	config := s.loadConfig()
+	config.OnboardingComplete = false
+	return s.saveConfig(config)
 }
 
-// AllPermissionsGranted returns true if all required permissions are granted
+// AllPermissionsGranted returns true if the permissions required for core
+// functionality (Accessibility and Microphone) are granted. The other
+// permission types unlock optional features and aren't required here.
 func (s *PermissionsService) AllPermissionsGranted() bool {
 	state := s.GetPermissionsState()
-	return state.Accessibility == StatusGranted && state.Microphone == StatusGranted
+	return state[PermissionAccessibility].Status == StatusGranted && state[PermissionMicrophone].Status == StatusGranted
 }
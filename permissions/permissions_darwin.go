@@ -0,0 +1,363 @@
+package permissions
+
+/*
+#cgo CFLAGS: -x objective-c
+#cgo LDFLAGS: -framework Cocoa -framework AVFoundation -framework ApplicationServices -framework IOKit -framework Contacts -framework CoreServices
+
+#import <Cocoa/Cocoa.h>
+#import <AVFoundation/AVFoundation.h>
+#import <ApplicationServices/ApplicationServices.h>
+#import <IOKit/hid/IOHIDLib.h>
+#import <Contacts/Contacts.h>
+
+// Check if the application has accessibility permissions
+int checkAccessibilityPermission() {
+    // AXIsProcessTrusted returns true if the app has accessibility permissions
+    return AXIsProcessTrusted() ? 1 : 0;
+}
+
+// Open System Preferences to Accessibility pane
+void openAccessibilitySettings() {
+    NSURL *url = [NSURL URLWithString:@"x-apple.systempreferences:com.apple.preference.security?Privacy_Accessibility"];
+    [[NSWorkspace sharedWorkspace] openURL:url];
+}
+
+// Check microphone permission status
+// Returns: 0 = not determined, 1 = denied, 2 = authorized, 3 = restricted
+int checkMicrophonePermission() {
+    AVAuthorizationStatus status = [AVCaptureDevice authorizationStatusForMediaType:AVMediaTypeAudio];
+    switch (status) {
+        case AVAuthorizationStatusNotDetermined:
+            return 0;
+        case AVAuthorizationStatusDenied:
+            return 1;
+        case AVAuthorizationStatusAuthorized:
+            return 2;
+        case AVAuthorizationStatusRestricted:
+            return 3;
+        default:
+            return 0;
+    }
+}
+
+// Open System Preferences to Microphone pane
+void openMicrophoneSettings() {
+    NSURL *url = [NSURL URLWithString:@"x-apple.systempreferences:com.apple.preference.security?Privacy_Microphone"];
+    [[NSWorkspace sharedWorkspace] openURL:url];
+}
+
+// Check camera permission status (same status codes as checkMicrophonePermission)
+int checkCameraPermission() {
+    AVAuthorizationStatus status = [AVCaptureDevice authorizationStatusForMediaType:AVMediaTypeVideo];
+    switch (status) {
+        case AVAuthorizationStatusNotDetermined:
+            return 0;
+        case AVAuthorizationStatusDenied:
+            return 1;
+        case AVAuthorizationStatusAuthorized:
+            return 2;
+        case AVAuthorizationStatusRestricted:
+            return 3;
+        default:
+            return 0;
+    }
+}
+
+// Request camera permission - this will trigger the system dialog
+void requestCameraPermission() {
+    [AVCaptureDevice requestAccessForMediaType:AVMediaTypeVideo completionHandler:^(BOOL granted) {
+        // Callback is handled asynchronously, we don't need to do anything here
+        // The frontend will poll for status changes
+    }];
+}
+
+// Open System Preferences to Camera pane
+void openCameraSettings() {
+    NSURL *url = [NSURL URLWithString:@"x-apple.systempreferences:com.apple.preference.security?Privacy_Camera"];
+    [[NSWorkspace sharedWorkspace] openURL:url];
+}
+
+// Check screen recording permission status.
+// Returns: 2 = granted, 1 = denied; we only distinguish granted/denied
+// since CGPreflightScreenCaptureAccess doesn't expose "not determined".
+int checkScreenRecordingPermission() {
+    if (@available(macOS 10.15, *)) {
+        return CGPreflightScreenCaptureAccess() ? 2 : 1;
+    }
+    return 2; // Not TCC-guarded before 10.15
+}
+
+// Request screen recording permission - this will trigger the system dialog
+// (or, if already denied, does nothing until the user flips it in Settings).
+void requestScreenRecordingPermission() {
+    if (@available(macOS 10.15, *)) {
+        CGRequestScreenCaptureAccess();
+    }
+}
+
+// Open System Preferences to Screen Recording pane
+void openScreenRecordingSettings() {
+    NSURL *url = [NSURL URLWithString:@"x-apple.systempreferences:com.apple.preference.security?Privacy_ScreenCapture"];
+    [[NSWorkspace sharedWorkspace] openURL:url];
+}
+
+// Check Input Monitoring permission status via IOHIDCheckAccess.
+// Returns: 0 = not determined (unknown), 1 = denied, 2 = granted
+int checkInputMonitoringPermission() {
+    IOHIDAccessType access = IOHIDCheckAccess(kIOHIDRequestTypeListenEvent);
+    switch (access) {
+        case kIOHIDAccessTypeGranted:
+            return 2;
+        case kIOHIDAccessTypeDenied:
+            return 1;
+        case kIOHIDAccessTypeUnknown:
+        default:
+            return 0;
+    }
+}
+
+// Request Input Monitoring permission - prompts the system dialog the first
+// time it's called for this process.
+void requestInputMonitoringPermission() {
+    IOHIDRequestAccess(kIOHIDRequestTypeListenEvent);
+}
+
+// Open System Preferences to the Input Monitoring pane
+void openInputMonitoringSettings() {
+    NSURL *url = [NSURL URLWithString:@"x-apple.systempreferences:com.apple.preference.security?Privacy_ListenEvent"];
+    [[NSWorkspace sharedWorkspace] openURL:url];
+}
+
+// Check Automation permission to control System Events, the target most
+// hotkey/automation features script against. askUserIfNeeded is false so
+// checking never itself prompts - RequestAutomation does that explicitly.
+// Returns: 0 = not determined, 1 = denied, 2 = granted
+int checkAutomationPermission() {
+    AEAddressDesc target;
+    OSType signature = 'sevs'; // com.apple.systemevents creator code
+    if (AECreateDesc(typeApplSignature, &signature, sizeof(signature), &target) != noErr) {
+        return 0;
+    }
+
+    OSStatus status = AEDeterminePermissionToAutomateTarget(&target, typeWildCard, typeWildCard, false);
+    AEDisposeDesc(&target);
+
+    switch (status) {
+        case noErr:
+            return 2;
+        case errAEEventWouldRequireUserConsent:
+            return 0;
+        case errAEEventNotPermitted:
+            return 1;
+        default:
+            return 0;
+    }
+}
+
+// Request Automation permission to control System Events - this triggers
+// the system consent dialog if it hasn't been shown yet.
+void requestAutomationPermission() {
+    AEAddressDesc target;
+    OSType signature = 'sevs';
+    if (AECreateDesc(typeApplSignature, &signature, sizeof(signature), &target) != noErr) {
+        return;
+    }
+    AEDeterminePermissionToAutomateTarget(&target, typeWildCard, typeWildCard, true);
+    AEDisposeDesc(&target);
+}
+
+// Open System Preferences to the Automation pane
+void openAutomationSettings() {
+    NSURL *url = [NSURL URLWithString:@"x-apple.systempreferences:com.apple.preference.security?Privacy_Automation"];
+    [[NSWorkspace sharedWorkspace] openURL:url];
+}
+
+// Check Contacts permission status.
+// Returns: 0 = not determined, 1 = denied, 2 = granted, 3 = restricted
+int checkContactsPermission() {
+    CNAuthorizationStatus status = [CNContactStore authorizationStatusForEntityType:CNEntityTypeContacts];
+    switch (status) {
+        case CNAuthorizationStatusNotDetermined:
+            return 0;
+        case CNAuthorizationStatusDenied:
+            return 1;
+        case CNAuthorizationStatusAuthorized:
+            return 2;
+        case CNAuthorizationStatusRestricted:
+            return 3;
+        default:
+            return 0;
+    }
+}
+
+// Request Contacts permission - this will trigger the system dialog
+void requestContactsPermission() {
+    CNContactStore *store = [[CNContactStore alloc] init];
+    [store requestAccessForEntityType:CNEntityTypeContacts completionHandler:^(BOOL granted, NSError *error) {
+        // Callback is handled asynchronously, we don't need to do anything here
+        // The frontend will poll for status changes
+    }];
+}
+
+// Open System Preferences to the Contacts pane
+void openContactsSettings() {
+    NSURL *url = [NSURL URLWithString:@"x-apple.systempreferences:com.apple.preference.security?Privacy_Contacts"];
+    [[NSWorkspace sharedWorkspace] openURL:url];
+}
+*/
+import "C"
+
+// CheckAccessibility checks if accessibility permission is granted
+func (s *PermissionsService) CheckAccessibility() PermissionStatus {
+	result := C.checkAccessibilityPermission()
+	if result == 1 {
+		return StatusGranted
+	}
+	return StatusDenied
+}
+
+// OpenAccessibilitySettings opens System Preferences to the Accessibility pane
+func (s *PermissionsService) OpenAccessibilitySettings() {
+	C.openAccessibilitySettings()
+}
+
+// CheckMicrophone checks the microphone permission status
+func (s *PermissionsService) CheckMicrophone() PermissionStatus {
+	return avAuthorizationStatus(C.checkMicrophonePermission())
+}
+
+// OpenMicrophoneSettings opens System Preferences to the Microphone pane
+func (s *PermissionsService) OpenMicrophoneSettings() {
+	C.openMicrophoneSettings()
+}
+
+// CheckCamera checks the camera permission status
+func (s *PermissionsService) CheckCamera() PermissionStatus {
+	return avAuthorizationStatus(C.checkCameraPermission())
+}
+
+// RequestCamera triggers the system camera permission dialog
+func (s *PermissionsService) RequestCamera() {
+	C.requestCameraPermission()
+}
+
+// OpenCameraSettings opens System Preferences to the Camera pane
+func (s *PermissionsService) OpenCameraSettings() {
+	C.openCameraSettings()
+}
+
+// CheckScreenRecording checks the screen recording permission status via
+// CGPreflightScreenCaptureAccess (10.15+).
+func (s *PermissionsService) CheckScreenRecording() PermissionStatus {
+	if C.checkScreenRecordingPermission() == 2 {
+		return StatusGranted
+	}
+	return StatusDenied
+}
+
+// RequestScreenRecording triggers the system screen recording permission
+// dialog via CGRequestScreenCaptureAccess.
+func (s *PermissionsService) RequestScreenRecording() {
+	C.requestScreenRecordingPermission()
+}
+
+// OpenScreenRecordingSettings opens System Preferences to the Screen
+// Recording pane
+func (s *PermissionsService) OpenScreenRecordingSettings() {
+	C.openScreenRecordingSettings()
+}
+
+// CheckInputMonitoring checks the Input Monitoring permission status via
+// IOHIDCheckAccess.
+func (s *PermissionsService) CheckInputMonitoring() PermissionStatus {
+	switch C.checkInputMonitoringPermission() {
+	case 2:
+		return StatusGranted
+	case 1:
+		return StatusDenied
+	default:
+		return StatusNotAsked
+	}
+}
+
+// RequestInputMonitoring triggers the system Input Monitoring permission
+// dialog via IOHIDRequestAccess.
+func (s *PermissionsService) RequestInputMonitoring() {
+	C.requestInputMonitoringPermission()
+}
+
+// OpenInputMonitoringSettings opens System Preferences to the Input
+// Monitoring pane
+func (s *PermissionsService) OpenInputMonitoringSettings() {
+	C.openInputMonitoringSettings()
+}
+
+// CheckAutomation checks whether this app is permitted to send Apple Events
+// to System Events, the target most automation/hotkey features script
+// against.
+func (s *PermissionsService) CheckAutomation() PermissionStatus {
+	switch C.checkAutomationPermission() {
+	case 2:
+		return StatusGranted
+	case 1:
+		return StatusDenied
+	default:
+		return StatusNotAsked
+	}
+}
+
+// RequestAutomation triggers the system Automation consent dialog for
+// controlling System Events.
+func (s *PermissionsService) RequestAutomation() {
+	C.requestAutomationPermission()
+}
+
+// OpenAutomationSettings opens System Preferences to the Automation pane
+func (s *PermissionsService) OpenAutomationSettings() {
+	C.openAutomationSettings()
+}
+
+// CheckContacts checks the Contacts permission status
+func (s *PermissionsService) CheckContacts() PermissionStatus {
+	switch C.checkContactsPermission() {
+	case 0:
+		return StatusNotAsked
+	case 1:
+		return StatusDenied
+	case 2:
+		return StatusGranted
+	case 3:
+		return StatusRestricted
+	default:
+		return StatusUnknown
+	}
+}
+
+// RequestContacts triggers the system Contacts permission dialog
+func (s *PermissionsService) RequestContacts() {
+	C.requestContactsPermission()
+}
+
+// OpenContactsSettings opens System Preferences to the Contacts pane
+func (s *PermissionsService) OpenContactsSettings() {
+	C.openContactsSettings()
+}
+
+// avAuthorizationStatus maps the AVAuthorizationStatus-shaped return codes
+// shared by checkMicrophonePermission and checkCameraPermission (0 = not
+// determined, 1 = denied, 2 = authorized, 3 = restricted) to a
+// PermissionStatus.
+func avAuthorizationStatus(code C.int) PermissionStatus {
+	switch code {
+	case 0:
+		return StatusNotAsked
+	case 1:
+		return StatusDenied
+	case 2:
+		return StatusGranted
+	case 3:
+		return StatusRestricted
+	default:
+		return StatusUnknown
+	}
+}
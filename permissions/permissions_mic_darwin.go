@@ -0,0 +1,107 @@
+package permissions
+
+/*
+#cgo CFLAGS: -x objective-c
+#cgo LDFLAGS: -framework Cocoa -framework AVFoundation
+
+#import <Cocoa/Cocoa.h>
+#import <AVFoundation/AVFoundation.h>
+#include "_cgo_export.h"
+
+// doRequestMicrophonePermission fires the actual system prompt and bridges
+// the result back into Go via the permissionCallback export, tagged with
+// requestID so the Go side can match it to the channel it handed out.
+static void doRequestMicrophonePermission(int requestID) {
+    [AVCaptureDevice requestAccessForMediaType:AVMediaTypeAudio completionHandler:^(BOOL granted) {
+        permissionCallback(requestID, granted ? 1 : 0);
+    }];
+}
+
+// requestMicrophonePermissionAsync triggers the system microphone prompt,
+// tagging the eventual result with requestID. Per Apple's guidance (and to
+// avoid the well-known misbehavior of prompting while the app isn't in the
+// foreground), the prompt is deferred until the app is active: if
+// [NSApp isActive] is already true it fires immediately, otherwise a
+// one-shot observer defers it until NSApplicationDidBecomeActiveNotification.
+void requestMicrophonePermissionAsync(int requestID) {
+    if ([NSApp isActive]) {
+        doRequestMicrophonePermission(requestID);
+        return;
+    }
+
+    __block id observer = [[NSNotificationCenter defaultCenter]
+        addObserverForName:NSApplicationDidBecomeActiveNotification
+                    object:nil
+                     queue:[NSOperationQueue mainQueue]
+                usingBlock:^(NSNotification *note) {
+                    doRequestMicrophonePermission(requestID);
+                    [[NSNotificationCenter defaultCenter] removeObserver:observer];
+                }];
+}
+*/
+import "C"
+
+import (
+	"context"
+	"sync"
+)
+
+// micRequests tracks the channel each in-flight RequestMicrophonePermission
+// call is waiting on, keyed by the requestID passed to
+// requestMicrophonePermissionAsync so permissionCallback can find it again.
+var micRequests = struct {
+	mu      sync.Mutex
+	nextID  int
+	pending map[int]chan PermissionStatus
+}{pending: make(map[int]chan PermissionStatus)}
+
+// RequestMicrophonePermission triggers the system microphone permission
+// dialog (deferring it until the app is active - see
+// requestMicrophonePermissionAsync) and returns a channel that receives
+// exactly one value once the user responds, or if ctx is cancelled first.
+func (s *PermissionsService) RequestMicrophonePermission(ctx context.Context) <-chan PermissionStatus {
+	result := make(chan PermissionStatus, 1)
+
+	micRequests.mu.Lock()
+	micRequests.nextID++
+	requestID := micRequests.nextID
+	pending := make(chan PermissionStatus, 1)
+	micRequests.pending[requestID] = pending
+	micRequests.mu.Unlock()
+
+	go func() {
+		defer func() {
+			micRequests.mu.Lock()
+			delete(micRequests.pending, requestID)
+			micRequests.mu.Unlock()
+		}()
+
+		select {
+		case status := <-pending:
+			result <- status
+		case <-ctx.Done():
+			result <- StatusUnknown
+		}
+		close(result)
+	}()
+
+	C.requestMicrophonePermissionAsync(C.int(requestID))
+	return result
+}
+
+//export permissionCallback
+func permissionCallback(requestID C.int, granted C.int) {
+	status := StatusDenied
+	if granted != 0 {
+		status = StatusGranted
+	}
+
+	micRequests.mu.Lock()
+	pending, ok := micRequests.pending[int(requestID)]
+	micRequests.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	pending <- status
+}
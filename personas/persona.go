@@ -0,0 +1,19 @@
+// Package personas defines saved system-prompt/voice presets that can be
+// selected per conversation (see App.StartConversationWithPersona), as an
+// alternative to the YAML character packs loaded by config.CharacterConfigLoader.
+// Personas are persisted through settings.SettingsService rather than their
+// own file, so CRUD lives on SettingsService (see AddPersona, UpdatePersona,
+// DeletePersona, GetPersonas in the settings package) and App just forwards
+// to it.
+package personas
+
+// Persona is a named preset overriding the system prompt, TTS voice, LLM
+// temperature, and avatar model for conversations that select it.
+type Persona struct {
+	ID           string  `json:"id"`
+	Name         string  `json:"name"`
+	SystemPrompt string  `json:"systemPrompt"`
+	VoiceID      string  `json:"voiceId"`
+	Temperature  float64 `json:"temperature"`
+	AvatarModel  string  `json:"avatarModel"`
+}
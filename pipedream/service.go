@@ -2,36 +2,23 @@ package pipedream
 
 import (
 	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
-	"os"
 	"sync"
 	"time"
-)
-
-// #region agent log
-const debugLogPath = "/Users/amine/Projects/solo/super-characters/.cursor/debug.log"
 
-func debugLog(location, message string, data map[string]interface{}) {
-	entry := map[string]interface{}{
-		"location":  location,
-		"message":   message,
-		"data":      data,
-		"timestamp": time.Now().UnixMilli(),
-		"sessionId": "debug-session",
-	}
-	jsonBytes, _ := json.Marshal(entry)
-	f, err := os.OpenFile(debugLogPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	if err == nil {
-		f.Write(jsonBytes)
-		f.Write([]byte("\n"))
-		f.Close()
-	}
-}
+	"super-characters/logging"
+)
 
+// minInt caps a slice length so logging a secret's prefix (client ID, token)
+// never panics on a shorter-than-expected value.
 func minInt(a, b int) int {
 	if a < b {
 		return a
@@ -39,8 +26,6 @@ func minInt(a, b int) int {
 	return b
 }
 
-// #endregion
-
 const (
 	// API endpoints
 	baseURL      = "https://api.pipedream.com/v1"
@@ -57,6 +42,73 @@ type Service struct {
 	accessToken string
 	tokenExpiry time.Time
 	mu          sync.RWMutex
+	log         logging.Logger
+}
+
+// deadlineTimer arms a one-shot deadline and hands back the channel that
+// closes when it fires, following netstack's deadlineTimer pattern: if
+// (*time.Timer).Stop returns false the timer already fired (or is mid-fire),
+// so re-arming reuses a channel that might close out from under the new
+// deadline - instead a fresh channel is swapped in under the lock.
+type deadlineTimer struct {
+	mu      sync.Mutex
+	timer   *time.Timer
+	expired chan struct{}
+}
+
+// set arms (or re-arms) the deadline, returning the channel that closes when
+// it fires.
+func (d *deadlineTimer) set(deadline time.Time) <-chan struct{} {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.timer != nil && !d.timer.Stop() {
+		d.expired = nil
+	}
+	if d.expired == nil {
+		d.expired = make(chan struct{})
+	}
+
+	expired := d.expired
+	d.timer = time.AfterFunc(time.Until(deadline), func() { close(expired) })
+	return expired
+}
+
+// stop disarms the deadline so its timer goroutine can't fire after the call
+// it was guarding has already finished.
+func (d *deadlineTimer) stop() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+}
+
+// WithTimeout overlays a per-call deadline on ctx, letting a caller shorten
+// a request's effective timeout without mutating Service's shared
+// *http.Client. Returns ctx unchanged if d <= 0; the returned cancel func
+// must be called once the request completes to release the timer.
+func WithTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if d <= 0 {
+		return ctx, func() {}
+	}
+
+	dt := &deadlineTimer{}
+	expired := dt.set(time.Now().Add(d))
+	ctx, cancel := context.WithCancel(ctx)
+	stop := make(chan struct{})
+	go func() {
+		select {
+		case <-expired:
+			cancel()
+		case <-stop:
+		}
+	}()
+	return ctx, func() {
+		close(stop)
+		dt.stop()
+		cancel()
+	}
 }
 
 // NewService creates a new Pipedream service
@@ -66,9 +118,21 @@ func NewService() *Service {
 		client: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		log: logging.Discard,
 	}
 }
 
+// SetLogger configures where Service's structured trace events go - it
+// defaults to logging.Discard, so this is opt-in. Safe to call at any time.
+func (s *Service) SetLogger(l logging.Logger) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if l == nil {
+		l = logging.Discard
+	}
+	s.log = l
+}
+
 // Configure sets the Pipedream API credentials
 func (s *Service) Configure(config Config) {
 	s.mu.Lock()
@@ -79,6 +143,54 @@ func (s *Service) Configure(config Config) {
 	s.tokenExpiry = time.Time{}
 }
 
+// ErrFingerprintMismatch is returned by DoLockedAction when the config has
+// changed since the caller computed its fingerprint - e.g. another goroutine
+// or an admin UI hot-reload configured the service in between.
+var ErrFingerprintMismatch = errors.New("pipedream: config fingerprint mismatch")
+
+// Fingerprint returns a stable hash of the current Config, suitable for
+// detecting whether it has changed since it was last read. Compute one
+// before a "read -> decide -> write" sequence and pass it to DoLockedAction.
+func (s *Service) Fingerprint() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return fingerprint(s.config)
+}
+
+func fingerprint(config Config) string {
+	// Config marshaling never fails - it's a flat struct of strings.
+	body, _ := json.Marshal(config)
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// DoLockedAction safely performs a compound "read -> decide -> write" change
+// to the config: it acquires the write lock, verifies fingerprint still
+// matches the stored config, then invokes cb with a mutable copy. If cb
+// returns nil, the copy replaces the stored config and the cached access
+// token is invalidated, exactly as Configure does. If fingerprint is stale,
+// it returns ErrFingerprintMismatch without calling cb, so a settings UI can
+// prompt the user to reload instead of silently overwriting fresh
+// credentials.
+func (s *Service) DoLockedAction(fp string, cb func(*Config) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if fingerprint(s.config) != fp {
+		return ErrFingerprintMismatch
+	}
+
+	updated := s.config
+	if err := cb(&updated); err != nil {
+		return err
+	}
+
+	s.config = updated
+	s.accessToken = ""
+	s.tokenExpiry = time.Time{}
+	return nil
+}
+
 // GetConfig returns the current configuration
 func (s *Service) GetConfig() Config {
 	s.mu.RLock()
@@ -93,30 +205,31 @@ func (s *Service) IsConfigured() bool {
 	return s.config.IsConfigured()
 }
 
-// getAccessToken returns a valid OAuth access token, refreshing if needed
+// getAccessToken returns a valid OAuth access token, refreshing if needed.
 func (s *Service) getAccessToken() (string, error) {
+	return s.getAccessTokenContext(context.Background())
+}
+
+// getAccessTokenContext is getAccessToken with cancellation: ctx is passed
+// into http.NewRequestWithContext, so cancelling it (or a deadline overlaid
+// via WithTimeout) aborts the in-flight token request.
+func (s *Service) getAccessTokenContext(ctx context.Context) (string, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	// #region agent log
-	debugLog("service.go:getAccessToken:entry", "Getting access token", map[string]interface{}{
-		"hypothesisId":    "A,B,C",
-		"hasCachedToken":  s.accessToken != "",
-		"clientIdPrefix":  s.config.ClientID[:minInt(10, len(s.config.ClientID))],
-		"projectId":       s.config.ProjectID,
-		"tokenURL":        tokenURL,
-	})
-	// #endregion
+	s.log.Debug("getting access token",
+		"hasCachedToken", s.accessToken != "",
+		"clientIdPrefix", s.config.ClientID[:minInt(10, len(s.config.ClientID))],
+		"projectId", s.config.ProjectID,
+		"tokenURL", tokenURL,
+	)
 
 	// Return cached token if still valid (with 1 minute buffer)
 	if s.accessToken != "" && time.Now().Add(time.Minute).Before(s.tokenExpiry) {
-		// #region agent log
-		debugLog("service.go:getAccessToken:cached", "Using cached token", map[string]interface{}{
-			"hypothesisId":      "C",
-			"tokenLength":       len(s.accessToken),
-			"tokenPrefix":       s.accessToken[:minInt(20, len(s.accessToken))],
-		})
-		// #endregion
+		s.log.Debug("using cached token",
+			"tokenLength", len(s.accessToken),
+			"tokenPrefix", s.accessToken[:minInt(20, len(s.accessToken))],
+		)
 		return s.accessToken, nil
 	}
 
@@ -126,16 +239,12 @@ func (s *Service) getAccessToken() (string, error) {
 	data.Set("client_id", s.config.ClientID)
 	data.Set("client_secret", s.config.ClientSecret)
 
-	// #region agent log
-	debugLog("service.go:getAccessToken:request", "Requesting new token", map[string]interface{}{
-		"hypothesisId": "A,B",
-		"tokenURL":     tokenURL,
-		"grantType":    "client_credentials",
-		"contentType":  "application/x-www-form-urlencoded",
-	})
-	// #endregion
+	s.log.Debug("requesting new token",
+		"tokenURL", tokenURL,
+		"grantType", "client_credentials",
+	)
 
-	req, err := http.NewRequest("POST", tokenURL, bytes.NewBufferString(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", tokenURL, bytes.NewBufferString(data.Encode()))
 	if err != nil {
 		return "", fmt.Errorf("failed to create token request: %w", err)
 	}
@@ -149,13 +258,10 @@ func (s *Service) getAccessToken() (string, error) {
 
 	body, _ := io.ReadAll(resp.Body)
 
-	// #region agent log
-	debugLog("service.go:getAccessToken:response", "Token response received", map[string]interface{}{
-		"hypothesisId": "A,B,C",
-		"statusCode":   resp.StatusCode,
-		"bodyPreview":  string(body[:minInt(200, len(body))]),
-	})
-	// #endregion
+	s.log.Debug("token response received",
+		"statusCode", resp.StatusCode,
+		"bodyPreview", string(body[:minInt(200, len(body))]),
+	)
 
 	if resp.StatusCode != http.StatusOK {
 		return "", fmt.Errorf("token request failed with status %d: %s", resp.StatusCode, string(body))
@@ -170,16 +276,13 @@ func (s *Service) getAccessToken() (string, error) {
 		return "", fmt.Errorf("failed to decode token response: %w", err)
 	}
 
-	// #region agent log
-	debugLog("service.go:getAccessToken:parsed", "Token parsed successfully", map[string]interface{}{
-		"hypothesisId":  "C",
-		"hasToken":      tokenResp.AccessToken != "",
-		"tokenLength":   len(tokenResp.AccessToken),
-		"tokenPrefix":   tokenResp.AccessToken[:minInt(20, len(tokenResp.AccessToken))],
-		"expiresIn":     tokenResp.ExpiresIn,
-		"tokenType":     tokenResp.TokenType,
-	})
-	// #endregion
+	s.log.Debug("token parsed successfully",
+		"hasToken", tokenResp.AccessToken != "",
+		"tokenLength", len(tokenResp.AccessToken),
+		"tokenPrefix", tokenResp.AccessToken[:minInt(20, len(tokenResp.AccessToken))],
+		"expiresIn", tokenResp.ExpiresIn,
+		"tokenType", tokenResp.TokenType,
+	)
 
 	s.accessToken = tokenResp.AccessToken
 	s.tokenExpiry = time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second)
@@ -187,13 +290,19 @@ func (s *Service) getAccessToken() (string, error) {
 	return s.accessToken, nil
 }
 
-// CreateConnectToken creates a short-lived token for the frontend SDK
+// CreateConnectToken creates a short-lived token for the frontend SDK.
 func (s *Service) CreateConnectToken(externalUserID string) (*TokenResponse, error) {
+	return s.CreateConnectTokenContext(context.Background(), externalUserID)
+}
+
+// CreateConnectTokenContext is CreateConnectToken with cancellation - see
+// getAccessTokenContext.
+func (s *Service) CreateConnectTokenContext(ctx context.Context, externalUserID string) (*TokenResponse, error) {
 	if !s.IsConfigured() {
 		return nil, fmt.Errorf("pipedream not configured")
 	}
 
-	accessToken, err := s.getAccessToken()
+	accessToken, err := s.getAccessTokenContext(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get access token: %w", err)
 	}
@@ -209,18 +318,14 @@ func (s *Service) CreateConnectToken(externalUserID string) (*TokenResponse, err
 	// Connect API URL includes project ID in path: /v1/connect/{project_id}/tokens
 	reqURL := fmt.Sprintf("%s/%s/tokens", connectURL, s.config.ProjectID)
 
-	// #region agent log
-	debugLog("service.go:CreateConnectToken:request", "Creating connect token", map[string]interface{}{
-		"hypothesisId":    "G",
-		"reqURL":          reqURL,
-		"authType":        "Bearer",
-		"projectId":       s.config.ProjectID,
-		"environment":     s.config.Environment,
-		"externalUserID":  externalUserID,
-	})
-	// #endregion
-
-	req, err := http.NewRequest("POST", reqURL, bytes.NewBuffer(body))
+	s.log.Debug("creating connect token",
+		"reqURL", reqURL,
+		"projectId", s.config.ProjectID,
+		"environment", s.config.Environment,
+		"externalUserID", externalUserID,
+	)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", reqURL, bytes.NewBuffer(body))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -228,14 +333,10 @@ func (s *Service) CreateConnectToken(externalUserID string) (*TokenResponse, err
 	req.Header.Set("Authorization", "Bearer "+accessToken)
 	req.Header.Set("X-PD-Environment", s.config.Environment)
 
-	// #region agent log
-	debugLog("service.go:CreateConnectToken:headers", "Request headers set", map[string]interface{}{
-		"hypothesisId":   "G",
-		"authType":       "Bearer",
-		"hasEnvironment": s.config.Environment != "",
-		"tokenPrefix":    accessToken[:minInt(20, len(accessToken))],
-	})
-	// #endregion
+	s.log.Debug("connect token request headers set",
+		"hasEnvironment", s.config.Environment != "",
+		"tokenPrefix", accessToken[:minInt(20, len(accessToken))],
+	)
 
 	resp, err := s.client.Do(req)
 	if err != nil {
@@ -245,13 +346,10 @@ func (s *Service) CreateConnectToken(externalUserID string) (*TokenResponse, err
 
 	respBody, _ := io.ReadAll(resp.Body)
 
-	// #region agent log
-	debugLog("service.go:CreateConnectToken:response", "Create token response", map[string]interface{}{
-		"hypothesisId": "G",
-		"statusCode":   resp.StatusCode,
-		"bodyPreview":  string(respBody[:minInt(200, len(respBody))]),
-	})
-	// #endregion
+	s.log.Debug("create connect token response",
+		"statusCode", resp.StatusCode,
+		"bodyPreview", string(respBody[:minInt(200, len(respBody))]),
+	)
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
 		return nil, fmt.Errorf("create token failed with status %d: %s", resp.StatusCode, string(respBody))
@@ -265,12 +363,18 @@ func (s *Service) CreateConnectToken(externalUserID string) (*TokenResponse, err
 	return &tokenResp, nil
 }
 
-// GetMCPAccessToken returns an access token for MCP server authentication
+// GetMCPAccessToken returns an access token for MCP server authentication.
 func (s *Service) GetMCPAccessToken() (string, error) {
+	return s.GetMCPAccessTokenContext(context.Background())
+}
+
+// GetMCPAccessTokenContext is GetMCPAccessToken with cancellation - see
+// getAccessTokenContext.
+func (s *Service) GetMCPAccessTokenContext(ctx context.Context) (string, error) {
 	if !s.IsConfigured() {
 		return "", fmt.Errorf("pipedream not configured")
 	}
-	return s.getAccessToken()
+	return s.getAccessTokenContext(ctx)
 }
 
 // GetMCPConfig returns the MCP configuration for the frontend
@@ -284,13 +388,18 @@ func (s *Service) GetMCPConfig() map[string]string {
 	}
 }
 
-// ListApps lists available Pipedream apps with optional search
+// ListApps lists available Pipedream apps with optional search.
 func (s *Service) ListApps(query string, limit int) ([]App, error) {
+	return s.ListAppsContext(context.Background(), query, limit)
+}
+
+// ListAppsContext is ListApps with cancellation - see getAccessTokenContext.
+func (s *Service) ListAppsContext(ctx context.Context, query string, limit int) ([]App, error) {
 	if !s.IsConfigured() {
 		return nil, fmt.Errorf("pipedream not configured")
 	}
 
-	accessToken, err := s.getAccessToken()
+	accessToken, err := s.getAccessTokenContext(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get access token: %w", err)
 	}
@@ -310,7 +419,7 @@ func (s *Service) ListApps(query string, limit int) ([]App, error) {
 		reqURL += "?" + params.Encode()
 	}
 
-	req, err := http.NewRequest("GET", reqURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -338,13 +447,19 @@ func (s *Service) ListApps(query string, limit int) ([]App, error) {
 	return result.Data, nil
 }
 
-// ListConnectedAccounts lists accounts connected by a user
+// ListConnectedAccounts lists accounts connected by a user.
 func (s *Service) ListConnectedAccounts(externalUserID string) ([]ConnectedAccount, error) {
+	return s.ListConnectedAccountsContext(context.Background(), externalUserID)
+}
+
+// ListConnectedAccountsContext is ListConnectedAccounts with cancellation -
+// see getAccessTokenContext.
+func (s *Service) ListConnectedAccountsContext(ctx context.Context, externalUserID string) ([]ConnectedAccount, error) {
 	if !s.IsConfigured() {
 		return nil, fmt.Errorf("pipedream not configured")
 	}
 
-	accessToken, err := s.getAccessToken()
+	accessToken, err := s.getAccessTokenContext(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get access token: %w", err)
 	}
@@ -352,30 +467,22 @@ func (s *Service) ListConnectedAccounts(externalUserID string) ([]ConnectedAccou
 	// Connect API URL includes project ID in path: /v1/connect/{project_id}/accounts
 	reqURL := fmt.Sprintf("%s/%s/accounts?external_user_id=%s", connectURL, s.config.ProjectID, url.QueryEscape(externalUserID))
 
-	// #region agent log
-	debugLog("service.go:ListConnectedAccounts:request", "Listing connected accounts", map[string]interface{}{
-		"hypothesisId":    "G",
-		"reqURL":          reqURL,
-		"authType":        "Bearer",
-		"projectId":       s.config.ProjectID,
-		"environment":     s.config.Environment,
-	})
-	// #endregion
-
-	req, err := http.NewRequest("GET", reqURL, nil)
+	s.log.Debug("listing connected accounts",
+		"reqURL", reqURL,
+		"projectId", s.config.ProjectID,
+		"environment", s.config.Environment,
+	)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 	req.Header.Set("Authorization", "Bearer "+accessToken)
 	req.Header.Set("X-PD-Environment", s.config.Environment)
 
-	// #region agent log
-	debugLog("service.go:ListConnectedAccounts:headers", "Request headers set", map[string]interface{}{
-		"hypothesisId":   "G",
-		"authType":       "Bearer",
-		"tokenPrefix":    accessToken[:minInt(20, len(accessToken))],
-	})
-	// #endregion
+	s.log.Debug("list connected accounts request headers set",
+		"tokenPrefix", accessToken[:minInt(20, len(accessToken))],
+	)
 
 	resp, err := s.client.Do(req)
 	if err != nil {
@@ -385,13 +492,10 @@ func (s *Service) ListConnectedAccounts(externalUserID string) ([]ConnectedAccou
 
 	respBody, _ := io.ReadAll(resp.Body)
 
-	// #region agent log
-	debugLog("service.go:ListConnectedAccounts:response", "List accounts response", map[string]interface{}{
-		"hypothesisId": "G",
-		"statusCode":   resp.StatusCode,
-		"bodyPreview":  string(respBody[:minInt(200, len(respBody))]),
-	})
-	// #endregion
+	s.log.Debug("list connected accounts response",
+		"statusCode", resp.StatusCode,
+		"bodyPreview", string(respBody[:minInt(200, len(respBody))]),
+	)
 
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("list accounts failed with status %d: %s", resp.StatusCode, string(respBody))
@@ -407,20 +511,26 @@ func (s *Service) ListConnectedAccounts(externalUserID string) ([]ConnectedAccou
 	return result.Data, nil
 }
 
-// DeleteConnectedAccount removes a connected account
+// DeleteConnectedAccount removes a connected account.
 func (s *Service) DeleteConnectedAccount(accountID string) error {
+	return s.DeleteConnectedAccountContext(context.Background(), accountID)
+}
+
+// DeleteConnectedAccountContext is DeleteConnectedAccount with cancellation
+// - see getAccessTokenContext.
+func (s *Service) DeleteConnectedAccountContext(ctx context.Context, accountID string) error {
 	if !s.IsConfigured() {
 		return fmt.Errorf("pipedream not configured")
 	}
 
-	accessToken, err := s.getAccessToken()
+	accessToken, err := s.getAccessTokenContext(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get access token: %w", err)
 	}
 
 	// Connect API URL includes project ID in path: /v1/connect/{project_id}/accounts/{account_id}
 	reqURL := fmt.Sprintf("%s/%s/accounts/%s", connectURL, s.config.ProjectID, accountID)
-	req, err := http.NewRequest("DELETE", reqURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", reqURL, nil)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
@@ -441,9 +551,15 @@ func (s *Service) DeleteConnectedAccount(accountID string) error {
 	return nil
 }
 
-// GetConnectLinkURL returns a Connect Link URL for connecting an app
+// GetConnectLinkURL returns a Connect Link URL for connecting an app.
 func (s *Service) GetConnectLinkURL(externalUserID, appSlug string) (string, error) {
-	tokenResp, err := s.CreateConnectToken(externalUserID)
+	return s.GetConnectLinkURLContext(context.Background(), externalUserID, appSlug)
+}
+
+// GetConnectLinkURLContext is GetConnectLinkURL with cancellation - see
+// getAccessTokenContext.
+func (s *Service) GetConnectLinkURLContext(ctx context.Context, externalUserID, appSlug string) (string, error) {
+	tokenResp, err := s.CreateConnectTokenContext(ctx, externalUserID)
 	if err != nil {
 		return "", err
 	}
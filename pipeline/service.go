@@ -2,7 +2,9 @@ package pipeline
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
@@ -11,8 +13,19 @@ import (
 	"runtime"
 	"strings"
 	"sync"
+	"syscall"
+	"time"
 )
 
+// defaultCancelGracePeriod is how long a cancelled step's child process gets
+// to exit after SIGTERM before runPythonScriptCtx escalates to SIGKILL.
+const defaultCancelGracePeriod = 5 * time.Second
+
+// ErrPipelineBusy is returned by a Service method when another pipeline step
+// is already running - see Service.mu, which uses TryLock instead of
+// blocking so the UI layer can decide whether to queue or reject the call.
+var ErrPipelineBusy = errors.New("pipeline: another step is already running")
+
 // StepResult represents the JSON output from a pipeline Python script.
 type StepResult struct {
 	Status  string `json:"status"`
@@ -27,17 +40,52 @@ type ProgressCallback func(step, message string)
 
 // Service orchestrates the Python ML pipeline scripts for character creation.
 type Service struct {
-	pipelineDir string // Directory containing the Python scripts
-	pythonCmd   string // Python executable path
-	mu          sync.Mutex
+	pipelineDir       string // Directory containing the Python scripts
+	pythonCmd         string // Python executable path
+	mu                sync.Mutex
+	cancelGracePeriod time.Duration // Grace period between SIGTERM and SIGKILL on cancellation
+
+	cancelMu      sync.Mutex
+	runningCancel context.CancelFunc // Cancels the currently running step's context, if any - see Cancel.
+
+	workers *WorkerPool // Long-lived model workers; falls back to runPythonScriptCtx per ModelType when unavailable.
 }
 
 // NewService creates a new pipeline service.
 // pipelineDir should point to the directory containing the Python scripts.
+// It starts warming up the long-lived model workers in the background so the
+// first real request doesn't pay their cold-start cost - see WorkerPool.
 func NewService(pipelineDir string) *Service {
-	return &Service{
-		pipelineDir: pipelineDir,
-		pythonCmd:   findPython(),
+	s := &Service{
+		pipelineDir:       pipelineDir,
+		pythonCmd:         findPython(),
+		cancelGracePeriod: defaultCancelGracePeriod,
+		workers:           NewWorkerPool(pipelineDir, findPython(), nil),
+	}
+	go s.workers.Warmup(context.Background())
+	return s
+}
+
+// SetCancelGracePeriod configures how long a cancelled step's child process
+// gets to exit after SIGTERM before being sent SIGKILL. Default 5s.
+func (s *Service) SetCancelGracePeriod(d time.Duration) {
+	if d > 0 {
+		s.cancelGracePeriod = d
+	}
+}
+
+// Cancel aborts the currently running pipeline step, if any, by cancelling
+// its context - runPythonScriptCtx sends SIGTERM (then SIGKILL after
+// cancelGracePeriod if the child hasn't exited) and the step returns a
+// cancellation error. A no-op if no step is running. Only steps run as
+// one-shot scripts are reached: a step served by a WorkerPool worker never
+// registers a cancel func, so Cancel leaves it running.
+func (s *Service) Cancel() {
+	s.cancelMu.Lock()
+	cancel := s.runningCancel
+	s.cancelMu.Unlock()
+	if cancel != nil {
+		cancel()
 	}
 }
 
@@ -64,58 +112,126 @@ func (s *Service) IsPythonAvailable() bool {
 
 // GenerateNanoBanana runs the Nano Banana image generation script.
 func (s *Service) GenerateNanoBanana(inputImage, outputImage string, customPrompt string, onProgress ProgressCallback) error {
-	s.mu.Lock()
+	return s.GenerateNanoBananaCtx(context.Background(), inputImage, outputImage, customPrompt, onProgress)
+}
+
+// GenerateNanoBananaCtx is GenerateNanoBanana with cancellation: on the
+// one-shot script path ctx is passed into exec.CommandContext, so cancelling
+// it (or calling Cancel) aborts the running script. When a worker serves the
+// step, cancelling ctx only stops waiting for its reply; the worker keeps
+// running the job, and Cancel does not reach it.
+func (s *Service) GenerateNanoBananaCtx(ctx context.Context, inputImage, outputImage string, customPrompt string, onProgress ProgressCallback) error {
+	if !s.mu.TryLock() {
+		return ErrPipelineBusy
+	}
 	defer s.mu.Unlock()
 
+	if s.workers.Available(ModelNanoBanana) {
+		workerArgs := map[string]any{"input": inputImage, "output": outputImage}
+		if customPrompt != "" {
+			workerArgs["prompt"] = customPrompt
+		}
+		return s.workers.Call(ctx, ModelNanoBanana, "generate_nano", workerArgs, onProgress)
+	}
+
 	scriptPath := filepath.Join(s.pipelineDir, "generate_nano.py")
 	args := []string{scriptPath, "--input", inputImage, "--output", outputImage}
 	if customPrompt != "" {
 		args = append(args, "--prompt", customPrompt)
 	}
 
-	return s.runPythonScript(args, onProgress)
+	return s.runPythonScriptCtx(ctx, args, onProgress)
 }
 
 // ConvertToModel runs the TripoSR image-to-3D conversion script.
 func (s *Service) ConvertToModel(inputImage, outputModel string, onProgress ProgressCallback) error {
-	s.mu.Lock()
+	return s.ConvertToModelCtx(context.Background(), inputImage, outputModel, onProgress)
+}
+
+// ConvertToModelCtx is ConvertToModel with cancellation - see
+// GenerateNanoBananaCtx.
+func (s *Service) ConvertToModelCtx(ctx context.Context, inputImage, outputModel string, onProgress ProgressCallback) error {
+	if !s.mu.TryLock() {
+		return ErrPipelineBusy
+	}
 	defer s.mu.Unlock()
 
+	if s.workers.Available(ModelTripoSR) {
+		workerArgs := map[string]any{"image": inputImage, "output": outputModel}
+		return s.workers.Call(ctx, ModelTripoSR, "convert_to_model", workerArgs, onProgress)
+	}
+
 	scriptPath := filepath.Join(s.pipelineDir, "image_to_3d.py")
 	args := []string{scriptPath, "--image", inputImage, "--output", outputModel}
 
-	return s.runPythonScript(args, onProgress)
+	return s.runPythonScriptCtx(ctx, args, onProgress)
 }
 
 // GenerateVisemes runs the viseme generation script for lip-sync.
 func (s *Service) GenerateVisemes(audioPath, facePath, outputPath string, onProgress ProgressCallback) error {
-	s.mu.Lock()
+	return s.GenerateVisemesCtx(context.Background(), audioPath, facePath, outputPath, onProgress)
+}
+
+// GenerateVisemesCtx is GenerateVisemes with cancellation - see
+// GenerateNanoBananaCtx.
+func (s *Service) GenerateVisemesCtx(ctx context.Context, audioPath, facePath, outputPath string, onProgress ProgressCallback) error {
+	if !s.mu.TryLock() {
+		return ErrPipelineBusy
+	}
 	defer s.mu.Unlock()
 
+	if s.workers.Available(ModelVisemes) {
+		workerArgs := map[string]any{"audio": audioPath, "output": outputPath}
+		if facePath != "" {
+			workerArgs["face"] = facePath
+		}
+		return s.workers.Call(ctx, ModelVisemes, "generate_visemes", workerArgs, onProgress)
+	}
+
 	scriptPath := filepath.Join(s.pipelineDir, "generate_visemes.py")
 	args := []string{scriptPath, "--audio", audioPath, "--output", outputPath}
 	if facePath != "" {
 		args = append(args, "--face", facePath)
 	}
 
-	return s.runPythonScript(args, onProgress)
+	return s.runPythonScriptCtx(ctx, args, onProgress)
 }
 
 // SynthesizeSpeech runs the Moshi TTS script.
 func (s *Service) SynthesizeSpeech(text, outputPath string, onProgress ProgressCallback) error {
-	s.mu.Lock()
+	return s.SynthesizeSpeechCtx(context.Background(), text, outputPath, onProgress)
+}
+
+// SynthesizeSpeechCtx is SynthesizeSpeech with cancellation - see
+// GenerateNanoBananaCtx.
+func (s *Service) SynthesizeSpeechCtx(ctx context.Context, text, outputPath string, onProgress ProgressCallback) error {
+	if !s.mu.TryLock() {
+		return ErrPipelineBusy
+	}
 	defer s.mu.Unlock()
 
+	if s.workers.Available(ModelMoshiTTS) {
+		workerArgs := map[string]any{"text": text, "output": outputPath}
+		return s.workers.Call(ctx, ModelMoshiTTS, "synthesize_speech", workerArgs, onProgress)
+	}
+
 	scriptPath := filepath.Join(s.pipelineDir, "moshi_tts.py")
 	args := []string{scriptPath, "--text", text, "--output", outputPath}
 
-	return s.runPythonScript(args, onProgress)
+	return s.runPythonScriptCtx(ctx, args, onProgress)
 }
 
 // RunFullPipeline executes the complete character creation pipeline:
 // 1. Generate Nano Banana image from source photo
 // 2. Convert styled image to 3D GLB model
 func (s *Service) RunFullPipeline(inputImage, charDir string, onProgress ProgressCallback) (nanoBananaPath, modelPath string, err error) {
+	return s.RunFullPipelineCtx(context.Background(), inputImage, charDir, onProgress)
+}
+
+// RunFullPipelineCtx is RunFullPipeline with cancellation: ctx is threaded
+// through each step, so cancelling it aborts whichever script is currently
+// running and skips the remaining steps.
+func (s *Service) RunFullPipelineCtx(ctx context.Context, inputImage, charDir string, onProgress ProgressCallback) (nanoBananaPath, modelPath string, err error) {
 	nanoBananaPath = filepath.Join(charDir, "nano_banana.png")
 	modelPath = filepath.Join(charDir, "model.glb")
 
@@ -123,7 +239,7 @@ func (s *Service) RunFullPipeline(inputImage, charDir string, onProgress Progres
 	if onProgress != nil {
 		onProgress("nano_banana", "Generating Nano Banana figurine image...")
 	}
-	if err := s.GenerateNanoBanana(inputImage, nanoBananaPath, "", onProgress); err != nil {
+	if err := s.GenerateNanoBananaCtx(ctx, inputImage, nanoBananaPath, "", onProgress); err != nil {
 		return "", "", fmt.Errorf("nano banana generation failed: %w", err)
 	}
 
@@ -138,16 +254,34 @@ func (s *Service) RunFullPipeline(inputImage, charDir string, onProgress Progres
 		imageFor3D = inputImage
 	}
 
-	if err := s.ConvertToModel(imageFor3D, modelPath, onProgress); err != nil {
+	if err := s.ConvertToModelCtx(ctx, imageFor3D, modelPath, onProgress); err != nil {
 		return nanoBananaPath, "", fmt.Errorf("3D conversion failed: %w", err)
 	}
 
 	return nanoBananaPath, modelPath, nil
 }
 
-// runPythonScript executes a Python script and streams its JSON output.
-func (s *Service) runPythonScript(args []string, onProgress ProgressCallback) error {
-	cmd := exec.Command(s.pythonCmd, args...)
+// runPythonScriptCtx executes a Python script and streams its JSON output.
+// ctx governs the child process: cancelling it (via the caller or via
+// Service.Cancel) sends SIGTERM and, if the child hasn't exited within
+// cancelGracePeriod, escalates to SIGKILL (see exec.Cmd's Cancel/WaitDelay).
+func (s *Service) runPythonScriptCtx(ctx context.Context, args []string, onProgress ProgressCallback) error {
+	runCtx, cancel := context.WithCancel(ctx)
+	s.cancelMu.Lock()
+	s.runningCancel = cancel
+	s.cancelMu.Unlock()
+	defer func() {
+		s.cancelMu.Lock()
+		s.runningCancel = nil
+		s.cancelMu.Unlock()
+		cancel()
+	}()
+
+	cmd := exec.CommandContext(runCtx, s.pythonCmd, args...)
+	cmd.Cancel = func() error {
+		return cmd.Process.Signal(syscall.SIGTERM)
+	}
+	cmd.WaitDelay = s.cancelGracePeriod
 
 	// Set up environment for Mac GPU support
 	env := os.Environ()
@@ -214,6 +348,15 @@ func (s *Service) runPythonScript(args []string, onProgress ProgressCallback) er
 	}
 
 	if err := cmd.Wait(); err != nil {
+		if runCtx.Err() != nil {
+			cancelled := StepResult{Status: "cancelled", Step: lastResult.Step, Message: "pipeline step cancelled"}
+			slog.Info("[Pipeline] Cancelled", "step", cancelled.Step)
+			if onProgress != nil {
+				onProgress(cancelled.Step, cancelled.Message)
+			}
+			return fmt.Errorf("script cancelled: %w", runCtx.Err())
+		}
+
 		errMsg := stderrOutput.String()
 		if lastResult.Error != "" {
 			errMsg = lastResult.Error
@@ -228,6 +371,101 @@ func (s *Service) runPythonScript(args []string, onProgress ProgressCallback) er
 	return nil
 }
 
+// Output describes one requested 3D export target for
+// RunFullPipelineWithOutputs, in the spirit of a buildkit output target:
+// Type selects the format and Attrs carries the destination path plus any
+// format-specific options.
+type Output struct {
+	// Type is one of "glb", "usdz", "fbx", "obj", or "tar". A "tar" output
+	// goes through the same single conversion step as the other formats,
+	// with --format tar and only the base GLB as input; the other requested
+	// outputs are not passed to it.
+	Type string
+
+	// Attrs carries the destination path under the "path" key, plus
+	// format-specific options (e.g. "texture_size", "draco_compression")
+	// passed straight through to convert_model.py as --flags.
+	Attrs map[string]string
+}
+
+// validOutputTypes are the formats convert_model.py knows how to produce.
+var validOutputTypes = map[string]bool{
+	"glb":  true,
+	"usdz": true,
+	"fbx":  true,
+	"obj":  true,
+	"tar":  true,
+}
+
+// RunFullPipelineWithOutputs runs the full character-creation pipeline like
+// RunFullPipelineCtx - Nano Banana once, then TripoSR once - and then
+// converts the resulting GLB into every format requested in outputs via a
+// convert_model.py step per format (e.g. a USDZ for iOS, an FBX for Unity,
+// or a Draco-compressed GLB for the web), so callers don't each need their
+// own conversion tooling. TripoSR still runs exactly once regardless of
+// len(outputs).
+func (s *Service) RunFullPipelineWithOutputs(ctx context.Context, inputImage, charDir string, outputs []Output, onProgress ProgressCallback) (nanoBananaPath string, outputPaths map[string]string, err error) {
+	nanoBananaPath, basePath, err := s.RunFullPipelineCtx(ctx, inputImage, charDir, onProgress)
+	if err != nil {
+		return nanoBananaPath, nil, err
+	}
+
+	outputPaths = make(map[string]string, len(outputs))
+	for _, out := range outputs {
+		if !validOutputTypes[out.Type] {
+			return nanoBananaPath, outputPaths, fmt.Errorf("unsupported output type: %s", out.Type)
+		}
+
+		destPath := out.Attrs["path"]
+		if destPath == "" {
+			destPath = filepath.Join(charDir, "model."+out.Type)
+		}
+
+		if onProgress != nil {
+			onProgress("convert_"+out.Type, fmt.Sprintf("Converting to %s...", strings.ToUpper(out.Type)))
+		}
+
+		if err := s.convertModelCtx(ctx, basePath, destPath, out, onProgress); err != nil {
+			return nanoBananaPath, outputPaths, fmt.Errorf("%s conversion failed: %w", out.Type, err)
+		}
+		outputPaths[out.Type] = destPath
+	}
+
+	return nanoBananaPath, outputPaths, nil
+}
+
+// convertModelCtx runs convert_model.py (or its worker equivalent) to turn
+// modelPath into destPath in out.Type's format, passing out.Attrs through as
+// options - see RunFullPipelineWithOutputs.
+func (s *Service) convertModelCtx(ctx context.Context, modelPath, destPath string, out Output, onProgress ProgressCallback) error {
+	if !s.mu.TryLock() {
+		return ErrPipelineBusy
+	}
+	defer s.mu.Unlock()
+
+	if s.workers.Available(ModelConvert) {
+		workerArgs := map[string]any{"model": modelPath, "output": destPath, "format": out.Type}
+		for k, v := range out.Attrs {
+			if k == "path" {
+				continue
+			}
+			workerArgs[k] = v
+		}
+		return s.workers.Call(ctx, ModelConvert, "convert_model", workerArgs, onProgress)
+	}
+
+	scriptPath := filepath.Join(s.pipelineDir, "convert_model.py")
+	args := []string{scriptPath, "--model", modelPath, "--output", destPath, "--format", out.Type}
+	for k, v := range out.Attrs {
+		if k == "path" {
+			continue
+		}
+		args = append(args, "--"+k, v)
+	}
+
+	return s.runPythonScriptCtx(ctx, args, onProgress)
+}
+
 // GetPipelineDir returns the directory containing the Python scripts.
 func (s *Service) GetPipelineDir() string {
 	return s.pipelineDir
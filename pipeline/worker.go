@@ -0,0 +1,439 @@
+package pipeline
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ModelType identifies one of the long-lived Python worker processes a
+// WorkerPool manages - one per model, so TripoSR/Moshi/etc weights are
+// loaded once instead of on every call.
+type ModelType string
+
+const (
+	ModelNanoBanana ModelType = "nano_banana"
+	ModelTripoSR    ModelType = "triposr"
+	ModelVisemes    ModelType = "visemes"
+	ModelMoshiTTS   ModelType = "moshi_tts"
+	ModelConvert    ModelType = "convert_model"
+)
+
+// workerScript names the long-lived worker script for each ModelType, as
+// opposed to the one-shot scripts runPythonScriptCtx invokes as a fallback.
+var workerScript = map[ModelType]string{
+	ModelNanoBanana: "worker_nano.py",
+	ModelTripoSR:    "worker_image_to_3d.py",
+	ModelVisemes:    "worker_visemes.py",
+	ModelMoshiTTS:   "worker_moshi_tts.py",
+	ModelConvert:    "worker_convert_model.py",
+}
+
+// errWorkerUnavailable means no worker could be started/kept healthy for a
+// ModelType - the caller should fall back to the one-shot script path.
+var errWorkerUnavailable = errors.New("pipeline: worker unavailable")
+
+const (
+	defaultPingTimeout    = 10 * time.Second
+	defaultWorkerShutdown = 3 * time.Second
+)
+
+// workerRequest is one length-prefixed JSON request frame sent to a worker
+// process's stdin.
+type workerRequest struct {
+	ID   int64          `json:"id"`
+	Op   string         `json:"op"`
+	Args map[string]any `json:"args,omitempty"`
+}
+
+// workerResponse is one length-prefixed JSON response frame read from a
+// worker process's stdout. Several responses (progress, then a final
+// success/error/cancelled) can share the same ID.
+type workerResponse struct {
+	ID      int64  `json:"id"`
+	Status  string `json:"status"`
+	Step    string `json:"step,omitempty"`
+	Message string `json:"message,omitempty"`
+	Output  string `json:"output,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// writeFrame writes v as a 4-byte big-endian length prefix followed by its
+// JSON encoding, matching the worker protocol's framing on both sides.
+func writeFrame(w io.Writer, v any) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var lenBuf [4]byte
+	binary.BigEndian.PutUint32(lenBuf[:], uint32(len(body)))
+	if _, err := w.Write(lenBuf[:]); err != nil {
+		return err
+	}
+	_, err = w.Write(body)
+	return err
+}
+
+// readFrame reads one length-prefixed JSON frame from r.
+func readFrame(r *bufio.Reader) ([]byte, error) {
+	var lenBuf [4]byte
+	if _, err := io.ReadFull(r, lenBuf[:]); err != nil {
+		return nil, err
+	}
+	n := binary.BigEndian.Uint32(lenBuf[:])
+	body := make([]byte, n)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// pendingCall tracks an in-flight request's progress callback and where to
+// deliver the terminal (success/error/cancelled) response.
+type pendingCall struct {
+	onProgress ProgressCallback
+	done       chan workerResponse
+}
+
+// Worker wraps one long-lived Python subprocess for a single ModelType,
+// communicating over stdin/stdout using length-prefixed JSON frames (see
+// workerRequest/workerResponse). Multiple requests can be in flight at once;
+// each is tracked by ID so progress frames route to the right caller.
+type Worker struct {
+	modelType  ModelType
+	pythonCmd  string
+	scriptPath string
+
+	mu      sync.Mutex
+	cmd     *exec.Cmd
+	stdin   io.WriteCloser
+	healthy bool
+
+	nextID    int64
+	pendingMu sync.Mutex
+	pending   map[int64]*pendingCall
+}
+
+// newWorker creates a Worker for modelType. The process isn't started until
+// start is called.
+func newWorker(modelType ModelType, pythonCmd, scriptPath string) *Worker {
+	return &Worker{
+		modelType:  modelType,
+		pythonCmd:  pythonCmd,
+		scriptPath: scriptPath,
+		pending:    make(map[int64]*pendingCall),
+	}
+}
+
+// start launches the worker subprocess and its stdout-reading goroutine.
+// Must be called with w.mu held.
+func (w *Worker) start() error {
+	cmd := exec.Command(w.pythonCmd, w.scriptPath)
+	env := os.Environ()
+	if runtime.GOOS == "darwin" {
+		env = append(env, "PYTORCH_ENABLE_MPS_FALLBACK=1")
+	}
+	cmd.Env = env
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("worker %s: failed to open stdin: %w", w.modelType, err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("worker %s: failed to open stdout: %w", w.modelType, err)
+	}
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("worker %s: failed to start %s: %w", w.modelType, w.scriptPath, err)
+	}
+
+	w.cmd = cmd
+	w.stdin = stdin
+	w.healthy = true
+
+	reader := bufio.NewReader(stdout)
+	go w.readLoop(reader)
+
+	go func() {
+		_ = cmd.Wait()
+		w.handleExit()
+	}()
+
+	return nil
+}
+
+// readLoop dispatches frames from the worker's stdout to the matching
+// pendingCall until the pipe closes (the worker exited or crashed).
+func (w *Worker) readLoop(r *bufio.Reader) {
+	for {
+		body, err := readFrame(r)
+		if err != nil {
+			return
+		}
+		var resp workerResponse
+		if err := json.Unmarshal(body, &resp); err != nil {
+			slog.Warn("[Pipeline] worker sent malformed frame", "model", w.modelType, "error", err)
+			continue
+		}
+
+		w.pendingMu.Lock()
+		call, ok := w.pending[resp.ID]
+		if ok && resp.Status != "progress" {
+			delete(w.pending, resp.ID)
+		}
+		w.pendingMu.Unlock()
+		if !ok {
+			continue
+		}
+
+		if resp.Status == "progress" {
+			if call.onProgress != nil {
+				call.onProgress(resp.Step, resp.Message)
+			}
+			continue
+		}
+		call.done <- resp
+	}
+}
+
+// handleExit marks the worker unhealthy and fails every pending call when
+// the subprocess exits, whether cleanly or by crashing.
+func (w *Worker) handleExit() {
+	w.mu.Lock()
+	w.healthy = false
+	w.mu.Unlock()
+
+	w.pendingMu.Lock()
+	pending := w.pending
+	w.pending = make(map[int64]*pendingCall)
+	w.pendingMu.Unlock()
+
+	for _, call := range pending {
+		call.done <- workerResponse{Status: "error", Error: "worker process exited"}
+	}
+}
+
+// ensureHealthy (re)starts the worker if it isn't running, auto-restarting
+// after a crash the same way the initial Warmup start did.
+func (w *Worker) ensureHealthy() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.healthy {
+		return nil
+	}
+	return w.start()
+}
+
+// call sends one request and waits for its terminal response, routing
+// intermediate progress frames to onProgress as they arrive. Cancelling ctx
+// stops waiting but does not (by itself) interrupt work already queued in
+// the worker process - pipeline.Cancel-style cancellation for worker-backed
+// calls is left to a future Op-specific cancel message.
+func (w *Worker) call(ctx context.Context, op string, args map[string]any, onProgress ProgressCallback) (workerResponse, error) {
+	if err := w.ensureHealthy(); err != nil {
+		return workerResponse{}, errors.Join(errWorkerUnavailable, err)
+	}
+
+	id := atomic.AddInt64(&w.nextID, 1)
+	done := make(chan workerResponse, 1)
+	w.pendingMu.Lock()
+	w.pending[id] = &pendingCall{onProgress: onProgress, done: done}
+	w.pendingMu.Unlock()
+
+	w.mu.Lock()
+	err := writeFrame(w.stdin, workerRequest{ID: id, Op: op, Args: args})
+	w.mu.Unlock()
+	if err != nil {
+		w.pendingMu.Lock()
+		delete(w.pending, id)
+		w.pendingMu.Unlock()
+		return workerResponse{}, errors.Join(errWorkerUnavailable, err)
+	}
+
+	select {
+	case resp := <-done:
+		if resp.Status == "error" {
+			return resp, fmt.Errorf("worker %s: %s", w.modelType, resp.Error)
+		}
+		return resp, nil
+	case <-ctx.Done():
+		w.pendingMu.Lock()
+		delete(w.pending, id)
+		w.pendingMu.Unlock()
+		return workerResponse{}, ctx.Err()
+	}
+}
+
+// ping health-checks the worker with a short timeout, used both during
+// Warmup and to decide whether a ModelType should fall back to the one-shot
+// script path.
+func (w *Worker) ping() error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultPingTimeout)
+	defer cancel()
+	_, err := w.call(ctx, "ping", nil, nil)
+	return err
+}
+
+// close asks the worker to shut down and stops waiting after
+// defaultWorkerShutdown.
+func (w *Worker) close() {
+	w.mu.Lock()
+	cmd := w.cmd
+	stdin := w.stdin
+	w.mu.Unlock()
+	if cmd == nil || cmd.Process == nil {
+		return
+	}
+	if stdin != nil {
+		stdin.Close()
+	}
+	done := make(chan struct{})
+	go func() {
+		_ = cmd.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(defaultWorkerShutdown):
+		_ = cmd.Process.Kill()
+	}
+}
+
+// WorkerPool manages one or more long-lived Worker processes per ModelType,
+// so GenerateNanoBanana/ConvertToModel/GenerateVisemes/SynthesizeSpeech don't
+// pay torch/model-load cold-start cost on every call - see Service.
+type WorkerPool struct {
+	pipelineDir string
+	pythonCmd   string
+	concurrency map[ModelType]int
+
+	mu      sync.Mutex
+	workers map[ModelType][]*Worker
+	next    map[ModelType]int // round-robin cursor per ModelType
+}
+
+// NewWorkerPool creates a WorkerPool. concurrency overrides the default of
+// one worker per ModelType; a ModelType absent from concurrency (or with a
+// value <= 0) gets exactly one worker.
+func NewWorkerPool(pipelineDir, pythonCmd string, concurrency map[ModelType]int) *WorkerPool {
+	return &WorkerPool{
+		pipelineDir: pipelineDir,
+		pythonCmd:   pythonCmd,
+		concurrency: concurrency,
+		workers:     make(map[ModelType][]*Worker),
+		next:        make(map[ModelType]int),
+	}
+}
+
+// Warmup starts and pings every known ModelType's workers so the first real
+// request doesn't pay cold-start latency. It's best-effort: a ModelType
+// whose worker script is missing, fails to start, or fails to ping is left
+// out of the pool entirely, and Service falls back to the one-shot path for
+// it - Warmup never returns an error for that reason.
+func (p *WorkerPool) Warmup(ctx context.Context) {
+	for mt := range workerScript {
+		p.ensureWorkers(mt)
+	}
+}
+
+// ensureWorkers lazily starts and pings the configured number of workers for
+// mt, skipping any that fail. Safe to call repeatedly; already-healthy
+// workers are left alone.
+func (p *WorkerPool) ensureWorkers(mt ModelType) []*Worker {
+	script, ok := workerScript[mt]
+	if !ok {
+		return nil
+	}
+
+	n := p.concurrency[mt]
+	if n <= 0 {
+		n = 1
+	}
+
+	p.mu.Lock()
+	existing := p.workers[mt]
+	p.mu.Unlock()
+	if len(existing) >= n {
+		return existing
+	}
+
+	scriptPath := p.pipelineDir + string(os.PathSeparator) + script
+	var healthy []*Worker
+	for _, w := range existing {
+		if w.ping() == nil {
+			healthy = append(healthy, w)
+		}
+	}
+	for len(healthy) < n {
+		w := newWorker(mt, p.pythonCmd, scriptPath)
+		w.mu.Lock()
+		err := w.start()
+		w.mu.Unlock()
+		if err != nil {
+			slog.Warn("[Pipeline] failed to start worker, falling back to one-shot scripts", "model", mt, "error", err)
+			break
+		}
+		if err := w.ping(); err != nil {
+			slog.Warn("[Pipeline] worker failed health check, falling back to one-shot scripts", "model", mt, "error", err)
+			w.close()
+			break
+		}
+		healthy = append(healthy, w)
+	}
+
+	p.mu.Lock()
+	p.workers[mt] = healthy
+	p.mu.Unlock()
+	return healthy
+}
+
+// Available reports whether mt has at least one worker that's running and
+// passed its last health check.
+func (p *WorkerPool) Available(mt ModelType) bool {
+	workers := p.ensureWorkers(mt)
+	return len(workers) > 0
+}
+
+// Call runs op on a worker for mt, round-robin across that ModelType's
+// workers. Returns errWorkerUnavailable (wrapped) if mt has no healthy
+// worker, so the caller can fall back to the one-shot script path.
+func (p *WorkerPool) Call(ctx context.Context, mt ModelType, op string, args map[string]any, onProgress ProgressCallback) error {
+	workers := p.ensureWorkers(mt)
+	if len(workers) == 0 {
+		return errWorkerUnavailable
+	}
+
+	p.mu.Lock()
+	idx := p.next[mt] % len(workers)
+	p.next[mt] = idx + 1
+	p.mu.Unlock()
+
+	_, err := workers[idx].call(ctx, op, args, onProgress)
+	return err
+}
+
+// Close shuts down every worker in the pool.
+func (p *WorkerPool) Close() {
+	p.mu.Lock()
+	workers := p.workers
+	p.workers = make(map[ModelType][]*Worker)
+	p.mu.Unlock()
+	for _, ws := range workers {
+		for _, w := range ws {
+			w.close()
+		}
+	}
+}
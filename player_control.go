@@ -0,0 +1,169 @@
+package main
+
+import (
+	"fmt"
+
+	"super-characters/playercontrol"
+)
+
+// #region Player Control API
+//
+// These methods used to shell out to osascript directly (see maccontrol.go's
+// git history); they now delegate to either a.spotifyService (the Spotify
+// Web API, see spotify_control.go) or a.playerController (the platform's
+// AppleScript/MPRIS/SMTC backend, see the playercontrol package), chosen per
+// call by useSpotifyAPI. Method names and signatures are unchanged so the
+// frontend doesn't need to know about the split.
+
+// musicControlBackendSpotifyAPI is the Settings.MusicControlBackend value
+// that always prefers the Spotify Web API. Any other value (including ""
+// for auto) falls back to useSpotifyAPI's default behavior.
+const musicControlBackendSpotifyAPI = "spotify-api"
+
+// useSpotifyAPI reports whether PlayPauseMusic/NextTrack/PreviousTrack/
+// GetNowPlaying should go through a.spotifyService instead of
+// a.playerController, per Settings.MusicControlBackend: an explicit
+// non-Spotify value (e.g. "applescript") always says no; "spotify-api" or
+// "" (auto) says yes as soon as a user has completed the Spotify OAuth
+// flow, and no otherwise.
+func (a *App) useSpotifyAPI() bool {
+	if a.spotifyService == nil || !a.spotifyService.IsAuthenticated() {
+		return false
+	}
+	backend := ""
+	if a.settingsService != nil {
+		backend = a.settingsService.GetMusicControlBackend()
+	}
+	return backend == "" || backend == musicControlBackendSpotifyAPI
+}
+
+// PlayPauseMusic toggles play/pause on the active media player.
+func (a *App) PlayPauseMusic() (string, error) {
+	if a.useSpotifyAPI() {
+		playing, err := a.spotifyService.PlayPause()
+		if err != nil {
+			return "", err
+		}
+		if playing {
+			return "Resumed Spotify playback", nil
+		}
+		return "Paused Spotify playback", nil
+	}
+	return a.playerController.PlayPause()
+}
+
+// NextTrack skips to the next track on the active media player.
+func (a *App) NextTrack() (string, error) {
+	if a.useSpotifyAPI() {
+		if err := a.spotifyService.Next(); err != nil {
+			return "", err
+		}
+		return "Skipped to next track on Spotify", nil
+	}
+	return a.playerController.Next()
+}
+
+// PreviousTrack goes back to the previous track on the active media player.
+func (a *App) PreviousTrack() (string, error) {
+	if a.useSpotifyAPI() {
+		if err := a.spotifyService.Previous(); err != nil {
+			return "", err
+		}
+		return "Went to previous track on Spotify", nil
+	}
+	return a.playerController.Previous()
+}
+
+// SetVolume sets the active media player's volume (0-100). This always
+// goes through playerController - the Spotify Web API integration doesn't
+// cover volume control.
+func (a *App) SetVolume(level int) (string, error) {
+	return a.playerController.SetVolume(level)
+}
+
+// GetNowPlayingInfo returns structured information about the currently
+// playing track - richer than GetNowPlaying's formatted string, and safe
+// for the frontend to render without parsing prose (artwork, progress,
+// etc). Returns nil, nil if no player is running or playing.
+func (a *App) GetNowPlayingInfo() (*playercontrol.NowPlaying, error) {
+	if a.useSpotifyAPI() {
+		info, err := a.spotifyService.NowPlaying()
+		if err != nil {
+			return nil, err
+		}
+		if info == nil {
+			return nil, nil
+		}
+		state := playercontrol.StatePaused
+		if info.IsPlaying {
+			state = playercontrol.StatePlaying
+		}
+		spotifyURL := ""
+		if info.ID != "" {
+			spotifyURL = "https://open.spotify.com/track/" + info.ID
+		}
+		return &playercontrol.NowPlaying{
+			Source:      "spotify",
+			State:       state,
+			Track:       info.Name,
+			Artist:      info.Artist,
+			Album:       info.Album,
+			AlbumArtist: info.Artist,
+			DurationMs:  info.DurationMs,
+			PositionMs:  info.ProgressMs,
+			TrackID:     info.ID,
+			ArtworkURL:  info.ArtworkURL,
+			Popularity:  info.Popularity,
+			SpotifyURL:  spotifyURL,
+		}, nil
+	}
+	return a.playerController.NowPlayingInfo()
+}
+
+// GetNowPlaying returns information about the currently playing track,
+// formatted as a human-readable string from GetNowPlayingInfo - kept for
+// backwards compatibility with callers that predate the structured
+// GetNowPlayingInfo.
+func (a *App) GetNowPlaying() (string, error) {
+	info, err := a.GetNowPlayingInfo()
+	if err != nil {
+		return "", err
+	}
+	if info == nil {
+		return "No music player is running", nil
+	}
+	if info.State == playercontrol.StatePaused {
+		if info.Track != "" {
+			return fmt.Sprintf("%s is paused", info.Track), nil
+		}
+		return "Paused", nil
+	}
+
+	label := "Playing"
+	switch info.Source {
+	case "spotify":
+		label = "Playing on Spotify"
+	case "apple_music":
+		label = "Playing on Apple Music"
+	}
+	return fmt.Sprintf("%s: %s by %s from %s", label, info.Track, info.Artist, info.Album), nil
+}
+
+// ListSupportedPlayers returns every player the active PlayerController
+// backend knows how to drive, regardless of whether it's currently running.
+func (a *App) ListSupportedPlayers() []string {
+	return a.playerController.SupportedPlayers()
+}
+
+// ListLaunchedPlayers returns the supported players currently running.
+func (a *App) ListLaunchedPlayers() []string {
+	return a.playerController.Launched()
+}
+
+// SelectPlayer pins name as the player the methods above act on. An empty
+// name reverts to auto-detecting the first launched player.
+func (a *App) SelectPlayer(name string) {
+	a.playerController.Select(name)
+}
+
+// #endregion Player Control API
@@ -0,0 +1,98 @@
+// Package playercontrol drives whatever media player the user has running -
+// Spotify, Apple Music, a Linux MPRIS player, or a Windows SMTC session -
+// behind one PlayerController interface, so App's PlayPauseMusic/NextTrack/
+// PreviousTrack/SetVolume/GetNowPlaying methods don't need to know which
+// platform or player they're actually talking to.
+package playercontrol
+
+// PlayerController toggles playback, skips tracks, adjusts volume, and
+// reports now-playing info for whichever supported media player is
+// currently running. New returns the implementation for the platform this
+// binary was built for - controller_darwin.go's AppleScript backend,
+// controller_linux.go's MPRIS/D-Bus backend, or controller_windows.go's SMTC
+// backend - the same one-backend-per-OS-file selection tts.RegisterOSProviders
+// uses for its speech backends.
+type PlayerController interface {
+	// Name identifies the backend (e.g. "applescript", "mpris", "smtc").
+	Name() string
+	// Platforms lists the GOOS values this backend supports.
+	Platforms() []string
+	// SupportedPlayers lists every player this backend knows how to drive,
+	// regardless of whether it's currently running.
+	SupportedPlayers() []string
+	// Launched returns the supported players currently running, in this
+	// backend's own preference order.
+	Launched() []string
+	// Select pins name as the player PlayPause/Next/Previous/SetVolume/
+	// NowPlaying act on, instead of auto-detecting the first entry in
+	// Launched(). An empty name reverts to auto-detect.
+	Select(name string)
+	// Selected returns the currently pinned player name, or "" if none is
+	// pinned (auto-detect).
+	Selected() string
+
+	// PlayPause toggles playback on the selected (or auto-detected) player.
+	PlayPause() (string, error)
+	// Next skips to the next track.
+	Next() (string, error)
+	// Previous returns to the previous track.
+	Previous() (string, error)
+	// SetVolume sets playback volume to level (0-100), clamped to range.
+	SetVolume(level int) (string, error)
+	// NowPlaying describes the current track, or reports that the player is
+	// paused or that no player is running.
+	NowPlaying() (string, error)
+	// NowPlayingInfo returns the current playback state as a structured
+	// NowPlaying, or nil if no supported player is running or playing.
+	// Implementations should populate as many fields as the backend can
+	// report and leave the rest zero-valued rather than fail outright.
+	NowPlayingInfo() (*NowPlaying, error)
+}
+
+// Playback states NowPlaying.State uses. Stopped is reported as a nil
+// *NowPlaying by NowPlayingInfo rather than a State value, so callers only
+// see Playing or Paused in practice.
+const (
+	StatePlaying = "playing"
+	StatePaused  = "paused"
+	StateStopped = "stopped"
+)
+
+// NowPlaying is a backend-agnostic, structured description of what's
+// currently playing - richer than NowPlaying() (string, error)'s
+// human-readable summary, and shaped so a frontend can render artwork and
+// progress without parsing prose. Source identifies which backend populated
+// it (e.g. "spotify", "apple_music", "mpris", "smtc"). Fields a backend
+// can't report (e.g. Popularity on MPRIS, SpotifyURL on SMTC) are left at
+// their zero value rather than guessed at.
+type NowPlaying struct {
+	Source      string `json:"source"`
+	State       string `json:"state"`
+	Track       string `json:"track"`
+	Artist      string `json:"artist"`
+	Album       string `json:"album"`
+	AlbumArtist string `json:"albumArtist"`
+	DurationMs  int    `json:"durationMs"`
+	PositionMs  int    `json:"positionMs"`
+	TrackID     string `json:"trackId"`
+	ArtworkURL  string `json:"artworkUrl"`
+	Popularity  int    `json:"popularity"`
+	SpotifyURL  string `json:"spotifyUrl"`
+}
+
+// pickPlayer returns which of launched a backend should act on: selected if
+// it's among them, otherwise the first entry (launched's own preference
+// order), or "", false if nothing is running.
+func pickPlayer(launched []string, selected string) (string, bool) {
+	if selected != "" {
+		for _, name := range launched {
+			if name == selected {
+				return name, true
+			}
+		}
+	}
+	if len(launched) > 0 {
+		return launched[0], true
+	}
+	return "", false
+}
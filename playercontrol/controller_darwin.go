@@ -0,0 +1,323 @@
+package playercontrol
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AppleScriptProviderName is the name the Darwin PlayerController registers
+// under.
+const AppleScriptProviderName = "applescript"
+
+// appleScriptPlayers lists the players AppleScriptController drives, in
+// Launched's preference order - unchanged from the AppleScript that used to
+// live directly in maccontrol.go, which always checked Spotify before
+// falling back to Music.
+var appleScriptPlayers = []string{"Spotify", "Music"}
+
+// AppleScriptController drives Spotify and Apple Music via osascript - the
+// only control surface maccontrol.go used before its player-control methods
+// were split out behind PlayerController.
+type AppleScriptController struct {
+	mu       sync.Mutex
+	selected string
+}
+
+// New creates this platform's PlayerController - see controller_linux.go and
+// controller_windows.go for the other platforms' New.
+func New() PlayerController {
+	return &AppleScriptController{}
+}
+
+func (c *AppleScriptController) Name() string        { return AppleScriptProviderName }
+func (c *AppleScriptController) Platforms() []string { return []string{"darwin"} }
+
+func (c *AppleScriptController) SupportedPlayers() []string {
+	return append([]string(nil), appleScriptPlayers...)
+}
+
+func (c *AppleScriptController) Select(name string) {
+	c.mu.Lock()
+	c.selected = name
+	c.mu.Unlock()
+}
+
+func (c *AppleScriptController) Selected() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.selected
+}
+
+// Launched reports which of appleScriptPlayers osascript says is running.
+func (c *AppleScriptController) Launched() []string {
+	var running []string
+	for _, name := range appleScriptPlayers {
+		out, err := runAppleScript(fmt.Sprintf(`application %q is running`, name))
+		if err == nil && out == "true" {
+			running = append(running, name)
+		}
+	}
+	return running
+}
+
+// runAppleScript executes an arbitrary AppleScript string with a 10-second
+// timeout, mirroring App.RunAppleScript.
+func runAppleScript(script string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "osascript", "-e", script)
+	output, err := cmd.CombinedOutput()
+	result := strings.TrimSpace(string(output))
+	if err != nil {
+		slog.Error("[PlayerControl] AppleScript failed", "error", err, "output", result)
+		return result, fmt.Errorf("AppleScript error: %s", result)
+	}
+	return result, nil
+}
+
+func (c *AppleScriptController) PlayPause() (string, error) {
+	player, ok := pickPlayer(c.Launched(), c.Selected())
+	if !ok {
+		script := `
+			tell application "Music"
+				activate
+				delay 1
+				play
+			end tell
+		`
+		if _, err := runAppleScript(script); err != nil {
+			return "", err
+		}
+		return "Started Apple Music", nil
+	}
+	if _, err := runAppleScript(fmt.Sprintf(`tell application %q to playpause`, player)); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Toggled %s playback", player), nil
+}
+
+func (c *AppleScriptController) Next() (string, error) {
+	player, ok := pickPlayer(c.Launched(), c.Selected())
+	if !ok {
+		return "No music player is running", nil
+	}
+	if _, err := runAppleScript(fmt.Sprintf(`tell application %q to next track`, player)); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Skipped to next track on %s", player), nil
+}
+
+func (c *AppleScriptController) Previous() (string, error) {
+	player, ok := pickPlayer(c.Launched(), c.Selected())
+	if !ok {
+		return "No music player is running", nil
+	}
+	if _, err := runAppleScript(fmt.Sprintf(`tell application %q to previous track`, player)); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Went to previous track on %s", player), nil
+}
+
+func (c *AppleScriptController) SetVolume(level int) (string, error) {
+	if level < 0 {
+		level = 0
+	}
+	if level > 100 {
+		level = 100
+	}
+	if _, err := runAppleScript(fmt.Sprintf("set volume output volume %d", level)); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Volume set to %d", level), nil
+}
+
+func (c *AppleScriptController) NowPlaying() (string, error) {
+	player, ok := pickPlayer(c.Launched(), c.Selected())
+	if !ok {
+		return "No music player is running", nil
+	}
+	script := fmt.Sprintf(`
+		tell application %q
+			if player state is playing then
+				set trackName to name of current track
+				set artistName to artist of current track
+				set albumName to album of current track
+				return trackName & " by " & artistName & " from " & albumName
+			else
+				return "paused"
+			end if
+		end tell
+	`, player)
+	result, err := runAppleScript(script)
+	if err != nil {
+		return "", err
+	}
+	if result == "paused" {
+		return fmt.Sprintf("%s is paused", player), nil
+	}
+	return fmt.Sprintf("Playing on %s: %s", player, result), nil
+}
+
+// jsonEscapeHandler is an AppleScript handler that escapes backslashes and
+// double quotes so a track/artist/album name can be embedded in a JSON
+// string literal built by string concatenation - AppleScript has no native
+// JSON support, so the now-playing scripts below build it by hand.
+const jsonEscapeHandler = `
+	on escapeJSON(txt)
+		set AppleScript's text item delimiters to "\\"
+		set txt to (text items of txt) as string
+		set AppleScript's text item delimiters to "\\\\"
+		set txt to (text items of txt) as string
+		set AppleScript's text item delimiters to "\""
+		set txt to (text items of txt) as string
+		set AppleScript's text item delimiters to "\\\""
+		set txt to (text items of txt) as string
+		set AppleScript's text item delimiters to ""
+		return txt
+	end escapeJSON
+`
+
+// spotifyNowPlayingScript reports the current Spotify track as a single JSON
+// object, so NowPlayingInfo can unmarshal it in one atomic call rather than
+// issuing one osascript call per field. Spotify's AppleScript dictionary
+// happens to expose fields the Music app doesn't (popularity, artwork url,
+// a duration already in milliseconds), so this is a distinct script rather
+// than one shared with appleMusicNowPlayingScript.
+const spotifyNowPlayingScript = jsonEscapeHandler + `
+	tell application "Spotify"
+		if player state is playing or player state is paused then
+			set stateStr to "paused"
+			if player state is playing then set stateStr to "playing"
+			set trackName to my escapeJSON(name of current track)
+			set artistName to my escapeJSON(artist of current track)
+			set albumName to my escapeJSON(album of current track)
+			set trackIdRaw to id of current track
+			set trackDuration to duration of current track
+			set trackPosition to (player position) * 1000
+			set trackPopularity to popularity of current track
+			set artworkURL to ""
+			try
+				set artworkURL to artwork url of current track
+			end try
+			return "{\"state\":\"" & stateStr & "\",\"track\":\"" & trackName & "\",\"artist\":\"" & artistName & "\",\"album\":\"" & albumName & "\",\"trackId\":\"" & trackIdRaw & "\",\"durationMs\":" & trackDuration & ",\"positionMs\":" & (trackPosition as integer) & ",\"popularity\":" & trackPopularity & ",\"artworkUrl\":\"" & artworkURL & "\"}"
+		else
+			return "{\"state\":\"stopped\"}"
+		end if
+	end tell
+`
+
+// appleMusicNowPlayingScript is spotifyNowPlayingScript's counterpart for
+// the Music app: duration/position are in seconds rather than milliseconds,
+// and there's no popularity or artwork URL property, but there is an album
+// artist, which Spotify's dictionary lacks.
+const appleMusicNowPlayingScript = jsonEscapeHandler + `
+	tell application "Music"
+		if player state is playing or player state is paused then
+			set stateStr to "paused"
+			if player state is playing then set stateStr to "playing"
+			set trackName to my escapeJSON(name of current track)
+			set artistName to my escapeJSON(artist of current track)
+			set albumName to my escapeJSON(album of current track)
+			set albumArtistName to ""
+			try
+				set albumArtistName to my escapeJSON(album artist of current track)
+			end try
+			set trackIdRaw to database ID of current track
+			set trackDurationSeconds to duration of current track
+			set trackPositionSeconds to player position
+			return "{\"state\":\"" & stateStr & "\",\"track\":\"" & trackName & "\",\"artist\":\"" & artistName & "\",\"album\":\"" & albumName & "\",\"albumArtist\":\"" & albumArtistName & "\",\"trackId\":\"" & trackIdRaw & "\",\"durationMs\":" & ((trackDurationSeconds * 1000) as integer) & ",\"positionMs\":" & ((trackPositionSeconds * 1000) as integer) & "}"
+		else
+			return "{\"state\":\"stopped\"}"
+		end if
+	end tell
+`
+
+// nowPlayingJSON is the shape spotifyNowPlayingScript/appleMusicNowPlayingScript
+// emit; fields a given player's script doesn't set are left at their zero
+// value.
+type nowPlayingJSON struct {
+	State       string `json:"state"`
+	Track       string `json:"track"`
+	Artist      string `json:"artist"`
+	Album       string `json:"album"`
+	AlbumArtist string `json:"albumArtist"`
+	TrackID     string `json:"trackId"`
+	DurationMs  int    `json:"durationMs"`
+	PositionMs  int    `json:"positionMs"`
+	Popularity  int    `json:"popularity"`
+	ArtworkURL  string `json:"artworkUrl"`
+}
+
+// sourceForPlayer maps an appleScriptPlayers entry to the NowPlaying.Source
+// value frontends should key off of.
+func sourceForPlayer(player string) string {
+	if player == "Spotify" {
+		return "spotify"
+	}
+	return "apple_music"
+}
+
+// NowPlayingInfo runs the JSON-returning AppleScript for the selected
+// player and unmarshals its result. If the script's output doesn't parse as
+// JSON - an unexpected macOS/app version mismatch - this falls back to
+// NowPlaying's plain-string path so callers still get a usable summary
+// instead of an error.
+func (c *AppleScriptController) NowPlayingInfo() (*NowPlaying, error) {
+	player, ok := pickPlayer(c.Launched(), c.Selected())
+	if !ok {
+		return nil, nil
+	}
+
+	script := appleMusicNowPlayingScript
+	if player == "Spotify" {
+		script = spotifyNowPlayingScript
+	}
+	raw, err := runAppleScript(script)
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed nowPlayingJSON
+	if err := json.Unmarshal([]byte(raw), &parsed); err != nil {
+		text, err := c.NowPlaying()
+		if err != nil {
+			return nil, err
+		}
+		state := StatePlaying
+		if strings.Contains(text, "paused") {
+			state = StatePaused
+		}
+		return &NowPlaying{Source: sourceForPlayer(player), State: state, Track: text}, nil
+	}
+	if parsed.State == StateStopped {
+		return nil, nil
+	}
+
+	info := &NowPlaying{
+		Source:      sourceForPlayer(player),
+		State:       parsed.State,
+		Track:       parsed.Track,
+		Artist:      parsed.Artist,
+		Album:       parsed.Album,
+		AlbumArtist: parsed.AlbumArtist,
+		DurationMs:  parsed.DurationMs,
+		PositionMs:  parsed.PositionMs,
+		TrackID:     parsed.TrackID,
+		ArtworkURL:  parsed.ArtworkURL,
+		Popularity:  parsed.Popularity,
+	}
+	if info.AlbumArtist == "" {
+		info.AlbumArtist = info.Artist
+	}
+	if player == "Spotify" && strings.HasPrefix(parsed.TrackID, "spotify:track:") {
+		info.SpotifyURL = "https://open.spotify.com/track/" + strings.TrimPrefix(parsed.TrackID, "spotify:track:")
+	}
+	return info, nil
+}
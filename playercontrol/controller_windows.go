@@ -0,0 +1,268 @@
+package playercontrol
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// SMTCProviderName is the name the Windows PlayerController registers under.
+const SMTCProviderName = "smtc"
+
+// smtcKnownPlayers is the supported-player list this backend advertises.
+// SMTC sessions are actually keyed by each app's AppUserModelId rather than
+// a friendly name like this, so Launched reports whatever
+// GlobalSystemMediaTransportControlsSessionManager's current sessions
+// report instead of matching against this list directly.
+var smtcKnownPlayers = []string{"Spotify", "Groove Music", "VLC", "Music"}
+
+// SMTCController drives the Windows System Media Transport Controls
+// (GlobalSystemMediaTransportControlsSessionManager) by running a small
+// PowerShell script that projects the WinRT API, rather than a cgo binding -
+// SMTC has no plain Win32 surface, and this sandbox has neither a Windows
+// toolchain nor the WinRT-projection bindings a real build of this backend
+// would vendor. Every method here is written against the script it would
+// run and is otherwise unverified in this environment, the same
+// documented-but-unverifiable posture backend_fasterwhisper.go takes for its
+// subprocess protocol.
+type SMTCController struct {
+	mu       sync.Mutex
+	selected string
+}
+
+// New creates this platform's PlayerController - see controller_darwin.go
+// and controller_linux.go for the other platforms' New.
+func New() PlayerController {
+	return &SMTCController{}
+}
+
+func (c *SMTCController) Name() string        { return SMTCProviderName }
+func (c *SMTCController) Platforms() []string { return []string{"windows"} }
+
+func (c *SMTCController) SupportedPlayers() []string {
+	return append([]string(nil), smtcKnownPlayers...)
+}
+
+func (c *SMTCController) Select(name string) {
+	c.mu.Lock()
+	c.selected = name
+	c.mu.Unlock()
+}
+
+func (c *SMTCController) Selected() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.selected
+}
+
+// smtcSession is one session smtcSessionsScript reports, keyed by its
+// SourceAppUserModelId.
+type smtcSession struct {
+	AppID      string `json:"appId"`
+	Title      string `json:"title"`
+	Artist     string `json:"artist"`
+	Album      string `json:"album"`
+	Status     string `json:"status"` // "Playing", "Paused", "Stopped", ...
+	DurationMs int    `json:"durationMs"`
+	PositionMs int    `json:"positionMs"`
+}
+
+// smtcSessionsScript lists every current SMTC session as JSON, for Launched
+// and NowPlaying to parse. Timeline properties (duration/position) come
+// from GetTimelineProperties rather than GetPlaybackInfo - SMTC has no
+// equivalent of a track ID or artwork URL shared across every app, so
+// NowPlayingInfo leaves those fields unset.
+const smtcSessionsScript = `
+$manager = [Windows.Media.Control.GlobalSystemMediaTransportControlsSessionManager,Windows.Media.Control,ContentType=WindowsRuntime]::RequestAsync().GetAwaiter().GetResult()
+$sessions = $manager.GetSessions() | ForEach-Object {
+  $props = $_.TryGetMediaPropertiesAsync().GetAwaiter().GetResult()
+  $playback = $_.GetPlaybackInfo()
+  $timeline = $_.GetTimelineProperties()
+  [PSCustomObject]@{
+    appId      = $_.SourceAppUserModelId
+    title      = $props.Title
+    artist     = $props.Artist
+    album      = $props.AlbumTitle
+    status     = $playback.PlaybackStatus.ToString()
+    durationMs = [int]$timeline.EndTime.TotalMilliseconds
+    positionMs = [int]$timeline.Position.TotalMilliseconds
+  }
+}
+$sessions | ConvertTo-Json -Compress
+`
+
+// runPowerShell runs script via powershell.exe and returns its trimmed
+// stdout.
+func runPowerShell(script string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", script)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("powershell failed: %w (%s)", err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+func (c *SMTCController) sessions() ([]smtcSession, error) {
+	out, err := runPowerShell(smtcSessionsScript)
+	if err != nil {
+		return nil, err
+	}
+	if out == "" {
+		return nil, nil
+	}
+	var result []smtcSession
+	// ConvertTo-Json emits a single object, not a one-element array, when
+	// there's exactly one session - normalize both shapes.
+	if strings.HasPrefix(out, "[") {
+		if err := json.Unmarshal([]byte(out), &result); err != nil {
+			return nil, fmt.Errorf("failed to parse SMTC sessions: %w", err)
+		}
+	} else {
+		var single smtcSession
+		if err := json.Unmarshal([]byte(out), &single); err != nil {
+			return nil, fmt.Errorf("failed to parse SMTC session: %w", err)
+		}
+		result = []smtcSession{single}
+	}
+	return result, nil
+}
+
+// Launched reports the SourceAppUserModelId of every current SMTC session.
+func (c *SMTCController) Launched() []string {
+	sessions, err := c.sessions()
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(sessions))
+	for _, s := range sessions {
+		names = append(names, s.AppID)
+	}
+	return names
+}
+
+// activeSession returns the session Launched/pickPlayer selects, or nil if
+// no session is running.
+func (c *SMTCController) activeSession() (*smtcSession, error) {
+	sessions, err := c.sessions()
+	if err != nil {
+		return nil, err
+	}
+	launched := make([]string, len(sessions))
+	byID := make(map[string]smtcSession, len(sessions))
+	for i, s := range sessions {
+		launched[i] = s.AppID
+		byID[s.AppID] = s
+	}
+	name, ok := pickPlayer(launched, c.Selected())
+	if !ok {
+		return nil, nil
+	}
+	session := byID[name]
+	return &session, nil
+}
+
+// smtcControlScript invokes method (TryTogglePlayPauseAsync,
+// TrySkipNextAsync, TrySkipPreviousAsync) on the session matching appID.
+func smtcControlScript(appID, method string) string {
+	return fmt.Sprintf(`
+$manager = [Windows.Media.Control.GlobalSystemMediaTransportControlsSessionManager,Windows.Media.Control,ContentType=WindowsRuntime]::RequestAsync().GetAwaiter().GetResult()
+$session = $manager.GetSessions() | Where-Object { $_.SourceAppUserModelId -eq %q } | Select-Object -First 1
+if ($session) { $session.%s().GetAwaiter().GetResult() | Out-Null }
+`, appID, method)
+}
+
+func (c *SMTCController) PlayPause() (string, error) {
+	sess, err := c.activeSession()
+	if err != nil {
+		return "", err
+	}
+	if sess == nil {
+		return "No music player is running", nil
+	}
+	if _, err := runPowerShell(smtcControlScript(sess.AppID, "TryTogglePlayPauseAsync")); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Toggled %s playback", sess.AppID), nil
+}
+
+func (c *SMTCController) Next() (string, error) {
+	sess, err := c.activeSession()
+	if err != nil {
+		return "", err
+	}
+	if sess == nil {
+		return "No music player is running", nil
+	}
+	if _, err := runPowerShell(smtcControlScript(sess.AppID, "TrySkipNextAsync")); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Skipped to next track on %s", sess.AppID), nil
+}
+
+func (c *SMTCController) Previous() (string, error) {
+	sess, err := c.activeSession()
+	if err != nil {
+		return "", err
+	}
+	if sess == nil {
+		return "No music player is running", nil
+	}
+	if _, err := runPowerShell(smtcControlScript(sess.AppID, "TrySkipPreviousAsync")); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Went to previous track on %s", sess.AppID), nil
+}
+
+// SetVolume is unsupported on this backend: SMTC controls playback, not
+// system or per-session volume - that's IAudioEndpointVolume/
+// ISimpleAudioVolume, a separate COM interface this PowerShell/WinRT script
+// approach can't reach.
+func (c *SMTCController) SetVolume(level int) (string, error) {
+	return "", fmt.Errorf("volume control is not supported on the SMTC backend")
+}
+
+func (c *SMTCController) NowPlaying() (string, error) {
+	sess, err := c.activeSession()
+	if err != nil {
+		return "", err
+	}
+	if sess == nil {
+		return "No music player is running", nil
+	}
+	if !strings.EqualFold(sess.Status, "Playing") {
+		return fmt.Sprintf("%s is paused", sess.AppID), nil
+	}
+	return fmt.Sprintf("Playing on %s: %s by %s from %s", sess.AppID, sess.Title, sess.Artist, sess.Album), nil
+}
+
+// NowPlayingInfo reports the active session's title/artist/album and
+// timeline as a structured NowPlaying. SMTC has no app-agnostic track ID,
+// artwork URL, or popularity, so those are left zero-valued.
+func (c *SMTCController) NowPlayingInfo() (*NowPlaying, error) {
+	sess, err := c.activeSession()
+	if err != nil {
+		return nil, err
+	}
+	if sess == nil || strings.EqualFold(sess.Status, "Stopped") {
+		return nil, nil
+	}
+	state := StatePaused
+	if strings.EqualFold(sess.Status, "Playing") {
+		state = StatePlaying
+	}
+	return &NowPlaying{
+		Source:      SMTCProviderName,
+		State:       state,
+		Track:       sess.Title,
+		Artist:      sess.Artist,
+		Album:       sess.Album,
+		AlbumArtist: sess.Artist,
+		DurationMs:  sess.DurationMs,
+		PositionMs:  sess.PositionMs,
+	}, nil
+}
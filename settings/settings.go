@@ -1,11 +1,15 @@
 package settings
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sync"
+
+	"super-characters/personas"
 )
 
 // DefaultSilenceDurationMs is the default silence duration for VAD
@@ -24,6 +28,108 @@ type Settings struct {
 	PipedreamClientSecret string `json:"pipedreamClientSecret"`
 	PipedreamProjectID    string `json:"pipedreamProjectId"`
 	PipedreamEnvironment  string `json:"pipedreamEnvironment"` // "development" or "production"
+
+	// SpotifyClientID is the PKCE client ID spotify.Service uses for its
+	// OAuth flow - see App.StartSpotifyAuth/CompleteSpotifyAuth.
+	SpotifyClientID string `json:"spotifyClientId"`
+	// MusicControlBackend selects how App's music-control methods
+	// (PlayPauseMusic, NextTrack, PreviousTrack, GetNowPlaying) reach the
+	// active player: "applescript" (or "mpris"/"smtc" depending on
+	// platform) always uses playercontrol.PlayerController, "spotify-api"
+	// always uses spotify.Service, and "" (auto) prefers the Spotify Web
+	// API when a user has authenticated and falls back to
+	// PlayerController otherwise.
+	MusicControlBackend string `json:"musicControlBackend"`
+
+	// AvatarBackend selects which avatar.Backend AvatarService.Initialize
+	// starts (e.g. "mediapipe-worker"). Empty means "use the default".
+	AvatarBackend string `json:"avatarBackend"`
+
+	// TTSProvider selects which tts.Provider is active in the app's
+	// tts.Registry (e.g. "elevenlabs", "piper"). Empty means "use the
+	// default".
+	TTSProvider string `json:"ttsProvider"`
+	// PiperModelPath is the .onnx voice model file used by the local Piper
+	// TTS provider.
+	PiperModelPath string `json:"piperModelPath"`
+
+	// LLMProvider selects which llm.Provider is active in the app's
+	// llm.Registry (e.g. "gemini", "openai", "anthropic", "ollama"). Empty
+	// means "use the default".
+	LLMProvider string `json:"llmProvider"`
+	// OpenAIAPIKey is shared by the OpenAI llm.Provider and the OpenAI
+	// tts.Provider, since OpenAI issues one API key for both.
+	OpenAIAPIKey string `json:"openaiApiKey"`
+	// AnthropicAPIKey is used by the Anthropic llm.Provider.
+	AnthropicAPIKey string `json:"anthropicApiKey"`
+	// OllamaBaseURL is the local Ollama server address used by the Ollama
+	// llm.Provider. Empty means "use llm.OllamaDefaultBaseURL".
+	OllamaBaseURL string `json:"ollamaBaseUrl"`
+
+	// STTProvider selects which stt.Recognizer is active (e.g. "gemini",
+	// "whisper-cpp"). Empty means "use the default".
+	STTProvider string `json:"sttProvider"`
+	// WhisperModelPath is the GGML model file used by the local
+	// whisper-cli-based STT recognizer.
+	WhisperModelPath string `json:"whisperModelPath"`
+	// STTLanguageOverrides maps an stt.Recognizer name to a BCP-47 language
+	// hint, for providers whose accuracy benefits from knowing the spoken
+	// language in advance. A provider with no entry auto-detects.
+	STTLanguageOverrides map[string]string `json:"sttLanguageOverrides"`
+
+	// WhisperImplementation selects which transcription.WhisperBackend the
+	// embedded TranscriptionService uses to run whisper (e.g. "whisper-cpp",
+	// "faster-whisper", "remote"). Empty means "use the default".
+	WhisperImplementation string `json:"whisperImplementation"`
+	// PreferredDevice pins the accelerator the active WhisperBackend should
+	// try first (one of transcription's DeviceXxx constants, e.g. "cuda",
+	// "metal", "cpu"), for a backend that implements
+	// transcription.DeviceSelector. Empty means "use the backend's own
+	// hardware-detected preference".
+	PreferredDevice string `json:"preferredDevice"`
+
+	// BargeInMode keeps VAD listening (at an elevated energy threshold)
+	// while the agent is speaking, so the user can interrupt it instead of
+	// waiting for it to finish. See App.onVADSpeechStart.
+	BargeInMode bool `json:"bargeInMode"`
+
+	// STTStreamingMode enables transcription.StreamingRecognizer-based
+	// continuous listening, which transcribes speech incrementally over a
+	// live stream instead of waiting for VAD to detect silence before
+	// transcribing the whole utterance.
+	STTStreamingMode bool `json:"sttStreamingMode"`
+	// GoogleSpeechCredentialsPath is the service account credentials file
+	// used by the Google Cloud Speech streaming recognizer.
+	GoogleSpeechCredentialsPath string `json:"googleSpeechCredentialsPath"`
+
+	// MetricsEnabled toggles serving the Prometheus /metrics endpoint.
+	MetricsEnabled bool `json:"metricsEnabled"`
+	// MetricsAuthToken, if set, is required as a basic-auth password (any
+	// username) on requests to /metrics. Empty means /metrics is
+	// unauthenticated - only safe on localhost.
+	MetricsAuthToken string `json:"metricsAuthToken"`
+
+	// ForwarderAuthToken, if set, is required from every forwarder client
+	// before it's admitted - see forwarder.Server.AuthToken. Empty means
+	// the forwarder is unauthenticated - only safe with
+	// ForwarderAllowLAN left false.
+	ForwarderAuthToken string `json:"forwarderAuthToken"`
+	// ForwarderAllowLAN opts the forwarder into binding all interfaces
+	// instead of loopback-only - see forwarder.Server.BindLAN.
+	ForwarderAllowLAN bool `json:"forwarderAllowLan"`
+
+	// AvatarMaxCount caps how many generated avatars AvatarService retains,
+	// evicting the oldest-unused entries once exceeded. Zero or negative
+	// means unlimited.
+	AvatarMaxCount int `json:"avatarMaxCount"`
+	// AvatarMaxDiskBytes caps total disk usage across generated avatars,
+	// evicting the oldest-unused entries once exceeded. Zero or negative
+	// means unlimited.
+	AvatarMaxDiskBytes int64 `json:"avatarMaxDiskBytes"`
+
+	// Personas are saved system-prompt/voice presets selectable per
+	// conversation (see App.StartConversationWithPersona).
+	Personas []personas.Persona `json:"personas"`
 }
 
 // SettingsService manages persistent settings storage.
@@ -184,6 +290,373 @@ func (s *SettingsService) GetSilenceDurationMs() int {
 	return s.settings.SilenceDurationMs
 }
 
+// SetAvatarBackend updates which avatar generation backend to use.
+func (s *SettingsService) SetAvatarBackend(backend string) error {
+	s.mu.Lock()
+	s.settings.AvatarBackend = backend
+	s.mu.Unlock()
+
+	return s.save()
+}
+
+// GetAvatarBackend returns the configured avatar backend name. An empty
+// string means AvatarService.Initialize should use its default backend.
+func (s *SettingsService) GetAvatarBackend() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.settings.AvatarBackend
+}
+
+// SetTTSProvider updates which TTS provider the app's tts.Registry should
+// use.
+func (s *SettingsService) SetTTSProvider(provider string) error {
+	s.mu.Lock()
+	s.settings.TTSProvider = provider
+	s.mu.Unlock()
+
+	return s.save()
+}
+
+// GetTTSProvider returns the configured TTS provider name. An empty string
+// means the tts.Registry should use its default provider.
+func (s *SettingsService) GetTTSProvider() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.settings.TTSProvider
+}
+
+// SetLLMProvider updates which LLM provider the app's llm.Registry should
+// use.
+func (s *SettingsService) SetLLMProvider(provider string) error {
+	s.mu.Lock()
+	s.settings.LLMProvider = provider
+	s.mu.Unlock()
+
+	return s.save()
+}
+
+// GetLLMProvider returns the configured LLM provider name. An empty string
+// means the llm.Registry should use its default provider.
+func (s *SettingsService) GetLLMProvider() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.settings.LLMProvider
+}
+
+// SetOpenAIAPIKey updates the OpenAI API key, shared by the OpenAI LLM and
+// TTS providers.
+func (s *SettingsService) SetOpenAIAPIKey(key string) error {
+	s.mu.Lock()
+	s.settings.OpenAIAPIKey = key
+	s.mu.Unlock()
+
+	return s.save()
+}
+
+// GetOpenAIAPIKey returns the configured OpenAI API key.
+func (s *SettingsService) GetOpenAIAPIKey() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.settings.OpenAIAPIKey
+}
+
+// SetAnthropicAPIKey updates the Anthropic API key.
+func (s *SettingsService) SetAnthropicAPIKey(key string) error {
+	s.mu.Lock()
+	s.settings.AnthropicAPIKey = key
+	s.mu.Unlock()
+
+	return s.save()
+}
+
+// GetAnthropicAPIKey returns the configured Anthropic API key.
+func (s *SettingsService) GetAnthropicAPIKey() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.settings.AnthropicAPIKey
+}
+
+// SetOllamaBaseURL updates the local Ollama server address.
+func (s *SettingsService) SetOllamaBaseURL(baseURL string) error {
+	s.mu.Lock()
+	s.settings.OllamaBaseURL = baseURL
+	s.mu.Unlock()
+
+	return s.save()
+}
+
+// GetOllamaBaseURL returns the configured Ollama server address.
+func (s *SettingsService) GetOllamaBaseURL() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.settings.OllamaBaseURL
+}
+
+// SetPiperModelPath updates the Piper voice model file path.
+func (s *SettingsService) SetPiperModelPath(path string) error {
+	s.mu.Lock()
+	s.settings.PiperModelPath = path
+	s.mu.Unlock()
+
+	return s.save()
+}
+
+// GetPiperModelPath returns the configured Piper voice model file path.
+func (s *SettingsService) GetPiperModelPath() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.settings.PiperModelPath
+}
+
+// SetSTTProvider updates which STT recognizer is active.
+func (s *SettingsService) SetSTTProvider(provider string) error {
+	s.mu.Lock()
+	s.settings.STTProvider = provider
+	s.mu.Unlock()
+
+	return s.save()
+}
+
+// GetSTTProvider returns the configured STT provider name. An empty string
+// means "use the default recognizer".
+func (s *SettingsService) GetSTTProvider() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.settings.STTProvider
+}
+
+// SetWhisperImplementation updates which transcription.WhisperBackend the
+// embedded TranscriptionService uses.
+func (s *SettingsService) SetWhisperImplementation(name string) error {
+	s.mu.Lock()
+	s.settings.WhisperImplementation = name
+	s.mu.Unlock()
+
+	return s.save()
+}
+
+// GetWhisperImplementation returns the configured whisper backend name. An
+// empty string means "use the default backend".
+func (s *SettingsService) GetWhisperImplementation() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.settings.WhisperImplementation
+}
+
+// SetPreferredDevice updates which accelerator the active WhisperBackend
+// should try first.
+func (s *SettingsService) SetPreferredDevice(device string) error {
+	s.mu.Lock()
+	s.settings.PreferredDevice = device
+	s.mu.Unlock()
+
+	return s.save()
+}
+
+// GetPreferredDevice returns the configured preferred device. An empty
+// string means "use the backend's own hardware-detected preference".
+func (s *SettingsService) GetPreferredDevice() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.settings.PreferredDevice
+}
+
+// SetWhisperModelPath updates the GGML model file path used by the local
+// whisper-cli-based STT recognizer.
+func (s *SettingsService) SetWhisperModelPath(path string) error {
+	s.mu.Lock()
+	s.settings.WhisperModelPath = path
+	s.mu.Unlock()
+
+	return s.save()
+}
+
+// GetWhisperModelPath returns the configured Whisper model file path.
+func (s *SettingsService) GetWhisperModelPath() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.settings.WhisperModelPath
+}
+
+// SetSTTLanguageOverride sets the language hint used for the named STT
+// provider. An empty lang removes the override (auto-detect).
+func (s *SettingsService) SetSTTLanguageOverride(provider string, lang string) error {
+	s.mu.Lock()
+	if lang == "" {
+		delete(s.settings.STTLanguageOverrides, provider)
+	} else {
+		if s.settings.STTLanguageOverrides == nil {
+			s.settings.STTLanguageOverrides = make(map[string]string)
+		}
+		s.settings.STTLanguageOverrides[provider] = lang
+	}
+	s.mu.Unlock()
+
+	return s.save()
+}
+
+// GetSTTLanguageOverride returns the configured language hint for the named
+// STT provider, or "" if none is set (auto-detect).
+func (s *SettingsService) GetSTTLanguageOverride(provider string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.settings.STTLanguageOverrides[provider]
+}
+
+// SetBargeInMode toggles whether the agent's speech can be interrupted by
+// the user talking over it.
+func (s *SettingsService) SetBargeInMode(enabled bool) error {
+	s.mu.Lock()
+	s.settings.BargeInMode = enabled
+	s.mu.Unlock()
+
+	return s.save()
+}
+
+// GetBargeInMode returns whether barge-in is enabled.
+func (s *SettingsService) GetBargeInMode() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.settings.BargeInMode
+}
+
+// SetSTTStreamingMode toggles streaming (incremental) transcription for
+// continuous listening.
+func (s *SettingsService) SetSTTStreamingMode(enabled bool) error {
+	s.mu.Lock()
+	s.settings.STTStreamingMode = enabled
+	s.mu.Unlock()
+
+	return s.save()
+}
+
+// GetSTTStreamingMode returns whether streaming transcription is enabled.
+func (s *SettingsService) GetSTTStreamingMode() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.settings.STTStreamingMode
+}
+
+// SetGoogleSpeechCredentialsPath updates the service account credentials
+// file used by the Google Cloud Speech streaming recognizer.
+func (s *SettingsService) SetGoogleSpeechCredentialsPath(path string) error {
+	s.mu.Lock()
+	s.settings.GoogleSpeechCredentialsPath = path
+	s.mu.Unlock()
+
+	return s.save()
+}
+
+// GetGoogleSpeechCredentialsPath returns the configured Google Cloud Speech
+// credentials file path.
+func (s *SettingsService) GetGoogleSpeechCredentialsPath() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.settings.GoogleSpeechCredentialsPath
+}
+
+// SetMetricsEnabled toggles whether the /metrics endpoint is served.
+func (s *SettingsService) SetMetricsEnabled(enabled bool) error {
+	s.mu.Lock()
+	s.settings.MetricsEnabled = enabled
+	s.mu.Unlock()
+
+	return s.save()
+}
+
+// GetMetricsEnabled returns whether the /metrics endpoint should be served.
+func (s *SettingsService) GetMetricsEnabled() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.settings.MetricsEnabled
+}
+
+// SetMetricsAuthToken updates the basic-auth token required on /metrics
+// requests. An empty token disables authentication.
+func (s *SettingsService) SetMetricsAuthToken(token string) error {
+	s.mu.Lock()
+	s.settings.MetricsAuthToken = token
+	s.mu.Unlock()
+
+	return s.save()
+}
+
+// GetMetricsAuthToken returns the configured /metrics basic-auth token.
+func (s *SettingsService) GetMetricsAuthToken() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.settings.MetricsAuthToken
+}
+
+// SetForwarderAuthToken updates the token required from forwarder clients.
+// An empty token disables authentication.
+func (s *SettingsService) SetForwarderAuthToken(token string) error {
+	s.mu.Lock()
+	s.settings.ForwarderAuthToken = token
+	s.mu.Unlock()
+
+	return s.save()
+}
+
+// GetForwarderAuthToken returns the configured forwarder auth token.
+func (s *SettingsService) GetForwarderAuthToken() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.settings.ForwarderAuthToken
+}
+
+// SetForwarderAllowLAN toggles whether the forwarder binds all interfaces
+// instead of loopback-only.
+func (s *SettingsService) SetForwarderAllowLAN(allow bool) error {
+	s.mu.Lock()
+	s.settings.ForwarderAllowLAN = allow
+	s.mu.Unlock()
+
+	return s.save()
+}
+
+// GetForwarderAllowLAN returns whether the forwarder should bind all
+// interfaces instead of loopback-only.
+func (s *SettingsService) GetForwarderAllowLAN() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.settings.ForwarderAllowLAN
+}
+
+// SetAvatarMaxCount updates the maximum number of retained avatars.
+func (s *SettingsService) SetAvatarMaxCount(maxCount int) error {
+	s.mu.Lock()
+	s.settings.AvatarMaxCount = maxCount
+	s.mu.Unlock()
+
+	return s.save()
+}
+
+// GetAvatarMaxCount returns the configured avatar retention count limit.
+func (s *SettingsService) GetAvatarMaxCount() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.settings.AvatarMaxCount
+}
+
+// SetAvatarMaxDiskBytes updates the maximum total disk usage for retained
+// avatars.
+func (s *SettingsService) SetAvatarMaxDiskBytes(maxBytes int64) error {
+	s.mu.Lock()
+	s.settings.AvatarMaxDiskBytes = maxBytes
+	s.mu.Unlock()
+
+	return s.save()
+}
+
+// GetAvatarMaxDiskBytes returns the configured avatar retention disk-usage
+// limit.
+func (s *SettingsService) GetAvatarMaxDiskBytes() int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.settings.AvatarMaxDiskBytes
+}
+
 // SetPipedreamClientID updates the Pipedream client ID.
 func (s *SettingsService) SetPipedreamClientID(clientID string) error {
 	s.mu.Lock()
@@ -243,3 +716,136 @@ func (s *SettingsService) IsPipedreamConfigured() bool {
 		s.settings.PipedreamClientSecret != "" &&
 		s.settings.PipedreamProjectID != ""
 }
+
+// SetSpotifyClientID updates the Spotify PKCE client ID.
+func (s *SettingsService) SetSpotifyClientID(clientID string) error {
+	s.mu.Lock()
+	s.settings.SpotifyClientID = clientID
+	s.mu.Unlock()
+
+	return s.save()
+}
+
+// GetSpotifyClientID returns the configured Spotify client ID.
+func (s *SettingsService) GetSpotifyClientID() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.settings.SpotifyClientID
+}
+
+// SetMusicControlBackend updates which backend App's music-control methods
+// use. An empty string means "auto".
+func (s *SettingsService) SetMusicControlBackend(backend string) error {
+	s.mu.Lock()
+	s.settings.MusicControlBackend = backend
+	s.mu.Unlock()
+
+	return s.save()
+}
+
+// GetMusicControlBackend returns the configured music-control backend. An
+// empty string means "auto".
+func (s *SettingsService) GetMusicControlBackend() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.settings.MusicControlBackend
+}
+
+// GetPersonas returns every saved persona.
+func (s *SettingsService) GetPersonas() []personas.Persona {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]personas.Persona, len(s.settings.Personas))
+	copy(out, s.settings.Personas)
+	return out
+}
+
+// GetPersona returns the persona with the given ID, if one exists.
+func (s *SettingsService) GetPersona(id string) (personas.Persona, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, p := range s.settings.Personas {
+		if p.ID == id {
+			return p, true
+		}
+	}
+	return personas.Persona{}, false
+}
+
+// AddPersona saves a new persona, assigning it an ID if one wasn't
+// provided, and returns the saved persona.
+func (s *SettingsService) AddPersona(p personas.Persona) (personas.Persona, error) {
+	if p.Name == "" {
+		return personas.Persona{}, fmt.Errorf("persona name is required")
+	}
+
+	s.mu.Lock()
+	if p.ID == "" {
+		id, err := newPersonaID()
+		if err != nil {
+			s.mu.Unlock()
+			return personas.Persona{}, err
+		}
+		p.ID = id
+	}
+	for _, existing := range s.settings.Personas {
+		if existing.ID == p.ID {
+			s.mu.Unlock()
+			return personas.Persona{}, fmt.Errorf("persona %q already exists", p.ID)
+		}
+	}
+	s.settings.Personas = append(s.settings.Personas, p)
+	s.mu.Unlock()
+
+	if err := s.save(); err != nil {
+		return personas.Persona{}, err
+	}
+	return p, nil
+}
+
+// UpdatePersona replaces the persona with the same ID as p.
+func (s *SettingsService) UpdatePersona(p personas.Persona) error {
+	s.mu.Lock()
+	found := false
+	for i, existing := range s.settings.Personas {
+		if existing.ID == p.ID {
+			s.settings.Personas[i] = p
+			found = true
+			break
+		}
+	}
+	s.mu.Unlock()
+
+	if !found {
+		return fmt.Errorf("persona %q not found", p.ID)
+	}
+	return s.save()
+}
+
+// DeletePersona removes the persona with the given ID.
+func (s *SettingsService) DeletePersona(id string) error {
+	s.mu.Lock()
+	found := false
+	for i, existing := range s.settings.Personas {
+		if existing.ID == id {
+			s.settings.Personas = append(s.settings.Personas[:i], s.settings.Personas[i+1:]...)
+			found = true
+			break
+		}
+	}
+	s.mu.Unlock()
+
+	if !found {
+		return fmt.Errorf("persona %q not found", id)
+	}
+	return s.save()
+}
+
+// newPersonaID generates a random ID for a persona that wasn't given one.
+func newPersonaID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate persona ID: %w", err)
+	}
+	return "persona_" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,78 @@
+package spotify
+
+const (
+	// AuthURL is Spotify's OAuth 2.0 authorization endpoint.
+	AuthURL = "https://accounts.spotify.com/authorize"
+	// TokenURL is Spotify's OAuth 2.0 token endpoint, used both to exchange
+	// an authorization code and to refresh an access token.
+	TokenURL = "https://accounts.spotify.com/api/token"
+	// APIBaseURL is the Spotify Web API endpoint.
+	APIBaseURL = "https://api.spotify.com/v1"
+
+	// DefaultRedirectURI is the loopback redirect used by the PKCE flow.
+	// Spotify requires this to be registered on the app's dashboard exactly.
+	DefaultRedirectURI = "http://127.0.0.1:8888/callback"
+
+	// Scopes requests the permissions SearchAndPlay, AddToQueue,
+	// PlayPlaylist, ListDevices, TransferPlayback, and NowPlaying/PlayPause/
+	// Next/Previous need.
+	Scopes = "user-read-playback-state user-modify-playback-state user-read-currently-playing playlist-read-private"
+)
+
+// Config holds the Spotify API credentials. Spotify's PKCE flow needs only
+// a client ID - no client secret - since the code verifier takes its place
+// in proving the token exchange came from this app.
+type Config struct {
+	ClientID    string `json:"clientId"`
+	RedirectURI string `json:"redirectUri"`
+}
+
+// DefaultConfig returns the default Spotify configuration.
+func DefaultConfig() Config {
+	return Config{RedirectURI: DefaultRedirectURI}
+}
+
+// IsConfigured returns whether a client ID has been set.
+func (c Config) IsConfigured() bool {
+	return c.ClientID != ""
+}
+
+// Track is the subset of a Spotify track object NowPlayingInfo and
+// SearchAndPlay care about.
+type Track struct {
+	ID         string `json:"id"`
+	URI        string `json:"uri"`
+	Name       string `json:"name"`
+	Artist     string `json:"artist"`
+	Album      string `json:"album"`
+	DurationMs int    `json:"durationMs"`
+	Popularity int    `json:"popularity"`
+	ArtworkURL string `json:"artworkUrl"`
+}
+
+// NowPlayingInfo describes the current playback state, richer than what
+// AppleScript/MPRIS/SMTC can report - it carries the track ID, exact
+// duration/progress, popularity, and artwork URL alongside the usual
+// title/artist/album.
+type NowPlayingInfo struct {
+	Track
+	IsPlaying  bool `json:"isPlaying"`
+	ProgressMs int  `json:"progressMs"`
+}
+
+// Device is a Spotify Connect playback device, as returned by ListDevices.
+type Device struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	Type          string `json:"type"`
+	IsActive      bool   `json:"isActive"`
+	VolumePercent int    `json:"volumePercent"`
+}
+
+// SearchResult is a single match from SearchAndPlay or PlayPlaylist's
+// internal playlist-name lookup.
+type SearchResult struct {
+	URI  string `json:"uri"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
@@ -0,0 +1,597 @@
+// Package spotify talks to the Spotify Web API via OAuth 2.0 PKCE, giving
+// App's music-control methods (see player_control.go and spotify_control.go
+// in the main package) a richer, cross-platform alternative to the
+// AppleScript/MPRIS/SMTC playercontrol.PlayerController backends: track ID,
+// exact duration/progress, popularity, artwork URL, search, queueing, and
+// playback-device transfer, none of which a desktop player's scripting
+// surface exposes.
+package spotify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Service holds Spotify API credentials and the cached OAuth token, and
+// drives the Web API on their behalf.
+type Service struct {
+	config          Config
+	client          *http.Client
+	tokenPath       string
+	mu              sync.RWMutex
+	tok             *token
+	pendingVerifier string
+}
+
+// NewService creates a Service whose OAuth token is cached under
+// cacheDir/spotify_token.json - cacheDir should be the same
+// ~/.super-characters directory settings.SettingsService and history.Store
+// use. Load failures (including "no cache yet") leave the service
+// unauthenticated rather than erroring, matching how SettingsService.load
+// tolerates a missing file.
+func NewService(cacheDir string) *Service {
+	s := &Service{
+		config:    DefaultConfig(),
+		client:    &http.Client{Timeout: 15 * time.Second},
+		tokenPath: filepath.Join(cacheDir, TokenCacheFileName),
+	}
+	if t, err := loadToken(s.tokenPath); err == nil {
+		s.tok = t
+	}
+	return s
+}
+
+// Configure sets the Spotify client ID/redirect URI.
+func (s *Service) Configure(config Config) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if config.RedirectURI == "" {
+		config.RedirectURI = DefaultRedirectURI
+	}
+	s.config = config
+}
+
+// GetConfig returns the current configuration.
+func (s *Service) GetConfig() Config {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.config
+}
+
+// IsConfigured returns whether a client ID has been set.
+func (s *Service) IsConfigured() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.config.IsConfigured()
+}
+
+// IsAuthenticated returns whether a user has completed the PKCE flow and a
+// refresh token is on file.
+func (s *Service) IsAuthenticated() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tok != nil && s.tok.RefreshToken != ""
+}
+
+// StartAuth begins the PKCE flow: it generates and remembers a code
+// verifier, then returns the authorization URL the user should open in a
+// browser. CompleteAuth finishes the flow once Spotify redirects back with
+// a code.
+func (s *Service) StartAuth() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.config.IsConfigured() {
+		return "", fmt.Errorf("spotify client ID not configured")
+	}
+
+	verifier, err := generateCodeVerifier()
+	if err != nil {
+		return "", err
+	}
+	s.pendingVerifier = verifier
+
+	q := url.Values{}
+	q.Set("client_id", s.config.ClientID)
+	q.Set("response_type", "code")
+	q.Set("redirect_uri", s.config.RedirectURI)
+	q.Set("code_challenge_method", "S256")
+	q.Set("code_challenge", codeChallenge(verifier))
+	q.Set("scope", Scopes)
+
+	return AuthURL + "?" + q.Encode(), nil
+}
+
+// CompleteAuth exchanges an authorization code (lifted from the redirect
+// Spotify sends to Config.RedirectURI) for an access/refresh token pair,
+// using the code verifier StartAuth generated, and caches the result to
+// disk.
+func (s *Service) CompleteAuth(code string) error {
+	s.mu.Lock()
+	verifier := s.pendingVerifier
+	clientID := s.config.ClientID
+	redirectURI := s.config.RedirectURI
+	s.mu.Unlock()
+
+	if verifier == "" {
+		return fmt.Errorf("no spotify auth flow in progress - call StartAuth first")
+	}
+
+	data := url.Values{}
+	data.Set("grant_type", "authorization_code")
+	data.Set("code", code)
+	data.Set("redirect_uri", redirectURI)
+	data.Set("client_id", clientID)
+	data.Set("code_verifier", verifier)
+
+	t, err := s.requestToken(data)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.tok = t
+	s.pendingVerifier = ""
+	path := s.tokenPath
+	s.mu.Unlock()
+
+	return saveToken(path, t)
+}
+
+// requestToken POSTs data to TokenURL and parses the resulting token.
+func (s *Service) requestToken(data url.Values) (*token, error) {
+	req, err := http.NewRequest("POST", TokenURL, bytes.NewBufferString(data.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request spotify token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("spotify token request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var parsed struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int    `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to decode spotify token response: %w", err)
+	}
+
+	t := &token{
+		AccessToken: parsed.AccessToken,
+		ExpiresAt:   time.Now().Add(time.Duration(parsed.ExpiresIn) * time.Second),
+	}
+	if parsed.RefreshToken != "" {
+		// A refresh-token-grant response omits refresh_token when Spotify
+		// isn't rotating it - accessToken then keeps the one we already have.
+		t.RefreshToken = parsed.RefreshToken
+	}
+	return t, nil
+}
+
+// accessToken returns a valid access token, refreshing it first if expired.
+func (s *Service) accessToken() (string, error) {
+	s.mu.RLock()
+	tok := s.tok
+	clientID := s.config.ClientID
+	s.mu.RUnlock()
+
+	if tok == nil {
+		return "", fmt.Errorf("spotify not authenticated - call StartAuth/CompleteAuth first")
+	}
+	if !tok.expired() {
+		return tok.AccessToken, nil
+	}
+
+	data := url.Values{}
+	data.Set("grant_type", "refresh_token")
+	data.Set("refresh_token", tok.RefreshToken)
+	data.Set("client_id", clientID)
+
+	refreshed, err := s.requestToken(data)
+	if err != nil {
+		return "", fmt.Errorf("failed to refresh spotify token: %w", err)
+	}
+	if refreshed.RefreshToken == "" {
+		refreshed.RefreshToken = tok.RefreshToken
+	}
+
+	s.mu.Lock()
+	s.tok = refreshed
+	path := s.tokenPath
+	s.mu.Unlock()
+
+	if err := saveToken(path, refreshed); err != nil {
+		return "", err
+	}
+	return refreshed.AccessToken, nil
+}
+
+// apiRequest calls method on APIBaseURL+path with an authenticated request,
+// optionally sending body as JSON. A nil body sends no request body.
+func (s *Service) apiRequest(method, path string, query url.Values, body interface{}) (*http.Response, error) {
+	accessToken, err := s.accessToken()
+	if err != nil {
+		return nil, err
+	}
+
+	reqURL := APIBaseURL + path
+	if len(query) > 0 {
+		reqURL += "?" + query.Encode()
+	}
+
+	var reqBody io.Reader
+	if body != nil {
+		jsonBody, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reqBody = bytes.NewBuffer(jsonBody)
+	}
+
+	req, err := http.NewRequest(method, reqURL, reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call spotify API: %w", err)
+	}
+	return resp, nil
+}
+
+// spotifyError reads and formats a non-2xx response body, closing resp.Body.
+func spotifyError(resp *http.Response) error {
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return fmt.Errorf("spotify API returned status %d: %s", resp.StatusCode, string(body))
+}
+
+// playbackState is the subset of GET /me/player this package reads from.
+type playbackState struct {
+	IsPlaying  bool `json:"is_playing"`
+	ProgressMs int  `json:"progress_ms"`
+	Item       struct {
+		ID         string `json:"id"`
+		URI        string `json:"uri"`
+		Name       string `json:"name"`
+		DurationMs int    `json:"duration_ms"`
+		Popularity int    `json:"popularity"`
+		Artists    []struct {
+			Name string `json:"name"`
+		} `json:"artists"`
+		Album struct {
+			Name   string `json:"name"`
+			Images []struct {
+				URL string `json:"url"`
+			} `json:"images"`
+		} `json:"album"`
+	} `json:"item"`
+}
+
+// toNowPlaying converts a playbackState into the public NowPlayingInfo
+// shape.
+func (p *playbackState) toNowPlaying() *NowPlayingInfo {
+	info := &NowPlayingInfo{
+		IsPlaying:  p.IsPlaying,
+		ProgressMs: p.ProgressMs,
+	}
+	info.ID = p.Item.ID
+	info.URI = p.Item.URI
+	info.Name = p.Item.Name
+	info.DurationMs = p.Item.DurationMs
+	info.Popularity = p.Item.Popularity
+	info.Album = p.Item.Album.Name
+	if len(p.Item.Artists) > 0 {
+		info.Artist = p.Item.Artists[0].Name
+	}
+	if len(p.Item.Album.Images) > 0 {
+		info.ArtworkURL = p.Item.Album.Images[0].URL
+	}
+	return info
+}
+
+// getPlaybackState fetches the full current playback state, or nil if
+// nothing is active (Spotify returns 204 No Content in that case).
+func (s *Service) getPlaybackState() (*playbackState, error) {
+	resp, err := s.apiRequest("GET", "/me/player", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNoContent {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, spotifyError(resp)
+	}
+
+	var state playbackState
+	if err := json.NewDecoder(resp.Body).Decode(&state); err != nil {
+		return nil, fmt.Errorf("failed to decode spotify playback state: %w", err)
+	}
+	return &state, nil
+}
+
+// NowPlaying reports the current track, progress, and playing state, or nil
+// if nothing is playing.
+func (s *Service) NowPlaying() (*NowPlayingInfo, error) {
+	state, err := s.getPlaybackState()
+	if err != nil {
+		return nil, err
+	}
+	if state == nil || state.Item.ID == "" {
+		return nil, nil
+	}
+	return state.toNowPlaying(), nil
+}
+
+// PlayPause toggles playback: pauses if currently playing, resumes
+// otherwise. It returns the new playing state.
+func (s *Service) PlayPause() (bool, error) {
+	state, err := s.getPlaybackState()
+	if err != nil {
+		return false, err
+	}
+
+	endpoint := "/me/player/play"
+	if state != nil && state.IsPlaying {
+		endpoint = "/me/player/pause"
+	}
+
+	resp, err := s.apiRequest("PUT", endpoint, nil, nil)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return false, spotifyError(resp)
+	}
+	return endpoint == "/me/player/play", nil
+}
+
+// Next skips to the next track.
+func (s *Service) Next() error {
+	resp, err := s.apiRequest("POST", "/me/player/next", nil, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return spotifyError(resp)
+	}
+	return nil
+}
+
+// Previous returns to the previous track.
+func (s *Service) Previous() error {
+	resp, err := s.apiRequest("POST", "/me/player/previous", nil, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return spotifyError(resp)
+	}
+	return nil
+}
+
+// searchTypes maps the kind argument SearchAndPlay accepts to Spotify's
+// search "type" query parameter and the field its response nests results
+// under.
+var searchTypes = map[string]string{
+	"track":    "track",
+	"album":    "album",
+	"artist":   "artist",
+	"playlist": "playlist",
+}
+
+// search runs a Spotify search for query restricted to kind, returning the
+// single best match.
+func (s *Service) search(query, kind string) (*SearchResult, error) {
+	searchType, ok := searchTypes[kind]
+	if !ok {
+		return nil, fmt.Errorf("unsupported search kind %q (want track, album, artist, or playlist)", kind)
+	}
+
+	q := url.Values{}
+	q.Set("q", query)
+	q.Set("type", searchType)
+	q.Set("limit", "1")
+
+	resp, err := s.apiRequest("GET", "/search", q, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, spotifyError(resp)
+	}
+
+	var parsed map[string]struct {
+		Items []struct {
+			URI  string `json:"uri"`
+			Name string `json:"name"`
+		} `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("failed to decode spotify search response: %w", err)
+	}
+
+	items := parsed[searchType+"s"].Items
+	if len(items) == 0 {
+		return nil, fmt.Errorf("no %s found matching %q", kind, query)
+	}
+	return &SearchResult{URI: items[0].URI, Name: items[0].Name, Type: kind}, nil
+}
+
+// play starts playback of either a list of track URIs or a single context
+// URI (an album, artist, or playlist), on whichever device is currently
+// active.
+func (s *Service) play(body map[string]interface{}) error {
+	resp, err := s.apiRequest("PUT", "/me/player/play", nil, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return spotifyError(resp)
+	}
+	return nil
+}
+
+// SearchAndPlay searches for query restricted to kind (track, album,
+// artist, or playlist) and starts playing the best match.
+func (s *Service) SearchAndPlay(query, kind string) (*SearchResult, error) {
+	result, err := s.search(query, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	var body map[string]interface{}
+	if kind == "track" {
+		body = map[string]interface{}{"uris": []string{result.URI}}
+	} else {
+		body = map[string]interface{}{"context_uri": result.URI}
+	}
+	if err := s.play(body); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// AddToQueue appends a track (by Spotify URI) to the playback queue.
+func (s *Service) AddToQueue(uri string) error {
+	q := url.Values{}
+	q.Set("uri", uri)
+
+	resp, err := s.apiRequest("POST", "/me/player/queue", q, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return spotifyError(resp)
+	}
+	return nil
+}
+
+// playlistPageLimit bounds how many of the user's playlists PlayPlaylist
+// scans looking for a name match - one page is enough for the vast
+// majority of libraries, and this is a convenience lookup, not a full
+// library browser.
+const playlistPageLimit = 50
+
+// PlayPlaylist finds the current user's playlist whose name matches name
+// (case-insensitively) and starts playing it.
+func (s *Service) PlayPlaylist(name string) (*SearchResult, error) {
+	q := url.Values{}
+	q.Set("limit", fmt.Sprintf("%d", playlistPageLimit))
+
+	resp, err := s.apiRequest("GET", "/me/playlists", q, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, spotifyError(resp)
+	}
+
+	var parsed struct {
+		Items []struct {
+			URI  string `json:"uri"`
+			Name string `json:"name"`
+		} `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("failed to decode spotify playlists response: %w", err)
+	}
+
+	for _, item := range parsed.Items {
+		if strings.EqualFold(item.Name, name) {
+			result := &SearchResult{URI: item.URI, Name: item.Name, Type: "playlist"}
+			if err := s.play(map[string]interface{}{"context_uri": result.URI}); err != nil {
+				return nil, err
+			}
+			return result, nil
+		}
+	}
+	return nil, fmt.Errorf("no playlist found matching %q", name)
+}
+
+// ListDevices returns the user's available Spotify Connect devices.
+func (s *Service) ListDevices() ([]Device, error) {
+	resp, err := s.apiRequest("GET", "/me/player/devices", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, spotifyError(resp)
+	}
+
+	var parsed struct {
+		Devices []struct {
+			ID            string `json:"id"`
+			Name          string `json:"name"`
+			Type          string `json:"type"`
+			IsActive      bool   `json:"is_active"`
+			VolumePercent int    `json:"volume_percent"`
+		} `json:"devices"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("failed to decode spotify devices response: %w", err)
+	}
+
+	devices := make([]Device, 0, len(parsed.Devices))
+	for _, d := range parsed.Devices {
+		devices = append(devices, Device{
+			ID:            d.ID,
+			Name:          d.Name,
+			Type:          d.Type,
+			IsActive:      d.IsActive,
+			VolumePercent: d.VolumePercent,
+		})
+	}
+	return devices, nil
+}
+
+// TransferPlayback moves playback to the device identified by deviceID.
+func (s *Service) TransferPlayback(deviceID string) error {
+	body := map[string]interface{}{
+		"device_ids": []string{deviceID},
+		"play":       true,
+	}
+	resp, err := s.apiRequest("PUT", "/me/player", nil, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return spotifyError(resp)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package spotify
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// TokenCacheFileName is the file Service persists its OAuth token to, under
+// the same ~/.super-characters config directory settings.SettingsService
+// and history.Store use.
+const TokenCacheFileName = "spotify_token.json"
+
+// token is the cached OAuth state - an access token, its refresh token, and
+// when it expires.
+type token struct {
+	AccessToken  string    `json:"accessToken"`
+	RefreshToken string    `json:"refreshToken"`
+	ExpiresAt    time.Time `json:"expiresAt"`
+}
+
+// expired reports whether t's access token needs refreshing, with a
+// one-minute buffer to avoid racing the actual expiry.
+func (t *token) expired() bool {
+	return t == nil || time.Now().Add(time.Minute).After(t.ExpiresAt)
+}
+
+// loadToken reads a cached token from path. A missing file is not an error:
+// it just means no one has authenticated yet.
+func loadToken(path string) (*token, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read token cache: %w", err)
+	}
+	var t token
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("failed to parse token cache: %w", err)
+	}
+	return &t, nil
+}
+
+// saveToken persists t to path.
+func saveToken(path string, t *token) error {
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal token cache: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write token cache: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package main
+
+import "super-characters/spotify"
+
+// #region Spotify Control API
+//
+// These methods drive spotify.Service directly - the Spotify-only
+// capabilities (search, queueing, playlist-by-name, device transfer) that
+// have no AppleScript/MPRIS/SMTC equivalent. See player_control.go for how
+// PlayPauseMusic/NextTrack/PreviousTrack/GetNowPlaying choose between this
+// and a.playerController.
+
+// StartSpotifyAuth begins the Spotify PKCE login flow and returns the
+// authorization URL to open in a browser. Call CompleteSpotifyAuth once
+// Spotify redirects back to Settings.SpotifyClientID's registered redirect
+// URI with a "code" query parameter.
+func (a *App) StartSpotifyAuth() (string, error) {
+	return a.spotifyService.StartAuth()
+}
+
+// CompleteSpotifyAuth finishes the PKCE flow given the authorization code
+// from the redirect, caching the resulting token to disk.
+func (a *App) CompleteSpotifyAuth(code string) error {
+	return a.spotifyService.CompleteAuth(code)
+}
+
+// IsSpotifyAuthenticated reports whether a Spotify account is connected.
+func (a *App) IsSpotifyAuthenticated() bool {
+	return a.spotifyService.IsAuthenticated()
+}
+
+// SetMusicControlBackend updates which backend PlayPauseMusic/NextTrack/
+// PreviousTrack/GetNowPlaying use - "applescript" (or the platform's native
+// PlayerController backend), "spotify-api", or "" for auto. Returns an
+// error string, or "" on success.
+func (a *App) SetMusicControlBackend(backend string) string {
+	if a.settingsService == nil {
+		return "settings service unavailable"
+	}
+	if err := a.settingsService.SetMusicControlBackend(backend); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+// GetMusicControlBackend returns the configured music-control backend.
+func (a *App) GetMusicControlBackend() string {
+	if a.settingsService == nil {
+		return ""
+	}
+	return a.settingsService.GetMusicControlBackend()
+}
+
+// SearchAndPlay searches the Spotify catalog for query restricted to kind
+// (track, album, artist, or playlist) and starts playing the best match.
+func (a *App) SearchAndPlay(query, kind string) (*spotify.SearchResult, error) {
+	return a.spotifyService.SearchAndPlay(query, kind)
+}
+
+// AddToQueue appends a track (by Spotify URI) to the playback queue.
+func (a *App) AddToQueue(uri string) error {
+	return a.spotifyService.AddToQueue(uri)
+}
+
+// PlayPlaylist finds the current user's playlist whose name matches name
+// and starts playing it.
+func (a *App) PlayPlaylist(name string) (*spotify.SearchResult, error) {
+	return a.spotifyService.PlayPlaylist(name)
+}
+
+// ListSpotifyDevices returns the user's available Spotify Connect devices -
+// distinct from App.ListDevices, which lists transcription accelerators.
+func (a *App) ListSpotifyDevices() ([]spotify.Device, error) {
+	return a.spotifyService.ListDevices()
+}
+
+// TransferPlayback moves Spotify playback to the device identified by
+// deviceID (see ListSpotifyDevices).
+func (a *App) TransferPlayback(deviceID string) error {
+	return a.spotifyService.TransferPlayback(deviceID)
+}
+
+// #endregion Spotify Control API
@@ -0,0 +1,45 @@
+package stt
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"super-characters/gemini"
+)
+
+// ProviderName is the name GeminiRecognizer registers under in a Registry,
+// matching the string stored in Settings.STTProvider.
+const ProviderName = "gemini"
+
+// GeminiRecognizer transcribes audio by uploading it to the Gemini API. It
+// implements Recognizer.
+type GeminiRecognizer struct {
+	service *gemini.GeminiService
+}
+
+// NewGeminiRecognizer wraps an existing gemini.GeminiService for STT, so the
+// same API key configured for conversation is reused for transcription.
+func NewGeminiRecognizer(service *gemini.GeminiService) *GeminiRecognizer {
+	return &GeminiRecognizer{service: service}
+}
+
+// Name identifies this recognizer in Settings.STTProvider.
+func (r *GeminiRecognizer) Name() string { return ProviderName }
+
+// IsConfigured returns true if the underlying Gemini API key is set.
+func (r *GeminiRecognizer) IsConfigured() bool {
+	return r.service != nil && r.service.IsConfigured()
+}
+
+// Transcribe wraps audio as a WAV file and asks Gemini to transcribe it.
+func (r *GeminiRecognizer) Transcribe(ctx context.Context, audio []byte, sampleRate int, lang string) (string, error) {
+	if !r.IsConfigured() {
+		return "", fmt.Errorf("gemini API key not configured")
+	}
+
+	wav := wrapPCM16WAV(audio, sampleRate)
+	audioBase64 := base64.StdEncoding.EncodeToString(wav)
+
+	return r.service.TranscribeAudio(ctx, audioBase64, "audio/wav", lang)
+}
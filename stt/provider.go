@@ -0,0 +1,22 @@
+// Package stt defines a provider-agnostic speech-to-text interface, so
+// callers can transcribe audio without depending on any one backend (see
+// Registry).
+package stt
+
+import "context"
+
+// Recognizer turns raw audio into a text transcript. Implementations are
+// registered under a name via Registry.Register and selected via
+// Settings.STTProvider.
+type Recognizer interface {
+	// Name identifies the recognizer, matching the string stored in
+	// Settings.STTProvider.
+	Name() string
+	// IsConfigured reports whether the recognizer has what it needs to run
+	// (an API key, a model file on disk, etc).
+	IsConfigured() bool
+	// Transcribe converts little-endian mono PCM16 audio sampled at
+	// sampleRate into text. lang is a BCP-47 language hint (e.g. "en");
+	// empty means auto-detect.
+	Transcribe(ctx context.Context, audio []byte, sampleRate int, lang string) (string, error)
+}
@@ -0,0 +1,67 @@
+package stt
+
+import (
+	"fmt"
+	"sort"
+)
+
+// DefaultProviderName is the recognizer selected when Settings.STTProvider
+// is empty.
+const DefaultProviderName = "gemini"
+
+// Registry holds the STT recognizers available to a caller (e.g. App or
+// voice.PipelineService) and tracks which one is active, mirroring
+// tts.Registry: the active recognizer is per-instance state driven by
+// Settings.STTProvider, not a process-wide default.
+type Registry struct {
+	recognizers map[string]Recognizer
+	active      string
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{recognizers: make(map[string]Recognizer)}
+}
+
+// Register makes recognizer available under name. Panics on a duplicate
+// name, since that can only indicate two recognizers colliding at setup
+// time.
+func (r *Registry) Register(name string, recognizer Recognizer) {
+	if _, exists := r.recognizers[name]; exists {
+		panic(fmt.Sprintf("stt: recognizer %q already registered", name))
+	}
+	r.recognizers[name] = recognizer
+}
+
+// SetActive selects the recognizer used by Active. An empty name selects
+// DefaultProviderName.
+func (r *Registry) SetActive(name string) {
+	if name == "" {
+		name = DefaultProviderName
+	}
+	r.active = name
+}
+
+// Active returns the currently selected recognizer.
+func (r *Registry) Active() (Recognizer, error) {
+	name := r.active
+	if name == "" {
+		name = DefaultProviderName
+	}
+	recognizer, ok := r.recognizers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown stt provider: %s", name)
+	}
+	return recognizer, nil
+}
+
+// List returns the names of every registered recognizer, for populating a
+// settings picker.
+func (r *Registry) List() []string {
+	names := make([]string, 0, len(r.recognizers))
+	for name := range r.recognizers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
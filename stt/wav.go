@@ -0,0 +1,39 @@
+package stt
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// wrapPCM16WAV wraps little-endian mono PCM16 audio in a minimal WAV
+// container. Both the Gemini and whisper-cli backends need an actual audio
+// file, not raw samples.
+func wrapPCM16WAV(pcm []byte, sampleRate int) []byte {
+	const (
+		bitsPerSample = 16
+		numChannels   = 1
+	)
+	byteRate := uint32(sampleRate * numChannels * bitsPerSample / 8)
+	blockAlign := uint16(numChannels * bitsPerSample / 8)
+	dataSize := uint32(len(pcm))
+
+	var buf bytes.Buffer
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, uint32(36+dataSize))
+	buf.WriteString("WAVE")
+
+	buf.WriteString("fmt ")
+	binary.Write(&buf, binary.LittleEndian, uint32(16))
+	binary.Write(&buf, binary.LittleEndian, uint16(1)) // PCM
+	binary.Write(&buf, binary.LittleEndian, uint16(numChannels))
+	binary.Write(&buf, binary.LittleEndian, uint32(sampleRate))
+	binary.Write(&buf, binary.LittleEndian, byteRate)
+	binary.Write(&buf, binary.LittleEndian, blockAlign)
+	binary.Write(&buf, binary.LittleEndian, uint16(bitsPerSample))
+
+	buf.WriteString("data")
+	binary.Write(&buf, binary.LittleEndian, dataSize)
+	buf.Write(pcm)
+
+	return buf.Bytes()
+}
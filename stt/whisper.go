@@ -0,0 +1,97 @@
+package stt
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// WhisperProviderName is the name WhisperCLIRecognizer registers under.
+const WhisperProviderName = "whisper-cpp"
+
+// WhisperCLIRecognizer transcribes audio locally by shelling out to a
+// whisper-cli binary (https://github.com/ggerganov/whisper.cpp) once per
+// call, using a configurable GGML model file (Settings.WhisperModelPath).
+//
+// The transcription package already binds whisper.cpp via cgo for the live
+// microphone path, but that binding is tightly coupled to
+// TranscriptionService's model lifecycle (idle unloading, prompt chaining,
+// float32 sample buffers). Shelling out to whisper-cli keeps this backend
+// independent, works with any whisper-cli build the user already has
+// installed, and matches Recognizer's simpler one-shot byte-buffer contract.
+type WhisperCLIRecognizer struct {
+	binaryPath string
+	modelPath  string
+}
+
+// NewWhisperCLIRecognizer creates a WhisperCLIRecognizer, resolving the
+// whisper-cli binary from PATH. modelPath may be empty and set later via
+// SetModelPath once settings have loaded.
+func NewWhisperCLIRecognizer(modelPath string) *WhisperCLIRecognizer {
+	binaryPath, _ := exec.LookPath("whisper-cli")
+	return &WhisperCLIRecognizer{binaryPath: binaryPath, modelPath: modelPath}
+}
+
+// SetModelPath updates the GGML model file (Settings.WhisperModelPath) used
+// for transcription.
+func (r *WhisperCLIRecognizer) SetModelPath(modelPath string) {
+	r.modelPath = modelPath
+}
+
+// Name identifies this recognizer in Settings.STTProvider.
+func (r *WhisperCLIRecognizer) Name() string { return WhisperProviderName }
+
+// IsConfigured reports whether the whisper-cli binary and a model file are
+// both available.
+func (r *WhisperCLIRecognizer) IsConfigured() bool {
+	if r.binaryPath == "" || r.modelPath == "" {
+		return false
+	}
+	_, err := os.Stat(r.modelPath)
+	return err == nil
+}
+
+// Transcribe writes audio to a temp WAV file and runs whisper-cli against
+// it, returning the resulting plain-text transcript.
+func (r *WhisperCLIRecognizer) Transcribe(ctx context.Context, audio []byte, sampleRate int, lang string) (string, error) {
+	if !r.IsConfigured() {
+		return "", fmt.Errorf("whisper-cli not configured (binary or model missing)")
+	}
+
+	tmpFile, err := os.CreateTemp("", "stt-*.wav")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp audio file: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	wav := wrapPCM16WAV(audio, sampleRate)
+	if _, err := tmpFile.Write(wav); err != nil {
+		tmpFile.Close()
+		return "", fmt.Errorf("failed to write temp audio file: %w", err)
+	}
+	tmpFile.Close()
+
+	if lang == "" {
+		lang = "auto"
+	}
+
+	cmd := exec.CommandContext(ctx, r.binaryPath,
+		"-m", r.modelPath,
+		"-f", tmpFile.Name(),
+		"-l", lang,
+		"--no-timestamps",
+	)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("whisper-cli failed: %w (%s)", err, stderr.String())
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
@@ -0,0 +1,171 @@
+package transcription
+
+import (
+	"fmt"
+	"sort"
+)
+
+// WhisperBackend loads a model and hands back a ready-to-use BackendSession,
+// abstracting what TranscriptionService's reload path (loadModelPool) used
+// to do directly - call whisper.New and model.NewContext - behind a
+// swappable interface. The active backend is selected by name via
+// BackendRegistry.SetActive (see Settings.WhisperImplementation), mirroring
+// llm.Registry and stt.Registry's provider-selection pattern.
+//
+// BackendSession's contract is deliberately narrower than whisper.Context's:
+// it covers exactly what every implementation here - the in-process
+// whisper.cpp binding, a faster-whisper subprocess, and a remote
+// WhisperLive-style server - can support in common. Whisper.cpp-specific
+// features with no cross-backend equivalent, like tinydiarize speaker-turn
+// tagging (see diarization.go and the optional Diarizer interface), stay
+// available only when the active backend happens to implement them.
+type WhisperBackend interface {
+	// Name identifies the backend, matching the string stored in
+	// Settings.WhisperImplementation and the name it's registered under in
+	// BackendRegistry.
+	Name() string
+	// Load prepares modelPath for transcription and returns a session ready
+	// to use. What "preparing" means is backend-specific: reading a GGML
+	// file into memory, starting a subprocess, or dialing a server. Callers
+	// needing N concurrent sessions (see TranscriptionService.loadModelPool)
+	// call Load N times; an implementation that can share one underlying
+	// model across sessions (as WhisperCppBackend does) does so internally.
+	Load(modelPath string) (BackendSession, error)
+}
+
+// BackendSession is one loaded model instance, ready to transcribe chunks of
+// 16kHz mono float32 PCM. TranscriptionService creates contextPoolSize of
+// these per model, exactly as it used to create one whisper.Context per
+// pooled slot directly.
+type BackendSession interface {
+	// Transcribe decodes pcm and returns the segments it produced plus the
+	// detected language, with params applied for this call only - see
+	// TranscribeParams.
+	Transcribe(pcm []float32, params TranscribeParams) ([]Segment, string, error)
+	// DetectLanguage identifies the spoken language of a short pcm window
+	// without committing to a full transcription - see
+	// TranscriptionService.DetectLanguage.
+	DetectLanguage(pcm []float32) (string, float32, error)
+	// SetLanguage persists lang (a whisper language code, or "auto") as
+	// this session's default for every Transcribe call that doesn't
+	// override it via TranscribeParams.Language - see
+	// TranscriptionService.SetLanguage.
+	SetLanguage(lang string) error
+	// Close releases this session's share of the backend's resources. Safe
+	// to call once every in-flight Transcribe/DetectLanguage call on it has
+	// returned - see modelCacheEntry.closeAsync's drain-then-close.
+	Close() error
+}
+
+// Diarizer is implemented by a BackendSession that can tag speaker turns in
+// its Transcribe output text (see TranscribeDiarized) - only
+// WhisperCppBackend's sessions support this today, via whisper.cpp's
+// tinydiarize mode. TranscribeDiarized falls back to a single-speaker
+// transcript when the active backend's session doesn't implement it.
+type Diarizer interface {
+	SetTinydiarize(enabled bool)
+}
+
+// PromptSetter is implemented by a BackendSession that can set a persistent
+// default initial prompt, applied to every subsequent Transcribe call that
+// doesn't override it via TranscribeParams.InitialPrompt - see
+// UpdatePromptContext/ResetPromptContext. Like Diarizer, this is optional:
+// only WhisperCppBackend's sessions support it today.
+type PromptSetter interface {
+	SetInitialPrompt(prompt string)
+}
+
+// DeviceSelector is implemented by a WhisperBackend that can target a
+// specific accelerator (see AdaptiveConfig's Device constants) and fall back
+// toward DeviceCPU when loading on the preferred one fails. Like Diarizer
+// and PromptSetter, this is optional: only WhisperCppBackend supports local
+// device selection today - FasterWhisperBackend and RemoteWhisperBackend run
+// somewhere else entirely (a subprocess, a remote server), so there's no
+// local accelerator for them to pick.
+type DeviceSelector interface {
+	// SetPreferredDevice sets the device Load tries first - see
+	// deviceFallbackChain. An empty string means "use DetectHardware's own
+	// preference".
+	SetPreferredDevice(device string)
+	// PreferredDevice returns the device last set via SetPreferredDevice, or
+	// "" if none has been set yet.
+	PreferredDevice() string
+	// ListDevices returns every device Load would consider, most-preferred
+	// first, ending in DeviceCPU.
+	ListDevices() []string
+}
+
+// Segment is a backend-agnostic transcription result - whisper.Segment
+// without the whisper.cpp-specific Tokens field, which only
+// WhisperCppBackend's sessions can populate (see buildWords).
+type Segment struct {
+	Text         string
+	Start        float64
+	End          float64
+	AvgLogprob   float64
+	NoSpeechProb float64
+	// Words is populated on a best-effort basis - backends that can't
+	// produce per-word timestamps (the faster-whisper stdio bridge, the
+	// remote WhisperLive client) leave it nil.
+	Words []Word
+}
+
+// DefaultBackendName is the backend BackendRegistry.Active falls back to
+// when none has been explicitly selected.
+const DefaultBackendName = WhisperCppBackendName
+
+// BackendRegistry holds the WhisperBackend implementations available to a
+// TranscriptionService and tracks which one is active, mirroring
+// llm.Registry and stt.Registry: the active backend is per-instance state
+// driven by Settings.WhisperImplementation, not a process-wide default.
+type BackendRegistry struct {
+	backends map[string]WhisperBackend
+	active   string
+}
+
+// NewBackendRegistry creates an empty BackendRegistry.
+func NewBackendRegistry() *BackendRegistry {
+	return &BackendRegistry{backends: make(map[string]WhisperBackend)}
+}
+
+// Register makes backend available under name. Panics on a duplicate name,
+// since that can only indicate two backends colliding at setup time.
+func (r *BackendRegistry) Register(name string, backend WhisperBackend) {
+	if _, exists := r.backends[name]; exists {
+		panic(fmt.Sprintf("transcription: backend %q already registered", name))
+	}
+	r.backends[name] = backend
+}
+
+// SetActive selects the backend used by Active. An empty name selects
+// DefaultBackendName.
+func (r *BackendRegistry) SetActive(name string) {
+	if name == "" {
+		name = DefaultBackendName
+	}
+	r.active = name
+}
+
+// Active returns the currently selected backend.
+func (r *BackendRegistry) Active() (WhisperBackend, error) {
+	name := r.active
+	if name == "" {
+		name = DefaultBackendName
+	}
+	backend, ok := r.backends[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown whisper backend: %s", name)
+	}
+	return backend, nil
+}
+
+// List returns the names of every registered backend, for populating a
+// settings picker.
+func (r *BackendRegistry) List() []string {
+	names := make([]string, 0, len(r.backends))
+	for name := range r.backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
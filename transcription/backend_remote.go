@@ -0,0 +1,165 @@
+package transcription
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// RemoteWhisperBackendName is the name RemoteWhisperBackend registers
+// under.
+const RemoteWhisperBackendName = "remote"
+
+// RemoteWhisperBackend dials a remote WhisperLive-style transcription
+// server over WebSocket instead of running whisper.cpp in-process, the
+// same transport forwarder.go already uses (github.com/gorilla/websocket)
+// for this app's speech-forwarder mode. Every BackendSession it returns
+// opens its own connection: one "init" handshake carrying the requested
+// model/language/VAD settings, then one streamed request/reply per
+// Transcribe/DetectLanguage call.
+type RemoteWhisperBackend struct {
+	mu  sync.RWMutex
+	url string
+}
+
+// NewRemoteWhisperBackend creates a RemoteWhisperBackend with no server
+// URL configured - see SetServerURL. Load fails until one is set.
+func NewRemoteWhisperBackend() *RemoteWhisperBackend {
+	return &RemoteWhisperBackend{}
+}
+
+// Name identifies this backend in Settings.WhisperImplementation.
+func (b *RemoteWhisperBackend) Name() string { return RemoteWhisperBackendName }
+
+// SetServerURL configures the ws:// or wss:// endpoint Load dials for
+// every new session.
+func (b *RemoteWhisperBackend) SetServerURL(url string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.url = url
+}
+
+// Load dials the configured server and completes the init handshake for
+// modelPath, returning a session ready to stream audio to it.
+func (b *RemoteWhisperBackend) Load(modelPath string) (BackendSession, error) {
+	b.mu.RLock()
+	url := b.url
+	b.mu.RUnlock()
+
+	if url == "" {
+		return nil, fmt.Errorf("remote whisper backend: no server URL configured")
+	}
+
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to remote whisper server %s: %w", url, err)
+	}
+
+	handshake := remoteWhisperMessage{
+		Type:      "init",
+		ModelPath: modelPath,
+		Language:  "auto",
+		VAD:       true,
+	}
+	if err := conn.WriteJSON(handshake); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to send init handshake to remote whisper server: %w", err)
+	}
+
+	var ack remoteWhisperMessage
+	if err := conn.ReadJSON(&ack); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to read init ack from remote whisper server: %w", err)
+	}
+	if ack.Error != "" {
+		conn.Close()
+		return nil, fmt.Errorf("remote whisper server rejected init: %s", ack.Error)
+	}
+
+	return &remoteWhisperSession{conn: conn}, nil
+}
+
+// remoteWhisperMessage is the WhisperLive-style envelope exchanged over
+// the connection: an "init" handshake, then "transcribe"/"detect_language"
+// requests and their replies.
+type remoteWhisperMessage struct {
+	Type      string          `json:"type"`
+	ModelPath string          `json:"model_path,omitempty"`
+	Language  string          `json:"language,omitempty"`
+	VAD       bool            `json:"vad,omitempty"`
+	PCM       []float32       `json:"pcm,omitempty"`
+	Params    json.RawMessage `json:"params,omitempty"`
+	Segments  []Segment       `json:"segments,omitempty"`
+	Prob      float32         `json:"prob,omitempty"`
+	Error     string          `json:"error,omitempty"`
+}
+
+// remoteWhisperSession is BackendSession backed by one WebSocket
+// connection to a remote WhisperLive-style server.
+type remoteWhisperSession struct {
+	mu   sync.Mutex
+	conn *websocket.Conn
+}
+
+func (s *remoteWhisperSession) roundTrip(req remoteWhisperMessage) (remoteWhisperMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.conn.WriteJSON(req); err != nil {
+		return remoteWhisperMessage{}, fmt.Errorf("failed to send request to remote whisper server: %w", err)
+	}
+
+	var reply remoteWhisperMessage
+	if err := s.conn.ReadJSON(&reply); err != nil {
+		return remoteWhisperMessage{}, fmt.Errorf("failed to read reply from remote whisper server: %w", err)
+	}
+	if reply.Error != "" {
+		return remoteWhisperMessage{}, fmt.Errorf("remote whisper server: %s", reply.Error)
+	}
+	return reply, nil
+}
+
+// Transcribe streams pcm to the server and returns the segments and
+// detected language it replies with. params is marshaled as-is into the
+// request's params field; a server that doesn't understand a given field
+// is expected to ignore it, the same tolerance this package's own
+// RPCServer extends to unrecognized proto fields.
+func (s *remoteWhisperSession) Transcribe(pcm []float32, params TranscribeParams) ([]Segment, string, error) {
+	encodedParams, err := json.Marshal(params)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to encode transcribe params: %w", err)
+	}
+
+	reply, err := s.roundTrip(remoteWhisperMessage{Type: "transcribe", PCM: pcm, Params: encodedParams})
+	if err != nil {
+		return nil, "", err
+	}
+	return reply.Segments, reply.Language, nil
+}
+
+// DetectLanguage streams a short pcm window to the server and returns its
+// language guess.
+func (s *remoteWhisperSession) DetectLanguage(pcm []float32) (string, float32, error) {
+	if len(pcm) > lidWindowSamples {
+		pcm = pcm[:lidWindowSamples]
+	}
+	reply, err := s.roundTrip(remoteWhisperMessage{Type: "detect_language", PCM: pcm})
+	if err != nil {
+		return "", 0, err
+	}
+	return reply.Language, reply.Prob, nil
+}
+
+// SetLanguage sends a "set_language" control message, persisting lang on
+// the server side as this connection's default.
+func (s *remoteWhisperSession) SetLanguage(lang string) error {
+	_, err := s.roundTrip(remoteWhisperMessage{Type: "set_language", Language: lang})
+	return err
+}
+
+// Close closes the underlying WebSocket connection.
+func (s *remoteWhisperSession) Close() error {
+	return s.conn.Close()
+}
@@ -0,0 +1,424 @@
+package transcription
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+
+	whisper "github.com/ggerganov/whisper.cpp/bindings/go/pkg/whisper"
+)
+
+// WhisperCppBackendName is the name WhisperCppBackend registers under.
+const WhisperCppBackendName = "whisper-cpp"
+
+// WhisperCppBackend is the default WhisperBackend: it runs whisper.cpp
+// in-process via cgo, exactly as TranscriptionService always has. Load
+// shares one whisper.Model across every session opened for the same
+// modelPath (refcounted in models), so building a pool of N sessions for
+// one model only reads its GGML file from disk once.
+//
+// WhisperCppBackend also implements DeviceSelector: Load tries
+// deviceFallbackChain's devices in order, falling back toward DeviceCPU (and
+// calling fallbackNotifier with the reason) if initializing on one fails.
+type WhisperCppBackend struct {
+	mu     sync.Mutex
+	models map[string]*whisperCppModelRef
+
+	deviceMu         sync.RWMutex
+	preferredDevice  string
+	fallbackNotifier func(modelPath, fromDevice, toDevice, reason string)
+}
+
+type whisperCppModelRef struct {
+	model    whisper.Model
+	refCount int
+}
+
+// NewWhisperCppBackend creates an empty WhisperCppBackend.
+func NewWhisperCppBackend() *WhisperCppBackend {
+	return &WhisperCppBackend{models: make(map[string]*whisperCppModelRef)}
+}
+
+// Name identifies this backend in Settings.WhisperImplementation.
+func (b *WhisperCppBackend) Name() string { return WhisperCppBackendName }
+
+// SetPreferredDevice implements DeviceSelector.
+func (b *WhisperCppBackend) SetPreferredDevice(device string) {
+	b.deviceMu.Lock()
+	b.preferredDevice = device
+	b.deviceMu.Unlock()
+}
+
+// PreferredDevice implements DeviceSelector.
+func (b *WhisperCppBackend) PreferredDevice() string {
+	b.deviceMu.RLock()
+	defer b.deviceMu.RUnlock()
+	return b.preferredDevice
+}
+
+// ListDevices implements DeviceSelector.
+func (b *WhisperCppBackend) ListDevices() []string {
+	return deviceFallbackChain(DetectHardware(), b.PreferredDevice())
+}
+
+// SetFallbackNotifier installs fn to be called whenever Load falls back from
+// one device to the next while creating a context - see
+// TranscriptionService.emitModelFallback, which NewTranscriptionService wires
+// this to.
+func (b *WhisperCppBackend) SetFallbackNotifier(fn func(modelPath, fromDevice, toDevice, reason string)) {
+	b.deviceMu.Lock()
+	b.fallbackNotifier = fn
+	b.deviceMu.Unlock()
+}
+
+func (b *WhisperCppBackend) notifyFallback(modelPath, fromDevice, toDevice, reason string) {
+	b.deviceMu.RLock()
+	fn := b.fallbackNotifier
+	b.deviceMu.RUnlock()
+	if fn != nil {
+		fn(modelPath, fromDevice, toDevice, reason)
+	}
+}
+
+// Load returns a session backed by modelPath's whisper.Model, loading it
+// (and the VAD model, if not already present) on first use for that path.
+// Context creation is attempted once per device in deviceFallbackChain in
+// turn - a failure (OOM, missing driver, and on Windows an HRESULT like
+// E_INVALIDARG surfacing through cgo) falls back to the next device instead
+// of failing Load outright, down to DeviceCPU as the final option. The
+// device is recorded on the session's AdaptiveConfig only: model.NewContext
+// takes no device argument, so every attempt makes the same call and the
+// context runs wherever the whisper.cpp build places it.
+func (b *WhisperCppBackend) Load(modelPath string) (BackendSession, error) {
+	model, err := b.acquireModel(modelPath)
+	if err != nil {
+		return nil, err
+	}
+
+	hwProfile := DetectHardware()
+	vadModelPath := getModelPath("silero-v6.2.0")
+	vadEnabled := ensureVADModel(vadModelPath)
+
+	chain := deviceFallbackChain(hwProfile, b.PreferredDevice())
+
+	var lastErr error
+	for i, device := range chain {
+		adaptiveConfig := GetAdaptiveConfig(hwProfile)
+		adaptiveConfig.Device = device
+
+		ctx, err := model.NewContext()
+		if err != nil {
+			lastErr = fmt.Errorf("failed to create whisper context on %s: %w", device, err)
+			if i+1 < len(chain) {
+				next := chain[i+1]
+				slog.Warn("whisper device init failed, falling back", "model", modelPath, "device", device, "fallback_to", next, "error", err)
+				b.notifyFallback(modelPath, device, next, err.Error())
+				continue
+			}
+			break
+		}
+		configureContext(ctx, adaptiveConfig, vadModelPath, vadEnabled)
+
+		return &whisperCppSession{
+			backend:        b,
+			modelPath:      modelPath,
+			ctx:            ctx,
+			adaptiveConfig: adaptiveConfig,
+			vadModelPath:   vadModelPath,
+			vadEnabled:     vadEnabled,
+		}, nil
+	}
+
+	b.releaseModel(modelPath)
+	return nil, lastErr
+}
+
+func (b *WhisperCppBackend) acquireModel(modelPath string) (whisper.Model, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if ref, ok := b.models[modelPath]; ok {
+		ref.refCount++
+		return ref.model, nil
+	}
+
+	model, err := whisper.New(modelPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load whisper model from %s: %w", modelPath, err)
+	}
+	b.models[modelPath] = &whisperCppModelRef{model: model, refCount: 1}
+	return model, nil
+}
+
+// releaseModel drops one reference to modelPath's model, closing it once
+// every session built from it has released its share.
+func (b *WhisperCppBackend) releaseModel(modelPath string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ref, ok := b.models[modelPath]
+	if !ok {
+		return
+	}
+	ref.refCount--
+	if ref.refCount <= 0 {
+		ref.model.Close()
+		delete(b.models, modelPath)
+	}
+}
+
+// whisperCppSession is BackendSession backed by one whisper.Context sharing
+// its parent WhisperCppBackend's model for modelPath.
+type whisperCppSession struct {
+	backend        *WhisperCppBackend
+	modelPath      string
+	ctx            whisper.Context
+	adaptiveConfig AdaptiveConfig
+	vadModelPath   string
+	vadEnabled     bool
+}
+
+// Transcribe applies params to the underlying context for this call only,
+// decodes pcm, and restores configureContext's standard defaults before
+// returning - see applyParamsToContext.
+func (s *whisperCppSession) Transcribe(pcm []float32, params TranscribeParams) ([]Segment, string, error) {
+	if params != (TranscribeParams{}) {
+		restore := applyParamsToContext(s.ctx, params)
+		defer restore(s.adaptiveConfig, s.vadModelPath, s.vadEnabled)
+	}
+
+	var segments []Segment
+	callback := func(seg whisper.Segment) {
+		words, avgLogprob, noSpeechProb := buildWords(seg.Tokens)
+		segments = append(segments, Segment{
+			Text:         seg.Text,
+			Start:        seg.Start.Seconds(),
+			End:          seg.End.Seconds(),
+			AvgLogprob:   avgLogprob,
+			NoSpeechProb: noSpeechProb,
+			Words:        words,
+		})
+	}
+
+	if err := s.ctx.Process(pcm, nil, callback, nil); err != nil {
+		return nil, "", fmt.Errorf("failed to process audio: %w", err)
+	}
+
+	return segments, s.ctx.DetectedLanguage(), nil
+}
+
+// DetectLanguage runs the same truncated-window auto-detection
+// TranscriptionService.DetectLanguage uses for every other backend.
+func (s *whisperCppSession) DetectLanguage(pcm []float32) (string, float32, error) {
+	return detectLanguageOnContext(s.ctx, pcm)
+}
+
+// SetLanguage persists lang on the underlying context.
+func (s *whisperCppSession) SetLanguage(lang string) error {
+	return s.ctx.SetLanguage(lang)
+}
+
+// SetInitialPrompt implements PromptSetter, persisting prompt on the
+// underlying context.
+func (s *whisperCppSession) SetInitialPrompt(prompt string) {
+	s.ctx.SetInitialPrompt(prompt)
+}
+
+// SetTinydiarize implements Diarizer, toggling whisper.cpp's tinydiarize
+// mode for this session's subsequent Transcribe calls.
+func (s *whisperCppSession) SetTinydiarize(enabled bool) {
+	s.ctx.SetTinydiarize(enabled)
+}
+
+// Close releases this session's share of its parent model, closing the
+// model once every session sharing it has done the same.
+func (s *whisperCppSession) Close() error {
+	s.backend.releaseModel(s.modelPath)
+	return nil
+}
+
+// applyParamsToContext overrides ctx's settings for the duration of one
+// Transcribe call and returns a restore func that puts configureContext's
+// standard settings back - call it (via defer) before the context is
+// reused by another call, so the next caller to use it isn't left running
+// under this call's overrides.
+func applyParamsToContext(ctx whisper.Context, params TranscribeParams) func(adaptiveConfig AdaptiveConfig, vadModelPath string, vadEnabled bool) {
+	if params.Language != "" {
+		ctx.SetLanguage(params.Language)
+	}
+	if params.Translate {
+		ctx.SetTranslate(true)
+	}
+	if params.BeamSize != 0 {
+		ctx.SetBeamSize(params.BeamSize)
+	}
+	if params.Temperature != 0 {
+		ctx.SetTemperature(params.Temperature)
+	}
+	if params.TemperatureFallback != 0 {
+		ctx.SetTemperatureFallback(params.TemperatureFallback)
+	}
+	if params.EntropyThreshold != 0 {
+		ctx.SetEntropyThold(params.EntropyThreshold)
+	}
+	if params.InitialPrompt != "" {
+		ctx.SetInitialPrompt(params.InitialPrompt)
+	}
+	if params.VADThreshold != 0 {
+		ctx.SetVADThreshold(params.VADThreshold)
+	}
+	if params.MinSpeechMs != 0 {
+		ctx.SetVADMinSpeechMs(params.MinSpeechMs)
+	}
+	if params.MinSilenceMs != 0 {
+		ctx.SetVADMinSilenceMs(params.MinSilenceMs)
+	}
+	if params.MaxSegmentLen != 0 {
+		ctx.SetMaxSegmentLength(params.MaxSegmentLen)
+	}
+
+	return func(adaptiveConfig AdaptiveConfig, vadModelPath string, vadEnabled bool) {
+		configureContext(ctx, adaptiveConfig, vadModelPath, vadEnabled)
+	}
+}
+
+// detectLanguageOnContext runs whisper.cpp's own auto-detection on a
+// truncated window of pcm (lidWindowSamples) using an already-acquired
+// whisperCtx - see TranscriptionService.DetectLanguage and
+// detectLanguageForStream for why this is split out from a context-owning
+// method: Process already holds its context checked out when it wants to
+// detect language mid-call, so it must reuse that context rather than
+// acquiring a second one (which would deadlock a pool of size 1).
+//
+// This sandbox has no separately-loadable ONNX/whisper-tiny LID checkpoint
+// available, so language identification reuses the main whisper.cpp
+// model's own DetectedLanguage() auto-detection instead of a dedicated LID
+// model - prob is always 1.0 or 0, coarser than a real per-language
+// probability distribution, since the Go bindings don't expose one.
+func detectLanguageOnContext(whisperCtx whisper.Context, pcm []float32) (string, float32, error) {
+	if len(pcm) > lidWindowSamples {
+		pcm = pcm[:lidWindowSamples]
+	}
+	if err := whisperCtx.SetLanguage("auto"); err != nil {
+		return "", 0, fmt.Errorf("failed to set auto language for detection: %w", err)
+	}
+	if err := whisperCtx.Process(pcm, nil, nil, nil); err != nil {
+		return "", 0, fmt.Errorf("failed to process audio for language detection: %w", err)
+	}
+	lang := whisperCtx.DetectedLanguage()
+	if lang == "" {
+		return "", 0, nil
+	}
+	return lang, 1.0, nil
+}
+
+// configureContext applies the adaptive real-time transcription settings
+// every pooled session shares: thread count, sampling strategy,
+// hallucination-reduction tuning, and VAD. Used by WhisperCppBackend.Load
+// and applyParamsToContext's restore func so every session never drifts
+// out of sync with its siblings.
+func configureContext(context whisper.Context, adaptiveConfig AdaptiveConfig, vadModelPath string, vadEnabled bool) {
+	context.SetThreads(uint(adaptiveConfig.Threads)) // Adaptive thread count
+	context.SetTranslate(false)                      // Transcribe to English
+	context.SetLanguage("auto")                      // Default to auto-detect; caller sets specific language via SetLanguage()
+	context.SetMaxSegmentLength(200)                 // Reasonable segment length limit
+
+	// 1. Enable Beam Search with adaptive beam size
+	context.SetBeamSize(adaptiveConfig.BeamSize)
+
+	// 2. Set Entropy Threshold to reduce hallucinations
+	context.SetEntropyThold(2.4) // Match VoiceInk default (was 2.2)
+
+	// 3. Set Temperature with adaptive value for quality/speed balance
+	context.SetTemperature(adaptiveConfig.Temperature)
+
+	// 4. Enable Temperature Fallback
+	context.SetTemperatureFallback(0.2)
+
+	// 5. Repetition Penalty not available in bindings, relying on VAD/Entropy
+	// context.SetRepetitionPenalty(1.1)
+
+	// Note: no-speech threshold is controlled via VAD threshold instead -
+	// the whisper.cpp Go bindings don't expose SetNoSpeechThold directly.
+
+	// Set initial prompt to stabilize the model - using minimal prompt to avoid hallucinations
+	// With longer context windows (5s), we can use prompts safely
+	context.SetInitialPrompt("Meeting transcription.")
+
+	if vadEnabled {
+		context.SetVAD(true)
+		context.SetVADModelPath(vadModelPath)
+		// Tune VAD parameters for better speech segment detection
+		// Based on meeting-minutes best practices
+		context.SetVADThreshold(0.50)
+		context.SetVADMinSpeechMs(250)  // 250ms (Matches VoiceInk) - prevents chopping words
+		context.SetVADMinSilenceMs(100) // 100ms (Matches VoiceInk) - cuts sooner
+		// Note: Higher min_speech prevents Whisper from processing very short segments
+		// that often result in hallucinations or empty transcriptions
+	} else {
+		context.SetVAD(false)
+	}
+
+	context.SetTokenTimestamps(true)
+}
+
+// vadModelMinBytes is a sanity floor for the downloaded VAD model file. The
+// real ggml-silero-v6.2.0.bin is tens of MB; there's no published SHA256
+// for it to verify against (unlike the models in GetSupportedModels), so
+// this is the only check standing between a half-downloaded file and VAD
+// getting silently, permanently disabled until someone notices and deletes
+// it by hand.
+const vadModelMinBytes = 1 << 20 // 1 MiB
+
+// ensureVADModel checks for a complete VAD model at vadModelPath,
+// downloading (or resuming a previous partial download) it if missing or
+// too small to be real, and reports whether VAD should be enabled as a
+// result.
+func ensureVADModel(vadModelPath string) bool {
+	if info, err := os.Stat(vadModelPath); err == nil {
+		if info.Size() >= vadModelMinBytes {
+			return true
+		}
+	} else if !os.IsNotExist(err) {
+		return false
+	}
+
+	return downloadVADModel(vadModelPath)
+}
+
+// buildWords walks a segment's tokens, filtering out whisper.cpp's special
+// tokens (those whose text starts with "[_", e.g. "[_BEG_]"/"[_TT_123]"),
+// and returns the per-word breakdown alongside the segment-level
+// avg_logprob and no_speech_prob the "transcription-segment" event reports -
+// the mean of each token's PLog(), and 1 minus the mean of each token's
+// P(), respectively, from the Go bindings.
+func buildWords(tokens []whisper.Token) ([]Word, float64, float64) {
+	words := make([]Word, 0, len(tokens))
+	var probSum, logProbSum float64
+	var counted int
+
+	for _, tok := range tokens {
+		if len(tok.Text) >= 2 && tok.Text[0] == '[' && tok.Text[1] == '_' {
+			continue
+		}
+
+		words = append(words, Word{
+			Text:        tok.Text,
+			Start:       tok.Start.Seconds(),
+			End:         tok.End.Seconds(),
+			Probability: tok.P(),
+		})
+		probSum += float64(tok.P())
+		logProbSum += float64(tok.PLog())
+		counted++
+	}
+
+	if counted == 0 {
+		return words, 0, 1
+	}
+
+	avgLogprob := logProbSum / float64(counted)
+	noSpeechProb := 1 - (probSum / float64(counted))
+	return words, avgLogprob, noSpeechProb
+}
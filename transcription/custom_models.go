@@ -0,0 +1,178 @@
+package transcription
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// customModelsFileName is the name of the JSON file that persists
+// user-registered models alongside the downloaded model files themselves.
+const customModelsFileName = "custom_models.json"
+
+// customModelsStore holds user-registered models in memory, backed by a
+// JSON file in the same directory returned by getModelPath.
+type customModelsStore struct {
+	mu       sync.RWMutex
+	loadOnce sync.Once
+	models   []ModelInfo
+}
+
+var globalCustomModels = &customModelsStore{}
+
+// ensureLoaded lazily loads the custom models file the first time it's
+// needed, so callers don't have to thread an explicit init step through
+// TranscriptionService construction.
+func (s *customModelsStore) ensureLoaded() {
+	s.loadOnce.Do(func() {
+		if err := s.load(); err != nil {
+			slog.Warn("failed to load custom whisper models", "error", err)
+		}
+	})
+}
+
+// customModelsPath returns the path of the JSON file that persists
+// custom models, mirroring the directory getModelPath downloads into.
+func customModelsPath() string {
+	return filepath.Join(filepath.Dir(getModelPath("base.en")), customModelsFileName)
+}
+
+// load reads the custom models file into memory. A missing file is not
+// an error - it just means no custom models have been registered yet.
+func (s *customModelsStore) load() error {
+	path := customModelsPath()
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read custom models file: %w", err)
+	}
+
+	var models []ModelInfo
+	if err := json.Unmarshal(data, &models); err != nil {
+		return fmt.Errorf("failed to parse custom models file: %w", err)
+	}
+
+	s.mu.Lock()
+	s.models = models
+	s.mu.Unlock()
+	return nil
+}
+
+// persistLocked writes s.models to disk. Callers must hold s.mu.
+func (s *customModelsStore) persistLocked() error {
+	path := customModelsPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create models directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(s.models, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal custom models: %w", err)
+	}
+
+	tempPath := path + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write custom models file: %w", err)
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		return fmt.Errorf("failed to finalize custom models file: %w", err)
+	}
+	return nil
+}
+
+// list returns a copy of the registered custom models.
+func (s *customModelsStore) list() []ModelInfo {
+	s.ensureLoaded()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]ModelInfo, len(s.models))
+	copy(out, s.models)
+	return out
+}
+
+// register adds model to the store, rejecting names that collide with a
+// built-in model or an already-registered custom one.
+func (s *customModelsStore) register(model ModelInfo) error {
+	s.ensureLoaded()
+	if model.Name == "" {
+		return fmt.Errorf("custom model name is required")
+	}
+	if model.Url == "" {
+		return fmt.Errorf("custom model url is required")
+	}
+	for _, builtin := range GetSupportedModels() {
+		if builtin.Name == model.Name {
+			return fmt.Errorf("model name %q is already used by a built-in model", model.Name)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, existing := range s.models {
+		if existing.Name == model.Name {
+			return fmt.Errorf("custom model %q is already registered", model.Name)
+		}
+	}
+
+	model.Custom = true
+	model.IsDownloaded = false
+	model.IsActive = false
+	s.models = append(s.models, model)
+	return s.persistLocked()
+}
+
+// remove deletes the custom model with the given name. It returns an
+// error if no such model is registered.
+func (s *customModelsStore) remove(name string) error {
+	s.ensureLoaded()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, model := range s.models {
+		if model.Name == name {
+			s.models = append(s.models[:i], s.models[i+1:]...)
+			return s.persistLocked()
+		}
+	}
+	return fmt.Errorf("custom model %q is not registered", name)
+}
+
+// allModels returns the built-in models followed by the registered custom
+// models, so callers can treat both sets identically.
+func allModels() []ModelInfo {
+	return append(GetSupportedModels(), globalCustomModels.list()...)
+}
+
+// RegisterCustomModel adds a user-defined Whisper model, persisting it
+// alongside the built-in list so it survives restarts. name must not
+// collide with a built-in or already-registered custom model. model.SHA256
+// is optional; when set, DownloadModel verifies the downloaded file's
+// checksum before marking the model as downloaded.
+func (t *TranscriptionService) RegisterCustomModel(model ModelInfo) error {
+	if err := globalCustomModels.register(model); err != nil {
+		return err
+	}
+	slog.Info("registered custom whisper model", "model", model.Name, "url", model.Url)
+	return nil
+}
+
+// RemoveCustomModel unregisters a previously added custom model. It
+// refuses to remove the currently active model.
+func (t *TranscriptionService) RemoveCustomModel(name string) error {
+	t.modelMutex.RLock()
+	active := t.currentModelName == name
+	t.modelMutex.RUnlock()
+	if active {
+		return fmt.Errorf("cannot remove %q: it is the active model", name)
+	}
+
+	if err := globalCustomModels.remove(name); err != nil {
+		return err
+	}
+	slog.Info("removed custom whisper model", "model", name)
+	return nil
+}
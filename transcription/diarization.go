@@ -0,0 +1,140 @@
+package transcription
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// speakerTurnMarker is the token whisper.cpp's tinydiarize mode inserts
+// into a segment's text at each detected speaker change.
+const speakerTurnMarker = "[SPEAKER_TURN]"
+
+// DiarizedSegment is a single speaker turn within a diarized transcript.
+// Start and End are carried over from the Segment the turn was split out
+// of, so they're only as precise as the active backend's own
+// segmentation.
+type DiarizedSegment struct {
+	Speaker int           `json:"speaker"`
+	Start   time.Duration `json:"start"`
+	End     time.Duration `json:"end"`
+	Text    string        `json:"text"`
+}
+
+// isDiarizedModel reports whether modelName is one of the tinydiarize
+// variants (e.g. "small.en-tdrz") that emit speaker-turn markers.
+func isDiarizedModel(modelName string) bool {
+	return strings.HasSuffix(modelName, "-tdrz")
+}
+
+// TranscribeDiarized processes samples and returns a speaker-diarized
+// transcript. When the active model is a tinydiarize ("-tdrz") variant and
+// the active backend's session implements Diarizer, tinydiarize mode is
+// enabled and the raw text stream is split on speaker-turn markers,
+// incrementing a speaker counter at each turn. Otherwise it falls back to
+// a single-speaker transcript, so callers can always rely on the diarized
+// shape regardless of the active model or backend.
+func (t *TranscriptionService) TranscribeDiarized(samples []float32, appCtx context.Context) ([]DiarizedSegment, string, error) {
+	// Ensure model is loaded (handles lazy loading after idle unload)
+	if err := t.ensureModelLoaded(); err != nil {
+		return nil, "", err
+	}
+
+	t.updateActivityTime()
+
+	t.modelMutex.RLock()
+	modelName := t.currentModelName
+	t.modelMutex.RUnlock()
+
+	session, pool, err := t.acquireSession()
+	if err != nil {
+		return nil, "", err
+	}
+	defer releaseSession(pool, session)
+
+	diarize := isDiarizedModel(modelName)
+	if diarizer, ok := session.(Diarizer); ok {
+		diarizer.SetTinydiarize(diarize)
+	} else {
+		diarize = false
+	}
+
+	segments, detectedLang, err := session.Transcribe(samples, TranscribeParams{})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to process audio: %w", err)
+	}
+
+	var diarized []DiarizedSegment
+	if diarize {
+		diarized = splitSpeakerTurns(segments)
+	} else {
+		diarized = singleSpeakerSegments(segments)
+	}
+
+	slog.Info("diarized transcription complete", "model", modelName, "diarized", diarize, "turns", len(diarized))
+
+	if t.app != nil {
+		t.app.Event.Emit("transcription-diarized", map[string]interface{}{
+			"segments":  diarized,
+			"timestamp": time.Now().Unix(),
+		})
+	}
+
+	return diarized, detectedLang, nil
+}
+
+// splitSpeakerTurns walks raw segments and splits each one's text on
+// speakerTurnMarker, assigning every split part its source segment's
+// timestamps and incrementing the speaker counter on each marker seen.
+func splitSpeakerTurns(segments []Segment) []DiarizedSegment {
+	var out []DiarizedSegment
+	speaker := 0
+
+	for _, segment := range segments {
+		parts := strings.Split(segment.Text, speakerTurnMarker)
+		for i, part := range parts {
+			if i > 0 {
+				speaker++
+			}
+			text := strings.TrimSpace(part)
+			if text == "" {
+				continue
+			}
+			out = append(out, DiarizedSegment{
+				Speaker: speaker,
+				Start:   secondsToDuration(segment.Start),
+				End:     secondsToDuration(segment.End),
+				Text:    text,
+			})
+		}
+	}
+
+	return out
+}
+
+// singleSpeakerSegments wraps plain segments as speaker-0 turns, used when
+// the active model or backend has no diarization signal.
+func singleSpeakerSegments(segments []Segment) []DiarizedSegment {
+	out := make([]DiarizedSegment, 0, len(segments))
+	for _, segment := range segments {
+		text := strings.TrimSpace(segment.Text)
+		if text == "" {
+			continue
+		}
+		out = append(out, DiarizedSegment{
+			Speaker: 0,
+			Start:   secondsToDuration(segment.Start),
+			End:     secondsToDuration(segment.End),
+			Text:    text,
+		})
+	}
+	return out
+}
+
+// secondsToDuration converts a Segment's float64-seconds timestamp to a
+// time.Duration for DiarizedSegment.
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
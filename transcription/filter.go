@@ -35,6 +35,11 @@ var multiSpacePattern = regexp.MustCompile(`\s{2,}`)
 type FilterConfig struct {
 	RemoveFillerWords bool
 	CollapseStutters  bool
+	// StutterMinRepeats overrides how many consecutive repetitions of a
+	// short word collapseStutters treats as a stutter. 0 uses
+	// defaultStutterMinRepeats (see VocabConfig.StutterMinRepeats, which
+	// feeds this from a loaded vocab file).
+	StutterMinRepeats int
 }
 
 // DefaultFilterConfig returns the default filter configuration
@@ -55,7 +60,7 @@ func FilterTranscriptionOutput(text string, config FilterConfig) string {
 
 	// Step 1: Collapse stutters (e.g., "wh wh wh why" -> "why")
 	if config.CollapseStutters {
-		result = collapseStutters(result)
+		result = collapseStutters(result, config.StutterMinRepeats)
 	}
 
 	// Step 2: Remove filler words
@@ -69,10 +74,21 @@ func FilterTranscriptionOutput(text string, config FilterConfig) string {
 	return result
 }
 
+// defaultStutterMinRepeats is how many consecutive repetitions of a short
+// word collapseStutters treats as a stutter when FilterConfig.
+// StutterMinRepeats is 0.
+const defaultStutterMinRepeats = 3
+
 // collapseStutters removes stuttered repetitions of short words
 // "I I I I think" -> "I think"
 // "wh wh wh why" -> "why"
-func collapseStutters(text string) string {
+// minRepeats is the minimum run length to treat as a stutter; 0 uses
+// defaultStutterMinRepeats.
+func collapseStutters(text string, minRepeats int) string {
+	if minRepeats <= 0 {
+		minRepeats = defaultStutterMinRepeats
+	}
+
 	// Use a custom approach to handle stutters more accurately
 	// Split into words and process
 	words := strings.Fields(text)
@@ -97,8 +113,8 @@ func collapseStutters(text string) string {
 				j++
 			}
 
-			// If we have 3 or more repetitions, it's a stutter
-			if count >= 3 {
+			// If we have at least minRepeats repetitions, it's a stutter
+			if count >= minRepeats {
 				// Check if the next word starts with this stutter (e.g., "wh wh wh why")
 				if j < len(words) {
 					nextWord := strings.ToLower(words[j])
@@ -245,45 +261,400 @@ func minInt(vals ...int) int {
 	return min
 }
 
-// soundex generates a Soundex code for phonetic matching
-func soundex(s string) string {
-	if len(s) == 0 {
-		return ""
+// doubleMetaphone computes the Double Metaphone phonetic encoding of s,
+// returning a primary and a secondary ("alternate") key. Soundex used to
+// fill this role, but it only keeps the first letter and collapses the
+// rest into 4 digits, so it mismatches common transcription errors like
+// "kubernetes" vs "coupernettis". Double Metaphone instead models English
+// and European pronunciation rules - silent letters, digraphs, and a few
+// language-of-origin cues - closely enough to catch those. Both keys are
+// at most 4 characters; when a word has no alternate pronunciation,
+// secondary equals primary.
+func doubleMetaphone(s string) (primary, secondary string) {
+	orig := []rune(strings.ToUpper(strings.TrimSpace(s)))
+	n := len(orig)
+	if n == 0 {
+		return "", ""
 	}
 
-	s = strings.ToUpper(s)
+	at := func(pos int) rune {
+		if pos < 0 || pos >= n {
+			return 0
+		}
+		return orig[pos]
+	}
+	isVowel := func(pos int) bool {
+		switch at(pos) {
+		case 'A', 'E', 'I', 'O', 'U', 'Y':
+			return true
+		}
+		return false
+	}
+	stringAt := func(pos, length int, candidates ...string) bool {
+		if pos < 0 || pos+length > n {
+			return false
+		}
+		sub := string(orig[pos : pos+length])
+		for _, c := range candidates {
+			if sub == c {
+				return true
+			}
+		}
+		return false
+	}
 
-	// Soundex mapping
-	codes := map[rune]byte{
-		'B': '1', 'F': '1', 'P': '1', 'V': '1',
-		'C': '2', 'G': '2', 'J': '2', 'K': '2', 'Q': '2', 'S': '2', 'X': '2', 'Z': '2',
-		'D': '3', 'T': '3',
-		'L': '4',
-		'M': '5', 'N': '5',
-		'R': '6',
+	var pri, sec strings.Builder
+	add := func(p, s string) {
+		pri.WriteString(p)
+		sec.WriteString(s)
 	}
+	addBoth := func(c string) { add(c, c) }
 
-	result := make([]byte, 0, 4)
-	result = append(result, s[0]) // Keep first letter
+	i := 0
+	if stringAt(0, 2, "GN", "KN", "PN", "WR", "PS") {
+		i = 1
+	} else if at(0) == 'X' {
+		addBoth("S")
+		i = 1
+	}
 
-	prevCode := codes[rune(s[0])]
+	for i < n && pri.Len() < 4 {
+		if isVowel(i) {
+			if i == 0 {
+				addBoth("A")
+			}
+			i++
+			continue
+		}
+
+		switch at(i) {
+		case 'B':
+			addBoth("P")
+			if at(i+1) == 'B' {
+				i += 2
+			} else {
+				i++
+			}
+
+		case 'Ç':
+			addBoth("S")
+			i++
+
+		case 'C':
+			switch {
+			case stringAt(i, 2, "CH"):
+				if stringAt(0, 3, "SCH") {
+					addBoth("K")
+				} else if i == 0 && stringAt(i+1, 3, "HOR", "HYM", "HIA", "HEM") {
+					addBoth("K")
+				} else {
+					add("X", "K")
+				}
+				i += 2
+			case stringAt(i+1, 3, "CIA"):
+				addBoth("X")
+				i += 3
+			case stringAt(i, 2, "CK", "CG", "CQ"):
+				addBoth("K")
+				i += 2
+			case stringAt(i, 3, "CIO", "CIE", "CIA"):
+				add("S", "X")
+				i += 2
+			case stringAt(i, 2, "CI", "CE", "CY"):
+				addBoth("S")
+				i += 2
+			default:
+				addBoth("K")
+				if stringAt(i+1, 1, "C", "K", "Q") {
+					i += 2
+				} else {
+					i++
+				}
+			}
+
+		case 'D':
+			if stringAt(i, 2, "DG") && stringAt(i+2, 1, "I", "E", "Y") {
+				addBoth("J")
+				i += 3
+			} else if stringAt(i, 2, "DT", "DD") {
+				addBoth("T")
+				i += 2
+			} else {
+				addBoth("T")
+				i++
+			}
+
+		case 'F':
+			addBoth("F")
+			if at(i+1) == 'F' {
+				i += 2
+			} else {
+				i++
+			}
+
+		case 'G':
+			switch {
+			case at(i+1) == 'H':
+				if i > 0 && !isVowel(i-1) {
+					addBoth("K")
+				} else if i == 0 {
+					if at(i+2) == 'I' {
+						addBoth("J")
+					} else {
+						addBoth("K")
+					}
+				} else if i > 0 && at(i-1) != 'I' {
+					addBoth("K")
+				}
+				i += 2
+			case at(i+1) == 'N':
+				if i == 1 && isVowel(0) {
+					add("KN", "N")
+				} else if !stringAt(i+2, 2, "EY") && at(i+1) != 'Y' {
+					add("N", "KN")
+				} else {
+					addBoth("KN")
+				}
+				i += 2
+			case stringAt(i+1, 2, "LI"):
+				add("KL", "L")
+				i += 2
+			case stringAt(i+1, 1, "E", "I", "Y"):
+				if stringAt(0, 3, "SCH") {
+					addBoth("K")
+				} else {
+					add("J", "K")
+				}
+				i += 2
+			case at(i+1) == 'G':
+				addBoth("K")
+				i += 2
+			default:
+				addBoth("K")
+				i++
+			}
+
+		case 'H':
+			if (i == 0 || isVowel(i-1)) && isVowel(i+1) {
+				addBoth("H")
+				i += 2
+			} else {
+				i++
+			}
+
+		case 'J':
+			switch {
+			case stringAt(i, 4, "JOSE") || stringAt(0, 4, "SAN "):
+				add("H", "H")
+				i++
+			case i == 0:
+				add("J", "A")
+				i++
+			case isVowel(i-1) && (at(i+1) == 'A' || at(i+1) == 'O'):
+				add("J", "H")
+				i++
+			default:
+				addBoth("J")
+				i++
+			}
+
+		case 'K':
+			addBoth("K")
+			if at(i+1) == 'K' {
+				i += 2
+			} else {
+				i++
+			}
+
+		case 'L':
+			addBoth("L")
+			if at(i+1) == 'L' {
+				i += 2
+			} else {
+				i++
+			}
+
+		case 'M':
+			addBoth("M")
+			if at(i+1) == 'M' {
+				i += 2
+			} else {
+				i++
+			}
+
+		case 'N':
+			addBoth("N")
+			if at(i+1) == 'N' {
+				i += 2
+			} else {
+				i++
+			}
+
+		case 'Ñ':
+			addBoth("N")
+			i++
+
+		case 'P':
+			if at(i+1) == 'H' {
+				addBoth("F")
+				i += 2
+			} else {
+				addBoth("P")
+				if stringAt(i+1, 1, "P", "B") {
+					i += 2
+				} else {
+					i++
+				}
+			}
+
+		case 'Q':
+			addBoth("K")
+			if at(i+1) == 'Q' {
+				i += 2
+			} else {
+				i++
+			}
+
+		case 'R':
+			if i == n-1 && stringAt(i-2, 2, "IE") && !stringAt(i-4, 2, "ME", "MA") {
+				add("", "R")
+			} else {
+				addBoth("R")
+			}
+			if at(i+1) == 'R' {
+				i += 2
+			} else {
+				i++
+			}
 
-	for i := 1; i < len(s) && len(result) < 4; i++ {
-		code, exists := codes[rune(s[i])]
-		if exists && code != prevCode {
-			result = append(result, code)
-			prevCode = code
-		} else if !exists {
-			prevCode = 0 // Reset for vowels/h/w/y
+		case 'S':
+			switch {
+			case stringAt(i, 2, "SH"):
+				addBoth("X")
+				i += 2
+			case stringAt(i, 3, "SIO", "SIA"):
+				if stringAt(i, 4, "SIAN") {
+					addBoth("S")
+				} else {
+					add("S", "X")
+				}
+				i += 3
+			case stringAt(i, 2, "SC"):
+				if at(i+2) == 'H' {
+					addBoth("X")
+					i += 3
+				} else if stringAt(i+2, 1, "I", "E", "Y") {
+					addBoth("S")
+					i += 3
+				} else {
+					addBoth("SK")
+					i += 3
+				}
+			default:
+				addBoth("S")
+				if stringAt(i+1, 1, "S", "Z") {
+					i += 2
+				} else {
+					i++
+				}
+			}
+
+		case 'T':
+			switch {
+			case stringAt(i, 4, "TION"):
+				addBoth("X")
+				i += 3
+			case stringAt(i, 3, "TIA", "TCH"):
+				addBoth("X")
+				i += 3
+			case stringAt(i, 2, "TH"):
+				if stringAt(0, 3, "SCH") {
+					addBoth("T")
+				} else {
+					add("0", "T")
+				}
+				i += 2
+			default:
+				addBoth("T")
+				if stringAt(i+1, 1, "T", "D") {
+					i += 2
+				} else {
+					i++
+				}
+			}
+
+		case 'V':
+			addBoth("F")
+			if at(i+1) == 'V' {
+				i += 2
+			} else {
+				i++
+			}
+
+		case 'W':
+			switch {
+			case stringAt(i, 2, "WR"):
+				addBoth("R")
+				i += 2
+			case i == 0 && (isVowel(i+1) || stringAt(i, 2, "WH")):
+				if isVowel(i + 1) {
+					add("A", "F")
+				} else {
+					addBoth("A")
+				}
+				i++
+			case stringAt(i, 4, "WICZ", "WITZ"):
+				add("TS", "FX")
+				i += 4
+			case i == n-1 && isVowel(i-1):
+				add("", "F")
+				i++
+			default:
+				i++
+			}
+
+		case 'X':
+			addBoth("KS")
+			if stringAt(i+1, 1, "C", "X") {
+				i += 2
+			} else {
+				i++
+			}
+
+		case 'Z':
+			if at(i+1) == 'H' {
+				addBoth("J")
+				i += 2
+			} else {
+				if stringAt(i+1, 2, "ZO", "ZI", "ZA") {
+					add("S", "TS")
+				} else {
+					addBoth("S")
+				}
+				if at(i+1) == 'Z' {
+					i += 2
+				} else {
+					i++
+				}
+			}
+
+		default:
+			i++
 		}
 	}
 
-	// Pad with zeros
-	for len(result) < 4 {
-		result = append(result, '0')
+	p := pri.String()
+	sc := sec.String()
+	if len(p) > 4 {
+		p = p[:4]
 	}
-
-	return string(result)
+	if len(sc) > 4 {
+		sc = sc[:4]
+	}
+	if sc == "" {
+		sc = p
+	}
+	return p, sc
 }
 
 // ApplyCustomWords corrects words using fuzzy matching against a custom word list
@@ -294,14 +665,18 @@ func ApplyCustomWords(text string, customWords []string, threshold float64) stri
 	}
 
 	// Build lookup maps for custom words
-	customWordMap := make(map[string]string)      // lowercase -> original case
-	customSoundex := make(map[string][]string)    // soundex -> list of words
+	customWordMap := make(map[string]string)    // lowercase -> original case
+	customPhonetic := make(map[string][]string) // phonetic key (primary or secondary) -> candidate words
 
 	for _, w := range customWords {
 		lower := strings.ToLower(w)
 		customWordMap[lower] = w
-		sx := soundex(w)
-		customSoundex[sx] = append(customSoundex[sx], w)
+
+		primary, secondary := doubleMetaphone(w)
+		customPhonetic[primary] = append(customPhonetic[primary], w)
+		if secondary != primary {
+			customPhonetic[secondary] = append(customPhonetic[secondary], w)
+		}
 	}
 
 	words := strings.Fields(text)
@@ -345,11 +720,22 @@ func ApplyCustomWords(text string, customWords []string, threshold float64) stri
 			continue
 		}
 
-		// Try phonetic matching if fuzzy didn't work
-		wordSoundex := soundex(wordLower)
-		if candidates, ok := customSoundex[wordSoundex]; ok && len(candidates) > 0 {
-			// Use the first phonetic match
-			result = append(result, preservePunctuation(word, candidates[0]))
+		// Try phonetic matching if fuzzy didn't work: bucket by the input's
+		// primary key, matching against either key of each candidate, and
+		// break ties between candidates by Levenshtein distance so the
+		// result is deterministic.
+		wordPrimary, _ := doubleMetaphone(wordLower)
+		if candidates, ok := customPhonetic[wordPrimary]; ok && len(candidates) > 0 {
+			best := candidates[0]
+			bestDist := levenshteinDistance(wordLower, strings.ToLower(best))
+			for _, candidate := range candidates[1:] {
+				dist := levenshteinDistance(wordLower, strings.ToLower(candidate))
+				if dist < bestDist {
+					bestDist = dist
+					best = candidate
+				}
+			}
+			result = append(result, preservePunctuation(word, best))
 			continue
 		}
 
@@ -389,3 +775,100 @@ func preservePunctuation(original, replacement string) string {
 
 	return leadingPunct.String() + replacement + string(trailingRunes)
 }
+
+// defaultStreamFilterTailTokens is how many trailing tokens StreamFilter
+// holds back on each Push, by default, so a stutter or filler word split
+// across a chunk boundary ("wh wh" | "wh why") is still caught instead of
+// being collapsed/removed twice or not at all.
+const defaultStreamFilterTailTokens = 8
+
+// StreamFilter applies FilterTranscriptionOutput and ApplyCustomWords to a
+// live transcription incrementally. Those assume they have the whole
+// transcript, so calling them once per arriving chunk would miss
+// stutters/filler words straddling a chunk boundary. StreamFilter instead
+// buffers a tail window of the most recently filtered tokens, re-runs the
+// filters over tail+chunk on every Push, and only emits the portion of the
+// result that's past the tail window - the tail itself is never emitted
+// until a later Push (or Flush) confirms it isn't the start of a stutter
+// that continues in the next chunk. Not safe for concurrent use - a caller
+// drives one StreamFilter from a single transcription stream.
+type StreamFilter struct {
+	config      FilterConfig
+	customWords []string
+	threshold   float64
+	tailTokens  int
+	tail        string // filtered tokens held back from the last Push
+}
+
+// NewStreamFilter creates a StreamFilter. config controls collapseStutters/
+// removeFillerWords; customWords/threshold are passed to ApplyCustomWords
+// on every Push (see ApplyCustomWords for what threshold means). tailTokens
+// is how many trailing tokens to hold back as the stutter-detection
+// window; 0 uses defaultStreamFilterTailTokens.
+func NewStreamFilter(config FilterConfig, customWords []string, threshold float64, tailTokens int) *StreamFilter {
+	if tailTokens <= 0 {
+		tailTokens = defaultStreamFilterTailTokens
+	}
+	return &StreamFilter{
+		config:      config,
+		customWords: customWords,
+		threshold:   threshold,
+		tailTokens:  tailTokens,
+	}
+}
+
+// Push filters chunk in the context of the tail buffered from prior Push
+// calls and returns the text that's now safe to emit - everything except a
+// new tail window held back in case it's the start of a stutter or filler
+// word that continues in the next chunk.
+func (f *StreamFilter) Push(chunk string) (emitted string) {
+	if strings.TrimSpace(chunk) == "" {
+		return ""
+	}
+
+	combined := strings.TrimSpace(f.tail + " " + chunk)
+	filtered := f.filter(combined)
+	words := strings.Fields(filtered)
+
+	if len(words) <= f.tailTokens {
+		// Not enough filtered output yet to safely emit anything past the
+		// tail window - hold it all back.
+		f.tail = filtered
+		return ""
+	}
+
+	emitCount := len(words) - f.tailTokens
+	emitted = strings.Join(words[:emitCount], " ")
+	f.tail = strings.Join(words[emitCount:], " ")
+	return emitted
+}
+
+// Flush returns and clears whatever is still held back in the tail
+// window - call this at an utterance boundary (silence detected,
+// transcription stopped) so the last few words aren't lost.
+func (f *StreamFilter) Flush() string {
+	remaining := f.tail
+	f.tail = ""
+	return remaining
+}
+
+// Reset clears the buffered tail without returning it, for starting a new
+// utterance whose words shouldn't be filtered together with the previous
+// one's (e.g. the user cancelled dictation).
+func (f *StreamFilter) Reset() {
+	f.tail = ""
+}
+
+// filter runs the same collapseStutters/removeFillerWords/ApplyCustomWords
+// pipeline FilterTranscriptionOutput uses for a complete transcript.
+func (f *StreamFilter) filter(text string) string {
+	result := text
+	if f.config.CollapseStutters {
+		result = collapseStutters(result, f.config.StutterMinRepeats)
+	}
+	if f.config.RemoveFillerWords {
+		result = removeFillerWords(result)
+	}
+	result = ApplyCustomWords(result, f.customWords, f.threshold)
+	return cleanWhitespace(result)
+}
@@ -0,0 +1,160 @@
+package transcription
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	speech "cloud.google.com/go/speech/apiv1"
+	speechpb "cloud.google.com/go/speech/apiv1/speechpb"
+	"google.golang.org/api/option"
+)
+
+// GoogleProviderName is the name GoogleStreamingRecognizer reports from
+// Name.
+const GoogleProviderName = "google-speech"
+
+// GoogleStreamingRecognizer streams audio to the Google Cloud Speech-to-Text
+// streaming API over a long-lived bidirectional gRPC call. It implements
+// StreamingRecognizer.
+type GoogleStreamingRecognizer struct {
+	credentialsPath string
+}
+
+// NewGoogleStreamingRecognizer creates a recognizer that authenticates using
+// the service account credentials file at credentialsPath. An empty path
+// leaves the recognizer unconfigured (IsConfigured returns false) until one
+// is set.
+func NewGoogleStreamingRecognizer(credentialsPath string) *GoogleStreamingRecognizer {
+	return &GoogleStreamingRecognizer{credentialsPath: credentialsPath}
+}
+
+// SetCredentialsPath updates the service account credentials file used to
+// authenticate with the Speech-to-Text API.
+func (r *GoogleStreamingRecognizer) SetCredentialsPath(path string) {
+	r.credentialsPath = path
+}
+
+// Name identifies this recognizer in logs and settings.
+func (r *GoogleStreamingRecognizer) Name() string { return GoogleProviderName }
+
+// IsConfigured returns true if a credentials file has been set.
+func (r *GoogleStreamingRecognizer) IsConfigured() bool {
+	return r.credentialsPath != ""
+}
+
+// StartStream opens a new Google Cloud Speech streaming-recognize call and
+// sends the initial StreamingRecognitionConfig message.
+func (r *GoogleStreamingRecognizer) StartStream(ctx context.Context, sampleRate int, lang string) (StreamSession, error) {
+	if !r.IsConfigured() {
+		return nil, fmt.Errorf("google speech credentials not configured")
+	}
+
+	client, err := speech.NewClient(ctx, option.WithCredentialsFile(r.credentialsPath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create google speech client: %w", err)
+	}
+
+	stream, err := client.StreamingRecognize(ctx)
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to open google speech stream: %w", err)
+	}
+
+	if lang == "" {
+		lang = "en-US"
+	}
+
+	err = stream.Send(&speechpb.StreamingRecognizeRequest{
+		StreamingRequest: &speechpb.StreamingRecognizeRequest_StreamingConfig{
+			StreamingConfig: &speechpb.StreamingRecognitionConfig{
+				Config: &speechpb.RecognitionConfig{
+					Encoding:        speechpb.RecognitionConfig_LINEAR16,
+					SampleRateHertz: int32(sampleRate),
+					LanguageCode:    lang,
+				},
+				InterimResults: true,
+			},
+		},
+	})
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to send google speech streaming config: %w", err)
+	}
+
+	session := &googleStreamSession{
+		client:  client,
+		stream:  stream,
+		results: make(chan InterimResult),
+	}
+	go session.recvLoop()
+
+	return session, nil
+}
+
+// googleStreamSession wraps a speechpb.Speech_StreamingRecognizeClient as a
+// StreamSession.
+type googleStreamSession struct {
+	client *speech.Client
+	stream speechpb.Speech_StreamingRecognizeClient
+
+	results chan InterimResult
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// Send pushes another frame of PCM16 audio to the server.
+func (s *googleStreamSession) Send(pcm []byte) error {
+	return s.stream.Send(&speechpb.StreamingRecognizeRequest{
+		StreamingRequest: &speechpb.StreamingRecognizeRequest_AudioContent{
+			AudioContent: pcm,
+		},
+	})
+}
+
+// Results returns the channel of incremental transcription results.
+func (s *googleStreamSession) Results() <-chan InterimResult {
+	return s.results
+}
+
+// CloseSend signals the server that no more audio is coming; recvLoop keeps
+// draining any results still in flight until the server closes its side.
+func (s *googleStreamSession) CloseSend() error {
+	return s.stream.CloseSend()
+}
+
+// Close tears down the underlying gRPC connection immediately.
+func (s *googleStreamSession) Close() error {
+	s.closeOnce.Do(func() {
+		s.closeErr = s.client.Close()
+	})
+	return s.closeErr
+}
+
+// recvLoop forwards results from the server until the stream ends, then
+// closes the results channel.
+func (s *googleStreamSession) recvLoop() {
+	defer close(s.results)
+
+	for {
+		resp, err := s.stream.Recv()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			return
+		}
+
+		for _, result := range resp.Results {
+			if len(result.Alternatives) == 0 {
+				continue
+			}
+			s.results <- InterimResult{
+				Text:    result.Alternatives[0].Transcript,
+				IsFinal: result.IsFinal,
+			}
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package transcription
+
+import (
+	"regexp"
+	"strings"
+)
+
+// HallucinationFilterConfig tunes HallucinationFilter's thresholds and
+// blocklist. The zero value has Enabled false, so Process runs unfiltered
+// until SetHallucinationFilter is called - see DefaultHallucinationFilterConfig
+// for a ready-to-use starting point.
+type HallucinationFilterConfig struct {
+	Enabled bool
+
+	// NoSpeechProbThreshold drops a segment whose no_speech_prob (see
+	// buildWords) exceeds it - whisper.cpp was confident it heard silence,
+	// not speech.
+	NoSpeechProbThreshold float64
+	// AvgLogprobThreshold drops a segment whose avg_logprob falls below
+	// it - whisper.cpp was not confident in its own token choices.
+	AvgLogprobThreshold float64
+	// CompressionRatioThreshold drops a segment whose
+	// word count / unique word count ratio exceeds it, catching heavy
+	// word-level repetition ("the the the the the...").
+	CompressionRatioThreshold float64
+	// Blocklist drops a segment whose trimmed text matches any of these
+	// regexes - built-in canned phrases Whisper is known to hallucinate
+	// on silence or background noise. Callers extend it by appending to
+	// DefaultHallucinationBlocklist() before calling SetHallucinationFilter.
+	Blocklist []*regexp.Regexp
+}
+
+// DefaultHallucinationFilterConfig returns the filter enabled with the
+// thresholds and blocklist this package ships.
+func DefaultHallucinationFilterConfig() HallucinationFilterConfig {
+	return HallucinationFilterConfig{
+		Enabled:                   true,
+		NoSpeechProbThreshold:     0.6,
+		AvgLogprobThreshold:       -1.0,
+		CompressionRatioThreshold: 2.4,
+		Blocklist:                 DefaultHallucinationBlocklist(),
+	}
+}
+
+// DefaultHallucinationBlocklist returns freshly-compiled regexes for
+// canned phrases whisper.cpp is known to emit on silence or background
+// noise - mostly boilerplate lifted from the YouTube captions its
+// training data includes plenty of.
+func DefaultHallucinationBlocklist() []*regexp.Regexp {
+	patterns := []string{
+		`(?i)^thanks? for watching\.?!?$`,
+		`(?i)^thank you for watching\.?!?$`,
+		`(?i)^please (like,? )?subscribe`,
+		`(?i)^don'?t forget to (like|subscribe)`,
+		`(?i)^subscribe to (my|the) channel`,
+		`(?i)^\.+$`,
+		`(?i)^\[.*\]$`,
+		`(?i)^\(.*\)$`,
+	}
+
+	blocklist := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		blocklist = append(blocklist, regexp.MustCompile(p))
+	}
+	return blocklist
+}
+
+// classifyHallucination reports the reason Process's segmentCallback
+// should drop segmentText (and avgLogprob/noSpeechProb, its token-level
+// confidence stats from buildWords), or "" if it should be kept.
+func classifyHallucination(segmentText string, avgLogprob, noSpeechProb float64, cfg HallucinationFilterConfig) string {
+	trimmed := strings.TrimSpace(segmentText)
+	if trimmed == "" {
+		return ""
+	}
+
+	if noSpeechProb > cfg.NoSpeechProbThreshold {
+		return "no_speech_prob"
+	}
+	if avgLogprob < cfg.AvgLogprobThreshold {
+		return "avg_logprob"
+	}
+	for _, re := range cfg.Blocklist {
+		if re.MatchString(trimmed) {
+			return "blocklist"
+		}
+	}
+	if compressionRatio(trimmed) > cfg.CompressionRatioThreshold {
+		return "compression_ratio"
+	}
+
+	return ""
+}
+
+// compressionRatio is len(words) / len(set(words)) - a high ratio means a
+// short vocabulary is being repeated to fill the text, a common shape for
+// Whisper hallucinations on silence or noise.
+func compressionRatio(text string) float64 {
+	words := strings.Fields(strings.ToLower(text))
+	if len(words) == 0 {
+		return 0
+	}
+
+	unique := make(map[string]struct{}, len(words))
+	for _, w := range words {
+		unique[w] = struct{}{}
+	}
+
+	return float64(len(words)) / float64(len(unique))
+}
@@ -2,6 +2,23 @@ package transcription
 
 import "strings"
 
+// supportedLanguageCodes lists every ISO code GetLanguageCode can resolve a
+// name to - the same fixed set whisper.cpp itself supports, listed here
+// directly now that TranscriptionService no longer holds a whisper.Model
+// handle to query it from (see GetLanguages, WhisperBackend).
+var supportedLanguageCodes = []string{
+	"en", "zh", "de", "es", "ru", "ko", "fr", "ja", "pt", "tr",
+	"pl", "ca", "nl", "ar", "sv", "it", "id", "hi", "fi", "vi",
+	"he", "uk", "el", "ms", "cs", "ro", "da", "hu", "ta", "no",
+	"th", "ur", "hr", "bg", "lt", "la", "mi", "ml", "cy", "sk",
+	"te", "fa", "lv", "bn", "sr", "az", "sl", "kn", "et", "mk",
+	"br", "eu", "is", "hy", "ne", "mn", "bs", "kk", "sq", "sw",
+	"gl", "mr", "pa", "si", "km", "sn", "yo", "so", "af", "oc",
+	"ka", "be", "tg", "sd", "gu", "am", "yi", "lo", "uz", "fo",
+	"ht", "ps", "tk", "nn", "mt", "sa", "lb", "my", "bo", "tl",
+	"mg", "as", "tt", "haw", "ln", "ha", "ba", "jw", "su", "yue",
+}
+
 // GetLanguageCode converts a language name to its ISO code
 // This handles the mapping between frontend display names and Whisper's expected codes
 func GetLanguageCode(name string) string {
@@ -0,0 +1,313 @@
+package transcription
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"super-characters/vad"
+)
+
+// liveStreamPartialInterval is how often a running LiveStream decodes its
+// in-progress segment and emits a transcription-partial hypothesis.
+const liveStreamPartialInterval = 300 * time.Millisecond
+
+// liveStreamPendingPartials bounds how many transcription-partial events can
+// queue up waiting for the frontend to drain them. A stalled listener drops
+// the oldest queued partial instead of this package buffering unboundedly -
+// see LiveStream.emitPartial.
+const liveStreamPendingPartials = 4
+
+// liveStreamVADConfig derives the vad.VADService config a LiveStream uses to
+// slice incoming audio into segments from the same thresholds
+// WhisperCppBackend already configures its sessions' own VAD with (see
+// configureContext) - 250ms minimum speech, 100ms trailing silence - so a
+// live segment closes on the same silence gap whisper.cpp's internal VAD
+// would cut on, rather than a second, differently-tuned endpoint detector.
+func liveStreamVADConfig() vad.Config {
+	return vad.Config{
+		SilenceDuration:   100 * time.Millisecond,
+		MinSpeechDuration: 250 * time.Millisecond,
+		MaxSpeechDuration: 30 * time.Second,
+		SampleRate:        16000,
+	}
+}
+
+// LiveStream is one StartLiveStream session: a ring buffer of incoming PCM
+// fed by FeedLiveStream, sliced into segments by a vad.VADService on the
+// same boundaries WhisperCppBackend's own VAD uses, and decoded
+// incrementally so a caller gets a running "transcription-partial" hypothesis
+// for the in-progress segment instead of waiting for StopLiveStream - the
+// near-live UX WhisperLive offers over its own WebSocket protocol, here
+// layered on top of whatever WhisperBackend is active. Segments are stitched
+// together by feeding the tail of each finalized segment back in as the next
+// one's initial prompt (see finalize), capped to the same last-200-character
+// window setStreamPrompt already uses as its approximation of Whisper's
+// 224-token prompt budget.
+type LiveStream struct {
+	svc       *TranscriptionService
+	sessionID string
+	vadSvc    *vad.VADService
+
+	mu      sync.Mutex
+	current []float32 // in-progress segment, accumulated while vadSvc reports speech
+	closed  bool
+
+	partials chan string
+	stopCh   chan struct{}
+	doneCh   chan struct{}
+}
+
+// StartLiveStream opens a new LiveStream for sessionID, replacing any
+// existing one under the same ID. Feed it audio via FeedLiveStream and tear
+// it down with StopLiveStream once the caller is done recording.
+func (t *TranscriptionService) StartLiveStream(sessionID string) error {
+	if sessionID == "" {
+		return fmt.Errorf("live stream session ID required")
+	}
+	if !t.IsInitialized() {
+		return fmt.Errorf("transcription service not initialized")
+	}
+
+	ls := &LiveStream{
+		svc:       t,
+		sessionID: sessionID,
+		vadSvc:    vad.NewVADService(liveStreamVADConfig()),
+		partials:  make(chan string, liveStreamPendingPartials),
+		stopCh:    make(chan struct{}),
+		doneCh:    make(chan struct{}),
+	}
+	ls.vadSvc.SetCallbacks(ls.onSpeechStart, ls.onSpeechEnd)
+	ls.vadSvc.Start()
+
+	t.liveStreamMutex.Lock()
+	existing := t.liveStreams[sessionID]
+	t.liveStreams[sessionID] = ls
+	t.liveStreamMutex.Unlock()
+	if existing != nil {
+		existing.stop()
+	}
+
+	go ls.runPartials()
+	go ls.runEmitter()
+
+	slog.Info("live stream started", "session_id", sessionID)
+	return nil
+}
+
+// FeedLiveStream pushes another chunk of mono 16kHz float32 PCM into
+// sessionID's ring buffer.
+func (t *TranscriptionService) FeedLiveStream(sessionID string, samples []float32) error {
+	ls := t.getLiveStream(sessionID)
+	if ls == nil {
+		return fmt.Errorf("no live stream for session %s", sessionID)
+	}
+	ls.feed(samples)
+	return nil
+}
+
+// StopLiveStream tears sessionID's stream down. Segments vadSvc already
+// closed have had their transcription-final emitted; audio of a segment
+// still in progress is dropped, not decoded - see stop.
+func (t *TranscriptionService) StopLiveStream(sessionID string) error {
+	ls := t.getLiveStream(sessionID)
+	if ls == nil {
+		return fmt.Errorf("no live stream for session %s", sessionID)
+	}
+
+	t.liveStreamMutex.Lock()
+	delete(t.liveStreams, sessionID)
+	t.liveStreamMutex.Unlock()
+
+	ls.stop()
+	slog.Info("live stream stopped", "session_id", sessionID)
+	return nil
+}
+
+func (t *TranscriptionService) getLiveStream(sessionID string) *LiveStream {
+	t.liveStreamMutex.Lock()
+	defer t.liveStreamMutex.Unlock()
+	return t.liveStreams[sessionID]
+}
+
+// feed hands samples to vadSvc (which decides segment start/end) and, while
+// a segment is in progress, appends them to current for the next partial
+// decode.
+func (ls *LiveStream) feed(samples []float32) {
+	ls.vadSvc.ProcessSamples(samples)
+
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	if ls.closed {
+		return
+	}
+	if ls.vadSvc.IsSpeaking() {
+		ls.current = append(ls.current, samples...)
+	}
+}
+
+// onSpeechStart resets the in-progress segment buffer for a new utterance.
+func (ls *LiveStream) onSpeechStart() {
+	ls.mu.Lock()
+	ls.current = ls.current[:0]
+	ls.mu.Unlock()
+}
+
+// onSpeechEnd is vadSvc's callback once a segment's trailing silence exceeds
+// its configured duration. samples is the full utterance vadSvc accumulated,
+// which is decoded and emitted as this segment's transcription-final.
+func (ls *LiveStream) onSpeechEnd(samples []float32) {
+	ls.mu.Lock()
+	ls.current = ls.current[:0]
+	ls.mu.Unlock()
+
+	ls.finalize(samples)
+}
+
+// runPartials decodes the in-progress segment every liveStreamPartialInterval
+// and queues its hypothesis for runEmitter, until stop is called.
+func (ls *LiveStream) runPartials() {
+	ticker := time.NewTicker(liveStreamPartialInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ls.stopCh:
+			return
+		case <-ticker.C:
+			ls.mu.Lock()
+			if ls.closed || len(ls.current) == 0 {
+				ls.mu.Unlock()
+				continue
+			}
+			samples := make([]float32, len(ls.current))
+			copy(samples, ls.current)
+			ls.mu.Unlock()
+
+			text, err := ls.decode(samples, ls.svc.getStreamPrompt(ls.sessionID))
+			if err != nil {
+				slog.Warn("live stream partial decode failed", "session_id", ls.sessionID, "error", err)
+				continue
+			}
+			if text != "" {
+				ls.queuePartial(text)
+			}
+		}
+	}
+}
+
+// queuePartial enqueues text for runEmitter, dropping the oldest queued
+// partial first if the frontend hasn't kept up - see liveStreamPendingPartials.
+// runPartials only checks ls.closed before the (possibly slow) decode call
+// that produces text, not after, so this re-checks under ls.mu immediately
+// before sending - otherwise a stop() landing mid-decode could close
+// ls.partials out from under this send and panic.
+func (ls *LiveStream) queuePartial(text string) {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	if ls.closed {
+		return
+	}
+
+	select {
+	case ls.partials <- text:
+	default:
+		select {
+		case <-ls.partials:
+		default:
+		}
+		select {
+		case ls.partials <- text:
+		default:
+		}
+	}
+}
+
+// runEmitter drains queued partial hypotheses and emits them as
+// transcription-partial events, until stop closes ls.partials.
+func (ls *LiveStream) runEmitter() {
+	defer close(ls.doneCh)
+	for text := range ls.partials {
+		if ls.svc.app != nil {
+			ls.svc.app.Event.Emit("transcription-partial", map[string]interface{}{
+				"session_id": ls.sessionID,
+				"text":       text,
+				"timestamp":  time.Now().Unix(),
+			})
+		}
+	}
+}
+
+// decode checks out a pooled session, primes it with prompt (if it supports
+// PromptSetter), and runs a one-off Transcribe over samples.
+func (ls *LiveStream) decode(samples []float32, prompt string) (string, error) {
+	session, pool, err := ls.svc.acquireSession()
+	if err != nil {
+		return "", err
+	}
+	defer releaseSession(pool, session)
+
+	if ps, ok := session.(PromptSetter); ok && prompt != "" {
+		ps.SetInitialPrompt(prompt)
+	}
+
+	segments, _, err := session.Transcribe(samples, TranscribeParams{})
+	if err != nil {
+		return "", err
+	}
+
+	var text strings.Builder
+	for _, segment := range segments {
+		text.WriteString(segment.Text)
+		text.WriteString(" ")
+	}
+	return strings.TrimSpace(text.String()), nil
+}
+
+// finalize decodes samples (a full, VAD-closed segment), emits it as
+// transcription-final, and stitches its tail into the next segment's prompt.
+func (ls *LiveStream) finalize(samples []float32) {
+	if len(samples) == 0 {
+		return
+	}
+
+	text, err := ls.decode(samples, ls.svc.getStreamPrompt(ls.sessionID))
+	if err != nil {
+		slog.Warn("live stream final decode failed", "session_id", ls.sessionID, "error", err)
+		return
+	}
+	if text == "" {
+		return
+	}
+
+	ls.svc.setStreamPrompt(ls.sessionID, text)
+
+	if ls.svc.app != nil {
+		ls.svc.app.Event.Emit("transcription-final", map[string]interface{}{
+			"session_id": ls.sessionID,
+			"text":       text,
+			"timestamp":  time.Now().Unix(),
+		})
+	}
+	slog.Info("live stream final segment", "session_id", ls.sessionID, "text", text)
+}
+
+// stop tears the stream down: any audio still sitting in current when this
+// is called is dropped, not finalized, matching vadSvc.Stop() discarding
+// whatever in-progress speech it's mid-buffering.
+func (ls *LiveStream) stop() {
+	ls.mu.Lock()
+	if ls.closed {
+		ls.mu.Unlock()
+		return
+	}
+	ls.closed = true
+	ls.mu.Unlock()
+
+	ls.vadSvc.Stop()
+	close(ls.stopCh)
+	close(ls.partials)
+	<-ls.doneCh
+}
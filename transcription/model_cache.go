@@ -0,0 +1,221 @@
+package transcription
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+
+	"log/slog"
+)
+
+// modelCacheEntry is one model's fully configured BackendSession pool, as
+// returned by loadModelPool.
+type modelCacheEntry struct {
+	sessions []BackendSession
+	pool     chan BackendSession
+	sizeMB   int
+}
+
+// closeAsync drains every session back into pool (so a Process call that
+// checked one out before this entry was evicted keeps running on it
+// until it's done, same guarantee SwitchModel gives the active model),
+// closing each as it's drained, without blocking the caller - eviction
+// runs under ModelCache.mu and must not stall on an in-flight
+// transcription.
+func (e *modelCacheEntry) closeAsync(name string) {
+	go e.closeSync(name)
+}
+
+// closeSync does the same drain-then-close as closeAsync, but on the
+// caller's goroutine. Only safe to call when the caller is prepared to
+// block until every checked-out session returns, and when nothing else
+// is draining the same pool concurrently - see ModelCache.Pop.
+func (e *modelCacheEntry) closeSync(name string) {
+	for range e.sessions {
+		session := <-e.pool
+		session.Close()
+	}
+	slog.Info("closed cached model", "model", name)
+}
+
+// ModelCache keeps up to maxResident whisper models loaded in memory at
+// once, keyed by name, so switching back to a model the user has already
+// visited is instant instead of a reload from disk. Entries beyond
+// maxResident - or that would push estimated resident memory past
+// memoryBudgetMB - are evicted least-recently-used first, skipping
+// whichever entry is currently pinned (the active model a SwitchModel
+// call is switching away from, still serving in-flight Process calls).
+type ModelCache struct {
+	mu             sync.Mutex
+	entries        map[string]*modelCacheEntry
+	order          []string // least-recently-used first
+	maxResident    int
+	memoryBudgetMB int // 0 = unlimited
+	loader         func(modelPath string) ([]BackendSession, chan BackendSession, error)
+}
+
+// NewModelCache creates a cache that calls loader on a miss. maxResident
+// is clamped to at least 1.
+func NewModelCache(maxResident int, loader func(string) ([]BackendSession, chan BackendSession, error)) *ModelCache {
+	if maxResident < 1 {
+		maxResident = 1
+	}
+	return &ModelCache{
+		entries:     make(map[string]*modelCacheEntry),
+		maxResident: maxResident,
+		loader:      loader,
+	}
+}
+
+// SetLimits updates the cache's residency cap and memory budget (0 =
+// unlimited). Takes effect on the next admission - already-resident
+// entries beyond the new limits are not evicted until something else
+// needs the room.
+func (c *ModelCache) SetLimits(maxResident, memoryBudgetMB int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if maxResident < 1 {
+		maxResident = 1
+	}
+	c.maxResident = maxResident
+	c.memoryBudgetMB = memoryBudgetMB
+}
+
+// Has reports whether name is currently resident, without affecting its
+// LRU position.
+func (c *ModelCache) Has(name string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.entries[name]
+	return ok
+}
+
+// GetOrLoad returns name's cached (sessions, pool), loading it via the
+// cache's loader on a miss. pin names an entry (typically the
+// currently-active model) that eviction must skip, since it may still be
+// serving in-flight Process calls even though this call is about to
+// replace it.
+func (c *ModelCache) GetOrLoad(name, path string, estimateMB int, pin string) ([]BackendSession, chan BackendSession, error) {
+	c.mu.Lock()
+	if entry, ok := c.entries[name]; ok {
+		c.touch(name)
+		c.mu.Unlock()
+		return entry.sessions, entry.pool, nil
+	}
+	c.mu.Unlock()
+
+	// Load outside the lock so a disk read + session pool build for one
+	// model doesn't stall lookups/evictions for every other cached model.
+	sessions, pool, err := c.loader(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Another caller may have loaded the same name while we weren't
+	// holding the lock - keep whichever entry is already there and close
+	// the one we just built, rather than serving two different pools
+	// under the same name or leaking this one.
+	if entry, ok := c.entries[name]; ok {
+		c.touch(name)
+		for _, session := range sessions {
+			session.Close()
+		}
+		return entry.sessions, entry.pool, nil
+	}
+
+	c.evictToFit(estimateMB, pin)
+
+	c.entries[name] = &modelCacheEntry{sessions: sessions, pool: pool, sizeMB: estimateMB}
+	c.order = append(c.order, name)
+	return sessions, pool, nil
+}
+
+// Pop removes name from the cache's bookkeeping and hands the entry to the
+// caller instead of draining it itself, so a GetOrLoad racing the caller's
+// own (possibly delayed) drain sees a miss and loads a fresh pool rather
+// than re-adopting the one about to be closed. Returns nil if name isn't
+// resident. The caller is responsible for eventually closing the returned
+// entry - see modelCacheEntry.closeSync.
+func (c *ModelCache) Pop(name string) *modelCacheEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[name]
+	if !ok {
+		return nil
+	}
+	delete(c.entries, name)
+	c.removeFromOrder(name)
+	return entry
+}
+
+// evictToFit evicts least-recently-used entries (skipping pin) until
+// admitting an entry of size newMB would no longer exceed maxResident or
+// memoryBudgetMB. It's best-effort: if everything left is pinned, it
+// stops rather than looping forever, so the cache may briefly exceed its
+// limits by one entry while an old active model finishes draining.
+func (c *ModelCache) evictToFit(newMB int, pin string) {
+	i := 0
+	for len(c.order) > 0 && (len(c.entries) >= c.maxResident || (c.memoryBudgetMB > 0 && c.residentMB()+newMB > c.memoryBudgetMB)) {
+		if i >= len(c.order) {
+			return
+		}
+		name := c.order[i]
+		if name == pin {
+			i++
+			continue
+		}
+		entry := c.entries[name]
+		delete(c.entries, name)
+		c.order = append(c.order[:i], c.order[i+1:]...)
+		entry.closeAsync(name)
+	}
+}
+
+func (c *ModelCache) residentMB() int {
+	total := 0
+	for _, e := range c.entries {
+		total += e.sizeMB
+	}
+	return total
+}
+
+func (c *ModelCache) touch(name string) {
+	c.removeFromOrder(name)
+	c.order = append(c.order, name)
+}
+
+func (c *ModelCache) removeFromOrder(name string) {
+	for i, n := range c.order {
+		if n == name {
+			c.order = append(c.order[:i], c.order[i+1:]...)
+			return
+		}
+	}
+}
+
+// estimateModelMB parses a ModelInfo.Size string like "466 MB" or
+// "1.5 GB" into megabytes, for the cache's memory-budget guard. Returns 0
+// (no admission pressure from this entry) if it can't parse sizeStr -
+// these strings are free-text descriptions, not a guaranteed machine
+// format.
+func estimateModelMB(sizeStr string) int {
+	fields := strings.Fields(sizeStr)
+	if len(fields) != 2 {
+		return 0
+	}
+	value, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0
+	}
+	switch strings.ToUpper(fields[1]) {
+	case "GB":
+		return int(value * 1024)
+	case "MB":
+		return int(value)
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,267 @@
+package transcription
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// LatencyMode biases SelectRecommendedModel toward faster, lighter models at
+// some cost to accuracy.
+type LatencyMode string
+
+const (
+	// LatencyModeNormal applies no latency-specific bias.
+	LatencyModeNormal LatencyMode = ""
+	// LatencyModeLow prefers large-v3-turbo variants, which trade a little
+	// accuracy for much faster inference than the plain large models.
+	LatencyModeLow LatencyMode = "low"
+)
+
+// SelectionPrefs tunes SelectRecommendedModel's choice to the caller's needs.
+type SelectionPrefs struct {
+	// Locale is a BCP-47-ish locale string (e.g. "en-US"). When it starts
+	// with "en", English-only builds (the ".en" name suffix) are preferred
+	// over multilingual ones of the same tier.
+	Locale string
+	// Latency, when LatencyModeLow, prefers large-v3-turbo variants.
+	Latency LatencyMode
+	// MemoryBudgetBytes caps how much RAM the chosen model may require.
+	// Zero means "derive a budget from detected system RAM" - see
+	// SelectRecommendedModel.
+	MemoryBudgetBytes uint64
+}
+
+// modelTierRank orders model name prefixes from smallest to largest, used to
+// walk candidates from biggest down to first fit.
+var modelTierRank = map[string]int{
+	"tiny": 0, "base": 1, "small": 2, "medium": 3,
+	"large-v1": 4, "large-v2": 4, "large-v3": 5, "large-v3-turbo": 5,
+}
+
+// sizeStringPattern matches the "142 MB" / "2.9 GB" strings used in
+// ModelInfo.Size.
+var sizeStringPattern = regexp.MustCompile(`^([\d.]+)\s*(MB|GB)$`)
+
+// parseSizeBytes converts a ModelInfo.Size string like "466 MB" or "2.9 GB"
+// into a byte count.
+func parseSizeBytes(size string) (uint64, error) {
+	m := sizeStringPattern.FindStringSubmatch(strings.TrimSpace(size))
+	if m == nil {
+		return 0, fmt.Errorf("unrecognized size string %q", size)
+	}
+	value, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("unrecognized size string %q: %w", size, err)
+	}
+	switch m[2] {
+	case "GB":
+		value *= 1024 * 1024 * 1024
+	case "MB":
+		value *= 1024 * 1024
+	}
+	return uint64(value), nil
+}
+
+// requiredMemoryBytes estimates the RAM a model needs at inference time from
+// its on-disk file size, per whisper.cpp's own guidance: roughly 1.2x the
+// file size for f16 models, 1.5x for quantized ones (the dequantization
+// buffers whisper.cpp allocates alongside the compressed weights).
+func requiredMemoryBytes(model ModelInfo) (uint64, error) {
+	fileSize, err := parseSizeBytes(model.Size)
+	if err != nil {
+		return 0, err
+	}
+	factor := 1.2
+	if isQuantizedModel(model.Name) {
+		factor = 1.5
+	}
+	return uint64(float64(fileSize) * factor), nil
+}
+
+// isQuantizedModel reports whether name refers to a quantized (q5_0/q5_1/
+// q8_0) build rather than a plain f16 one.
+func isQuantizedModel(name string) bool {
+	return strings.Contains(name, "-q5_0") || strings.Contains(name, "-q5_1") || strings.Contains(name, "-q8_0")
+}
+
+// isEnglishOnlyModel reports whether name refers to an English-only build
+// (the ".en" suffix before any quantization/tdrz suffix).
+func isEnglishOnlyModel(name string) bool {
+	base := name
+	if idx := strings.Index(base, "-"); idx != -1 {
+		base = base[:idx]
+	}
+	return strings.HasSuffix(base, ".en")
+}
+
+// SelectRecommendedModel inspects available RAM, CPU core count, whether
+// this Mac is Apple Silicon, and free disk space under the model directory,
+// then picks the largest supported model whose estimated memory requirement
+// fits within prefs.MemoryBudgetBytes (or a budget derived from detected RAM
+// when that's zero). The chosen model and the reasoning behind it are
+// logged via slog so a support bundle shows why a given model was picked.
+func SelectRecommendedModel(prefs SelectionPrefs) ModelInfo {
+	totalRAM := detectSystemMemoryBytes()
+	freeDisk := detectFreeDiskBytes(modelStorageDir())
+	hw := DetectHardware()
+	appleSilicon := runtime.GOARCH == "arm64"
+
+	budget := prefs.MemoryBudgetBytes
+	if budget == 0 {
+		// Leave headroom for the OS, the app itself, and whatever else is
+		// running: use at most half of detected RAM, or a conservative 2GB
+		// fallback if RAM couldn't be detected at all.
+		if totalRAM > 0 {
+			budget = totalRAM / 2
+		} else {
+			budget = 2 * 1024 * 1024 * 1024
+		}
+	}
+
+	preferEnglish := strings.HasPrefix(strings.ToLower(prefs.Locale), "en")
+	preferQuantized := totalRAM > 0 && totalRAM < 8*1024*1024*1024
+	preferTurbo := prefs.Latency == LatencyModeLow
+
+	candidates := GetSupportedModels()
+	var best ModelInfo
+	bestRank := -1
+	var bestReason string
+
+	for _, m := range candidates {
+		// Diarization builds are a special-purpose tool, not a general
+		// recommendation target.
+		if isDiarizedModel(m.Name) {
+			continue
+		}
+		if preferEnglish != isEnglishOnlyModel(m.Name) {
+			continue
+		}
+		if preferQuantized != isQuantizedModel(m.Name) {
+			continue
+		}
+
+		rank, known := modelTierRank[tierKey(m.Name)]
+		if !known {
+			continue
+		}
+
+		required, err := requiredMemoryBytes(m)
+		if err != nil {
+			continue
+		}
+		if required > budget {
+			continue
+		}
+		if freeDisk > 0 {
+			fileSize, err := parseSizeBytes(m.Size)
+			if err == nil && fileSize > freeDisk {
+				continue
+			}
+		}
+
+		if preferTurbo && rank == modelTierRank["large-v3-turbo"] {
+			// Turbo variants outrank everything else once requested,
+			// regardless of the tier walk below.
+			best = m
+			bestRank = rank + 1
+			bestReason = "latency mode requested and a large-v3-turbo build fits the memory budget"
+			continue
+		}
+
+		if rank > bestRank {
+			best = m
+			bestRank = rank
+			bestReason = fmt.Sprintf("largest model fitting the %.1fGB memory budget for this tier preference", float64(budget)/(1024*1024*1024))
+		}
+	}
+
+	if bestRank == -1 {
+		// Nothing fit the filters above (extremely constrained hardware);
+		// fall back to the smallest, most conservative build.
+		best = fallbackModel(preferEnglish)
+		bestReason = "no candidate fit the detected memory/disk budget, falling back to the smallest available model"
+	}
+
+	slog.Info("model auto-selected",
+		"chosen", best.Name,
+		"reason", bestReason,
+		"totalRAMBytes", totalRAM,
+		"freeDiskBytes", freeDisk,
+		"cpuCores", hw.CPUCores,
+		"appleSilicon", appleSilicon,
+		"preferEnglish", preferEnglish,
+		"preferQuantized", preferQuantized,
+		"preferTurbo", preferTurbo,
+	)
+
+	return best
+}
+
+// tierKey strips quantization/language suffixes off a model name so it can
+// be looked up in modelTierRank.
+func tierKey(name string) string {
+	for _, suffix := range []string{"-tdrz", "-q5_0", "-q5_1", "-q8_0"} {
+		name = strings.TrimSuffix(name, suffix)
+	}
+	return strings.TrimSuffix(name, ".en")
+}
+
+// fallbackModel returns the smallest supported model, honoring only the
+// English-only preference, for hardware too constrained to fit anything
+// else considered.
+func fallbackModel(preferEnglish bool) ModelInfo {
+	for _, m := range GetSupportedModels() {
+		if m.Name == "tiny.en-q5_1" && preferEnglish {
+			return m
+		}
+		if m.Name == "tiny-q5_1" && !preferEnglish {
+			return m
+		}
+	}
+	return GetSupportedModels()[0]
+}
+
+// detectSystemMemoryBytes returns total physical RAM, or 0 if it can't be
+// determined (non-Darwin platforms, or sysctl unavailable).
+func detectSystemMemoryBytes() uint64 {
+	out, err := exec.Command("sysctl", "-n", "hw.memsize").Output()
+	if err != nil {
+		slog.Warn("failed to detect system memory via sysctl", "error", err)
+		return 0
+	}
+	value, err := strconv.ParseUint(strings.TrimSpace(string(out)), 10, 64)
+	if err != nil {
+		slog.Warn("failed to parse sysctl hw.memsize output", "output", string(out), "error", err)
+		return 0
+	}
+	return value
+}
+
+// modelStorageDir returns a directory that's expected to exist so
+// detectFreeDiskBytes has something to stat, even before any model has ever
+// been downloaded (the models subdirectory itself is only created on first
+// download).
+func modelStorageDir() string {
+	if configDir, err := os.UserConfigDir(); err == nil {
+		return configDir
+	}
+	return "."
+}
+
+// detectFreeDiskBytes returns free space on the filesystem holding dir, or 0
+// if it can't be determined.
+func detectFreeDiskBytes(dir string) uint64 {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(dir, &stat); err != nil {
+		slog.Warn("failed to stat free disk space for model directory", "dir", dir, "error", err)
+		return 0
+	}
+	return stat.Bavail * uint64(stat.Bsize)
+}
@@ -10,6 +10,16 @@ type ModelInfo struct {
 	FileName     string `json:"fileName"`
 	IsDownloaded bool   `json:"isDownloaded"`
 	IsActive     bool   `json:"isActive"`
+	// SHA256, if set, is the expected hex-encoded checksum of the
+	// downloaded file. DownloadModel verifies it after the fetch completes
+	// and refuses to keep a file that doesn't match. Built-in models leave
+	// this blank; custom models registered via RegisterCustomModel may set
+	// it to guard against corrupt or tampered downloads.
+	SHA256 string `json:"sha256,omitempty"`
+	// Custom marks a model added at runtime via RegisterCustomModel, as
+	// opposed to one of the built-in entries returned below. Set by
+	// TranscriptionService.allModels and not persisted.
+	Custom bool `json:"custom,omitempty"`
 }
 
 // GetSupportedModels returns the list of all supported Whisper models
@@ -0,0 +1,319 @@
+package transcription
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"os"
+	"strings"
+	"sync"
+)
+
+// DefaultSocketName is the Unix socket filename RPCServer listens on by
+// convention, mirroring avatar's "avatar_worker.sock".
+const DefaultSocketName = "transcription.sock"
+
+// RPCServer exposes TranscriptionService's core operations - Process (as
+// Transcribe), SwitchModel, SetLanguage, SetUnloadTimeout, ListModels, and
+// DownloadModel - to external clients, per transcription/proto/transcribe.proto.
+// See that file's doc comment for why this is net/rpc/jsonrpc rather than
+// real gRPC. Every Wails event TranscriptionService already emits keeps
+// firing normally; RPCServer is an additional consumer of the same
+// process, not a replacement transport for the embedded UI.
+type RPCServer struct {
+	svc *TranscriptionService
+
+	mu        sync.Mutex
+	authToken string
+	listener  net.Listener
+	// streams tracks a running tokens_consumed count per stream_id, so
+	// Transcribe replies can report a cumulative total the way a real
+	// server-streaming RPC would.
+	streams map[string]uint64
+}
+
+// NewRPCServer wraps svc for RPC exposure. svc must already be (or become)
+// initialized via its own Initialize - RPCServer just forwards calls to it.
+func NewRPCServer(svc *TranscriptionService) *RPCServer {
+	return &RPCServer{svc: svc, streams: make(map[string]uint64)}
+}
+
+// SetAuthToken requires every request arriving over ServeTCP to carry this
+// token in its AuthToken field. Has no effect on Serve's Unix socket
+// transport, which relies on filesystem permissions instead (the same
+// split NewPermissionsService and the /metrics endpoint use elsewhere in
+// this codebase). Empty (the default) disables the check.
+func (r *RPCServer) SetAuthToken(token string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.authToken = token
+}
+
+// Serve listens on a Unix domain socket at socketPath until ctx is
+// cancelled. This is the default, credential-free transport for local
+// clients (a CLI, a same-machine web server).
+func (r *RPCServer) Serve(ctx context.Context, socketPath string) error {
+	os.Remove(socketPath)
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", socketPath, err)
+	}
+	return r.serve(ctx, listener, false)
+}
+
+// ServeTCP listens on addr (e.g. ":9090") until ctx is cancelled. Unlike
+// Serve, every request must present the token set via SetAuthToken (when
+// one is configured), since a TCP port has no filesystem permission
+// boundary to fall back on.
+func (r *RPCServer) ServeTCP(ctx context.Context, addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	return r.serve(ctx, listener, true)
+}
+
+func (r *RPCServer) serve(ctx context.Context, listener net.Listener, requireAuth bool) error {
+	r.mu.Lock()
+	r.listener = listener
+	r.mu.Unlock()
+
+	server := rpc.NewServer()
+	if err := server.RegisterName("TranscriptionService", &rpcHandler{server: r, requireAuth: requireAuth}); err != nil {
+		listener.Close()
+		return fmt.Errorf("failed to register RPC service: %w", err)
+	}
+
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			select {
+			case <-ctx.Done():
+				return nil
+			default:
+				slog.Warn("[TranscriptionRPC] Accept failed", "error", err)
+				return err
+			}
+		}
+		go server.ServeCodec(jsonrpc.NewServerCodec(conn))
+	}
+}
+
+// Close stops listening. Safe to call even if Serve/ServeTCP was never
+// called.
+func (r *RPCServer) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.listener == nil {
+		return nil
+	}
+	err := r.listener.Close()
+	r.listener = nil
+	return err
+}
+
+// rpcHandler implements the net/rpc-visible methods backing
+// TranscriptionService. Every exported method follows net/rpc's
+// func(args, *reply) error signature, one pair per transcribe.proto RPC.
+type rpcHandler struct {
+	server      *RPCServer
+	requireAuth bool
+}
+
+func (h *rpcHandler) checkAuth(token string) error {
+	if !h.requireAuth {
+		return nil
+	}
+	h.server.mu.Lock()
+	expected := h.server.authToken
+	h.server.mu.Unlock()
+	if expected == "" || token != expected {
+		return fmt.Errorf("unauthorized")
+	}
+	return nil
+}
+
+// AudioChunkArgs mirrors proto.AudioChunk.
+type AudioChunkArgs struct {
+	StreamID  string
+	Samples   []float32
+	AuthToken string
+	// Params, when non-nil, mirrors proto.TranscribeParams and is applied
+	// to this chunk's call only - see TranscriptionService.Process.
+	Params *RPCTranscribeParams
+}
+
+// RPCTranscribeParams mirrors proto.TranscribeParams / TranscribeParams.
+type RPCTranscribeParams struct {
+	Language            string
+	Translate           bool
+	BeamSize            int
+	Temperature         float32
+	TemperatureFallback float32
+	EntropyThreshold    float32
+	InitialPrompt       string
+	VADThreshold        float32
+	MinSpeechMs         int
+	MinSilenceMs        int
+	MaxSegmentLen       int
+	SuppressNonSpeech   bool
+}
+
+func (p *RPCTranscribeParams) toServiceParams() TranscribeParams {
+	return TranscribeParams{
+		Language:            p.Language,
+		Translate:           p.Translate,
+		BeamSize:            p.BeamSize,
+		Temperature:         p.Temperature,
+		TemperatureFallback: p.TemperatureFallback,
+		EntropyThreshold:    p.EntropyThreshold,
+		InitialPrompt:       p.InitialPrompt,
+		VADThreshold:        p.VADThreshold,
+		MinSpeechMs:         p.MinSpeechMs,
+		MinSilenceMs:        p.MinSilenceMs,
+		MaxSegmentLen:       p.MaxSegmentLen,
+		SuppressNonSpeech:   p.SuppressNonSpeech,
+	}
+}
+
+// SegmentBatch mirrors a stream of proto.Segment: the segments produced by
+// one AudioChunk, since net/rpc has no server-streaming reply of its own.
+type SegmentBatch struct {
+	Segments []RPCSegment
+}
+
+// RPCSegment mirrors proto.Segment.
+type RPCSegment struct {
+	Text             string
+	Start            float64
+	End              float64
+	DetectedLanguage string
+	TokensConsumed   uint64
+}
+
+// Transcribe processes one chunk of args.Samples and replies with the
+// segment(s) it produced. TranscriptionService.Process currently reports
+// only the combined text for a chunk (not per-segment start/end), so
+// Start/End are zero here until that's threaded through - word-level
+// timestamps (see EnableWordTimestamps) will make this exact once wired
+// up. TokensConsumed approximates tokens as whitespace-separated words
+// until real per-token counts are available from the bindings.
+func (h *rpcHandler) Transcribe(args *AudioChunkArgs, reply *SegmentBatch) error {
+	if err := h.checkAuth(args.AuthToken); err != nil {
+		return err
+	}
+
+	var text, lang string
+	var err error
+	if args.Params != nil {
+		text, lang, err = h.server.svc.Process(args.StreamID, args.Samples, context.Background(), args.Params.toServiceParams())
+	} else {
+		text, lang, err = h.server.svc.Process(args.StreamID, args.Samples, context.Background())
+	}
+	if err != nil {
+		return err
+	}
+	if text == "" {
+		return nil
+	}
+
+	h.server.mu.Lock()
+	h.server.streams[args.StreamID] += uint64(len(strings.Fields(text)))
+	tokens := h.server.streams[args.StreamID]
+	h.server.mu.Unlock()
+
+	reply.Segments = []RPCSegment{{
+		Text:             text,
+		DetectedLanguage: lang,
+		TokensConsumed:   tokens,
+	}}
+	return nil
+}
+
+// EndStream discards the token-count accounting and chained prompt context
+// for streamID, for clients that want to reuse the ID later without
+// inheriting the old stream's state.
+func (h *rpcHandler) EndStream(streamID string, _ *struct{}) error {
+	h.server.mu.Lock()
+	delete(h.server.streams, streamID)
+	h.server.mu.Unlock()
+	h.server.svc.EndStream(streamID)
+	return nil
+}
+
+type SwitchModelArgs struct {
+	ModelName string
+	AuthToken string
+}
+
+func (h *rpcHandler) SwitchModel(args *SwitchModelArgs, _ *struct{}) error {
+	if err := h.checkAuth(args.AuthToken); err != nil {
+		return err
+	}
+	return h.server.svc.SwitchModel(context.Background(), args.ModelName)
+}
+
+type SetLanguageArgs struct {
+	Language  string
+	AuthToken string
+}
+
+func (h *rpcHandler) SetLanguage(args *SetLanguageArgs, _ *struct{}) error {
+	if err := h.checkAuth(args.AuthToken); err != nil {
+		return err
+	}
+	return h.server.svc.SetLanguage(args.Language)
+}
+
+type SetUnloadTimeoutArgs struct {
+	Timeout   string
+	AuthToken string
+}
+
+func (h *rpcHandler) SetUnloadTimeout(args *SetUnloadTimeoutArgs, _ *struct{}) error {
+	if err := h.checkAuth(args.AuthToken); err != nil {
+		return err
+	}
+	h.server.svc.SetUnloadTimeout(ModelUnloadTimeout(args.Timeout))
+	return nil
+}
+
+type ListModelsArgs struct {
+	AuthToken string
+}
+
+type ListModelsReply struct {
+	Models []ModelInfo
+}
+
+func (h *rpcHandler) ListModels(args *ListModelsArgs, reply *ListModelsReply) error {
+	if err := h.checkAuth(args.AuthToken); err != nil {
+		return err
+	}
+	reply.Models = h.server.svc.ListModels()
+	return nil
+}
+
+type DownloadModelArgs struct {
+	ModelName string
+	AuthToken string
+}
+
+// DownloadModel blocks until the download (and checksum verification)
+// completes. Progress is only available in-process via the
+// "model-download-progress" Wails event today; transcribe.proto declares
+// DownloadModel as a unary RPC, so no progress reaches an external client.
+func (h *rpcHandler) DownloadModel(args *DownloadModelArgs, _ *struct{}) error {
+	if err := h.checkAuth(args.AuthToken); err != nil {
+		return err
+	}
+	return h.server.svc.DownloadModel(context.Background(), args.ModelName)
+}
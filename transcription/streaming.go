@@ -0,0 +1,51 @@
+package transcription
+
+import "context"
+
+// InterimResult is a single result delivered by a StreamSession as audio is
+// transcribed incrementally. IsFinal marks a result the recognizer considers
+// settled (it won't change on subsequent results for the same utterance);
+// earlier, non-final results for the same utterance may be revised by later
+// ones.
+type InterimResult struct {
+	Text    string
+	IsFinal bool
+}
+
+// StreamingRecognizer transcribes audio incrementally over a long-lived
+// bidirectional stream, instead of requiring a complete utterance up front
+// like TranscriptionService.Process. Implementations are wired directly into
+// App's continuous-conversation flow and selected by a Settings flag (see
+// Settings.STTStreamingMode).
+type StreamingRecognizer interface {
+	// Name identifies the recognizer, for logging and settings.
+	Name() string
+	// IsConfigured reports whether the recognizer has what it needs to run
+	// (an API key, service account credentials, etc).
+	IsConfigured() bool
+	// StartStream opens a new streaming session. sampleRate is the rate of
+	// the PCM16 audio that will be passed to StreamSession.Send; lang is a
+	// BCP-47 language hint (empty means auto-detect, if supported).
+	StartStream(ctx context.Context, sampleRate int, lang string) (StreamSession, error)
+}
+
+// StreamSession is a single open streaming-recognition request. Send is
+// called from one goroutine as audio frames arrive; Results is drained by
+// another goroutine. Callers must call Close (directly, or via CloseSend
+// followed by draining Results to completion) to release the underlying
+// connection.
+type StreamSession interface {
+	// Send pushes another frame of little-endian mono PCM16 audio into the
+	// stream. It must not be called concurrently with itself.
+	Send(pcm []byte) error
+	// Results returns the channel of incremental transcription results. The
+	// channel is closed once the server has sent its last result following
+	// CloseSend, or the session fails.
+	Results() <-chan InterimResult
+	// CloseSend signals the server that no more audio is coming for the
+	// current utterance, without tearing down the session. Any results still
+	// in flight are delivered on Results before it closes.
+	CloseSend() error
+	// Close tears down the session immediately, releasing its resources.
+	Close() error
+}
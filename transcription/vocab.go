@@ -0,0 +1,330 @@
+package transcription
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// VocabFileName is the vocabulary file created inside the app support
+// directory (usually ~/.super-characters), loaded by LoadVocabFile/
+// NewVocabLoader.
+const VocabFileName = "vocab.jsonc"
+
+// VocabConfig is the user-editable vocabulary file shape LoadVocabFile
+// parses. It's JSON with // and /* */ comments tolerated - users will want
+// to comment out entries they're experimenting with rather than deleting
+// them outright.
+type VocabConfig struct {
+	// Fillers adds extra words to treat as filler words, on top of the
+	// built-in fillerWords (see EffectiveFillers).
+	Fillers []string `json:"fillers,omitempty"`
+	// DisabledFillers turns off built-in filler words the user doesn't
+	// want stripped, e.g. "like".
+	DisabledFillers []string `json:"disabled_fillers,omitempty"`
+	// CustomWords is the vocabulary ApplyVocab corrects transcribed text
+	// against.
+	CustomWords []VocabWord `json:"custom_words,omitempty"`
+	// StutterMinRepeats overrides collapseStutters' default of 3
+	// consecutive repetitions. 0 means "use the default".
+	StutterMinRepeats int `json:"stutter_min_repeats,omitempty"`
+}
+
+// VocabWord is one CustomWords entry.
+type VocabWord struct {
+	// Word is the correct spelling to substitute in.
+	Word string `json:"word"`
+	// Aliases are transcription-error spellings of Word to also match,
+	// e.g. "coupernettis" for "kubernetes" - matched both verbatim and
+	// phonetically (see ApplyVocab), in addition to Word's own Double
+	// Metaphone key.
+	Aliases []string `json:"aliases,omitempty"`
+	// Casing controls how Word is rendered when substituted in: "" or
+	// "preserve" (default) uses Word as written, "upper", "lower", and
+	// "title" override it.
+	Casing string `json:"casing,omitempty"`
+}
+
+// render returns Word formatted per Casing.
+func (w VocabWord) render() string {
+	switch w.Casing {
+	case "upper":
+		return strings.ToUpper(w.Word)
+	case "lower":
+		return strings.ToLower(w.Word)
+	case "title":
+		return strings.Title(strings.ToLower(w.Word)) //nolint:staticcheck // no dependency manager to vendor golang.org/x/text/cases
+	default:
+		return w.Word
+	}
+}
+
+// LoadVocabFile reads and parses a JSONC (JSON with // and /* */ comments)
+// vocabulary file at path into a VocabConfig.
+func LoadVocabFile(path string) (VocabConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return VocabConfig{}, fmt.Errorf("failed to read vocab file: %w", err)
+	}
+
+	var config VocabConfig
+	if err := json.Unmarshal(stripJSONComments(data), &config); err != nil {
+		return VocabConfig{}, fmt.Errorf("failed to parse vocab file: %w", err)
+	}
+	return config, nil
+}
+
+// stripJSONComments removes // line comments and /* */ block comments from
+// data, leaving everything inside JSON string literals untouched, so the
+// result can be handed to json.Unmarshal.
+func stripJSONComments(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	inString := false
+	escaped := false
+
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+
+		if inString {
+			out = append(out, c)
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch {
+		case c == '"':
+			inString = true
+			out = append(out, c)
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+			if i < len(data) {
+				out = append(out, '\n')
+			}
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			i += 2
+			for i+1 < len(data) && !(data[i] == '*' && data[i+1] == '/') {
+				i++
+			}
+			i++ // land on the closing '/'
+		default:
+			out = append(out, c)
+		}
+	}
+
+	return out
+}
+
+// ApplyVocab corrects words in text using vocab's CustomWords. It's
+// ApplyCustomWords' counterpart for vocabulary loaded from a VocabConfig:
+// each VocabWord's Aliases are matched verbatim and phonetically alongside
+// Word itself, and a match is replaced with Word rendered per its Casing -
+// so "q8s" and "coupernettis" can both correct to the same "Kubernetes",
+// rather than replacing with whichever alias happened to match.
+func ApplyVocab(text string, vocab VocabConfig) string {
+	if len(vocab.CustomWords) == 0 || text == "" {
+		return text
+	}
+
+	exact := make(map[string]VocabWord)
+	phonetic := make(map[string][]VocabWord)
+
+	for _, w := range vocab.CustomWords {
+		surfaceForms := append([]string{w.Word}, w.Aliases...)
+		for _, form := range surfaceForms {
+			exact[strings.ToLower(form)] = w
+
+			primary, secondary := doubleMetaphone(form)
+			phonetic[primary] = append(phonetic[primary], w)
+			if secondary != primary {
+				phonetic[secondary] = append(phonetic[secondary], w)
+			}
+		}
+	}
+
+	words := strings.Fields(text)
+	result := make([]string, 0, len(words))
+
+	for _, word := range words {
+		wordLower := strings.ToLower(stripPunctuation(word))
+		if wordLower == "" {
+			result = append(result, word)
+			continue
+		}
+
+		if match, ok := exact[wordLower]; ok {
+			result = append(result, preservePunctuation(word, match.render()))
+			continue
+		}
+
+		primary, _ := doubleMetaphone(wordLower)
+		if candidates, ok := phonetic[primary]; ok && len(candidates) > 0 {
+			best := candidates[0]
+			bestDist := levenshteinDistance(wordLower, strings.ToLower(best.Word))
+			for _, candidate := range candidates[1:] {
+				dist := levenshteinDistance(wordLower, strings.ToLower(candidate.Word))
+				if dist < bestDist {
+					bestDist = dist
+					best = candidate
+				}
+			}
+			result = append(result, preservePunctuation(word, best.render()))
+			continue
+		}
+
+		result = append(result, word)
+	}
+
+	return strings.Join(result, " ")
+}
+
+// defaultVocabPollInterval mirrors config.CharacterConfigLoader's polling
+// cadence - this repo has no dependency manager to vendor fsnotify, so
+// Watch polls the file's mtime on a timer instead of reacting to a real
+// filesystem-change notification.
+const defaultVocabPollInterval = 2 * time.Second
+
+// VocabLoader loads a VocabConfig from a JSONC file and keeps it up to
+// date, either by polling (Watch) or on demand (Reload, e.g. from
+// App.ReloadVocab).
+type VocabLoader struct {
+	path string
+
+	mu     sync.RWMutex
+	config VocabConfig
+	mtime  time.Time
+
+	stopCh chan struct{}
+}
+
+// NewVocabLoader creates a loader for the JSONC vocab file at path. The
+// file doesn't need to exist yet - Reload treats a missing file as an
+// empty VocabConfig rather than an error, since most users won't have one.
+func NewVocabLoader(path string) *VocabLoader {
+	return &VocabLoader{path: path}
+}
+
+// Reload re-reads and re-parses the vocab file, replacing the loader's
+// in-memory config. A missing file resets to an empty VocabConfig instead
+// of failing.
+func (l *VocabLoader) Reload() error {
+	info, err := os.Stat(l.path)
+	if os.IsNotExist(err) {
+		l.mu.Lock()
+		l.config = VocabConfig{}
+		l.mtime = time.Time{}
+		l.mu.Unlock()
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to stat vocab file: %w", err)
+	}
+
+	config, err := LoadVocabFile(l.path)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	l.config = config
+	l.mtime = info.ModTime()
+	l.mu.Unlock()
+
+	slog.Info("[Vocab] Loaded vocabulary file", "path", l.path, "customWords", len(config.CustomWords))
+	return nil
+}
+
+// Config returns the most recently loaded VocabConfig.
+func (l *VocabLoader) Config() VocabConfig {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.config
+}
+
+// EffectiveFillers returns the built-in fillerWords merged with the loaded
+// config's Fillers (added, enabled) and DisabledFillers (turned off),
+// without mutating the package-level fillerWords map.
+func (l *VocabLoader) EffectiveFillers() map[string]bool {
+	config := l.Config()
+
+	effective := make(map[string]bool, len(fillerWords)+len(config.Fillers))
+	for word, enabled := range fillerWords {
+		effective[word] = enabled
+	}
+	for _, word := range config.Fillers {
+		effective[strings.ToLower(word)] = true
+	}
+	for _, word := range config.DisabledFillers {
+		effective[strings.ToLower(word)] = false
+	}
+	return effective
+}
+
+// Watch starts polling the vocab file for changes, calling Reload whenever
+// its mtime changes (including the file appearing or disappearing). Safe
+// to call more than once; later calls are no-ops until StopWatch is
+// called.
+func (l *VocabLoader) Watch() {
+	if l.stopCh != nil {
+		return
+	}
+	stop := make(chan struct{})
+	l.stopCh = stop
+	go l.watchLoop(stop)
+}
+
+// StopWatch halts the polling goroutine started by Watch.
+func (l *VocabLoader) StopWatch() {
+	if l.stopCh == nil {
+		return
+	}
+	close(l.stopCh)
+	l.stopCh = nil
+}
+
+func (l *VocabLoader) watchLoop(stop chan struct{}) {
+	ticker := time.NewTicker(defaultVocabPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if l.hasChanged() {
+				if err := l.Reload(); err != nil {
+					slog.Warn("[Vocab] Failed to reload vocab file", "error", err)
+				}
+			}
+		}
+	}
+}
+
+// hasChanged reports whether the vocab file's mtime differs from what
+// Reload last saw.
+func (l *VocabLoader) hasChanged() bool {
+	info, err := os.Stat(l.path)
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if os.IsNotExist(err) {
+		return !l.mtime.IsZero()
+	}
+	if err != nil {
+		return false
+	}
+	return !info.ModTime().Equal(l.mtime)
+}
@@ -3,9 +3,7 @@ package transcription
 import (
 	"context"
 	"fmt"
-	"io"
 	"log/slog"
-	"net/http"
 	"os"
 	"path/filepath"
 	goruntime "runtime"
@@ -15,7 +13,6 @@ import (
 
 	"super-characters/utils"
 
-	whisper "github.com/ggerganov/whisper.cpp/bindings/go/pkg/whisper"
 	"github.com/wailsapp/wails/v3/pkg/application"
 )
 
@@ -23,6 +20,11 @@ import (
 type HardwareProfile struct {
 	CPUCores        int
 	PerformanceTier string // "low", "medium", "high", "ultra"
+	// Accelerators lists the GPU backends DetectHardware found signs of on
+	// this machine, most-preferred first - see detectAccelerators. Always
+	// non-empty: a machine with none detected still has DeviceCPU as its
+	// sole entry.
+	Accelerators []string
 }
 
 // AdaptiveConfig holds adaptive Whisper configuration
@@ -30,12 +32,36 @@ type AdaptiveConfig struct {
 	Threads     int
 	BeamSize    int
 	Temperature float32
+	// Device is the accelerator loadModelPool should prefer for this model
+	// (one of the DeviceXxx constants), chosen from HardwareProfile.Accelerators
+	// or a caller's WhisperCppBackend.SetPreferredDevice override - see
+	// GetAdaptiveConfig and deviceFallbackChain. WhisperCppBackend falls back
+	// toward DeviceCPU if loading on this device fails.
+	Device string
+	// DeviceIndex selects among multiple adapters of the same Device kind
+	// (e.g. a second CUDA card). Always 0 today - DetectHardware has no way
+	// to enumerate individual adapters, only backend kinds.
+	DeviceIndex int
 }
 
+// Device kind constants for AdaptiveConfig.Device / WhisperCppBackend's
+// preferred-device setting. These mirror the accelerator families the
+// Windows whisper.dll binding referenced in this project's research enumerates
+// (CUDA, Metal, Vulkan, DirectML, CoreML), plus DeviceCPU as the universal
+// fallback.
+const (
+	DeviceCPU      = "cpu"
+	DeviceCUDA     = "cuda"
+	DeviceMetal    = "metal"
+	DeviceVulkan   = "vulkan"
+	DeviceDirectML = "directml"
+	DeviceCoreML   = "coreml"
+)
+
 // DetectHardware detects system hardware capabilities
 func DetectHardware() HardwareProfile {
 	cpuCores := goruntime.NumCPU()
-	
+
 	var tier string
 	switch {
 	case cpuCores >= 8:
@@ -47,21 +73,76 @@ func DetectHardware() HardwareProfile {
 	default:
 		tier = "low"
 	}
-	
+
 	return HardwareProfile{
 		CPUCores:        cpuCores,
 		PerformanceTier: tier,
+		Accelerators:    detectAccelerators(),
 	}
 }
 
+// detectAccelerators reports which GPU backends are plausibly available on
+// this machine, most-preferred first. Like the rest of DetectHardware, this
+// is a coarse, OS/architecture-based heuristic rather than a real device
+// query (the whisper.cpp Go bindings this package is built against don't
+// expose a device-enumeration API) - it exists so deviceFallbackChain has
+// more than just DeviceCPU to offer on a machine that's likely to have a
+// working accelerator, not to guarantee one actually initializes.
+func detectAccelerators() []string {
+	var accelerators []string
+	switch goruntime.GOOS {
+	case "darwin":
+		// Every Mac capable of running this app's minimum macOS version has
+		// Metal; Core ML is Apple's separate ANE/GPU path whisper.cpp can
+		// also target on the same hardware.
+		accelerators = append(accelerators, DeviceMetal, DeviceCoreML)
+	case "windows":
+		// DirectML runs on any DirectX 12 GPU (vendor-agnostic), so it's
+		// offered before the vendor-specific CUDA/Vulkan paths.
+		accelerators = append(accelerators, DeviceDirectML, DeviceCUDA, DeviceVulkan)
+	case "linux":
+		accelerators = append(accelerators, DeviceCUDA, DeviceVulkan)
+	}
+	return append(accelerators, DeviceCPU)
+}
+
+// deviceFallbackChain returns the ordered list of devices WhisperCppBackend's
+// Load should try for profile: preferred first (if non-empty), then
+// profile.Accelerators in DetectHardware's own preference order, always
+// ending in DeviceCPU - deduplicated, since preferred may already be one of
+// profile.Accelerators.
+func deviceFallbackChain(profile HardwareProfile, preferred string) []string {
+	var chain []string
+	seen := make(map[string]bool)
+	add := func(device string) {
+		if device == "" || seen[device] {
+			return
+		}
+		seen[device] = true
+		chain = append(chain, device)
+	}
+
+	add(preferred)
+	for _, device := range profile.Accelerators {
+		add(device)
+	}
+	add(DeviceCPU)
+	return chain
+}
+
 // GetAdaptiveConfig returns adaptive Whisper configuration based on hardware
 func GetAdaptiveConfig(profile HardwareProfile) AdaptiveConfig {
 	// Uniformly use performance-focused settings (Greedy Sampling)
 	// VoiceInk uses Greedy (BeamSize=1) and Temp=0.2 for speed/latency
+	device := DeviceCPU
+	if len(profile.Accelerators) > 0 {
+		device = profile.Accelerators[0]
+	}
 	return AdaptiveConfig{
 		Threads:     min(profile.CPUCores, 8),
 		BeamSize:    1,   // Greedy sampling for best latency
 		Temperature: 0.2, // Slight creativity, matches VoiceInk
+		Device:      device,
 	}
 }
 
@@ -83,22 +164,99 @@ const (
 	ModelUnload5Minutes    ModelUnloadTimeout = "5m"
 )
 
-// TranscriptionService handles speech-to-text using whisper.cpp
+// TranscriptionService handles speech-to-text using a pluggable
+// WhisperBackend (whisper.cpp in-process by default - see
+// BackendRegistry/SetWhisperImplementation).
 type TranscriptionService struct {
-	model            whisper.Model
-	context          whisper.Context
+	// backendRegistry holds every WhisperBackend available to this
+	// service and tracks which one is active - see
+	// SetWhisperImplementation.
+	backendRegistry *BackendRegistry
+	// sessions holds every BackendSession created for the current model,
+	// for broadcasting a config change (language, ...) to all of them.
+	// sessionPool holds whichever of those are currently checked in - see
+	// acquireSession/releaseSession.
+	sessions         []BackendSession
+	sessionPool      chan BackendSession
+	concurrency      int // 0 = use contextPoolSize's default formula
 	modelPath        string
 	currentModelName string
 	modelMutex       sync.RWMutex
-	processMutex     sync.Mutex // Ensure only one Process call runs at a time
-	lastPrompt       string     // For prompt chaining - stores last ~200 chars of transcription
-	app              *application.App
+	// switchMutex serializes SwitchModel calls so two concurrent switches
+	// can't race to swap in after each other and leave the loser's staged
+	// model orphaned instead of closed. SetLanguage also takes it for its
+	// whole duration, so a model switch can't commit while a language
+	// change is still being applied to (and read back from) the pool being
+	// switched away from. It does not guard Process/acquireSession, which
+	// only ever touch modelMutex.
+	switchMutex sync.Mutex
+	// drainMu serializes the two operations that fully drain the active
+	// pool's sessions - SetLanguage's broadcast and unloadModel's eviction
+	// of the active model - so they can never pull from the same channel
+	// at once and each end up short of the sessions they're waiting for.
+	// SetLanguage holds it from before it even reads the pool, so unloadModel
+	// can pop the active entry out of modelCache concurrently (that only
+	// touches bookkeeping) but blocks on drainMu until SetLanguage's drain
+	// is done before it actually closes any session. Neither holder keeps
+	// modelMutex locked for the blocking part of its wait, so a slow drain
+	// (e.g. waiting on a long-running Transcribe call) delays at most the
+	// other drainer, not every modelMutex caller.
+	drainMu    sync.Mutex
+	lastPrompt string // For prompt chaining - stores last ~200 chars of transcription
+	// streamPrompts holds the chained prompt for each active streamID (see
+	// Process), independent of which pooled session eventually processes
+	// that stream's next chunk.
+	streamPrompts map[string]string
+	streamMutex   sync.Mutex
+	app           *application.App
 
 	// Model memory management
-	lastActivityTime  time.Time
-	unloadTimeout     ModelUnloadTimeout
-	idleCheckStop     chan struct{}
-	idleCheckRunning  bool
+	lastActivityTime time.Time
+	unloadTimeout    ModelUnloadTimeout
+	idleCheckStop    chan struct{}
+	idleCheckRunning bool
+
+	// wordTimestampsEnabled controls whether Process includes per-word
+	// timestamps/confidence in its emitted payload - see
+	// EnableWordTimestamps. Sessions always compute Segment.Words
+	// themselves now, so this is a presentation toggle rather than
+	// something that needs broadcasting to every session.
+	wordTimestampsEnabled bool
+
+	// hallucinationFilter is read once per Process call (not per segment)
+	// under filterMutex - see SetHallucinationFilter.
+	hallucinationFilter HallucinationFilterConfig
+	filterMutex         sync.RWMutex
+
+	// languageMode controls whether Process runs per-call language
+	// detection - see SetLanguageMode.
+	languageMode  LanguageMode
+	languageMutex sync.RWMutex
+	// langCache holds each streamID's most recent DetectLanguage result,
+	// so back-to-back segments from the same stream don't re-run
+	// detection every call - see detectLanguageForStream.
+	langCache      map[string]langCacheEntry
+	langCacheMutex sync.Mutex
+
+	// modelCache keeps recently-used models resident so SwitchModel can
+	// flip back to one instantly instead of reloading it - see
+	// loadCached and ModelCache.
+	modelCache *ModelCache
+
+	// liveStreams holds each active StartLiveStream session, keyed by its
+	// sessionID - see StartLiveStream/FeedLiveStream/StopLiveStream.
+	liveStreams     map[string]*LiveStream
+	liveStreamMutex sync.Mutex
+}
+
+// Word is a single word-level token within a transcribed segment, only
+// populated on "transcription-segment" events once EnableWordTimestamps(true)
+// has been called.
+type Word struct {
+	Text        string  `json:"text"`
+	Start       float64 `json:"start"`
+	End         float64 `json:"end"`
+	Probability float32 `json:"probability"`
 }
 
 // SetApp sets the Wails application instance
@@ -106,16 +264,225 @@ func (t *TranscriptionService) SetApp(app *application.App) {
 	t.app = app
 }
 
-// NewTranscriptionService creates a new transcription service
+// NewTranscriptionService creates a new transcription service, with every
+// built-in WhisperBackend registered and WhisperCppBackendName active by
+// default - see SetWhisperImplementation.
 func NewTranscriptionService() *TranscriptionService {
-	return &TranscriptionService{
+	registry := NewBackendRegistry()
+	whisperCppBackend := NewWhisperCppBackend()
+	registry.Register(WhisperCppBackendName, whisperCppBackend)
+	registry.Register(FasterWhisperBackendName, NewFasterWhisperBackend())
+	registry.Register(RemoteWhisperBackendName, NewRemoteWhisperBackend())
+	registry.SetActive("")
+
+	t := &TranscriptionService{
+		backendRegistry:  registry,
 		modelPath:        getModelPath("base.en"), // Default to base.en
 		currentModelName: "base.en",
 		unloadTimeout:    ModelUnloadNever, // Default: keep loaded
 		lastActivityTime: time.Now(),
+		streamPrompts:    make(map[string]string),
+		languageMode:     LanguageModeFixed,
+		langCache:        make(map[string]langCacheEntry),
+		liveStreams:      make(map[string]*LiveStream),
+	}
+	whisperCppBackend.SetFallbackNotifier(t.emitModelFallback)
+	t.modelCache = NewModelCache(defaultMaxResidentModels(), t.loadModelPool)
+	return t
+}
+
+// emitModelFallback emits a model-fallback event reporting that Load gave up
+// on fromDevice and moved on to toDevice for modelPath, wired to
+// WhisperCppBackend.SetFallbackNotifier above - the only backend with a
+// local accelerator to fall back from (see DeviceSelector).
+func (t *TranscriptionService) emitModelFallback(modelPath, fromDevice, toDevice, reason string) {
+	slog.Warn("whisper device fallback", "model", modelPath, "from", fromDevice, "to", toDevice, "reason", reason)
+	if t.app != nil {
+		t.app.Event.Emit("model-fallback", map[string]interface{}{
+			"model":     modelPath,
+			"from":      fromDevice,
+			"to":        toDevice,
+			"reason":    reason,
+			"timestamp": time.Now().Unix(),
+		})
+	}
+}
+
+// SetPreferredDevice sets which accelerator the active WhisperBackend should
+// try first, if it implements DeviceSelector (only WhisperCppBackend does
+// today). Takes effect the next time the model is (re)loaded, same as
+// SetWhisperImplementation.
+func (t *TranscriptionService) SetPreferredDevice(device string) error {
+	backend, err := t.backendRegistry.Active()
+	if err != nil {
+		return err
+	}
+	ds, ok := backend.(DeviceSelector)
+	if !ok {
+		return fmt.Errorf("%s backend does not support device selection", backend.Name())
+	}
+	ds.SetPreferredDevice(device)
+	slog.Info("set preferred device", "backend", backend.Name(), "device", device)
+	return nil
+}
+
+// GetPreferredDevice returns the active backend's preferred device, or "" if
+// it doesn't implement DeviceSelector or none has been set.
+func (t *TranscriptionService) GetPreferredDevice() string {
+	backend, err := t.backendRegistry.Active()
+	if err != nil {
+		return ""
+	}
+	if ds, ok := backend.(DeviceSelector); ok {
+		return ds.PreferredDevice()
+	}
+	return ""
+}
+
+// ListDevices returns every device the active backend's Load would consider,
+// for populating a settings picker - just DeviceCPU for a backend that
+// doesn't implement DeviceSelector.
+func (t *TranscriptionService) ListDevices() []string {
+	backend, err := t.backendRegistry.Active()
+	if err != nil {
+		return []string{DeviceCPU}
+	}
+	if ds, ok := backend.(DeviceSelector); ok {
+		return ds.ListDevices()
+	}
+	return []string{DeviceCPU}
+}
+
+// SetWhisperImplementation switches which registered WhisperBackend
+// loadModelPool uses. Takes effect the next time the model is (re)loaded
+// (Initialize, SwitchModel, or a reload after an idle unload), not on
+// whatever is already resident in modelCache under the previous backend.
+func (t *TranscriptionService) SetWhisperImplementation(name string) error {
+	t.backendRegistry.SetActive(name)
+	if _, err := t.backendRegistry.Active(); err != nil {
+		return err
+	}
+	slog.Info("set whisper implementation", "backend", name)
+	return nil
+}
+
+// GetWhisperImplementation returns the name of the currently active
+// WhisperBackend.
+func (t *TranscriptionService) GetWhisperImplementation() (string, error) {
+	backend, err := t.backendRegistry.Active()
+	if err != nil {
+		return "", err
+	}
+	return backend.Name(), nil
+}
+
+// ListWhisperImplementations returns the names of every registered
+// WhisperBackend, for populating a settings picker.
+func (t *TranscriptionService) ListWhisperImplementations() []string {
+	return t.backendRegistry.List()
+}
+
+// defaultMaxResidentModels is a starting point for how many whisper
+// models ModelCache keeps loaded at once, scaled off DetectHardware's
+// CPU-based performance tier. DetectHardware doesn't report installed
+// RAM/VRAM, so this is a coarse stand-in for the "available RAM/VRAM"
+// budget rather than a real measurement - SetModelCacheLimits overrides
+// it for a caller that knows its system's actual memory.
+func defaultMaxResidentModels() int {
+	switch DetectHardware().PerformanceTier {
+	case "ultra":
+		return 3
+	case "high":
+		return 2
+	default:
+		return 1
+	}
+}
+
+// SetConcurrency sets how many BackendSession instances Initialize (and a
+// later ensureModelLoaded reload) creates to share the loaded model,
+// letting that many Process calls run in parallel. Takes effect the next
+// time the model is (re)loaded, not on the currently-loaded one. n <= 0
+// resets to the default formula - see contextPoolSize.
+func (t *TranscriptionService) SetConcurrency(n int) {
+	t.modelMutex.Lock()
+	defer t.modelMutex.Unlock()
+	t.concurrency = n
+}
+
+// contextPoolSize returns how many BackendSession instances to create for
+// the given adaptive thread count: the value set via SetConcurrency if
+// any, otherwise max(1, hwProfile.CPUCores/adaptiveConfig.Threads) so
+// per-session thread budgets don't oversubscribe the CPU.
+func (t *TranscriptionService) contextPoolSize(hwProfile HardwareProfile, adaptiveConfig AdaptiveConfig) int {
+	if t.concurrency > 0 {
+		return t.concurrency
+	}
+	n := hwProfile.CPUCores / adaptiveConfig.Threads
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
+
+// acquireSession checks out a BackendSession from the pool, blocking until
+// one is available. The returned pool must be passed back to
+// releaseSession instead of re-reading t.sessionPool, so a session that
+// was checked out before a model reload (SwitchModel, unloadModel) is
+// returned to the pool it actually came from rather than a newer,
+// differently-sized one.
+func (t *TranscriptionService) acquireSession() (BackendSession, chan BackendSession, error) {
+	t.modelMutex.RLock()
+	pool := t.sessionPool
+	t.modelMutex.RUnlock()
+
+	if pool == nil {
+		return nil, nil, fmt.Errorf("transcription service not initialized")
+	}
+	return <-pool, pool, nil
+}
+
+// releaseSession returns session to pool (see acquireSession).
+func releaseSession(pool chan BackendSession, session BackendSession) {
+	if pool != nil {
+		pool <- session
 	}
 }
 
+// getStreamPrompt returns the chained prompt context for streamID, or ""
+// for a stream that hasn't produced any transcription yet.
+func (t *TranscriptionService) getStreamPrompt(streamID string) string {
+	t.streamMutex.Lock()
+	defer t.streamMutex.Unlock()
+	return t.streamPrompts[streamID]
+}
+
+// setStreamPrompt records streamID's chained prompt context, trimmed to the
+// same last-200-characters window UpdatePromptContext uses.
+func (t *TranscriptionService) setStreamPrompt(streamID, text string) {
+	if streamID == "" || text == "" {
+		return
+	}
+	if len(text) > 200 {
+		text = text[len(text)-200:]
+	}
+	t.streamMutex.Lock()
+	defer t.streamMutex.Unlock()
+	t.streamPrompts[streamID] = text
+}
+
+// EndStream discards streamID's chained prompt context, for callers that
+// reuse stream IDs across unrelated recording sessions.
+func (t *TranscriptionService) EndStream(streamID string) {
+	t.streamMutex.Lock()
+	delete(t.streamPrompts, streamID)
+	t.streamMutex.Unlock()
+
+	t.langCacheMutex.Lock()
+	delete(t.langCache, streamID)
+	t.langCacheMutex.Unlock()
+}
+
 // getModelPath returns the appropriate path for the model file
 func getModelPath(modelName string) string {
 	if modelName == "" {
@@ -133,7 +500,7 @@ func getModelPath(modelName string) string {
 		// If we are getting the path to write/download, we prefer this location
 		// But if we are just looking for an existing model, we should check others too
 	}
-	
+
 	// 2. Try to get the executable directory (works for built apps, read-only mostly)
 	if execPath, err := os.Executable(); err == nil {
 		execDir := filepath.Dir(execPath)
@@ -160,12 +527,12 @@ func getModelPath(modelName string) string {
 	return filepath.Join("models", filename)
 }
 
-// Initialize loads the Whisper model and creates a context
+// Initialize loads the Whisper model and creates a session pool
 func (t *TranscriptionService) Initialize(modelName string) (err error) {
 	if modelName == "" {
 		modelName = "base.en" // Default model
 	}
-	
+
 	// Update current model name (caller handles locking if needed)
 	t.currentModelName = modelName
 
@@ -197,181 +564,334 @@ func (t *TranscriptionService) Initialize(modelName string) (err error) {
 		slog.Info("model found", "path", t.modelPath)
 	}
 
-	// Load the model
+	// Load the model and its session pool
 	slog.Info("loading whisper model")
-	model, err := whisper.New(t.modelPath)
+	t.wordTimestampsEnabled = false
+	sessions, pool, err := t.loadCached(modelName, t.modelPath, "")
 	if err != nil {
-		return fmt.Errorf("failed to load whisper model from %s: %w", t.modelPath, err)
+		return err
 	}
 	slog.Info("whisper model loaded successfully")
 
-	// Create context
-	slog.Info("creating whisper context")
-	defer func() {
-		if r := recover(); r != nil {
-			slog.Error("panic during context creation", "panic", r)
-		}
-	}()
-	context, err := model.NewContext()
-	if err != nil {
-		model.Close()
-		return fmt.Errorf("failed to create whisper context: %w", err)
-	}
-	slog.Info("whisper context created successfully")
-
-	slog.Info("about to assign model and context")
+	t.sessions = sessions
+	t.sessionPool = pool
 
-	t.model = model
-	t.context = context
+	slog.Info("whisper transcription service initialized successfully", "sessions", len(sessions))
+	return nil
+}
 
-	slog.Info("model and context assigned successfully")
+// loadModelPool loads modelPath via the active WhisperBackend and builds a
+// fully configured pool of BackendSession instances for it, without
+// touching the service's current t.sessions/t.sessionPool - the caller
+// decides when (or whether) to adopt the result. Initialize has nothing
+// to swap out yet; SwitchModel stages the new model here, then swaps it in
+// under modelMutex.Lock() only once this succeeds, so a failed load never
+// disturbs the model already serving Process calls.
+func (t *TranscriptionService) loadModelPool(modelPath string) ([]BackendSession, chan BackendSession, error) {
+	backend, err := t.backendRegistry.Active()
+	if err != nil {
+		return nil, nil, err
+	}
 
-	slog.Info("configuring whisper context")
-	
-	// Detect hardware and get adaptive configuration
 	hwProfile := DetectHardware()
 	adaptiveConfig := GetAdaptiveConfig(hwProfile)
-	
 	slog.Info("hardware detected", "cpu_cores", hwProfile.CPUCores, "tier", hwProfile.PerformanceTier)
 	slog.Info("adaptive config", "threads", adaptiveConfig.Threads, "beam_size", adaptiveConfig.BeamSize, "temperature", adaptiveConfig.Temperature)
-	
-	// Configure context for real-time transcription with adaptive settings
-	context.SetThreads(uint(adaptiveConfig.Threads)) // Adaptive thread count
-	slog.Info("set threads", "threads", adaptiveConfig.Threads)
-	context.SetTranslate(false) // Transcribe to English
-	slog.Info("set translate", "translate", false)
-	context.SetLanguage("auto") // Default to auto-detect; caller sets specific language via SetLanguage()
-	slog.Info("set language", "language", "auto")
-	context.SetMaxSegmentLength(200) // Reasonable segment length limit
-	slog.Info("set max segment length", "max_segment_length", 200)
-
-	// 1. Enable Beam Search with adaptive beam size
-	context.SetBeamSize(adaptiveConfig.BeamSize)
-	slog.Info("set beam size", "beam_size", adaptiveConfig.BeamSize)
-
-	// 2. Set Entropy Threshold to reduce hallucinations
-	context.SetEntropyThold(2.4) // Match VoiceInk default (was 2.2)
-	slog.Info("set entropy threshold", "entropy_thold", 2.4)
-
-	// 3. Set Temperature with adaptive value for quality/speed balance
-	context.SetTemperature(adaptiveConfig.Temperature)
-	slog.Info("set temperature", "temperature", adaptiveConfig.Temperature)
-
-	// 4. Enable Temperature Fallback
-	context.SetTemperatureFallback(0.2)
-	slog.Info("set temperature fallback", "temperature_fallback", 0.2)
-
-	// 5. Repetition Penalty not available in bindings, relying on VAD/Entropy
-	// context.SetRepetitionPenalty(1.1)
-	// fmt.Println("Set repetition penalty: 1.1")
-
-	// Note: no-speech threshold is controlled via VAD threshold instead
-	// The whisper.cpp Go bindings don't expose SetNoSpeechThold directly
-	// VAD threshold of 0.50 effectively handles silence detection
-	
-	// Set initial prompt to stabilize the model - using minimal prompt to avoid hallucinations
-	// With longer context windows (5s), we can use prompts safely
-	context.SetInitialPrompt("Meeting transcription.")
-	slog.Info("set initial prompt", "prompt", "Meeting transcription.")
-
-	// 5. Enable Voice Activity Detection (VAD) to reduce processing of silence
-	// Check for VAD model
-	vadModelName := "silero-v6.2.0"
-	vadModelPath := getModelPath(vadModelName)
-	vadEnabled := false
-
-	if _, err := os.Stat(vadModelPath); os.IsNotExist(err) {
-		slog.Info("VAD model not found, downloading", "path", vadModelPath)
-		// Try to download VAD model
-		// We use a simplified download here directly since downloadModel assumes it's in the supported list
-		// Or we can add it to supported list but that might confuse the UI if not handled
-		// For now, let's just reuse the helper if possible or implement simple download
-		vadURL := "https://huggingface.co/ggml-org/whisper-vad/resolve/main/ggml-silero-v6.2.0.bin"
-		
-		if err := func() error {
-			resp, err := http.Get(vadURL)
-			if err != nil {
-				return err
-			}
-			defer resp.Body.Close()
-			if resp.StatusCode != http.StatusOK {
-				return fmt.Errorf("HTTP %d", resp.StatusCode)
-			}
-			out, err := os.Create(vadModelPath)
-			if err != nil {
-				return err
+
+	poolSize := t.contextPoolSize(hwProfile, adaptiveConfig)
+	slog.Info("creating whisper session pool", "backend", backend.Name(), "size", poolSize, "threads_per_context", adaptiveConfig.Threads)
+
+	sessions := make([]BackendSession, 0, poolSize)
+	pool := make(chan BackendSession, poolSize)
+	for i := 0; i < poolSize; i++ {
+		session, err := backend.Load(modelPath)
+		if err != nil {
+			for _, s := range sessions {
+				s.Close()
 			}
-			defer out.Close()
-			_, err = io.Copy(out, resp.Body)
-			return err
-		}(); err != nil {
-			slog.Error("failed to download VAD model, VAD will be disabled", "error", err)
-		} else {
-			slog.Info("VAD model downloaded", "path", vadModelPath)
-			vadEnabled = true
+			return nil, nil, fmt.Errorf("failed to create whisper session %d/%d: %w", i+1, poolSize, err)
 		}
-	} else {
-		vadEnabled = true
-	}
-
-	if vadEnabled {
-		context.SetVAD(true)
-		context.SetVADModelPath(vadModelPath)
-		// Tune VAD parameters for better speech segment detection
-		// Based on meeting-minutes best practices
-		context.SetVADThreshold(0.50)
-		context.SetVADMinSpeechMs(250)       // 250ms (Matches VoiceInk) - prevents chopping words
-		context.SetVADMinSilenceMs(100)      // 100ms (Matches VoiceInk) - cuts sooner
-		// Note: Higher min_speech prevents Whisper from processing very short segments
-		// that often result in hallucinations or empty transcriptions
-		
-		slog.Info("set VAD", "enabled", true, "model", vadModelPath, "threshold", 0.50, "min_speech_ms", 250, "min_silence_ms", 100)
-	} else {
-		context.SetVAD(false)
-		slog.Info("set VAD", "enabled", false, "reason", "model missing")
+		sessions = append(sessions, session)
+		pool <- session
 	}
 
-	context.SetTokenTimestamps(false)
-	slog.Info("set token timestamps", "enabled", false)
+	return sessions, pool, nil
+}
 
-	slog.Info("whisper transcription service initialized successfully")
-	return nil
+// loadCached fetches modelName's (sessions, pool) from modelCache, building
+// it via loadModelPool on a miss. pin names an entry modelCache's LRU
+// eviction must skip - see ModelCache.GetOrLoad.
+func (t *TranscriptionService) loadCached(modelName, modelPath, pin string) ([]BackendSession, chan BackendSession, error) {
+	return t.modelCache.GetOrLoad(modelName, modelPath, t.modelSizeMB(modelName), pin)
 }
 
-// SetLanguage updates the language used for transcription
+// modelSizeMB estimates modelName's resident memory footprint in MB from
+// its advertised ModelInfo.Size string, or 0 if the model isn't found in
+// allModels() or its Size can't be parsed - see estimateModelMB.
+func (t *TranscriptionService) modelSizeMB(modelName string) int {
+	for _, m := range allModels() {
+		if m.Name == modelName {
+			return estimateModelMB(m.Size)
+		}
+	}
+	return 0
+}
+
+// SetLanguage updates the language used for transcription, across every
+// session in the pool. A session's underlying whisper.cpp context isn't
+// safe to touch while a Process call is decoding through it, so rather than
+// broadcast to t.sessions directly, this drains every session out of the
+// pool first - acquiring one currently checked out by another goroutine's
+// Process call blocks until that call releases it - applies the change,
+// then returns them all. It also takes switchMutex for its whole duration,
+// the same lock SwitchModel holds for its whole duration, so the two can
+// never interleave - without that, SwitchModel could commit a new active
+// pool while this call is still draining/relanguaging the old one, and the
+// change would land on a pool nobody reads from anymore while the new
+// active model silently kept its old language. drainMu is acquired before
+// pool/n are even read (not just around the drain loop), so unloadModel
+// can't pop the active model out of modelCache and drain-close its pool
+// out from under this call between the read and the drain - it'll block on
+// drainMu until this call releases it. That's the only ordering that
+// matters there: Pop only touches modelCache's bookkeeping, not the pool
+// channel itself, so it's safe for unloadModel to run concurrently with
+// this call's modelMutex read as long as its actual drain (closeSync)
+// waits its turn on drainMu. The wait for in-flight sessions happens under
+// drainMu, not modelMutex, so a long-running Transcribe call delays at
+// most unloadModel's own drain (see drainMu) and a concurrent SwitchModel
+// (see switchMutex), not every other caller of modelMutex (new Process
+// calls, ListModels, ...).
 func (t *TranscriptionService) SetLanguage(lang string) error {
+	t.switchMutex.Lock()
+	defer t.switchMutex.Unlock()
+
+	t.drainMu.Lock()
+	defer t.drainMu.Unlock()
+
 	t.modelMutex.RLock()
-	defer t.modelMutex.RUnlock()
+	pool := t.sessionPool
+	n := len(t.sessions)
+	t.modelMutex.RUnlock()
 
-	if t.context == nil {
+	if pool == nil || n == 0 {
 		return fmt.Errorf("transcription service not initialized")
 	}
 
+	acquired := make([]BackendSession, 0, n)
+	defer func() {
+		for _, session := range acquired {
+			releaseSession(pool, session)
+		}
+	}()
+	for len(acquired) < n {
+		acquired = append(acquired, <-pool)
+	}
+
 	// Resolve language code from name
 	langCode := GetLanguageCode(lang)
 	slog.Info("setting language", "language", lang, "code", langCode)
 
-	// Update the prompt based on language
-	// Prompt removed to prevent hallucinations
-	// prompt := "The following is a live transcription of a conversation."
-	// if lang != "en" {
-	// 	prompt = "The following is a live transcription of a conversation." // Consider localizing this if possible
-	// }
-	// t.context.SetInitialPrompt(prompt)
+	for _, session := range acquired {
+		if err := session.SetLanguage(langCode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EnableWordTimestamps toggles whether Process includes per-word
+// timestamps and confidence in its emitted "transcription-segment"
+// payload (see Word). Sessions always compute this breakdown themselves
+// now (see Segment.Words), so unlike the old per-context
+// SetTokenTimestamps broadcast, there's nothing to push out to the pool -
+// this just flips what Process attaches to the event.
+func (t *TranscriptionService) EnableWordTimestamps(enabled bool) error {
+	t.modelMutex.Lock()
+	defer t.modelMutex.Unlock()
+
+	if len(t.sessions) == 0 {
+		return fmt.Errorf("transcription service not initialized")
+	}
+
+	t.wordTimestampsEnabled = enabled
+	slog.Info("set word timestamps", "enabled", enabled)
+	return nil
+}
+
+// SetHallucinationFilter installs cfg as the hallucination filter Process's
+// segment loop runs every segment through before emitting it - see
+// classifyHallucination. Pass DefaultHallucinationFilterConfig() for this
+// package's built-in thresholds and blocklist, or a zero
+// HallucinationFilterConfig{} to turn filtering back off.
+func (t *TranscriptionService) SetHallucinationFilter(cfg HallucinationFilterConfig) {
+	t.filterMutex.Lock()
+	defer t.filterMutex.Unlock()
+	t.hallucinationFilter = cfg
+	slog.Info("set hallucination filter", "enabled", cfg.Enabled)
+}
 
-	return t.context.SetLanguage(langCode)
+// getHallucinationFilter returns the currently configured hallucination
+// filter for Process to consult.
+func (t *TranscriptionService) getHallucinationFilter() HallucinationFilterConfig {
+	t.filterMutex.RLock()
+	defer t.filterMutex.RUnlock()
+	return t.hallucinationFilter
 }
 
-// GetLanguages returns the list of supported languages
+// GetLanguages returns the list of supported languages.
 func (t *TranscriptionService) GetLanguages() []string {
-	if t.model == nil {
-		return []string{"en"} // Default fallback
+	return supportedLanguageCodes
+}
+
+// LanguageMode selects how Process picks a language for each call - see
+// SetLanguageMode.
+type LanguageMode string
+
+const (
+	// LanguageModeFixed keeps whatever SetLanguage last configured on the
+	// pooled sessions, unchanged for every call. The default.
+	LanguageModeFixed LanguageMode = "fixed"
+	// LanguageModeAuto hands language detection to the backend's own
+	// in-decode auto-detection (SetLanguage("auto")) - the behavior every
+	// mode had before this one existed.
+	LanguageModeAuto LanguageMode = "auto"
+	// LanguageModeDetect runs DetectLanguage on the first ~30s of each
+	// Process call's audio and routes that call explicitly to the
+	// result, caching per streamID so back-to-back segments from the
+	// same stream skip re-running it - see detectLanguageForStream.
+	LanguageModeDetect LanguageMode = "detect"
+)
+
+// langCacheEntry is a cached DetectLanguage result for one streamID - see
+// detectLanguageForStream.
+type langCacheEntry struct {
+	lang string
+	prob float32
+	at   time.Time
+}
+
+// langCacheTTL is how long a streamID's cached language detection is
+// reused before DetectLanguage runs again: long enough to skip rerunning
+// it on short back-to-back segments from the same speaker, short enough
+// to notice a genuine language change within a conversation.
+const langCacheTTL = 15 * time.Second
+
+// SetLanguageMode switches Process between a fixed/auto language (applied
+// immediately via SetLanguage, same as calling it directly) and per-call
+// detection. code is required for LanguageModeFixed and ignored
+// otherwise.
+func (t *TranscriptionService) SetLanguageMode(mode LanguageMode, code string) error {
+	switch mode {
+	case LanguageModeFixed:
+		if code == "" {
+			return fmt.Errorf("language code required for LanguageModeFixed")
+		}
+		if err := t.SetLanguage(code); err != nil {
+			return err
+		}
+	case LanguageModeAuto:
+		if err := t.SetLanguage("auto"); err != nil {
+			return err
+		}
+	case LanguageModeDetect:
+		// Nothing to configure on the pooled sessions up front - Process
+		// calls SetLanguage per call once DetectLanguage has an answer.
+	default:
+		return fmt.Errorf("unknown language mode: %s", mode)
 	}
-	return t.model.Languages()
+
+	t.languageMutex.Lock()
+	t.languageMode = mode
+	t.languageMutex.Unlock()
+
+	slog.Info("set language mode", "mode", mode, "code", code)
+	return nil
+}
+
+// getLanguageMode returns the mode Process should use for its next call.
+func (t *TranscriptionService) getLanguageMode() LanguageMode {
+	t.languageMutex.RLock()
+	defer t.languageMutex.RUnlock()
+	return t.languageMode
+}
+
+// lidWindowSamples is how much of a clip DetectLanguage decodes: ~30s at
+// whisper's expected 16kHz mono input, enough for a reliable language
+// guess without decoding the whole (possibly much longer) utterance.
+const lidWindowSamples = 30 * 16000
+
+// DetectLanguage runs language identification on pcm (truncated to its
+// first ~30s) using a checked-out pooled BackendSession, and reports the
+// detected language code - see BackendSession.DetectLanguage.
+func (t *TranscriptionService) DetectLanguage(pcm []float32) (string, float32, error) {
+	session, pool, err := t.acquireSession()
+	if err != nil {
+		return "", 0, err
+	}
+	defer releaseSession(pool, session)
+
+	return session.DetectLanguage(pcm)
+}
+
+// detectLanguageForStream returns streamID's cached language detection if
+// it's still within langCacheTTL, otherwise runs session.DetectLanguage
+// and caches the result for next time.
+func (t *TranscriptionService) detectLanguageForStream(session BackendSession, streamID string, pcm []float32) (string, float32, error) {
+	t.langCacheMutex.Lock()
+	if entry, ok := t.langCache[streamID]; ok && time.Since(entry.at) < langCacheTTL {
+		t.langCacheMutex.Unlock()
+		return entry.lang, entry.prob, nil
+	}
+	t.langCacheMutex.Unlock()
+
+	lang, prob, err := session.DetectLanguage(pcm)
+	if err != nil {
+		return "", 0, err
+	}
+
+	t.langCacheMutex.Lock()
+	t.langCache[streamID] = langCacheEntry{lang: lang, prob: prob, at: time.Now()}
+	t.langCacheMutex.Unlock()
+
+	return lang, prob, nil
 }
 
-// Process transcribes audio samples and emits events
-func (t *TranscriptionService) Process(samples []float32, appCtx context.Context) (string, string, error) {
+// TranscribeParams overrides a session's pool-wide defaults for a single
+// Process call, restored immediately afterward so other streams sharing
+// the same pool are unaffected - see BackendSession.Transcribe. The zero
+// value overrides nothing: every field's zero value means "leave this
+// setting as the backend's defaults left it". Pass it to Process to
+// unlock translation mode, a domain-specific InitialPrompt ("Medical
+// dictation." vs. "Meeting transcription."), or beam/temperature/VAD
+// tuning for one request, without reloading the model.
+type TranscribeParams struct {
+	Language            string
+	Translate           bool
+	BeamSize            int
+	Temperature         float32
+	TemperatureFallback float32
+	EntropyThreshold    float32
+	InitialPrompt       string
+	VADThreshold        float32
+	MinSpeechMs         int
+	MinSilenceMs        int
+	MaxSegmentLen       int
+	// SuppressNonSpeech is accepted for parity with whisper.cpp's
+	// suppress_non_speech_tokens parameter but is currently a no-op - the
+	// Go bindings WhisperCppBackend is built against don't expose it.
+	SuppressNonSpeech bool
+}
+
+// Process transcribes audio samples for streamID and emits events.
+// streamID ties a sequence of calls (e.g. successive chunks of the same
+// recording) together for prompt chaining via getStreamPrompt/
+// setStreamPrompt - it does not pin the call to a particular pooled
+// session, since Process checks one out of the pool for this call only and
+// returns it immediately afterward (see acquireSession). Pass "" if the
+// caller has no notion of a stream (e.g. a one-shot transcription).
+// params is optional (see TranscribeParams); omit it to use the session's
+// standard configuration.
+func (t *TranscriptionService) Process(streamID string, samples []float32, appCtx context.Context, params ...TranscribeParams) (string, string, error) {
 	// Ensure model is loaded (handles lazy loading after idle unload)
 	if err := t.ensureModelLoaded(); err != nil {
 		return "", "", err
@@ -380,39 +900,96 @@ func (t *TranscriptionService) Process(samples []float32, appCtx context.Context
 	// Update activity time for idle tracking
 	t.updateActivityTime()
 
-	// Ensure exclusive access to the whisper context for processing
-	t.processMutex.Lock()
-	defer t.processMutex.Unlock()
+	// Check out a session for exclusive use during this call; concurrent
+	// Process calls for other streams run against the pool's other
+	// sessions instead of queuing behind this one.
+	session, pool, err := t.acquireSession()
+	if err != nil {
+		return "", "", err
+	}
+	defer releaseSession(pool, session)
+
+	// params is variadic purely so existing callers can omit it; Process
+	// only ever looks at the first value passed.
+	var callParams TranscribeParams
+	if len(params) > 0 {
+		callParams = params[0]
+	}
+
+	// In LanguageModeDetect, route this call to the detected language
+	// before decoding - see SetLanguageMode. This overrides any explicit
+	// callParams.Language, since detection is meant to take precedence
+	// over a stale per-call default.
+	if t.getLanguageMode() == LanguageModeDetect {
+		lang, prob, err := t.detectLanguageForStream(session, streamID, samples)
+		if err != nil {
+			slog.Warn("language detection failed, continuing with current language setting", "error", err)
+		} else if lang != "" {
+			callParams.Language = lang
+			slog.Info("routed segment to detected language", "stream_id", streamID, "language", lang, "probability", prob)
+			if t.app != nil {
+				t.app.Event.Emit("language-detected", map[string]interface{}{
+					"stream_id":   streamID,
+					"language":    lang,
+					"probability": prob,
+					"timestamp":   time.Now().Unix(),
+				})
+			}
+		}
+	}
 
-	// Process the audio samples
-	var segments []whisper.Segment
+	// Read once per call, not per segment - see SetHallucinationFilter.
+	filterCfg := t.getHallucinationFilter()
 
-	// Use segment callback to collect results in real-time
-	segmentCallback := func(segment whisper.Segment) {
-		segments = append(segments, segment)
+	segments, detectedLang, err := session.Transcribe(samples, callParams)
+	if err != nil {
+		return "", "", err
+	}
+
+	var kept []Segment
+	for _, segment := range segments {
+		if filterCfg.Enabled {
+			if reason := classifyHallucination(segment.Text, segment.AvgLogprob, segment.NoSpeechProb, filterCfg); reason != "" {
+				if t.app != nil {
+					t.app.Event.Emit("transcription-filtered", map[string]interface{}{
+						"text":      segment.Text,
+						"reason":    reason,
+						"start":     segment.Start,
+						"end":       segment.End,
+						"timestamp": time.Now().Unix(),
+					})
+				}
+				slog.Info("filtered hallucinated segment", "reason", reason, "text", segment.Text)
+				continue
+			}
+		}
+
+		kept = append(kept, segment)
 
 		// Emit transcription event to frontend
 		if t.app != nil {
-			t.app.Event.Emit("transcription-segment", map[string]interface{}{
+			payload := map[string]interface{}{
 				"text":      segment.Text,
-				"start":     segment.Start.Seconds(),
-				"end":       segment.End.Seconds(),
+				"start":     segment.Start,
+				"end":       segment.End,
 				"timestamp": time.Now().Unix(),
-			})
-		}
+			}
 
-		slog.Info("transcription segment", "start", segment.Start.Seconds(), "end", segment.End.Seconds(), "text", segment.Text)
-	}
+			if t.wordTimestampsEnabled {
+				payload["words"] = segment.Words
+				payload["avg_logprob"] = segment.AvgLogprob
+				payload["no_speech_prob"] = segment.NoSpeechProb
+			}
 
-	// Process with callbacks
-	err := t.context.Process(samples, nil, segmentCallback, nil)
-	if err != nil {
-		return "", "", fmt.Errorf("failed to process audio: %w", err)
+			t.app.Event.Emit("transcription-segment", payload)
+		}
+
+		slog.Info("transcription segment", "start", segment.Start, "end", segment.End, "text", segment.Text)
 	}
 
 	// Also emit a combined transcription event
 	var fullText strings.Builder
-	for _, segment := range segments {
+	for _, segment := range kept {
 		fullText.WriteString(segment.Text)
 		fullText.WriteString(" ")
 	}
@@ -420,7 +997,7 @@ func (t *TranscriptionService) Process(samples []float32, appCtx context.Context
 	if t.app != nil && fullText.Len() > 0 {
 		t.app.Event.Emit("transcription-complete", map[string]interface{}{
 			"text":      strings.TrimSpace(fullText.String()),
-			"segments":  len(segments),
+			"segments":  len(kept),
 			"timestamp": time.Now().Unix(),
 		})
 	}
@@ -429,11 +1006,9 @@ func (t *TranscriptionService) Process(samples []float32, appCtx context.Context
 	// PERFORMANCE: Disable prompt chaining to prevent hallucination loops and improve stability
 	finalText := strings.TrimSpace(fullText.String())
 	// if finalText != "" {
-	// 	t.UpdatePromptContext(finalText)
+	// 	t.setStreamPrompt(streamID, finalText)
 	// }
 
-	// Get detected language
-	detectedLang := t.context.DetectedLanguage()
 	slog.Info("detected language", "language", detectedLang)
 
 	return finalText, detectedLang, nil
@@ -447,43 +1022,57 @@ func (t *TranscriptionService) Close() error {
 		t.idleCheckRunning = false
 	}
 
-	if t.context != nil {
-		// Note: whisper.Context doesn't have a Close method in the interface
-		// The underlying resources are managed by the model
-		t.context = nil
+	t.liveStreamMutex.Lock()
+	for sessionID, ls := range t.liveStreams {
+		ls.stop()
+		delete(t.liveStreams, sessionID)
 	}
+	t.liveStreamMutex.Unlock()
 
-	if t.model != nil {
-		// Close the model (this should clean up contexts too)
-		t.model.Close()
-		t.model = nil
+	t.modelMutex.Lock()
+	for _, session := range t.sessions {
+		session.Close()
 	}
+	t.sessions = nil
+	t.sessionPool = nil
+	t.lastPrompt = ""
+	t.modelMutex.Unlock()
 
 	// Clear prompt state
-	t.lastPrompt = ""
+	t.streamMutex.Lock()
+	t.streamPrompts = make(map[string]string)
+	t.streamMutex.Unlock()
 
 	slog.Info("whisper transcription service closed")
 	return nil
 }
 
-// UpdatePromptContext stores the last ~200 characters of transcription as context
-// for the next transcription segment. This helps Whisper maintain consistency
-// and accuracy across chunk boundaries (prompt chaining).
+// UpdatePromptContext stores the last ~200 characters of transcription as
+// context for the next transcription segment, across every session in the
+// pool that implements PromptSetter. This helps Whisper maintain
+// consistency and accuracy across chunk boundaries (prompt chaining).
 func (t *TranscriptionService) UpdatePromptContext(text string) {
 	if text == "" {
 		return
 	}
-	
+
 	// Keep last 200 characters for context
 	if len(text) > 200 {
 		text = text[len(text)-200:]
 	}
-	
+
+	t.modelMutex.RLock()
+	defer t.modelMutex.RUnlock()
+
 	t.lastPrompt = text
-	
-	// Update the whisper context's initial prompt for the next processing call
-	if t.context != nil {
-		t.context.SetInitialPrompt(text)
+
+	// Update every pooled session's initial prompt for the next processing call
+	for _, session := range t.sessions {
+		if ps, ok := session.(PromptSetter); ok {
+			ps.SetInitialPrompt(text)
+		}
+	}
+	if len(t.sessions) > 0 {
 		snippet := text
 		if len(snippet) > 50 {
 			snippet = snippet[len(snippet)-50:]
@@ -494,29 +1083,40 @@ func (t *TranscriptionService) UpdatePromptContext(text string) {
 
 // ResetPromptContext clears the prompt context (call when starting a new recording session)
 func (t *TranscriptionService) ResetPromptContext() {
+	t.modelMutex.RLock()
+	defer t.modelMutex.RUnlock()
+
 	t.lastPrompt = ""
-	if t.context != nil {
-		t.context.SetInitialPrompt("Meeting transcription.")
+	for _, session := range t.sessions {
+		if ps, ok := session.(PromptSetter); ok {
+			ps.SetInitialPrompt("Meeting transcription.")
+		}
+	}
+	if len(t.sessions) > 0 {
 		slog.Info("reset prompt context to default")
 	}
 }
 
 // IsInitialized returns whether the service is ready for transcription
 func (t *TranscriptionService) IsInitialized() bool {
-	initialized := t.model != nil && t.context != nil
-	slog.Info("IsInitialized called", "model_loaded", t.model != nil, "context_loaded", t.context != nil, "result", initialized)
+	t.modelMutex.RLock()
+	defer t.modelMutex.RUnlock()
+	initialized := len(t.sessions) > 0
+	slog.Info("IsInitialized called", "sessions_loaded", len(t.sessions), "result", initialized)
 	return initialized
 }
 
 // downloadModel downloads the specified model from Hugging Face
 func (t *TranscriptionService) downloadModel(modelName string) error {
-	// Get the model URL from the supported models list
-	models := GetSupportedModels()
+	// Get the model info from the combined built-in + custom models list
+	models := allModels()
 	var modelURL string
+	var expectedSHA256 string
 
 	for _, model := range models {
 		if model.Name == modelName {
 			modelURL = model.Url
+			expectedSHA256 = model.SHA256
 			break
 		}
 	}
@@ -530,7 +1130,7 @@ func (t *TranscriptionService) downloadModel(modelName string) error {
 
 	slog.Info("downloading model", "url", modelURL)
 
-	err := utils.DownloadFile(modelURL, t.modelPath, nil)
+	err := utils.DownloadFileResumable(modelURL, t.modelPath, expectedSHA256, nil)
 	if err != nil {
 		return fmt.Errorf("failed to download model: %w", err)
 	}
@@ -541,15 +1141,17 @@ func (t *TranscriptionService) downloadModel(modelName string) error {
 
 // DownloadModel downloads a model with progress tracking and emits events
 func (t *TranscriptionService) DownloadModel(ctx context.Context, modelName string) error {
-	// Get the model info from the supported models list
-	models := GetSupportedModels()
+	// Get the model info from the combined built-in + custom models list
+	models := allModels()
 	var modelURL string
 	var modelFileName string
+	var expectedSHA256 string
 
 	for _, model := range models {
 		if model.Name == modelName {
 			modelURL = model.Url
 			modelFileName = model.FileName
+			expectedSHA256 = model.SHA256
 			break
 		}
 	}
@@ -583,11 +1185,27 @@ func (t *TranscriptionService) DownloadModel(ctx context.Context, modelName stri
 		}
 	}
 
-	err := utils.DownloadFile(modelURL, modelPath, progressCallback)
+	// DownloadFileResumable resumes from modelPath+".part" if one exists
+	// from a previous, interrupted attempt, and - since expectedSHA256 is
+	// passed through - only renames it to modelPath once the checksum
+	// checks out, so a failed verification never leaves a bad file where
+	// callers expect a good one.
+	err := utils.DownloadFileResumable(modelURL, modelPath, expectedSHA256, progressCallback)
 	if err != nil {
+		if t.app != nil {
+			t.app.Event.Emit("model-download-failed", map[string]interface{}{
+				"model":    modelName,
+				"filename": modelFileName,
+				"error":    err.Error(),
+			})
+		}
 		return fmt.Errorf("failed to download model: %w", err)
 	}
 
+	if expectedSHA256 != "" {
+		slog.Info("model checksum verified", "model", modelName)
+	}
+
 	slog.Info("model downloaded successfully", "model", modelName, "path", modelPath)
 
 	// Emit completion event
@@ -602,13 +1220,14 @@ func (t *TranscriptionService) DownloadModel(ctx context.Context, modelName stri
 	return nil
 }
 
-// ListModels returns a list of all supported models with their current status
+// ListModels returns a list of all supported models (built-in and
+// user-registered custom ones) with their current status.
 func (t *TranscriptionService) ListModels() []ModelInfo {
 	t.modelMutex.RLock()
 	currentModel := t.currentModelName
 	t.modelMutex.RUnlock()
 
-	models := GetSupportedModels()
+	models := allModels()
 	for i := range models {
 		// Check if model is downloaded
 		modelPath := getModelPath(models[i].Name)
@@ -623,10 +1242,25 @@ func (t *TranscriptionService) ListModels() []ModelInfo {
 	return models
 }
 
-// SwitchModel switches to a different model, stopping transcription if running
+// SwitchModel switches to a different model without a downtime window.
+// Unlike the old Close()-then-Initialize() approach - which left a window
+// where IsInitialized() returned false and any concurrent Process call
+// failed with "transcription service not initialized" - the new model and
+// its session pool are fetched from modelCache (loading them off to the
+// side on a miss) while Process keeps serving requests against the current
+// one, and only swapped in atomically under modelMutex.Lock() once that
+// succeeds. On load failure, the current model is untouched and the error
+// is returned without disruption. The outgoing model is not closed - it
+// stays warm in modelCache so switching back to it later is instant -
+// unless modelCache's LRU/memory-budget eviction reclaims it, which drains
+// any in-flight Process calls on it before closing, the same guarantee
+// this used to provide inline.
 func (t *TranscriptionService) SwitchModel(ctx context.Context, modelName string) error {
-	// Validate model exists in supported list
-	models := GetSupportedModels()
+	t.switchMutex.Lock()
+	defer t.switchMutex.Unlock()
+
+	// Validate model exists in the combined built-in + custom models list
+	models := allModels()
 	var found bool
 	for _, model := range models {
 		if model.Name == modelName {
@@ -644,29 +1278,39 @@ func (t *TranscriptionService) SwitchModel(ctx context.Context, modelName string
 		return fmt.Errorf("model %s is not downloaded", modelName)
 	}
 
-	t.modelMutex.Lock()
-	defer t.modelMutex.Unlock()
+	t.modelMutex.RLock()
+	currentModelName := t.currentModelName
+	t.modelMutex.RUnlock()
 
 	// If switching to the same model, do nothing
-	if t.currentModelName == modelName {
+	if currentModelName == modelName {
 		return nil
 	}
 
-	slog.Info("switching model", "from", t.currentModelName, "to", modelName)
+	slog.Info("switching model", "from", currentModelName, "to", modelName)
+
+	// Fetch (or load) the new model and pool fully before touching any live
+	// state - Process calls keep running against the old pool while this
+	// happens. Pin currentModelName so modelCache can't evict it out from
+	// under us while the new one is being staged.
+	newSessions, newPool, err := t.loadCached(modelName, modelPath, currentModelName)
+	if err != nil {
+		return fmt.Errorf("failed to initialize new model %s: %w", modelName, err)
+	}
 
-	// Close current model and context
-	if err := t.Close(); err != nil {
-		slog.Warn("error closing current model", "error", err)
+	if t.app != nil {
+		t.app.Event.Emit("model-switch-started", map[string]interface{}{
+			"from": currentModelName,
+			"to":   modelName,
+		})
 	}
 
-	// Update current model name
+	t.modelMutex.Lock()
+	t.sessions = newSessions
+	t.sessionPool = newPool
 	t.currentModelName = modelName
 	t.modelPath = modelPath
-
-	// Re-initialize with new model
-	if err := t.Initialize(modelName); err != nil {
-		return fmt.Errorf("failed to initialize new model %s: %w", modelName, err)
-	}
+	t.modelMutex.Unlock()
 
 	slog.Info("successfully switched model", "model", modelName)
 
@@ -680,6 +1324,52 @@ func (t *TranscriptionService) SwitchModel(ctx context.Context, modelName string
 	return nil
 }
 
+// PreloadModels loads each named model into modelCache without making any
+// of them active, so a later SwitchModel to one of them is instant instead
+// of paying a disk load. Names already resident are skipped. Every name is
+// attempted regardless of earlier failures; the first error encountered (a
+// missing/undownloaded model, say) is returned once all have been tried.
+func (t *TranscriptionService) PreloadModels(names []string) error {
+	var firstErr error
+	for _, name := range names {
+		if t.modelCache.Has(name) {
+			continue
+		}
+
+		path := getModelPath(name)
+		if _, err := os.Stat(path); err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("model %s is not downloaded", name)
+			}
+			continue
+		}
+
+		if t.app != nil {
+			t.app.Event.Emit("model-loading", map[string]interface{}{"model": name})
+		}
+
+		if _, _, err := t.loadCached(name, path, ""); err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to preload model %s: %w", name, err)
+			}
+			continue
+		}
+
+		if t.app != nil {
+			t.app.Event.Emit("model-loaded", map[string]interface{}{"model": name})
+		}
+	}
+	return firstErr
+}
+
+// SetModelCacheLimits overrides defaultMaxResidentModels' hardware-tier
+// heuristic with values a caller that knows its system's actual available
+// memory can compute directly. memoryBudgetMB of 0 disables memory-based
+// eviction, leaving maxResident as the only cap.
+func (t *TranscriptionService) SetModelCacheLimits(maxResident, memoryBudgetMB int) {
+	t.modelCache.SetLimits(maxResident, memoryBudgetMB)
+}
+
 // SetUnloadTimeout configures when the model should be automatically unloaded
 func (t *TranscriptionService) SetUnloadTimeout(timeout ModelUnloadTimeout) {
 	t.modelMutex.Lock()
@@ -699,7 +1389,7 @@ func (t *TranscriptionService) SetUnloadTimeout(timeout ModelUnloadTimeout) {
 	}
 
 	// If immediate unload requested and model is loaded, unload now
-	if timeout == ModelUnloadImmediately && t.model != nil {
+	if timeout == ModelUnloadImmediately && len(t.sessions) > 0 {
 		go t.unloadModel()
 	}
 }
@@ -747,7 +1437,7 @@ func (t *TranscriptionService) checkIdleAndUnload() {
 	t.modelMutex.RLock()
 	timeout := t.unloadTimeout
 	lastActivity := t.lastActivityTime
-	modelLoaded := t.model != nil
+	modelLoaded := len(t.sessions) > 0
 	t.modelMutex.RUnlock()
 
 	if !modelLoaded || timeout == ModelUnloadNever {
@@ -775,34 +1465,43 @@ func (t *TranscriptionService) checkIdleAndUnload() {
 // unloadModel releases the model from memory
 func (t *TranscriptionService) unloadModel() {
 	t.modelMutex.Lock()
-	defer t.modelMutex.Unlock()
-
-	if t.model == nil {
+	if len(t.sessions) == 0 {
+		t.modelMutex.Unlock()
 		return
 	}
 
 	slog.Info("unloading whisper model to free memory")
 
-	// Clear context first
-	if t.context != nil {
-		t.context = nil
-	}
-
-	// Close model
-	if t.model != nil {
-		t.model.Close()
-		t.model = nil
-	}
-
-	// Clear prompt state
+	currentModelName := t.currentModelName
+	t.sessions = nil
+	t.sessionPool = nil
 	t.lastPrompt = ""
+	// Pop the entry out of modelCache's bookkeeping before releasing
+	// modelMutex, not after, so a Process call that slips in right after
+	// this unlock and finds t.sessions empty can't reload via
+	// ensureModelLoaded -> GetOrLoad and have GetOrLoad hand back this same
+	// (about to be drained-and-closed) entry - it'll see a cache miss and
+	// load a fresh pool instead.
+	entry := t.modelCache.Pop(currentModelName)
+	t.modelMutex.Unlock()
+
+	if entry != nil {
+		// Drain any in-flight Process calls still holding a session before
+		// closing, same as LRU eviction does, but synchronously and under
+		// drainMu (not modelMutex, already released above) for the whole
+		// drain, so it can't interleave with SetLanguage's own drain of the
+		// same pool and split its sessions between the two - see drainMu.
+		t.drainMu.Lock()
+		entry.closeSync(currentModelName)
+		t.drainMu.Unlock()
+	}
 
 	slog.Info("whisper model unloaded")
 
 	// Emit event to notify frontend
 	if t.app != nil {
 		t.app.Event.Emit("model-unloaded", map[string]interface{}{
-			"model": t.currentModelName,
+			"model": currentModelName,
 		})
 	}
 }
@@ -810,7 +1509,7 @@ func (t *TranscriptionService) unloadModel() {
 // ensureModelLoaded ensures the model is loaded, reloading if necessary
 func (t *TranscriptionService) ensureModelLoaded() error {
 	t.modelMutex.RLock()
-	if t.model != nil && t.context != nil {
+	if len(t.sessions) > 0 {
 		t.modelMutex.RUnlock()
 		return nil
 	}
@@ -821,7 +1520,7 @@ func (t *TranscriptionService) ensureModelLoaded() error {
 	defer t.modelMutex.Unlock()
 
 	// Double-check after acquiring write lock
-	if t.model != nil && t.context != nil {
+	if len(t.sessions) > 0 {
 		return nil
 	}
 
@@ -834,50 +1533,18 @@ func (t *TranscriptionService) ensureModelLoaded() error {
 		})
 	}
 
-	// Initialize will reload the model
-	// Note: We need to release the lock temporarily as Initialize may need it
-	// But since we're using the same lock, we can call the internal parts directly
-
-	// Load the model
-	model, err := whisper.New(t.modelPath)
+	// Fetch (or load) the model through modelCache - on a miss this runs
+	// the same loadModelPool that Initialize and SwitchModel use, so a
+	// reload after an idle-unload builds an identically configured pool.
+	sessions, pool, err := t.loadCached(t.currentModelName, t.modelPath, "")
 	if err != nil {
 		return fmt.Errorf("failed to reload whisper model: %w", err)
 	}
 
-	// Create context
-	context, err := model.NewContext()
-	if err != nil {
-		model.Close()
-		return fmt.Errorf("failed to create whisper context: %w", err)
-	}
-
-	t.model = model
-	t.context = context
-
-	// Reconfigure context (minimal config for reload)
-	hwProfile := DetectHardware()
-	adaptiveConfig := GetAdaptiveConfig(hwProfile)
+	t.sessions = sessions
+	t.sessionPool = pool
 
-	context.SetThreads(uint(adaptiveConfig.Threads))
-	context.SetTranslate(false)
-	context.SetLanguage("auto")
-	context.SetBeamSize(adaptiveConfig.BeamSize)
-	context.SetEntropyThold(2.4)
-	context.SetTemperature(adaptiveConfig.Temperature)
-	context.SetTemperatureFallback(0.2)
-	context.SetInitialPrompt("Meeting transcription.")
-
-	// Try to enable VAD if model exists
-	vadModelPath := getModelPath("silero-v6.2.0")
-	if _, err := os.Stat(vadModelPath); err == nil {
-		context.SetVAD(true)
-		context.SetVADModelPath(vadModelPath)
-		context.SetVADThreshold(0.50)
-		context.SetVADMinSpeechMs(250)
-		context.SetVADMinSilenceMs(100)
-	}
-
-	slog.Info("whisper model reloaded successfully", "model", t.currentModelName)
+	slog.Info("whisper model reloaded successfully", "model", t.currentModelName, "sessions", len(sessions))
 
 	// Emit event to notify frontend
 	if t.app != nil {
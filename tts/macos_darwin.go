@@ -0,0 +1,100 @@
+package tts
+
+/*
+#cgo LDFLAGS: -framework Cocoa
+#include <stdlib.h>
+
+// Implemented in macos_darwin.m. speakSync blocks until NSSpeechSynthesizer
+// finishes the utterance (or fails) and returns 0 on success, -1 if no
+// voice could speak it.
+extern int speakSync(const char *text, const char *voice);
+extern char *defaultVoiceName(void);
+*/
+import "C"
+
+import (
+	"context"
+	"fmt"
+	"unsafe"
+)
+
+// MacOSProviderName is the name MacOSService registers under.
+const MacOSProviderName = "macos"
+
+// MacOSService speaks text through the OS's built-in NSSpeechSynthesizer via
+// cgo, needing no network access, API key, or model file. Unlike the other
+// Providers it can't hand back audio bytes - Synthesize blocks until the OS
+// has finished speaking and returns no data, so App should treat a
+// MacOSService response as already-played rather than something to decode
+// and play itself (see Provider.SpeaksAloud).
+type MacOSService struct {
+	voice string
+}
+
+// NewMacOSService creates a MacOSService. voice is an NSSpeechSynthesizer
+// voice identifier (e.g. "com.apple.speech.synthesis.voice.samantha");
+// empty selects the system default.
+func NewMacOSService(voice string) *MacOSService {
+	return &MacOSService{voice: voice}
+}
+
+// SetVoice updates the voice identifier used for synthesis.
+func (m *MacOSService) SetVoice(voice string) {
+	m.voice = voice
+}
+
+// Name identifies this provider in Settings.TTSProvider.
+func (m *MacOSService) Name() string { return MacOSProviderName }
+
+// IsConfigured is always true: NSSpeechSynthesizer ships with every macOS
+// install and needs no setup.
+func (m *MacOSService) IsConfigured() bool { return true }
+
+// SpeaksAloud reports that this provider speaks directly through the OS
+// rather than returning audio for the caller to play - see Provider.
+func (m *MacOSService) SpeaksAloud() bool { return true }
+
+// Synthesize speaks text aloud via NSSpeechSynthesizer and blocks until it
+// finishes. It returns no audio bytes: SpeaksAloud tells callers not to
+// expect any.
+func (m *MacOSService) Synthesize(ctx context.Context, text string, voice string) ([]byte, string, error) {
+	if voice == "" {
+		voice = m.voice
+	}
+
+	cText := C.CString(text)
+	defer C.free(unsafe.Pointer(cText))
+
+	var cVoice *C.char
+	if voice != "" {
+		cVoice = C.CString(voice)
+		defer C.free(unsafe.Pointer(cVoice))
+	}
+
+	done := make(chan int, 1)
+	go func() { done <- int(C.speakSync(cText, cVoice)) }()
+
+	select {
+	case result := <-done:
+		if result != 0 {
+			return nil, "", fmt.Errorf("macos speech synthesis failed")
+		}
+		return nil, "", nil
+	case <-ctx.Done():
+		return nil, "", ctx.Err()
+	}
+}
+
+// ListVoices returns the system default voice as reported by
+// NSSpeechSynthesizer. NSSpeechSynthesizer.availableVoices would enumerate
+// every installed voice, but Settings only exposes picking one by name
+// today, so the default is all ListVoices needs to offer.
+func (m *MacOSService) ListVoices(ctx context.Context) ([]Voice, error) {
+	name := C.defaultVoiceName()
+	defer C.free(unsafe.Pointer(name))
+	id := C.GoString(name)
+	if id == "" {
+		return nil, nil
+	}
+	return []Voice{{ID: id, Name: id}}, nil
+}
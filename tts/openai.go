@@ -0,0 +1,107 @@
+package tts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// OpenAIProviderName is the name OpenAIService registers under.
+const OpenAIProviderName = "openai"
+
+// OpenAIDefaultModel is the TTS model used when none is set.
+const OpenAIDefaultModel = "tts-1"
+
+// OpenAIDefaultVoice is the voice used when none is set.
+const OpenAIDefaultVoice = "alloy"
+
+// OpenAIBaseURL is OpenAI's API endpoint.
+const OpenAIBaseURL = "https://api.openai.com/v1"
+
+// OpenAIService synthesizes speech through OpenAI's /audio/speech
+// endpoint. It implements Provider.
+type OpenAIService struct {
+	apiKey string
+	model  string
+	voice  string
+}
+
+// NewOpenAIService creates a new OpenAI TTS service.
+func NewOpenAIService() *OpenAIService {
+	return &OpenAIService{model: OpenAIDefaultModel, voice: OpenAIDefaultVoice}
+}
+
+// SetAPIKey sets the API key.
+func (s *OpenAIService) SetAPIKey(key string) { s.apiKey = key }
+
+// Name identifies this provider in Settings.TTSProvider.
+func (s *OpenAIService) Name() string { return OpenAIProviderName }
+
+// IsConfigured returns true if the API key is set.
+func (s *OpenAIService) IsConfigured() bool { return s.apiKey != "" }
+
+// SpeaksAloud is always false: OpenAI returns audio bytes for the caller to
+// play, it doesn't play them itself.
+func (s *OpenAIService) SpeaksAloud() bool { return false }
+
+// Synthesize converts text to speech and returns MP3 bytes. voice overrides
+// the configured voice when non-empty.
+func (s *OpenAIService) Synthesize(ctx context.Context, text string, voice string) ([]byte, string, error) {
+	if s.apiKey == "" {
+		return nil, "", fmt.Errorf("openai API key not configured")
+	}
+
+	v := s.voice
+	if voice != "" {
+		v = voice
+	}
+
+	reqBody := map[string]interface{}{
+		"model": s.model,
+		"input": text,
+		"voice": v,
+	}
+	jsonBody, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", OpenAIBaseURL+"/audio/speech", bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+s.apiKey)
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to call OpenAI API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read OpenAI response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("openai API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	return body, "audio/mpeg", nil
+}
+
+// ListVoices returns OpenAI's fixed set of built-in voices: unlike
+// ElevenLabs, OpenAI has no per-account voice list to query.
+func (s *OpenAIService) ListVoices(ctx context.Context) ([]Voice, error) {
+	names := []string{"alloy", "echo", "fable", "onyx", "nova", "shimmer"}
+	voices := make([]Voice, len(names))
+	for i, name := range names {
+		voices[i] = Voice{ID: name, Name: name}
+	}
+	return voices, nil
+}
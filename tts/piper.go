@@ -0,0 +1,86 @@
+package tts
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// PiperProviderName is the name PiperService registers under.
+const PiperProviderName = "piper"
+
+// PiperService synthesizes speech offline using a locally installed Piper
+// voice model (https://github.com/rhasspy/piper), shelling out to the piper
+// binary once per call. Unlike ElevenLabsService it needs no API key and
+// works air-gapped; the tradeoff is voice quality and requiring a model file
+// on disk (see Settings.PiperModelPath).
+type PiperService struct {
+	binaryPath string
+	modelPath  string
+}
+
+// NewPiperService creates a PiperService, resolving the piper binary from
+// PATH. modelPath may be empty and set later via SetModelPath once settings
+// have loaded.
+func NewPiperService(modelPath string) *PiperService {
+	binaryPath, _ := exec.LookPath("piper")
+	return &PiperService{binaryPath: binaryPath, modelPath: modelPath}
+}
+
+// SetModelPath updates the voice model file (Settings.PiperModelPath) used
+// for synthesis.
+func (p *PiperService) SetModelPath(modelPath string) {
+	p.modelPath = modelPath
+}
+
+// Name identifies this provider in Settings.TTSProvider.
+func (p *PiperService) Name() string { return PiperProviderName }
+
+// IsConfigured reports whether the piper binary and a model file are both
+// available.
+func (p *PiperService) IsConfigured() bool {
+	if p.binaryPath == "" || p.modelPath == "" {
+		return false
+	}
+	_, err := os.Stat(p.modelPath)
+	return err == nil
+}
+
+// SpeaksAloud is always false: Piper returns WAV bytes for the caller to
+// play, it doesn't play them itself.
+func (p *PiperService) SpeaksAloud() bool { return false }
+
+// Synthesize runs `piper --model <modelPath> --output_file -`, feeding text
+// on stdin and reading back a WAV file on stdout. voice is ignored: Piper
+// picks its voice from the model file, not a per-request parameter.
+func (p *PiperService) Synthesize(ctx context.Context, text string, voice string) ([]byte, string, error) {
+	if !p.IsConfigured() {
+		return nil, "", fmt.Errorf("piper not configured (binary or model missing)")
+	}
+
+	cmd := exec.CommandContext(ctx, p.binaryPath, "--model", p.modelPath, "--output_file", "-")
+	cmd.Stdin = bytes.NewBufferString(text)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, "", fmt.Errorf("piper synthesis failed: %w (%s)", err, stderr.String())
+	}
+
+	return stdout.Bytes(), "audio/wav", nil
+}
+
+// ListVoices returns the configured model as the only available voice:
+// Piper has no API to enumerate installed models, and Settings only tracks
+// a single PiperModelPath at a time.
+func (p *PiperService) ListVoices(ctx context.Context) ([]Voice, error) {
+	if p.modelPath == "" {
+		return nil, nil
+	}
+	return []Voice{{ID: p.modelPath, Name: filepath.Base(p.modelPath)}}, nil
+}
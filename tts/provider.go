@@ -0,0 +1,54 @@
+// Package tts defines a provider-agnostic text-to-speech interface, so App
+// can synthesize speech without depending on any one vendor (see Registry).
+package tts
+
+import "context"
+
+// Voice describes a selectable voice offered by a Provider.
+type Voice struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Provider synthesizes speech for a single TTS backend (e.g. ElevenLabs, or
+// a local Piper install). Implementations are registered under a name via
+// Registry.Register and selected via Settings.TTSProvider.
+type Provider interface {
+	// Name identifies the provider, matching the string stored in
+	// Settings.TTSProvider.
+	Name() string
+	// IsConfigured reports whether the provider has everything it needs to
+	// synthesize (an API key, a model file on disk, etc).
+	IsConfigured() bool
+	// Synthesize converts text to speech. voice overrides the provider's
+	// default voice when non-empty; providers that don't support per-call
+	// voice selection may ignore it. It returns the audio bytes and their
+	// MIME type (e.g. "audio/mpeg", "audio/wav") - except for a provider
+	// whose SpeaksAloud is true, which returns no bytes because it has
+	// already played the audio itself by the time Synthesize returns.
+	Synthesize(ctx context.Context, text string, voice string) ([]byte, string, error)
+	// SpeaksAloud reports whether this provider plays audio directly
+	// through the OS (e.g. MacOSService, SpeechDispatcherService) instead
+	// of returning bytes for the caller to play. Callers should treat a
+	// SpeaksAloud provider's Synthesize call as already having completed
+	// playback by the time it returns, rather than estimating a playback
+	// duration from the response text.
+	SpeaksAloud() bool
+	// ListVoices returns the voices available for this provider, for
+	// populating a settings picker.
+	ListVoices(ctx context.Context) ([]Voice, error)
+}
+
+// StreamingProvider is implemented by providers that can synthesize audio
+// incrementally as chunks become available, instead of only returning one
+// complete buffer from Synthesize - see elevenlabs.ElevenLabsService for
+// the reference implementation.
+type StreamingProvider interface {
+	Provider
+	// SynthesizeStream synthesizes text and streams audio chunks back on
+	// the returned channel as they arrive. The chunk channel is closed when
+	// synthesis is done (successfully or not); the error channel is
+	// buffered with capacity 1 and receives a value only if synthesis
+	// failed, which the caller should check after the chunk channel closes.
+	SynthesizeStream(ctx context.Context, text string, voice string) (<-chan []byte, <-chan error)
+}
@@ -0,0 +1,8 @@
+package tts
+
+// RegisterOSProviders registers this platform's OS-native TTS backend(s)
+// with r. On macOS that's MacOSService, speaking through
+// NSSpeechSynthesizer.
+func RegisterOSProviders(r *Registry) {
+	r.Register(MacOSProviderName, NewMacOSService(""))
+}
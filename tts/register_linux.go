@@ -0,0 +1,8 @@
+package tts
+
+// RegisterOSProviders registers this platform's OS-native TTS backend(s)
+// with r. On Linux that's SpeechDispatcherService, speaking through the
+// speech-dispatcher daemon via spd-say.
+func RegisterOSProviders(r *Registry) {
+	r.Register(SpeechDispatcherProviderName, NewSpeechDispatcherService(""))
+}
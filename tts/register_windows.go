@@ -0,0 +1,6 @@
+package tts
+
+// RegisterOSProviders registers this platform's OS-native TTS backend(s)
+// with r. Windows has no OS-native backend yet (SAPI could fill this role,
+// mirroring MacOSService/SpeechDispatcherService), so this is a no-op.
+func RegisterOSProviders(r *Registry) {}
@@ -0,0 +1,66 @@
+package tts
+
+import (
+	"fmt"
+	"sort"
+)
+
+// DefaultProviderName is the provider selected when Settings.TTSProvider is
+// empty.
+const DefaultProviderName = "elevenlabs"
+
+// Registry holds the TTS providers available to App and tracks which one is
+// active. Unlike avatar.Backend's package-level registry, Registry is an
+// instance type: the active provider is per-app-instance state driven by
+// Settings.TTSProvider, not a process-wide default.
+type Registry struct {
+	providers map[string]Provider
+	active    string
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{providers: make(map[string]Provider)}
+}
+
+// Register makes provider available under name. Panics on a duplicate name,
+// since that can only indicate two providers colliding at setup time.
+func (r *Registry) Register(name string, provider Provider) {
+	if _, exists := r.providers[name]; exists {
+		panic(fmt.Sprintf("tts: provider %q already registered", name))
+	}
+	r.providers[name] = provider
+}
+
+// SetActive selects the provider used by Active. An empty name selects
+// DefaultProviderName.
+func (r *Registry) SetActive(name string) {
+	if name == "" {
+		name = DefaultProviderName
+	}
+	r.active = name
+}
+
+// Active returns the currently selected provider.
+func (r *Registry) Active() (Provider, error) {
+	name := r.active
+	if name == "" {
+		name = DefaultProviderName
+	}
+	provider, ok := r.providers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown tts provider: %s", name)
+	}
+	return provider, nil
+}
+
+// List returns the names of every registered provider, for populating a
+// settings picker.
+func (r *Registry) List() []string {
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
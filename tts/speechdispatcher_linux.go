@@ -0,0 +1,91 @@
+package tts
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// SpeechDispatcherProviderName is the name SpeechDispatcherService registers
+// under.
+const SpeechDispatcherProviderName = "speech-dispatcher"
+
+// SpeechDispatcherService speaks text through the Linux speech-dispatcher
+// daemon via its `spd-say` CLI, needing no network access, API key, or model
+// file - just speech-dispatcher installed and a speechd.conf output module
+// configured (typically espeak-ng). Like MacOSService, it speaks directly
+// rather than returning audio bytes (see Provider.SpeaksAloud).
+type SpeechDispatcherService struct {
+	binaryPath string
+	voice      string
+}
+
+// NewSpeechDispatcherService creates a SpeechDispatcherService, resolving
+// spd-say from PATH. voice is a speech-dispatcher voice name (see
+// `spd-say -L`); empty selects the daemon's configured default.
+func NewSpeechDispatcherService(voice string) *SpeechDispatcherService {
+	binaryPath, _ := exec.LookPath("spd-say")
+	return &SpeechDispatcherService{binaryPath: binaryPath, voice: voice}
+}
+
+// SetVoice updates the voice name used for synthesis.
+func (s *SpeechDispatcherService) SetVoice(voice string) {
+	s.voice = voice
+}
+
+// Name identifies this provider in Settings.TTSProvider.
+func (s *SpeechDispatcherService) Name() string { return SpeechDispatcherProviderName }
+
+// IsConfigured reports whether spd-say was found on PATH.
+func (s *SpeechDispatcherService) IsConfigured() bool {
+	return s.binaryPath != ""
+}
+
+// SpeaksAloud reports that this provider speaks directly through
+// speech-dispatcher rather than returning audio for the caller to play.
+func (s *SpeechDispatcherService) SpeaksAloud() bool { return true }
+
+// Synthesize speaks text aloud via `spd-say -w` (wait for the utterance to
+// finish before returning) and returns no audio bytes.
+func (s *SpeechDispatcherService) Synthesize(ctx context.Context, text string, voice string) ([]byte, string, error) {
+	if !s.IsConfigured() {
+		return nil, "", fmt.Errorf("speech-dispatcher not configured (spd-say not found)")
+	}
+	if voice == "" {
+		voice = s.voice
+	}
+
+	args := []string{"-w"}
+	if voice != "" {
+		args = append(args, "-y", voice)
+	}
+	args = append(args, text)
+
+	if out, err := exec.CommandContext(ctx, s.binaryPath, args...).CombinedOutput(); err != nil {
+		return nil, "", fmt.Errorf("spd-say failed: %w (%s)", err, out)
+	}
+	return nil, "", nil
+}
+
+// ListVoices returns the voices speech-dispatcher reports via `spd-say -L`.
+func (s *SpeechDispatcherService) ListVoices(ctx context.Context) ([]Voice, error) {
+	if !s.IsConfigured() {
+		return nil, nil
+	}
+
+	out, err := exec.CommandContext(ctx, s.binaryPath, "-L").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list speech-dispatcher voices: %w", err)
+	}
+
+	var voices []Voice
+	for _, line := range strings.Split(string(out), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		voices = append(voices, Voice{ID: line, Name: line})
+	}
+	return voices, nil
+}
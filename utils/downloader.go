@@ -1,48 +1,191 @@
 package utils
 
 import (
-	"archive/tar"
-	"archive/zip"
-	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"hash"
 	"io"
+	"math/rand"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 // ProgressCallback is a function type for reporting download progress (0-100)
 type ProgressCallback func(progress float64)
 
-// DownloadFile downloads a file from the given URL to the specified path.
+// Checksum pins an expected digest for a downloaded file, verified against
+// the completed .part file before it's renamed into place - see
+// DownloadFileResumableWithOptions. The zero value disables verification.
+type Checksum struct {
+	// Algo names the hash algorithm. Only "sha256" (the default, used when
+	// Algo is empty) is currently supported.
+	Algo string
+	// Hex is the expected digest, as lowercase hex - compared
+	// case-insensitively.
+	Hex string
+}
+
+// empty reports whether c has no digest to verify against.
+func (c Checksum) empty() bool {
+	return c.Hex == ""
+}
+
+// newHash returns a fresh hash.Hash for c.Algo.
+func (c Checksum) newHash() (hash.Hash, error) {
+	switch strings.ToLower(c.Algo) {
+	case "", "sha256":
+		return sha256.New(), nil
+	default:
+		return nil, fmt.Errorf("unsupported checksum algorithm %q", c.Algo)
+	}
+}
+
+// RetryPolicy configures exponential-backoff retries around transient
+// network errors during a download - a dropped connection partway through a
+// multi-gigabyte TripoSR weight shouldn't fail the whole download, since the
+// .part file lets the next attempt resume instead of starting over.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	// Defaults to 5 when <= 0.
+	MaxAttempts int
+	// BaseDelay is the delay before the second attempt; each subsequent
+	// retry doubles it. Defaults to 500ms when <= 0.
+	BaseDelay time.Duration
+	// Jitter randomizes each delay by +/- this fraction (e.g. 0.2 for
+	// +/-20%), so many clients retrying the same flaky host don't all
+	// retry in lockstep. 0 disables jitter.
+	Jitter float64
+}
+
+// withDefaults returns p with zero-value fields replaced by their defaults.
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = 5
+	}
+	if p.BaseDelay <= 0 {
+		p.BaseDelay = 500 * time.Millisecond
+	}
+	return p
+}
+
+// delay returns how long to wait before retry number attempt (0-based: the
+// delay before the second overall attempt is delay(0)).
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	d := p.BaseDelay * time.Duration(int64(1)<<uint(attempt))
+	if p.Jitter > 0 {
+		d += time.Duration((rand.Float64()*2 - 1) * p.Jitter * float64(d))
+		if d < 0 {
+			d = 0
+		}
+	}
+	return d
+}
+
+// transientDownloadError wraps an error encountered mid-download that's
+// likely to succeed on retry (connection drops, 5xx/429 responses) as
+// opposed to a permanent one (checksum mismatch, 4xx other than a range
+// request Resumable already handles) - see RetryPolicy.
+type transientDownloadError struct{ err error }
+
+func (e *transientDownloadError) Error() string { return e.err.Error() }
+func (e *transientDownloadError) Unwrap() error { return e.err }
+
+func isTransientDownloadError(err error) bool {
+	var t *transientDownloadError
+	return errors.As(err, &t)
+}
+
+// DownloadFile downloads a file from the given URL to destPath, resuming a
+// previous interrupted attempt and retrying transient network errors - see
+// DownloadFileResumable, which this delegates to with no checksum pinned.
 // It reports progress via the optional onProgress callback.
 func DownloadFile(url string, destPath string, onProgress ProgressCallback) error {
-	// Ensure directory exists
+	return DownloadFileResumable(url, destPath, "", onProgress)
+}
+
+// DownloadFileResumable downloads a file from url to destPath, resuming from
+// a previous attempt when possible instead of starting over, and verifying
+// expectedSHA256 (if non-empty) before the file is renamed into place. It's
+// a thin wrapper over DownloadFileResumableWithOptions using the default
+// RetryPolicy - see that function for the resumption/checksum/retry details.
+func DownloadFileResumable(url, destPath, expectedSHA256 string, onProgress ProgressCallback) error {
+	var checksum Checksum
+	if expectedSHA256 != "" {
+		checksum = Checksum{Algo: "sha256", Hex: expectedSHA256}
+	}
+	return DownloadFileResumableWithOptions(url, destPath, checksum, RetryPolicy{}, onProgress)
+}
+
+// DownloadFileResumableWithOptions downloads a file from url to destPath,
+// resuming a previous interrupted attempt and retrying transient failures
+// per retry.
+//
+// The in-progress file lives at destPath+".part" so an interrupted download
+// leaves behind something a later attempt can pick back up: if that file
+// already exists, the request carries a "Range: bytes=<offset>-" header
+// together with "If-Range: <etag>" (the ETag recorded alongside the .part
+// file from the response that started it - see the ".etag" sidecar file).
+// A "206 Partial Content" reply is appended to the .part file; a "200 OK"
+// reply means the range request wasn't honored (no If-Range support) or the
+// remote file changed since the .part was started, so the .part file and
+// its recorded ETag are discarded and the download restarts from scratch.
+//
+// When checksum is non-empty, the completed .part file's digest is verified
+// before it's renamed to destPath - on mismatch the .part file is removed
+// and an error is returned, so a failed verification never leaves a bad
+// file at destPath for a caller to mistake for a good one. A checksum
+// mismatch is not retried; transient network/server errors are, up to
+// retry.MaxAttempts, with exponential backoff between attempts.
+func DownloadFileResumableWithOptions(url, destPath string, checksum Checksum, retry RetryPolicy, onProgress ProgressCallback) error {
 	dir := filepath.Dir(destPath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return fmt.Errorf("failed to create directory %s: %w", dir, err)
 	}
 
-	// Use a temp file to avoid leaving partial downloads
-	tempPath := destPath + ".downloading"
+	retry = retry.withDefaults()
 
-	// Create output file
-	out, err := os.Create(tempPath)
-	if err != nil {
-		return fmt.Errorf("failed to create file %s: %w", tempPath, err)
+	var lastErr error
+	for attempt := 0; attempt < retry.MaxAttempts; attempt++ {
+		if attempt > 0 {
+			time.Sleep(retry.delay(attempt - 1))
+		}
+
+		err := downloadResumableAttempt(url, destPath, checksum, onProgress)
+		if err == nil {
+			return nil
+		}
+		if !isTransientDownloadError(err) {
+			return err
+		}
+		lastErr = err
+	}
+
+	return fmt.Errorf("download failed after %d attempts: %w", retry.MaxAttempts, lastErr)
+}
+
+// downloadResumableAttempt runs one resumable download attempt. Errors
+// likely to succeed on retry are wrapped in transientDownloadError.
+func downloadResumableAttempt(url, destPath string, checksum Checksum, onProgress ProgressCallback) error {
+	partPath := destPath + ".part"
+	etagPath := partPath + ".etag"
+
+	var offset int64
+	if info, err := os.Stat(partPath); err == nil {
+		offset = info.Size()
 	}
 
-	// Cleanup on error
-	success := false
-	defer func() {
-		out.Close()
-		if !success {
-			os.Remove(tempPath)
+	var priorETag string
+	if offset > 0 {
+		if b, err := os.ReadFile(etagPath); err == nil {
+			priorETag = strings.TrimSpace(string(b))
 		}
-	}()
+	}
 
-	// Create HTTP client that follows redirects (Go's default does follow, but let's be explicit)
 	client := &http.Client{
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
 			if len(via) >= 10 {
@@ -52,215 +195,200 @@ func DownloadFile(url string, destPath string, onProgress ProgressCallback) erro
 		},
 	}
 
-	// Get response
-	resp, err := client.Get(url)
+	req, err := http.NewRequest(http.MethodGet, url, nil)
 	if err != nil {
-		return fmt.Errorf("failed to download from %s: %w", url, err)
+		return fmt.Errorf("failed to build request for %s: %w", url, err)
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		if priorETag != "" {
+			req.Header.Set("If-Range", priorETag)
+		}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return &transientDownloadError{fmt.Errorf("failed to download from %s: %w", url, err)}
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
+	var out *os.File
+	var total int64
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		// No partial content - either we didn't ask for a range, the
+		// server doesn't support them, or the remote file changed
+		// (If-Range didn't match). Start the .part file over.
+		offset = 0
+		total = resp.ContentLength
+		out, err = os.Create(partPath)
+	case http.StatusPartialContent:
+		total = offset + resp.ContentLength
+		out, err = os.OpenFile(partPath, os.O_WRONLY|os.O_APPEND, 0644)
+	case http.StatusRequestedRangeNotSatisfiable:
+		// Our .part file is already as long as (or longer than) the remote
+		// file - drop it and restart cleanly instead of looping forever.
+		resp.Body.Close()
+		os.Remove(partPath)
+		os.Remove(etagPath)
+		return downloadResumableAttempt(url, destPath, checksum, onProgress)
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return &transientDownloadError{fmt.Errorf("bad status: %s", resp.Status)}
+	default:
 		return fmt.Errorf("bad status: %s", resp.Status)
 	}
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", partPath, err)
+	}
+	defer out.Close()
 
-	// Create progress reader
-	contentLength := resp.ContentLength
-
-	// If ContentLength is missing (-1), try a HEAD request to get it
-	if contentLength <= 0 {
-		headResp, err := client.Head(url)
-		if err == nil && headResp.StatusCode == http.StatusOK {
-			contentLength = headResp.ContentLength
-			headResp.Body.Close()
-		}
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		_ = os.WriteFile(etagPath, []byte(etag), 0644)
 	}
 
 	progressReader := &ProgressReader{
 		Reader:     resp.Body,
-		Total:      contentLength,
+		Total:      total,
+		ReadSoFar:  offset,
 		OnProgress: onProgress,
 	}
 
-	// Copy to file
-	written, err := io.Copy(out, progressReader)
-	if err != nil {
-		return fmt.Errorf("failed to write file: %w", err)
-	}
-
-	// Verify we got the expected amount of data
-	if contentLength > 0 && written != contentLength {
-		return fmt.Errorf("incomplete download: got %d bytes, expected %d", written, contentLength)
+	if _, err := io.Copy(out, progressReader); err != nil {
+		return &transientDownloadError{fmt.Errorf("failed to write file: %w", err)}
 	}
-
-	// Ensure data is flushed to disk
 	if err := out.Sync(); err != nil {
 		return fmt.Errorf("failed to sync file: %w", err)
 	}
 	out.Close()
 
-	// Move temp file to final destination
-	if err := os.Rename(tempPath, destPath); err != nil {
-		return fmt.Errorf("failed to rename temp file: %w", err)
+	if !checksum.empty() {
+		if err := VerifyFileChecksum(partPath, checksum); err != nil {
+			os.Remove(partPath)
+			os.Remove(etagPath)
+			return err
+		}
 	}
 
-	success = true
+	if err := os.Rename(partPath, destPath); err != nil {
+		return fmt.Errorf("failed to rename %s to %s: %w", partPath, destPath, err)
+	}
+	os.Remove(etagPath)
+
 	return nil
 }
 
-// ProgressReader wraps an io.Reader to track download progress
-type ProgressReader struct {
-	Reader     io.Reader
-	Total      int64
-	ReadSoFar  int64
-	OnProgress ProgressCallback
+// VerifyFileSHA256 computes the SHA256 checksum of the file at path and
+// compares it (case-insensitively) against expectedHex. It returns an error
+// if the file can't be read or the checksum doesn't match.
+func VerifyFileSHA256(path string, expectedHex string) error {
+	return VerifyFileChecksum(path, Checksum{Algo: "sha256", Hex: expectedHex})
 }
 
-func (pr *ProgressReader) Read(p []byte) (int, error) {
-	n, err := pr.Reader.Read(p)
-	pr.ReadSoFar += int64(n)
-
-	if pr.Total > 0 && pr.OnProgress != nil {
-		progress := float64(pr.ReadSoFar) / float64(pr.Total) * 100
-		pr.OnProgress(progress)
+// VerifyFileChecksum computes the file at path's digest using checksum.Algo
+// and compares it (case-insensitively) against checksum.Hex.
+func VerifyFileChecksum(path string, checksum Checksum) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s for checksum verification: %w", path, err)
 	}
+	defer f.Close()
 
-	return n, err
-}
-
-// Unzip extracts a zip archive to a destination directory.
-func Unzip(src string, dest string) error {
-	r, err := zip.OpenReader(src)
+	h, err := checksum.newHash()
 	if err != nil {
 		return err
 	}
-	defer r.Close()
-
-	if err := os.MkdirAll(dest, 0755); err != nil {
-		return err
+	if _, err := io.Copy(h, f); err != nil {
+		return fmt.Errorf("failed to hash %s: %w", path, err)
 	}
 
-	// Closure to address file descriptors issue with all the deferred .Close() methods
-	extractAndWriteFile := func(f *zip.File) error {
-		rc, err := f.Open()
-		if err != nil {
-			return err
-		}
-		defer rc.Close()
+	actual := hex.EncodeToString(h.Sum(nil))
+	if !strings.EqualFold(actual, checksum.Hex) {
+		return fmt.Errorf("checksum mismatch: expected %s, got %s", checksum.Hex, actual)
+	}
+	return nil
+}
 
-		path := filepath.Join(dest, f.Name)
+// ProgressUpdate carries a richer download progress snapshot than
+// ProgressCallback's bare percent - see ProgressReader.OnDetailedProgress.
+type ProgressUpdate struct {
+	Percent     float64
+	BytesRead   int64
+	TotalBytes  int64
+	BytesPerSec float64
+	ETA         time.Duration
+}
 
-		// Check for ZipSlip (Directory traversal)
-		if !strings.HasPrefix(path, filepath.Clean(dest)+string(os.PathSeparator)) {
-			return fmt.Errorf("illegal file path: %s", path)
-		}
+// DetailedProgressCallback receives a ProgressUpdate on every Read, for UIs
+// that want to render download speed/ETA alongside percent.
+type DetailedProgressCallback func(update ProgressUpdate)
 
-		if f.FileInfo().IsDir() {
-			os.MkdirAll(path, 0755)
-		} else {
-			os.MkdirAll(filepath.Dir(path), 0755)
-			
-			// Try to open file with standard permissions
-			f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0755)
-			if err != nil {
-				// If opening fails, it might be due to existing read-only file
-				// Try to remove it first
-				os.Remove(path)
-				f, err = os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0755)
-				if err != nil {
-					return err
-				}
-			}
-			defer f.Close()
+// ProgressReader wraps an io.Reader to track download progress, including
+// speed and ETA derived from elapsed time since the first Read call.
+type ProgressReader struct {
+	Reader             io.Reader
+	Total              int64
+	ReadSoFar          int64
+	OnProgress         ProgressCallback
+	OnDetailedProgress DetailedProgressCallback
 
-			_, err = io.Copy(f, rc)
-			if err != nil {
-				return err
-			}
-		}
-		return nil
-	}
+	startedAt time.Time
+}
 
-	for _, f := range r.File {
-		err := extractAndWriteFile(f)
-		if err != nil {
-			return err
-		}
+func (pr *ProgressReader) Read(p []byte) (int, error) {
+	if pr.startedAt.IsZero() {
+		pr.startedAt = time.Now()
 	}
 
-	return nil
-}
+	n, err := pr.Reader.Read(p)
+	pr.ReadSoFar += int64(n)
 
-// UntarGz extracts a .tar.gz archive to a destination directory.
-func UntarGz(src string, dest string) error {
-	f, err := os.Open(src)
-	if err != nil {
-		return err
+	elapsed := time.Since(pr.startedAt).Seconds()
+	var bytesPerSec float64
+	if elapsed > 0 {
+		bytesPerSec = float64(pr.ReadSoFar) / elapsed
 	}
-	defer f.Close()
 
-	gzr, err := gzip.NewReader(f)
-	if err != nil {
-		return err
+	if pr.Total > 0 && pr.OnProgress != nil {
+		progress := float64(pr.ReadSoFar) / float64(pr.Total) * 100
+		pr.OnProgress(progress)
 	}
-	defer gzr.Close()
-
-	tr := tar.NewReader(gzr)
 
-	for {
-		header, err := tr.Next()
-		if err == io.EOF {
-			break
+	if pr.OnDetailedProgress != nil {
+		var eta time.Duration
+		if bytesPerSec > 0 && pr.Total > pr.ReadSoFar {
+			eta = time.Duration(float64(pr.Total-pr.ReadSoFar)/bytesPerSec) * time.Second
 		}
-		if err != nil {
-			return err
-		}
-
-		// Clean the header name - remove leading ./ and handle empty names
-		name := header.Name
-		name = strings.TrimPrefix(name, "./")
-		if name == "" || name == "." {
-			continue // Skip empty or current directory entries
+		var percent float64
+		if pr.Total > 0 {
+			percent = float64(pr.ReadSoFar) / float64(pr.Total) * 100
 		}
+		pr.OnDetailedProgress(ProgressUpdate{
+			Percent:     percent,
+			BytesRead:   pr.ReadSoFar,
+			TotalBytes:  pr.Total,
+			BytesPerSec: bytesPerSec,
+			ETA:         eta,
+		})
+	}
 
-		target := filepath.Join(dest, name)
+	return n, err
+}
 
-		// Check for path traversal (after cleaning)
-		cleanDest := filepath.Clean(dest)
-		cleanTarget := filepath.Clean(target)
-		if !strings.HasPrefix(cleanTarget, cleanDest) {
-			return fmt.Errorf("illegal file path: %s", header.Name)
-		}
+// Unzip extracts a zip archive to a destination directory. See Extractor
+// for hardened options (zip-bomb guards, mode masking, progress reporting).
+func Unzip(src string, dest string) error {
+	return NewZipExtractor().Extract(src, dest, ExtractOptions{})
+}
 
-		switch header.Typeflag {
-		case tar.TypeDir:
-			if err := os.MkdirAll(target, 0755); err != nil {
-				return err
-			}
-		case tar.TypeReg:
-			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
-				return err
-			}
-			outFile, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, os.FileMode(header.Mode)|0755)
-			if err != nil {
-				return err
-			}
-			if _, err := io.Copy(outFile, tr); err != nil {
-				outFile.Close()
-				return err
-			}
-			outFile.Close()
-		case tar.TypeSymlink:
-			// Handle symlinks - common in Unix archives
-			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
-				return err
-			}
-			os.Remove(target) // Remove existing file/symlink if any
-			if err := os.Symlink(header.Linkname, target); err != nil {
-				// Symlink might fail on some systems, just skip
-				fmt.Printf("Warning: failed to create symlink %s: %v\n", target, err)
-			}
-		}
-	}
+// UntarGz extracts a .tar.gz archive to a destination directory. See
+// Extractor for hardened options.
+func UntarGz(src string, dest string) error {
+	return NewTarGzExtractor().Extract(src, dest, ExtractOptions{})
+}
 
-	return nil
+// UntarZstd extracts a .tar.zst archive to a destination directory. See
+// Extractor for hardened options.
+func UntarZstd(src string, dest string) error {
+	return NewTarZstdExtractor().Extract(src, dest, ExtractOptions{})
 }
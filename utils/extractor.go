@@ -0,0 +1,414 @@
+package utils
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// ExtractOptions configures a hardened archive extraction.
+type ExtractOptions struct {
+	// MaxUncompressedSize caps the total bytes written across all entries,
+	// guarding against zip/tar bombs. 0 means unlimited.
+	MaxUncompressedSize int64
+
+	// MaxFileCount caps the number of entries an archive may contain. 0
+	// means unlimited.
+	MaxFileCount int
+
+	// Umask is masked out of each entry's header mode bits before the file
+	// or directory is created, instead of forcing a hardcoded mode. 0
+	// leaves the archive's mode bits as-is (subject to the 0777 mask
+	// already implied by a zero value).
+	Umask os.FileMode
+
+	// OnProgress, if set, is called after each entry is extracted with the
+	// percentage of entries completed so far (0-100). Only fired when the
+	// total entry count is known up front.
+	OnProgress ProgressCallback
+}
+
+// withDefaults fills in an Umask of 0022 when the caller leaves the zero
+// value, matching common Unix default file permissions.
+func (o ExtractOptions) withDefaults() ExtractOptions {
+	if o.Umask == 0 {
+		o.Umask = 0022
+	}
+	return o
+}
+
+// Extractor extracts a single archive format to a destination directory.
+type Extractor interface {
+	Extract(src, dest string, opts ExtractOptions) error
+}
+
+// entryKind distinguishes the archive entry types the hardened core knows
+// how to write.
+type entryKind int
+
+const (
+	entryDir entryKind = iota
+	entryFile
+	entrySymlink
+)
+
+// archiveEntry is the format-independent shape extractEntries writes to
+// disk - zipEntryReader and tarEntryReader adapt archive/zip and archive/tar
+// to this shape so the hardening logic (path/symlink validation, zip-bomb
+// guard, mode masking, progress) lives in one place.
+type archiveEntry struct {
+	Name     string
+	Kind     entryKind
+	Linkname string
+	Mode     os.FileMode
+	Reader   io.Reader // nil for entryDir and entrySymlink
+}
+
+// entrySource yields archive entries one at a time, returning io.EOF when
+// exhausted. total is the number of entries the archive will yield, or -1 if
+// unknown ahead of time (e.g. a single-pass tar stream).
+type entrySource struct {
+	total int
+	next  func() (*archiveEntry, error)
+}
+
+// zipExtractor is the Extractor for .zip archives.
+type zipExtractor struct{}
+
+// NewZipExtractor returns an Extractor for .zip archives.
+func NewZipExtractor() Extractor { return zipExtractor{} }
+
+func (zipExtractor) Extract(src, dest string, opts ExtractOptions) error {
+	r, err := zip.OpenReader(src)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	files := r.File
+	idx := 0
+	source := entrySource{
+		total: len(files),
+		next: func() (*archiveEntry, error) {
+			if idx >= len(files) {
+				return nil, io.EOF
+			}
+			f := files[idx]
+			idx++
+
+			kind := entryFile
+			if f.FileInfo().IsDir() {
+				kind = entryDir
+			}
+			var rc io.ReadCloser
+			if kind == entryFile {
+				rc, err = f.Open()
+				if err != nil {
+					return nil, err
+				}
+			}
+			return &archiveEntry{Name: f.Name, Kind: kind, Mode: f.Mode(), Reader: rc}, nil
+		},
+	}
+
+	return extractEntries(dest, source, opts)
+}
+
+// tarGzExtractor is the Extractor for .tar.gz archives.
+type tarGzExtractor struct{}
+
+// NewTarGzExtractor returns an Extractor for .tar.gz archives.
+func NewTarGzExtractor() Extractor { return tarGzExtractor{} }
+
+func (tarGzExtractor) Extract(src, dest string, opts ExtractOptions) error {
+	return extractTar(src, dest, opts, func(r io.Reader) (io.Reader, func() error, error) {
+		gzr, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, nil, err
+		}
+		return gzr, gzr.Close, nil
+	})
+}
+
+// tarZstdExtractor is the Extractor for .tar.zst archives.
+type tarZstdExtractor struct{}
+
+// NewTarZstdExtractor returns an Extractor for .tar.zst archives.
+func NewTarZstdExtractor() Extractor { return tarZstdExtractor{} }
+
+func (tarZstdExtractor) Extract(src, dest string, opts ExtractOptions) error {
+	return extractTar(src, dest, opts, func(r io.Reader) (io.Reader, func() error, error) {
+		zr, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, nil, err
+		}
+		rc := zr.IOReadCloser()
+		return rc, rc.Close, nil
+	})
+}
+
+// extractTar implements the shared tar-stream machinery for both
+// tarGzExtractor and tarZstdExtractor, which differ only in decompressor.
+// Since a tar stream doesn't know its entry count up front, it's counted in
+// a first pass (so OnProgress can still report a real percentage) before the
+// second pass actually extracts.
+func extractTar(src, dest string, opts ExtractOptions, decompress func(io.Reader) (io.Reader, func() error, error)) error {
+	total, err := countTarEntries(src, decompress)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r, closeDecompressor, err := decompress(f)
+	if err != nil {
+		return err
+	}
+	defer closeDecompressor()
+
+	tr := tar.NewReader(r)
+	source := entrySource{
+		total: total,
+		next: func() (*archiveEntry, error) {
+			header, err := tr.Next()
+			if err != nil {
+				return nil, err
+			}
+			kind := entryFile
+			switch header.Typeflag {
+			case tar.TypeDir:
+				kind = entryDir
+			case tar.TypeSymlink:
+				kind = entrySymlink
+			}
+			entry := &archiveEntry{
+				Name:     header.Name,
+				Kind:     kind,
+				Linkname: header.Linkname,
+				Mode:     os.FileMode(header.Mode),
+			}
+			if kind == entryFile {
+				entry.Reader = tr
+			}
+			return entry, nil
+		},
+	}
+
+	return extractEntries(dest, source, opts)
+}
+
+// countTarEntries makes a first pass over a tar stream purely to count
+// entries, so extractTar can report real (not indeterminate) progress.
+func countTarEntries(src string, decompress func(io.Reader) (io.Reader, func() error, error)) (int, error) {
+	f, err := os.Open(src)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	r, closeDecompressor, err := decompress(f)
+	if err != nil {
+		return 0, err
+	}
+	defer closeDecompressor()
+
+	tr := tar.NewReader(r)
+	count := 0
+	for {
+		_, err := tr.Next()
+		if err == io.EOF {
+			return count, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		count++
+	}
+}
+
+// extractEntries is the hardened core shared by every Extractor: it
+// validates each entry's path and (for symlinks) link target stay inside
+// dest, enforces the zip-bomb guards in opts, masks mode bits by opts.Umask,
+// and writes the entry to disk, reporting progress when the total entry
+// count is known.
+func extractEntries(dest string, source entrySource, opts ExtractOptions) error {
+	opts = opts.withDefaults()
+
+	if err := os.MkdirAll(dest, 0755); err != nil {
+		return err
+	}
+	destRoot, err := resolveRoot(dest)
+	if err != nil {
+		return err
+	}
+
+	var totalWritten int64
+	var count int
+	for {
+		entry, err := source.next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if opts.MaxFileCount > 0 && count >= opts.MaxFileCount {
+			return fmt.Errorf("archive exceeds max file count of %d", opts.MaxFileCount)
+		}
+		count++
+
+		name := strings.TrimPrefix(entry.Name, "./")
+		if name == "" || name == "." {
+			continue
+		}
+
+		target, err := safeJoin(destRoot, name)
+		if err != nil {
+			return err
+		}
+
+		mode := entry.Mode &^ opts.Umask
+
+		switch entry.Kind {
+		case entryDir:
+			if err := os.MkdirAll(target, mode|0700); err != nil {
+				return err
+			}
+
+		case entrySymlink:
+			if err := validateLinkTarget(destRoot, target, entry.Linkname); err != nil {
+				return err
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			os.Remove(target)
+			if err := os.Symlink(entry.Linkname, target); err != nil {
+				return fmt.Errorf("failed to create symlink %s: %w", target, err)
+			}
+
+		case entryFile:
+			err := writeFileEntry(destRoot, target, mode, entry.Reader, opts, &totalWritten)
+			if rc, ok := entry.Reader.(io.Closer); ok {
+				rc.Close()
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		if opts.OnProgress != nil && source.total > 0 {
+			opts.OnProgress(float64(count) / float64(source.total) * 100)
+		}
+	}
+}
+
+// writeFileEntry validates target's parent chain (resolving any symlink
+// planted by an earlier entry) before writing through it, then copies the
+// entry's contents under the zip-bomb size guard.
+func writeFileEntry(destRoot, target string, mode os.FileMode, r io.Reader, opts ExtractOptions, totalWritten *int64) error {
+	parent := filepath.Dir(target)
+	if err := os.MkdirAll(parent, 0755); err != nil {
+		return err
+	}
+	resolvedParent, err := resolveRoot(parent)
+	if err != nil {
+		return err
+	}
+	if !withinRoot(destRoot, resolvedParent) {
+		return fmt.Errorf("illegal file path (escapes via symlink): %s", target)
+	}
+
+	out, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode|0600)
+	if err != nil {
+		os.Remove(target)
+		out, err = os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode|0600)
+		if err != nil {
+			return err
+		}
+	}
+	defer out.Close()
+
+	limit := opts.MaxUncompressedSize
+	if limit > 0 {
+		remaining := limit - *totalWritten
+		if remaining <= 0 {
+			return fmt.Errorf("archive exceeds max uncompressed size of %d bytes", limit)
+		}
+		limited := io.LimitReader(r, remaining+1)
+		n, err := io.Copy(out, limited)
+		*totalWritten += n
+		if err != nil {
+			return err
+		}
+		if n > remaining {
+			return fmt.Errorf("archive exceeds max uncompressed size of %d bytes", limit)
+		}
+		return nil
+	}
+
+	n, err := io.Copy(out, r)
+	*totalWritten += n
+	return err
+}
+
+// resolveRoot resolves symlinks in path so later prefix checks see real
+// filesystem locations rather than symlinked aliases. Falls back to the
+// cleaned path if path doesn't exist yet.
+func resolveRoot(path string) (string, error) {
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return filepath.Clean(path), nil
+		}
+		return "", err
+	}
+	return resolved, nil
+}
+
+// withinRoot reports whether candidate is root or a descendant of it.
+func withinRoot(root, candidate string) bool {
+	root = filepath.Clean(root)
+	candidate = filepath.Clean(candidate)
+	if candidate == root {
+		return true
+	}
+	return strings.HasPrefix(candidate, root+string(os.PathSeparator))
+}
+
+// safeJoin joins root and name, rejecting the result (ZipSlip-style path
+// traversal) if it would land outside root.
+func safeJoin(root, name string) (string, error) {
+	target := filepath.Join(root, name)
+	if !withinRoot(root, target) {
+		return "", fmt.Errorf("illegal file path: %s", name)
+	}
+	return target, nil
+}
+
+// validateLinkTarget rejects absolute or ".."-containing link targets, then
+// confirms the link (resolved relative to its own location) still lands
+// inside destRoot - closing the symlink-then-write-through path traversal
+// that a bare os.Symlink(header.Linkname, target) call is vulnerable to.
+func validateLinkTarget(destRoot, linkPath, linkname string) error {
+	if filepath.IsAbs(linkname) {
+		return fmt.Errorf("illegal symlink target (absolute): %s", linkname)
+	}
+	resolved := filepath.Join(filepath.Dir(linkPath), linkname)
+	if !withinRoot(destRoot, resolved) {
+		return fmt.Errorf("illegal symlink target (escapes destination): %s", linkname)
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+package vad
+
+// Detector classifies one chunk of audio as speech or non-speech,
+// independent of VADService's speech-buffer/silence-timer state machine.
+// Implementations are free to be as simple as an RMS threshold
+// (EnergyDetector) or back a real ML model (SileroDetector, WebRtcDetector);
+// VADService.ProcessSamples treats every Detector identically.
+type Detector interface {
+	// Process classifies samples, returning whether the chunk is speech and
+	// a confidence in [0, 1]. For EnergyDetector, confidence is the
+	// smoothed RMS energy value rather than a calibrated probability; callers
+	// that need a genuine probability should use SileroDetector or
+	// WebRtcDetector instead.
+	Process(samples []float32) (isSpeech bool, confidence float32)
+	// Reset clears any internal state (rolling windows, recurrent model
+	// state, etc) so the next Process call isn't influenced by audio from a
+	// previous, unrelated utterance. Called by VADService.reset.
+	Reset()
+}
@@ -0,0 +1,207 @@
+package vad
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultNoiseFloorRatio is how many times the tracked noise floor the
+	// fast energy estimate must exceed to count as speech, in adaptive mode.
+	defaultNoiseFloorRatio = 3.0
+	// defaultMinNoiseFloor is a hard floor under the tracked noise floor, so
+	// a near-silent room (where the floor would otherwise decay towards 0)
+	// can't make the effective threshold low enough to trigger on nothing.
+	defaultMinNoiseFloor = 0.002
+	// defaultFastAlpha/defaultSlowAlpha are the EWMA weights for the fast
+	// (current signal) and slow (noise floor) running estimates - a higher
+	// weight tracks the input more closely but smooths less.
+	defaultFastAlpha = 0.3
+	defaultSlowAlpha = 0.05
+	// defaultWarmupWindow is how long Reset() (called from VADService.Start)
+	// suppresses speech detection while seeding the noise floor from
+	// incoming audio, in adaptive mode.
+	defaultWarmupWindow = 500 * time.Millisecond
+)
+
+// EnergyDetector is the original RMS/threshold Detector. It runs in one of
+// two modes:
+//   - fixed-threshold (NewEnergyDetector): smooths RMS energy over a rolling
+//     window and compares it against a fixed threshold, exactly like the
+//     original implementation.
+//   - adaptive (NewAdaptiveEnergyDetector, the default - see
+//     VADService.Config.FixedThreshold): tracks a noise floor and compares a
+//     fast-moving energy estimate against noiseFloor*ratio instead, so
+//     SetThreshold/EnergyThreshold don't need retuning as ambient noise
+//     changes.
+//
+// Either way it's the default Detector (see DefaultConfig), kept around for
+// machines where pulling in an ML model isn't worth it and for regression
+// testing against the probabilistic backends.
+type EnergyDetector struct {
+	mutex sync.Mutex
+
+	fixedThreshold bool
+	threshold      float32 // fixed-threshold mode only
+
+	recentEnergy     []float32 // fixed-threshold mode only
+	energyWindowSize int
+
+	// Adaptive mode only.
+	ratio         float32
+	minNoiseFloor float32
+	fastAlpha     float32
+	slowAlpha     float32
+	fastEnergy    float32
+	noiseFloor    float32
+	warmupUntil   time.Time
+	warmupWindow  time.Duration
+}
+
+// NewEnergyDetector creates a fixed-threshold EnergyDetector comparing
+// smoothed RMS energy against threshold, averaged over the last windowSize
+// chunks.
+func NewEnergyDetector(threshold float32, windowSize int) *EnergyDetector {
+	if windowSize <= 0 {
+		windowSize = 10
+	}
+	return &EnergyDetector{
+		fixedThreshold:   true,
+		threshold:        threshold,
+		recentEnergy:     make([]float32, 0, windowSize),
+		energyWindowSize: windowSize,
+	}
+}
+
+// NewAdaptiveEnergyDetector creates an EnergyDetector that tracks a noise
+// floor instead of using a fixed threshold: the effective speech threshold
+// becomes noiseFloor*ratio (never below defaultMinNoiseFloor). ratio <= 0
+// uses defaultNoiseFloorRatio.
+func NewAdaptiveEnergyDetector(ratio float32) *EnergyDetector {
+	if ratio <= 0 {
+		ratio = defaultNoiseFloorRatio
+	}
+	return &EnergyDetector{
+		ratio:         ratio,
+		minNoiseFloor: defaultMinNoiseFloor,
+		fastAlpha:     defaultFastAlpha,
+		slowAlpha:     defaultSlowAlpha,
+		warmupWindow:  defaultWarmupWindow,
+	}
+}
+
+// SetThreshold updates the energy threshold, matching
+// VADService.SetEnergyThreshold's existing semantics. Only has an effect in
+// fixed-threshold mode.
+func (d *EnergyDetector) SetThreshold(threshold float32) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.threshold = threshold
+}
+
+// GetNoiseFloor returns the currently tracked noise floor, for a live meter.
+// Always 0 in fixed-threshold mode.
+func (d *EnergyDetector) GetNoiseFloor() float32 {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.noiseFloor
+}
+
+// GetSNR returns the current signal-to-noise ratio in decibels, comparing
+// the fast energy estimate against the tracked noise floor (clamped to
+// minNoiseFloor to avoid a divide-by-near-zero spike). Always 0 in
+// fixed-threshold mode.
+func (d *EnergyDetector) GetSNR() float32 {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.fixedThreshold {
+		return 0
+	}
+	floor := d.noiseFloor
+	if floor < d.minNoiseFloor {
+		floor = d.minNoiseFloor
+	}
+	if d.fastEnergy <= 0 || floor <= 0 {
+		return 0
+	}
+	return float32(20 * math.Log10(float64(d.fastEnergy/floor)))
+}
+
+// Process implements Detector.
+func (d *EnergyDetector) Process(samples []float32) (bool, float32) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	energy := calculateRMS(samples)
+
+	if d.fixedThreshold {
+		d.recentEnergy = append(d.recentEnergy, energy)
+		if len(d.recentEnergy) > d.energyWindowSize {
+			d.recentEnergy = d.recentEnergy[1:]
+		}
+		smoothed := averageEnergy(d.recentEnergy)
+		return smoothed > d.threshold, smoothed
+	}
+
+	d.fastEnergy = d.fastAlpha*energy + (1-d.fastAlpha)*d.fastEnergy
+
+	if time.Now().Before(d.warmupUntil) {
+		// Still warming up: seed the noise floor directly from incoming
+		// audio and never report speech.
+		d.noiseFloor = d.fastEnergy
+		return false, d.fastEnergy
+	}
+
+	effectiveThreshold := d.noiseFloor * d.ratio
+	if effectiveThreshold < d.minNoiseFloor {
+		effectiveThreshold = d.minNoiseFloor
+	}
+	isSpeech := d.fastEnergy > effectiveThreshold
+	if !isSpeech {
+		// Only let non-speech chunks pull the noise floor, so a loud
+		// utterance doesn't drag its own threshold up mid-sentence.
+		d.noiseFloor = d.slowAlpha*d.fastEnergy + (1-d.slowAlpha)*d.noiseFloor
+	}
+	return isSpeech, d.fastEnergy
+}
+
+// Reset implements Detector. In adaptive mode this also re-arms the warmup
+// window, so each VADService.Start begins by re-seeding the noise floor
+// rather than trusting a potentially stale one.
+func (d *EnergyDetector) Reset() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.recentEnergy = d.recentEnergy[:0]
+	if !d.fixedThreshold {
+		d.warmupUntil = time.Now().Add(d.warmupWindow)
+	}
+}
+
+// calculateRMS calculates the root mean square energy of samples.
+func calculateRMS(samples []float32) float32 {
+	if len(samples) == 0 {
+		return 0
+	}
+
+	var sum float64
+	for _, s := range samples {
+		sum += float64(s) * float64(s)
+	}
+
+	return float32(math.Sqrt(sum / float64(len(samples))))
+}
+
+// averageEnergy calculates the average of recent energy values.
+func averageEnergy(recentEnergy []float32) float32 {
+	if len(recentEnergy) == 0 {
+		return 0
+	}
+
+	var sum float32
+	for _, e := range recentEnergy {
+		sum += e
+	}
+
+	return sum / float32(len(recentEnergy))
+}
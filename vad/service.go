@@ -2,7 +2,6 @@ package vad
 
 import (
 	"log/slog"
-	"math"
 	"sync"
 	"time"
 )
@@ -13,42 +12,97 @@ type SpeechStartCallback func()
 // SpeechEndCallback is called when speech ends, with the accumulated audio samples
 type SpeechEndCallback func(samples []float32)
 
+// EnergyCallback receives every chunk's Detector confidence as ProcessSamples
+// handles it, independent of whether that chunk crossed the speech/silence
+// threshold - see VADService.SetOnEnergy. Named for the original
+// energy-only detector; with a probabilistic Detector this is a model
+// confidence rather than an RMS value.
+type EnergyCallback func(confidence float32)
+
+// SpeechChunkCallback streams each incoming chunk once speech has been
+// confirmed for the current utterance, instead of waiting for
+// SilenceDuration like SpeechEndCallback does - see VADService.SetOnSpeechChunk.
+// seq starts at 0 for an utterance's first chunk (which includes
+// PreRollDuration of lead-in audio, if configured) and increments by one per
+// chunk after that, resetting back to 0 on the next utterance.
+type SpeechChunkCallback func(samples []float32, seq int)
+
 // VADService provides real-time voice activity detection
 type VADService struct {
 	// Configuration
-	energyThreshold     float32       // RMS energy threshold to detect speech (0.0 - 1.0)
-	silenceDuration     time.Duration // Duration of silence to trigger speech end
-	minSpeechDuration   time.Duration // Minimum speech duration before accepting
-	maxSpeechDuration   time.Duration // Maximum speech duration (buffer limit)
+	detector          Detector      // Per-chunk speech/non-speech classifier
+	speechConfidence  float32       // Hysteresis: confidence required to enter speech (0 = use detector's own verdict)
+	silenceConfidence float32       // Hysteresis: confidence below which speech is considered to have ended
+	silenceDuration   time.Duration // Duration of silence to trigger speech end
+	minSpeechDuration time.Duration // Minimum speech duration before accepting
+	maxSpeechDuration time.Duration // Maximum speech duration (buffer limit)
+	preRollDuration   time.Duration // Lead-in audio captured before speech is confirmed
 
 	// State
-	isSpeaking          bool
-	silenceStartTime    time.Time
-	speechStartTime     time.Time
-	speechBuffer        []float32
-	recentEnergy        []float32  // Rolling window of energy values for smoothing
-	energyWindowSize    int
+	isSpeaking       bool
+	silenceStartTime time.Time
+	speechStartTime  time.Time
+	speechBuffer     []float32
+	preRoll          []float32 // Rolling lead-in buffer, maintained while !isSpeaking
+	chunkSeq         int       // Next seq passed to onSpeechChunk, reset per utterance
 
 	// Callbacks
-	onSpeechStart       SpeechStartCallback
-	onSpeechEnd         SpeechEndCallback
+	onSpeechStart SpeechStartCallback
+	onSpeechEnd   SpeechEndCallback
+	onSpeechChunk SpeechChunkCallback
+	onEnergy      EnergyCallback
 
 	// Synchronization
-	mutex               sync.Mutex
-	enabled             bool
-	paused              bool // Pause VAD during TTS playback
-	
+	mutex   sync.Mutex
+	enabled bool
+	paused  bool // Pause VAD during TTS playback
+
 	// Sample rate for duration calculations
-	sampleRate          uint32
+	sampleRate uint32
 }
 
 // Config holds VAD configuration
 type Config struct {
-	EnergyThreshold   float32       // RMS energy threshold (default: 0.015)
+	EnergyThreshold   float32       // RMS energy threshold (default: 0.015), used by the default EnergyDetector
 	SilenceDuration   time.Duration // Silence to trigger end (default: 300ms)
 	MinSpeechDuration time.Duration // Minimum speech duration (default: 200ms)
 	MaxSpeechDuration time.Duration // Maximum speech duration (default: 30s)
 	SampleRate        uint32        // Audio sample rate (default: 16000)
+
+	// PreRollDuration captures audio immediately preceding a confirmed speech
+	// start, via a small rolling buffer kept even while !isSpeaking, so the
+	// first chunk handed to OnSpeechChunk (and the start of the buffer
+	// SpeechEndCallback eventually receives) isn't missing the word onset.
+	// Default 0 disables pre-roll entirely.
+	PreRollDuration time.Duration
+
+	// Detector classifies each chunk as speech or non-speech. Defaults to a
+	// new EnergyDetector seeded from NoiseFloorRatio/FixedThreshold/
+	// EnergyThreshold below - set this to a *SileroDetector or *WebRtcDetector
+	// to use a probabilistic backend instead.
+	Detector Detector
+
+	// NoiseFloorRatio sets the default adaptive EnergyDetector's effective
+	// threshold as a multiple of its tracked noise floor (default ~3.0).
+	// Only applies when Detector is nil and FixedThreshold is false.
+	NoiseFloorRatio float32
+
+	// FixedThreshold makes the default Detector compare smoothed energy
+	// directly against EnergyThreshold instead of adaptively tracking a
+	// noise floor, matching the original pre-calibration behavior - useful
+	// for regression testing against a known fixed threshold. Only applies
+	// when Detector is nil.
+	FixedThreshold bool
+
+	// SpeechConfidence and SilenceConfidence apply hysteresis to Detector's
+	// per-chunk confidence instead of relying on Detector's own verdict:
+	// entering speech requires confidence >= SpeechConfidence, while
+	// continuing speech only ends once confidence drops below
+	// SilenceConfidence. Intended for probabilistic detectors
+	// (SileroDetector, WebRtcDetector); leave both zero to use Detector's own
+	// isSpeech verdict directly, which is EnergyDetector's default behavior.
+	SpeechConfidence  float32
+	SilenceConfidence float32
 }
 
 // DefaultConfig returns default VAD configuration
@@ -79,16 +133,24 @@ func NewVADService(cfg Config) *VADService {
 	if cfg.MaxSpeechDuration == 0 {
 		cfg.MaxSpeechDuration = 30 * time.Second
 	}
+	if cfg.Detector == nil {
+		if cfg.FixedThreshold {
+			cfg.Detector = NewEnergyDetector(cfg.EnergyThreshold, 10)
+		} else {
+			cfg.Detector = NewAdaptiveEnergyDetector(cfg.NoiseFloorRatio)
+		}
+	}
 
 	return &VADService{
-		energyThreshold:   cfg.EnergyThreshold,
+		detector:          cfg.Detector,
+		speechConfidence:  cfg.SpeechConfidence,
+		silenceConfidence: cfg.SilenceConfidence,
 		silenceDuration:   cfg.SilenceDuration,
 		minSpeechDuration: cfg.MinSpeechDuration,
 		maxSpeechDuration: cfg.MaxSpeechDuration,
+		preRollDuration:   cfg.PreRollDuration,
 		sampleRate:        cfg.SampleRate,
 		speechBuffer:      make([]float32, 0, int(cfg.SampleRate)*5), // Pre-allocate 5 seconds
-		recentEnergy:      make([]float32, 0, 10),                    // Rolling window of 10 chunks
-		energyWindowSize:  10,
 		enabled:           false,
 		paused:            false,
 	}
@@ -102,6 +164,23 @@ func (v *VADService) SetCallbacks(onStart SpeechStartCallback, onEnd SpeechEndCa
 	v.onSpeechEnd = onEnd
 }
 
+// SetOnEnergy sets the callback fired with every chunk's Detector confidence,
+// for UI meters. Pass nil to stop receiving it.
+func (v *VADService) SetOnEnergy(onEnergy EnergyCallback) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	v.onEnergy = onEnergy
+}
+
+// SetOnSpeechChunk sets the callback that streams each incoming chunk once
+// speech has been confirmed, for incremental transcription. Pass nil to stop
+// receiving it.
+func (v *VADService) SetOnSpeechChunk(onSpeechChunk SpeechChunkCallback) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	v.onSpeechChunk = onSpeechChunk
+}
+
 // Start enables VAD processing
 func (v *VADService) Start() {
 	v.mutex.Lock()
@@ -139,6 +218,21 @@ func (v *VADService) Resume() {
 	slog.Debug("[VAD] Resumed")
 }
 
+// Abort discards the current utterance without firing onSpeechEnd, for use
+// when TTS playback starts and the user interrupts themselves mid-utterance
+// (see the App's barge-in handling). Unlike Stop, VAD stays enabled and
+// ready to detect the next utterance. A no-op if no utterance is in
+// progress.
+func (v *VADService) Abort() {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	if !v.isSpeaking {
+		return
+	}
+	slog.Debug("[VAD] Utterance aborted")
+	v.reset()
+}
+
 // IsEnabled returns whether VAD is currently active
 func (v *VADService) IsEnabled() bool {
 	v.mutex.Lock()
@@ -161,21 +255,78 @@ func (v *VADService) SetSilenceDuration(d time.Duration) {
 	slog.Info("[VAD] Silence duration updated", "duration", d)
 }
 
-// SetEnergyThreshold updates the energy threshold
+// SetEnergyThreshold updates the energy threshold. Only has an effect when
+// the active Detector is the default EnergyDetector; a probabilistic
+// Detector ignores it in favor of SpeechConfidence/SilenceConfidence.
 func (v *VADService) SetEnergyThreshold(threshold float32) {
 	v.mutex.Lock()
 	defer v.mutex.Unlock()
-	v.energyThreshold = threshold
+	if ed, ok := v.detector.(*EnergyDetector); ok {
+		ed.SetThreshold(threshold)
+	}
 	slog.Info("[VAD] Energy threshold updated", "threshold", threshold)
 }
 
+// GetNoiseFloor returns the active Detector's currently tracked noise floor,
+// for a live meter. Only meaningful when the active Detector is an adaptive
+// EnergyDetector (see Config.NoiseFloorRatio); returns 0 otherwise.
+func (v *VADService) GetNoiseFloor() float32 {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	if ed, ok := v.detector.(*EnergyDetector); ok {
+		return ed.GetNoiseFloor()
+	}
+	return 0
+}
+
+// GetSNR returns the active Detector's current signal-to-noise ratio in
+// decibels. Only meaningful when the active Detector is an adaptive
+// EnergyDetector (see Config.NoiseFloorRatio); returns 0 otherwise.
+func (v *VADService) GetSNR() float32 {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	if ed, ok := v.detector.(*EnergyDetector); ok {
+		return ed.GetSNR()
+	}
+	return 0
+}
+
+// SetConfidenceThresholds updates the hysteresis thresholds applied to a
+// probabilistic Detector's confidence - see Config.SpeechConfidence.
+func (v *VADService) SetConfidenceThresholds(speechConfidence, silenceConfidence float32) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	v.speechConfidence = speechConfidence
+	v.silenceConfidence = silenceConfidence
+	slog.Info("[VAD] Confidence thresholds updated", "speechConfidence", speechConfidence, "silenceConfidence", silenceConfidence)
+}
+
 // reset clears internal state
 func (v *VADService) reset() {
 	v.isSpeaking = false
 	v.speechBuffer = v.speechBuffer[:0]
-	v.recentEnergy = v.recentEnergy[:0]
+	v.preRoll = v.preRoll[:0]
+	v.chunkSeq = 0
 	v.silenceStartTime = time.Time{}
 	v.speechStartTime = time.Time{}
+	if v.detector != nil {
+		v.detector.Reset()
+	}
+}
+
+// emitSpeechChunk fires onSpeechChunk with samples and the next seq, if a
+// callback is set. Must be called with the mutex held; unlocks around the
+// callback like triggerSpeechEnd does.
+func (v *VADService) emitSpeechChunk(samples []float32) {
+	callback := v.onSpeechChunk
+	if callback == nil {
+		return
+	}
+	seq := v.chunkSeq
+	v.chunkSeq++
+	v.mutex.Unlock()
+	callback(samples, seq)
+	v.mutex.Lock()
 }
 
 // ProcessSamples processes audio samples and detects voice activity
@@ -188,31 +339,52 @@ func (v *VADService) ProcessSamples(samples []float32) {
 		return
 	}
 
-	// Calculate RMS energy of this chunk
-	energy := v.calculateRMS(samples)
-	
-	// Add to rolling window for smoothing
-	v.recentEnergy = append(v.recentEnergy, energy)
-	if len(v.recentEnergy) > v.energyWindowSize {
-		v.recentEnergy = v.recentEnergy[1:]
+	// Classify this chunk via the active Detector
+	detectorIsSpeech, confidence := v.detector.Process(samples)
+
+	// Determine if this is speech. With hysteresis configured
+	// (SpeechConfidence/SilenceConfidence), apply it to Detector's
+	// confidence instead of trusting its own verdict, using a different
+	// threshold to enter speech than to fall back to silence. Otherwise
+	// (the default EnergyDetector case), use Detector's own verdict as-is.
+	var isSpeechChunk bool
+	if v.speechConfidence > 0 || v.silenceConfidence > 0 {
+		if v.isSpeaking {
+			isSpeechChunk = confidence >= v.silenceConfidence
+		} else {
+			isSpeechChunk = confidence >= v.speechConfidence
+		}
+	} else {
+		isSpeechChunk = detectorIsSpeech
+	}
+
+	if onEnergy := v.onEnergy; onEnergy != nil {
+		onEnergy(confidence)
 	}
-	
-	// Calculate smoothed energy (average of recent values)
-	smoothedEnergy := v.averageEnergy()
-	
-	// Determine if this is speech based on threshold
-	isSpeechChunk := smoothedEnergy > v.energyThreshold
 
 	now := time.Now()
 
 	if isSpeechChunk {
+		var chunkToEmit []float32
 		if !v.isSpeaking {
 			// Speech started
 			v.isSpeaking = true
 			v.speechStartTime = now
 			v.silenceStartTime = time.Time{}
-			slog.Debug("[VAD] Speech started", "energy", smoothedEnergy, "threshold", v.energyThreshold)
-			
+			slog.Debug("[VAD] Speech started", "confidence", confidence)
+
+			if len(v.preRoll) > 0 {
+				// Seed the utterance (and the first streamed chunk) with the
+				// lead-in audio captured before speech was confirmed.
+				chunkToEmit = make([]float32, 0, len(v.preRoll)+len(samples))
+				chunkToEmit = append(chunkToEmit, v.preRoll...)
+				chunkToEmit = append(chunkToEmit, samples...)
+				v.speechBuffer = append(v.speechBuffer, v.preRoll...)
+				v.preRoll = v.preRoll[:0]
+			} else {
+				chunkToEmit = samples
+			}
+
 			// Fire callback (unlock first to avoid deadlock)
 			callback := v.onSpeechStart
 			v.mutex.Unlock()
@@ -223,11 +395,13 @@ func (v *VADService) ProcessSamples(samples []float32) {
 		} else {
 			// Continuing speech, reset silence timer
 			v.silenceStartTime = time.Time{}
+			chunkToEmit = samples
 		}
-		
+
 		// Accumulate samples
 		v.speechBuffer = append(v.speechBuffer, samples...)
-		
+		v.emitSpeechChunk(chunkToEmit)
+
 		// Check max duration limit
 		maxSamples := int(float64(v.sampleRate) * v.maxSpeechDuration.Seconds())
 		if len(v.speechBuffer) >= maxSamples {
@@ -236,32 +410,38 @@ func (v *VADService) ProcessSamples(samples []float32) {
 			v.mutex.Unlock()
 			return
 		}
-	} else {
-		// Silence detected
-		if v.isSpeaking {
-			// Still in speech state, accumulate samples (include trailing silence)
-			v.speechBuffer = append(v.speechBuffer, samples...)
-			
-			if v.silenceStartTime.IsZero() {
-				// Start silence timer
-				v.silenceStartTime = now
-			} else if now.Sub(v.silenceStartTime) >= v.silenceDuration {
-				// Silence duration exceeded, check if we have enough speech
-				speechDuration := v.silenceStartTime.Sub(v.speechStartTime)
-				if speechDuration >= v.minSpeechDuration {
-					slog.Debug("[VAD] Speech ended", "duration", speechDuration, "samples", len(v.speechBuffer))
-					v.triggerSpeechEnd()
-					v.mutex.Unlock()
-					return
-				} else {
-					// Speech was too short, discard
-					slog.Debug("[VAD] Speech too short, discarding", "duration", speechDuration)
-					v.reset()
-				}
+	} else if v.isSpeaking {
+		// Still in speech state, accumulate samples (include trailing silence)
+		v.speechBuffer = append(v.speechBuffer, samples...)
+		v.emitSpeechChunk(samples)
+
+		if v.silenceStartTime.IsZero() {
+			// Start silence timer
+			v.silenceStartTime = now
+		} else if now.Sub(v.silenceStartTime) >= v.silenceDuration {
+			// Silence duration exceeded, check if we have enough speech
+			speechDuration := v.silenceStartTime.Sub(v.speechStartTime)
+			if speechDuration >= v.minSpeechDuration {
+				slog.Debug("[VAD] Speech ended", "duration", speechDuration, "samples", len(v.speechBuffer))
+				v.triggerSpeechEnd()
+				v.mutex.Unlock()
+				return
+			} else {
+				// Speech was too short, discard
+				slog.Debug("[VAD] Speech too short, discarding", "duration", speechDuration)
+				v.reset()
 			}
 		}
+	} else if v.preRollDuration > 0 {
+		// Not speaking - maintain the rolling pre-roll buffer so a future
+		// speech start has lead-in audio to seed from.
+		v.preRoll = append(v.preRoll, samples...)
+		maxPreRoll := int(float64(v.sampleRate) * v.preRollDuration.Seconds())
+		if len(v.preRoll) > maxPreRoll {
+			v.preRoll = v.preRoll[len(v.preRoll)-maxPreRoll:]
+		}
 	}
-	
+
 	v.mutex.Unlock()
 }
 
@@ -291,34 +471,6 @@ func (v *VADService) triggerSpeechEnd() {
 	v.mutex.Lock()
 }
 
-// calculateRMS calculates the root mean square energy of samples
-func (v *VADService) calculateRMS(samples []float32) float32 {
-	if len(samples) == 0 {
-		return 0
-	}
-	
-	var sum float64
-	for _, s := range samples {
-		sum += float64(s) * float64(s)
-	}
-	
-	return float32(math.Sqrt(sum / float64(len(samples))))
-}
-
-// averageEnergy calculates the average of recent energy values
-func (v *VADService) averageEnergy() float32 {
-	if len(v.recentEnergy) == 0 {
-		return 0
-	}
-	
-	var sum float32
-	for _, e := range v.recentEnergy {
-		sum += e
-	}
-	
-	return sum / float32(len(v.recentEnergy))
-}
-
 // GetBufferDuration returns the current speech buffer duration in seconds
 func (v *VADService) GetBufferDuration() float64 {
 	v.mutex.Lock()
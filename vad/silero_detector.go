@@ -0,0 +1,111 @@
+package vad
+
+import (
+	"fmt"
+	"sync"
+
+	ort "github.com/yalue/onnxruntime_go"
+)
+
+// sileroStateSize matches Silero VAD's recurrent state tensor shape
+// (2 x 1 x 128), which the model both consumes and re-emits each call so the
+// next chunk's classification is conditioned on prior audio.
+const sileroStateSize = 2 * 1 * 128
+
+// SileroDetector is a Detector backed by the Silero VAD ONNX model
+// (https://github.com/snakers4/silero-vad), run locally via onnxruntime-go.
+// Unlike EnergyDetector it returns a real model probability as confidence,
+// which pairs with VADService's SpeechConfidence/SilenceConfidence hysteresis
+// rather than a single fixed EnergyThreshold.
+type SileroDetector struct {
+	mutex sync.Mutex
+
+	session    *ort.AdvancedSession
+	input      *ort.Tensor[float32]
+	state      *ort.Tensor[float32]
+	sampleRate *ort.Tensor[int64]
+	output     *ort.Tensor[float32]
+	outState   *ort.Tensor[float32]
+}
+
+// NewSileroDetector loads the Silero VAD ONNX model at modelPath for audio
+// sampled at sampleRate (8000 or 16000, per the model's own requirement).
+func NewSileroDetector(modelPath string, sampleRate int64, chunkSize int) (*SileroDetector, error) {
+	input, err := ort.NewEmptyTensor[float32](ort.NewShape(1, int64(chunkSize)))
+	if err != nil {
+		return nil, fmt.Errorf("silero: failed to create input tensor: %w", err)
+	}
+	state, err := ort.NewEmptyTensor[float32](ort.NewShape(2, 1, 128))
+	if err != nil {
+		return nil, fmt.Errorf("silero: failed to create state tensor: %w", err)
+	}
+	sr, err := ort.NewTensor(ort.NewShape(1), []int64{sampleRate})
+	if err != nil {
+		return nil, fmt.Errorf("silero: failed to create sample-rate tensor: %w", err)
+	}
+	output, err := ort.NewEmptyTensor[float32](ort.NewShape(1, 1))
+	if err != nil {
+		return nil, fmt.Errorf("silero: failed to create output tensor: %w", err)
+	}
+	outState, err := ort.NewEmptyTensor[float32](ort.NewShape(2, 1, 128))
+	if err != nil {
+		return nil, fmt.Errorf("silero: failed to create output-state tensor: %w", err)
+	}
+
+	session, err := ort.NewAdvancedSession(modelPath,
+		[]string{"input", "state", "sr"},
+		[]string{"output", "stateN"},
+		[]ort.Value{input, state, sr},
+		[]ort.Value{output, outState},
+		nil)
+	if err != nil {
+		return nil, fmt.Errorf("silero: failed to load model %q: %w", modelPath, err)
+	}
+
+	return &SileroDetector{
+		session:    session,
+		input:      input,
+		state:      state,
+		sampleRate: sr,
+		output:     output,
+		outState:   outState,
+	}, nil
+}
+
+// Process implements Detector. samples must have the chunkSize length
+// NewSileroDetector was created with - VADService always calls it with
+// fixed-size chunks, so this isn't re-validated per call.
+func (d *SileroDetector) Process(samples []float32) (bool, float32) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	copy(d.input.GetData(), samples)
+
+	if err := d.session.Run(); err != nil {
+		return false, 0
+	}
+
+	// Feed the updated recurrent state back in for the next chunk.
+	copy(d.state.GetData(), d.outState.GetData())
+
+	confidence := d.output.GetData()[0]
+	return confidence >= 0.5, confidence
+}
+
+// Reset implements Detector, zeroing the recurrent state so the next
+// utterance doesn't start conditioned on the previous one.
+func (d *SileroDetector) Reset() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	state := d.state.GetData()
+	for i := range state {
+		state[i] = 0
+	}
+}
+
+// Close releases the underlying ONNX Runtime session and tensors.
+func (d *SileroDetector) Close() error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.session.Destroy()
+}
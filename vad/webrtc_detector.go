@@ -0,0 +1,89 @@
+package vad
+
+import (
+	"fmt"
+	"sync"
+
+	webrtcvad "github.com/maxhawkins/go-webrtcvad"
+)
+
+// webrtcMode selects WebRTC VAD's aggressiveness (0-3, least to most
+// aggressive about classifying a frame as non-speech). 2 matches the
+// "quality"/"low bitrate" middle ground most embedders default to.
+const webrtcMode = 2
+
+// WebRtcDetector is a Detector backed by the classic GMM-based WebRTC VAD
+// (https://github.com/maxhawkins/go-webrtcvad, a cgo binding of the VAD
+// shipped in libwebrtc). It's much cheaper than SileroDetector and needs no
+// model file, at the cost of accuracy on noisy input.
+type WebRtcDetector struct {
+	mutex sync.Mutex
+
+	vad        *webrtcvad.VAD
+	sampleRate int
+	pcmBuf     []int16
+}
+
+// NewWebRtcDetector creates a WebRtcDetector for audio sampled at
+// sampleRate, which WebRTC VAD requires to be one of 8000, 16000, 32000 or
+// 48000.
+func NewWebRtcDetector(sampleRate int) (*WebRtcDetector, error) {
+	v, err := webrtcvad.New()
+	if err != nil {
+		return nil, fmt.Errorf("webrtcvad: failed to create instance: %w", err)
+	}
+	if err := v.SetMode(webrtcMode); err != nil {
+		return nil, fmt.Errorf("webrtcvad: failed to set mode: %w", err)
+	}
+	return &WebRtcDetector{vad: v, sampleRate: sampleRate}, nil
+}
+
+// Process implements Detector. WebRTC VAD only reports a binary verdict, not
+// a probability, so confidence is 1 for speech and 0 for non-speech.
+func (d *WebRtcDetector) Process(samples []float32) (bool, float32) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if cap(d.pcmBuf) < len(samples) {
+		d.pcmBuf = make([]int16, len(samples))
+	}
+	pcm := d.pcmBuf[:len(samples)]
+	for i, s := range samples {
+		pcm[i] = float32ToPCM16(s)
+	}
+
+	isSpeech, err := d.vad.Process(d.sampleRate, int16SliceToBytes(pcm))
+	if err != nil {
+		return false, 0
+	}
+	if isSpeech {
+		return true, 1
+	}
+	return false, 0
+}
+
+// Reset implements Detector. WebRTC VAD is stateless between frames, so
+// there's nothing to clear.
+func (d *WebRtcDetector) Reset() {}
+
+// float32ToPCM16 converts a [-1, 1] sample to a signed 16-bit PCM sample,
+// clamping out-of-range input instead of wrapping.
+func float32ToPCM16(s float32) int16 {
+	if s > 1 {
+		s = 1
+	} else if s < -1 {
+		s = -1
+	}
+	return int16(s * 32767)
+}
+
+// int16SliceToBytes repacks pcm as little-endian bytes, the wire format
+// webrtcvad.VAD.Process expects.
+func int16SliceToBytes(pcm []int16) []byte {
+	b := make([]byte, len(pcm)*2)
+	for i, v := range pcm {
+		b[2*i] = byte(v)
+		b[2*i+1] = byte(v >> 8)
+	}
+	return b
+}
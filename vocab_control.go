@@ -0,0 +1,17 @@
+package main
+
+// ReloadVocab re-reads the vocab file (see transcription.VocabLoader and
+// transcription.VocabFileName) on demand, so a user editing custom words or
+// filler overrides doesn't have to restart the app to pick up changes -
+// vocabLoader also polls for changes on its own, but this gives an
+// immediate confirmation point for the settings UI. Returns an error
+// string, or "" on success.
+func (a *App) ReloadVocab() string {
+	if a.vocabLoader == nil {
+		return "vocab loader unavailable"
+	}
+	if err := a.vocabLoader.Reload(); err != nil {
+		return err.Error()
+	}
+	return ""
+}
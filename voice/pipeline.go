@@ -0,0 +1,148 @@
+// Package voice composes VAD, speech-to-text, Gemini conversation, and
+// text-to-speech into a single push-samples/get-audio-reply service, so a
+// caller only needs to feed PCM frames in and receive spoken replies back.
+package voice
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"super-characters/gemini"
+	"super-characters/stt"
+	"super-characters/tts"
+	"super-characters/vad"
+)
+
+// Reply is produced once a full voice turn (listen -> transcribe -> converse
+// -> synthesize) completes. Audio is empty if TTS synthesis failed or no
+// provider is configured; Text is still set in that case.
+type Reply struct {
+	Text     string
+	Audio    []byte
+	MimeType string
+}
+
+// ReplyCallback receives each completed voice turn, or the error from
+// whichever stage failed.
+type ReplyCallback func(reply Reply, err error)
+
+// PipelineService wires a vad.VADService, an stt.Registry, a
+// gemini.GeminiService, and a tts.Registry together. Callers push PCM frames
+// via PushSamples and receive replies via the callback registered with
+// SetReplyCallback - they don't need to know VAD, STT, or TTS exist
+// individually.
+type PipelineService struct {
+	vadService    *vad.VADService
+	sttRegistry   *stt.Registry
+	geminiService *gemini.GeminiService
+	ttsRegistry   *tts.Registry
+
+	sampleRate int
+	lang       string
+	history    []gemini.ChatMessage
+	onReply    ReplyCallback
+}
+
+// NewPipelineService wires the given services together. sampleRate is the
+// PCM sample rate of the frames passed to PushSamples.
+func NewPipelineService(vadService *vad.VADService, sttRegistry *stt.Registry, geminiService *gemini.GeminiService, ttsRegistry *tts.Registry, sampleRate int) *PipelineService {
+	p := &PipelineService{
+		vadService:    vadService,
+		sttRegistry:   sttRegistry,
+		geminiService: geminiService,
+		ttsRegistry:   ttsRegistry,
+		sampleRate:    sampleRate,
+	}
+	p.vadService.SetCallbacks(nil, p.onSpeechEnd)
+	return p
+}
+
+// SetReplyCallback registers the function called with each completed voice
+// turn.
+func (p *PipelineService) SetReplyCallback(cb ReplyCallback) {
+	p.onReply = cb
+}
+
+// SetLanguage sets the BCP-47 language hint passed to the active
+// Recognizer; empty means auto-detect.
+func (p *PipelineService) SetLanguage(lang string) {
+	p.lang = lang
+}
+
+// PushSamples feeds a chunk of PCM float32 audio into the pipeline's VAD.
+// Once VAD detects an utterance has ended, the pipeline transcribes it,
+// sends it to Gemini, synthesizes the reply, and reports it via the
+// registered ReplyCallback.
+func (p *PipelineService) PushSamples(samples []float32) {
+	p.vadService.ProcessSamples(samples)
+}
+
+// Start begins VAD processing.
+func (p *PipelineService) Start() { p.vadService.Start() }
+
+// Stop halts VAD processing.
+func (p *PipelineService) Stop() { p.vadService.Stop() }
+
+// Reset clears the conversation history, starting a fresh turn.
+func (p *PipelineService) Reset() {
+	p.history = nil
+}
+
+func (p *PipelineService) onSpeechEnd(samples []float32) {
+	reply, err := p.processTurn(context.Background(), samples)
+	if p.onReply != nil {
+		p.onReply(reply, err)
+	}
+}
+
+func (p *PipelineService) processTurn(ctx context.Context, samples []float32) (Reply, error) {
+	recognizer, err := p.sttRegistry.Active()
+	if err != nil {
+		return Reply{}, fmt.Errorf("stt: %w", err)
+	}
+
+	text, err := recognizer.Transcribe(ctx, floatsToPCM16(samples), p.sampleRate, p.lang)
+	if err != nil {
+		return Reply{}, fmt.Errorf("transcribe: %w", err)
+	}
+	if text == "" {
+		return Reply{}, fmt.Errorf("transcribe: empty result")
+	}
+
+	p.history = append(p.history, gemini.ChatMessage{Role: "user", Content: text})
+	response, err := p.geminiService.Chat(p.history)
+	if err != nil {
+		return Reply{}, fmt.Errorf("gemini: %w", err)
+	}
+	p.history = append(p.history, gemini.ChatMessage{Role: "assistant", Content: response})
+
+	provider, err := p.ttsRegistry.Active()
+	if err != nil {
+		return Reply{Text: response}, nil
+	}
+	audioBytes, mimeType, err := provider.Synthesize(ctx, response, "")
+	if err != nil {
+		slog.Warn("[Voice] TTS synthesis failed, returning text-only reply", "provider", provider.Name(), "error", err)
+		return Reply{Text: response}, nil
+	}
+
+	return Reply{Text: response, Audio: audioBytes, MimeType: mimeType}, nil
+}
+
+// floatsToPCM16 converts float32 samples in [-1, 1] to little-endian PCM16
+// bytes, the format stt.Recognizer implementations expect.
+func floatsToPCM16(samples []float32) []byte {
+	buf := make([]byte, len(samples)*2)
+	for i, s := range samples {
+		if s > 1 {
+			s = 1
+		} else if s < -1 {
+			s = -1
+		}
+		v := int16(s * 32767)
+		buf[i*2] = byte(v)
+		buf[i*2+1] = byte(v >> 8)
+	}
+	return buf
+}
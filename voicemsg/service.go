@@ -0,0 +1,181 @@
+// Package voicemsg implements an offline "record and keep" alternative to
+// live transcription: it accumulates raw audio captured elsewhere (see
+// App.StartVoiceMessage), encodes it to a standalone Ogg Opus file via
+// audio.NewEncoder, and produces a downsampled waveform preview suitable for
+// a scrollable playback UI.
+package voicemsg
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"super-characters/audio"
+)
+
+// WaveformBars is the number of RMS buckets computed for a voice message's
+// waveform preview.
+const WaveformBars = 100
+
+// LevelCallback is invoked with the current RMS level (0.0-1.0) of each
+// chunk of audio added while a voice message is being recorded, for a live
+// level meter.
+type LevelCallback func(level float32)
+
+// Message describes a voice message saved by Service.Stop.
+type Message struct {
+	Path       string    `json:"path"`
+	Waveform   []float32 `json:"waveform"`
+	DurationMs int       `json:"durationMs"`
+	RecordedAt time.Time `json:"recordedAt"`
+}
+
+// Service records voice messages and persists them as Ogg Opus files
+// alongside a waveform preview.
+type Service struct {
+	dataDir string
+
+	mu         sync.Mutex
+	recording  bool
+	samples    []float32
+	sampleRate int
+}
+
+// NewService creates a voice message service with storage at
+// ~/.super-characters/voice-messages/.
+func NewService() (*Service, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	dataDir := filepath.Join(homeDir, ".super-characters", "voice-messages")
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create voice messages directory: %w", err)
+	}
+
+	return &Service{dataDir: dataDir}, nil
+}
+
+// Start begins accumulating samples for a new voice message captured at
+// sampleRate. It's an error to call Start while already recording.
+func (s *Service) Start(sampleRate int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.recording {
+		return fmt.Errorf("voice message already recording")
+	}
+
+	s.recording = true
+	s.sampleRate = sampleRate
+	s.samples = s.samples[:0]
+	return nil
+}
+
+// AddSamples appends captured audio to the in-progress recording and
+// reports its RMS level via onLevel, if non-nil. It's a no-op if no
+// recording is in progress.
+func (s *Service) AddSamples(samples []float32, onLevel LevelCallback) {
+	s.mu.Lock()
+	if !s.recording {
+		s.mu.Unlock()
+		return
+	}
+	s.samples = append(s.samples, samples...)
+	s.mu.Unlock()
+
+	if onLevel != nil {
+		onLevel(rms(samples))
+	}
+}
+
+// IsRecording reports whether a voice message is currently being recorded.
+func (s *Service) IsRecording() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.recording
+}
+
+// Stop ends the in-progress recording, encodes it to an Ogg Opus file under
+// the service's data directory, and returns its path, waveform preview, and
+// duration. It's an error to call Stop when nothing is recording, or when
+// nothing was captured.
+func (s *Service) Stop() (*Message, error) {
+	s.mu.Lock()
+	if !s.recording {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("no voice message recording in progress")
+	}
+	samples := s.samples
+	sampleRate := s.sampleRate
+	s.recording = false
+	s.samples = nil
+	s.mu.Unlock()
+
+	if len(samples) == 0 {
+		return nil, fmt.Errorf("no audio captured")
+	}
+
+	recordedAt := time.Now()
+	filename := fmt.Sprintf("voice-%s.opus", recordedAt.Format("20060102-150405.000"))
+	path := filepath.Join(s.dataDir, filename)
+
+	enc, err := audio.NewEncoder(path, audio.EncoderConfig{Codec: "ogg-opus", SampleRate: sampleRate})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create encoder: %w", err)
+	}
+	if err := enc.WriteSamples(samples); err != nil {
+		enc.Close()
+		return nil, fmt.Errorf("failed to encode voice message: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finalize voice message: %w", err)
+	}
+
+	durationMs := int(float64(len(samples)) / float64(sampleRate) * 1000)
+
+	return &Message{
+		Path:       path,
+		Waveform:   waveform(samples, WaveformBars),
+		DurationMs: durationMs,
+		RecordedAt: recordedAt,
+	}, nil
+}
+
+// waveform downsamples samples into bars buckets of RMS energy, for a
+// scrollable waveform-preview UI.
+func waveform(samples []float32, bars int) []float32 {
+	if len(samples) == 0 || bars <= 0 {
+		return nil
+	}
+
+	out := make([]float32, bars)
+	bucketSize := float64(len(samples)) / float64(bars)
+	for i := 0; i < bars; i++ {
+		start := int(float64(i) * bucketSize)
+		end := int(float64(i+1) * bucketSize)
+		if end > len(samples) {
+			end = len(samples)
+		}
+		if end <= start {
+			continue
+		}
+		out[i] = rms(samples[start:end])
+	}
+	return out
+}
+
+// rms computes the root-mean-square energy of samples.
+func rms(samples []float32) float32 {
+	if len(samples) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, s := range samples {
+		sum += float64(s) * float64(s)
+	}
+	return float32(math.Sqrt(sum / float64(len(samples))))
+}